@@ -108,16 +108,22 @@ func matrixData() *FeatureMatrix {
 		ProviderThreadSafe       = "[Concurrency Verified](../advanced-features/concurrency-verified.md)"
 		DomainModifierAlias      = "[`ALIAS`](../language-reference/domain-modifiers/ALIAS.md)"
 		DomainModifierCaa        = "[`CAA`](../language-reference/domain-modifiers/CAA.md)"
+		DomainModifierCdnskey    = "[`CDNSKEY`](../language-reference/domain-modifiers/CDNSKEY.md)"
+		DomainModifierCds        = "[`CDS`](../language-reference/domain-modifiers/CDS.md)"
+		DomainModifierCert       = "[`CERT`](../language-reference/domain-modifiers/CERT.md)"
 		DomainModifierDhcid      = "[`DHCID`](../language-reference/domain-modifiers/DHCID.md)"
 		DomainModifierDname      = "[`DNAME`](../language-reference/domain-modifiers/DNAME.md)"
 		DomainModifierDnskey     = "[`DNSKEY`](../language-reference/domain-modifiers/DNSKEY.md)"
 		DomainModifierDnssec     = "[`AUTODNSSEC`](../language-reference/domain-modifiers/AUTODNSSEC_ON.md)"
 		DomainModifierDs         = "[`DS`](../language-reference/domain-modifiers/DS.md)"
+		DomainModifierHinfo      = "[`HINFO`](../language-reference/domain-modifiers/HINFO.md)"
 		DomainModifierHTTPS      = "[`HTTPS`](../language-reference/domain-modifiers/HTTPS.md)"
+		DomainModifierIpseckey   = "[`IPSECKEY`](../language-reference/domain-modifiers/IPSECKEY.md)"
 		DomainModifierLoc        = "[`LOC`](../language-reference/domain-modifiers/LOC.md)"
 		DomainModifierNaptr      = "[`NAPTR`](../language-reference/domain-modifiers/NAPTR.md)"
 		DomainModifierOpenpgpkey = "[`DNSKEY`](../language-reference/domain-modifiers/OPENPGPKEY.md)"
 		DomainModifierPtr        = "[`PTR`](../language-reference/domain-modifiers/PTR.md)"
+		DomainModifierRaw        = "[`RAW`](../language-reference/domain-modifiers/RAW.md)"
 		DomainModifierRP         = "[`RP`](../language-reference/domain-modifiers/RP.md)"
 		DomainModifierSMIMEA     = "[`SMIMEA`](../language-reference/domain-modifiers/SMIMEA.md)"
 		DomainModifierSoa        = "[`SOA`](../language-reference/domain-modifiers/SOA.md)"
@@ -125,6 +131,10 @@ func matrixData() *FeatureMatrix {
 		DomainModifierSshfp      = "[`SSHFP`](../language-reference/domain-modifiers/SSHFP.md)"
 		DomainModifierSvcb       = "[`SVCB`](../language-reference/domain-modifiers/SVCB.md)"
 		DomainModifierTlsa       = "[`TLSA`](../language-reference/domain-modifiers/TLSA.md)"
+		DomainModifierUri        = "[`URI`](../language-reference/domain-modifiers/URI.md)"
+		DomainModifierZonemd     = "[`ZONEMD`](../language-reference/domain-modifiers/ZONEMD.md)"
+		RecordModifierSteer      = "[`STEER`](../language-reference/record-modifiers/STEER.md)"
+		TopLevelHealthCheck      = "[`HEALTH_CHECK`](../language-reference/top-level-functions/HEALTH_CHECK.md)"
 		DualHost                 = "[dual host](../advanced-features/dual-host.md)"
 		CreateDomains            = "create-domains"
 		GetZones                 = "get-zones"
@@ -159,6 +169,8 @@ func matrixData() *FeatureMatrix {
 				DomainModifierLoc,
 				DomainModifierPtr,
 				DomainModifierSoa,
+				RecordModifierSteer,
+				TopLevelHealthCheck,
 			},
 			[]string{ // service discovery
 				DomainModifierDhcid,
@@ -284,10 +296,50 @@ func matrixData() *FeatureMatrix {
 			DomainModifierPtr,
 			providers.CanUsePTR,
 		)
+		setCapability(
+			DomainModifierCdnskey,
+			providers.CanUseCDNSKEY,
+		)
+		setCapability(
+			DomainModifierCds,
+			providers.CanUseCDS,
+		)
+		setCapability(
+			DomainModifierCert,
+			providers.CanUseCERT,
+		)
+		setCapability(
+			DomainModifierHinfo,
+			providers.CanUseHINFO,
+		)
+		setCapability(
+			DomainModifierIpseckey,
+			providers.CanUseIPSECKEY,
+		)
+		setCapability(
+			DomainModifierRaw,
+			providers.CanUseRAW,
+		)
 		setCapability(
 			DomainModifierRP,
 			providers.CanUseRP,
 		)
+		setCapability(
+			DomainModifierUri,
+			providers.CanUseURI,
+		)
+		setCapability(
+			DomainModifierZonemd,
+			providers.CanUseZONEMD,
+		)
+		setCapability(
+			RecordModifierSteer,
+			providers.CanUseSteering,
+		)
+		setCapability(
+			TopLevelHealthCheck,
+			providers.CanUseHealthChecks,
+		)
 		setCapability(
 			DomainModifierSMIMEA,
 			providers.CanUseSMIMEA,
@@ -0,0 +1,25 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/DNSControl/dnscontrol/v4/models"
+	"github.com/DNSControl/dnscontrol/v4/providers/cloudflare/rtypes/cfloadbalancer"
+)
+
+func TestMakeCfLoadBalancer(t *testing.T) {
+	rec := models.RecordConfig{
+		Type: "CF_LOAD_BALANCER",
+		Name: "www",
+		F: &cfloadbalancer.LoadBalancer{
+			Steering: "off",
+			Pools: []cfloadbalancer.Pool{
+				{Name: "main", Origins: []cfloadbalancer.Origin{{Name: "web1", Address: "192.0.2.1", Weight: 1}}},
+			},
+		},
+	}
+	w := `CF_LOAD_BALANCER("www", [{"name":"main","origins":[{"name":"web1","address":"192.0.2.1","weight":1}]}], "off", CF_PROXY_ON)`
+	if g := makeCfLoadBalancer(&rec, ", CF_PROXY_ON", ""); g != w {
+		t.Errorf("makeCfLoadBalancer failure: got `%s` want `%s`", g, w)
+	}
+}
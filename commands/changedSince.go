@@ -0,0 +1,302 @@
+package commands
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/DNSControl/dnscontrol/v4/models"
+	"github.com/DNSControl/dnscontrol/v4/pkg/normalize"
+)
+
+// changedZones evaluates dnsconfig.js as it was at the git revision ref and
+// reports for each zone of cfg whether its desired state is different there
+// (preview --changed-since). Zones that are new since ref are changed. The
+// comparison uses the IR, so changes to files that dnsconfig.js require()s
+// are noticed, and so are changes to the zone's registrar and DNS
+// providers. cfg must not be normalized yet.
+func changedZones(cfg *models.DNSConfig, args ExecuteDSLArgs, ref string) (map[string]bool, error) {
+	old, err := executeDSLAtRevision(args, ref)
+	if err != nil {
+		return nil, err
+	}
+	return diffZones(old, cfg)
+}
+
+// diffZones reports for each zone of cur whether it is not the same in old.
+//
+// The IR is compared before it is normalized, so the records that other
+// zones contribute during normalization are not in it yet. Instead, a zone is
+// also changed if a zone it imports from with IMPORT_TRANSFORM changed, and
+// the reverse zones with AUTOPTR changed whenever a forward zone did.
+func diffZones(old, cur *models.DNSConfig) (map[string]bool, error) {
+	// Any zone may use a health check, so all zones change with them.
+	oldChecks, err := fingerprint(old.HealthChecks)
+	if err != nil {
+		return nil, err
+	}
+	curChecks, err := fingerprint(cur.HealthChecks)
+	if err != nil {
+		return nil, err
+	}
+	allChanged := oldChecks != curChecks
+
+	oldZones := map[string]string{}
+	for _, dc := range old.Domains {
+		if oldZones[dc.UniqueName], err = zoneFingerprint(old, dc); err != nil {
+			return nil, err
+		}
+	}
+	changed := map[string]bool{}
+	for _, dc := range cur.Domains {
+		fp, err := zoneFingerprint(cur, dc)
+		if err != nil {
+			return nil, err
+		}
+		prev, ok := oldZones[dc.UniqueName]
+		changed[dc.UniqueName] = allChanged || !ok || prev != fp
+		delete(oldZones, dc.UniqueName)
+	}
+	followImports(cur, changed)
+
+	forwardChanged := false
+	for _, dc := range cur.Domains {
+		if changed[dc.UniqueName] && !normalize.IsReverseZone(dc.Name) {
+			forwardChanged = true
+		}
+	}
+	for _, dc := range old.Domains {
+		if _, removed := oldZones[dc.UniqueName]; removed && !normalize.IsReverseZone(dc.Name) {
+			forwardChanged = true
+		}
+	}
+	if forwardChanged {
+		for _, dc := range cur.Domains {
+			if normalize.IsReverseZone(dc.Name) && dc.Metadata["autoptr"] == "true" {
+				changed[dc.UniqueName] = true
+			}
+		}
+	}
+	return changed, nil
+}
+
+// followImports marks the zones that import (IMPORT_TRANSFORM) from a
+// changed zone as changed, directly or through other imports.
+func followImports(cfg *models.DNSConfig, changed map[string]bool) {
+	for more := true; more; {
+		more = false
+		changedNames := map[string]bool{}
+		for _, dc := range cfg.Domains {
+			if changed[dc.UniqueName] {
+				changedNames[dc.Name] = true
+			}
+		}
+		for _, dc := range cfg.Domains {
+			if changed[dc.UniqueName] {
+				continue
+			}
+			for _, rec := range dc.Records {
+				if rec.Type == "IMPORT_TRANSFORM" && changedNames[strings.TrimSuffix(rec.GetTargetField(), ".")] {
+					changed[dc.UniqueName] = true
+					more = true
+					break
+				}
+			}
+		}
+	}
+}
+
+// zoneFingerprint returns a string that is the same for two zones if their
+// desired state is the same, including the definition of their registrar
+// and DNS providers.
+func zoneFingerprint(cfg *models.DNSConfig, dc *models.DomainConfig) (string, error) {
+	var registrars []*models.RegistrarConfig
+	for _, r := range cfg.Registrars {
+		if r.Name == dc.RegistrarName {
+			registrars = append(registrars, r)
+		}
+	}
+	var dnsProviders []*models.DNSProviderConfig
+	for _, p := range cfg.DNSProviders {
+		if _, ok := dc.DNSProviderNames[p.Name]; ok {
+			dnsProviders = append(dnsProviders, p)
+		}
+	}
+	return fingerprint(struct {
+		Zone         *models.DomainConfig
+		Registrars   []*models.RegistrarConfig
+		DNSProviders []*models.DNSProviderConfig
+	}{dc, registrars, dnsProviders})
+}
+
+// fingerprint returns the JSON of v without the positions in dnsconfig.js
+// ("filepos"), which change whenever a line is added above a record. Map
+// keys are sorted, so the result does not depend on their order.
+func fingerprint(v any) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	var generic any
+	if err := json.Unmarshal(b, &generic); err != nil {
+		return "", err
+	}
+	b, err = json.Marshal(withoutFilePos(generic))
+	return string(b), err
+}
+
+func withoutFilePos(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		delete(v, "filepos")
+		for k, e := range v {
+			v[k] = withoutFilePos(e)
+		}
+	case []any:
+		for i, e := range v {
+			v[i] = withoutFilePos(e)
+		}
+	}
+	return v
+}
+
+// onlyChangedZones returns the zones that changed. Zones that were not in
+// the evaluated dnsconfig.js, such as generated catalog zones, are kept.
+func onlyChangedZones(zones []*models.DomainConfig, changed map[string]bool) []*models.DomainConfig {
+	var picked []*models.DomainConfig
+	for _, dc := range zones {
+		if c, ok := changed[dc.UniqueName]; !ok || c {
+			picked = append(picked, dc)
+		}
+	}
+	return picked
+}
+
+// executeDSLAtRevision runs the dnsconfig.js of args as it was at the git
+// revision ref. The repository is exported to a temporary directory, so
+// that require()d files are from the same revision.
+func executeDSLAtRevision(args ExecuteDSLArgs, ref string) (*models.DNSConfig, error) {
+	if args.JSFile == "" {
+		return nil, errors.New("no config specified")
+	}
+	dir := filepath.Dir(args.JSFile)
+	top, err := git(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+	prefix, err := git(dir, "rev-parse", "--show-prefix")
+	if err != nil {
+		return nil, err
+	}
+	archive, err := git(strings.TrimSpace(top), "archive", "--format=tar", ref)
+	if err != nil {
+		return nil, err
+	}
+
+	tmp, err := os.MkdirTemp("", "dnscontrol-changed-since-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmp)
+	if err := untar(tmp, bytes.NewReader([]byte(archive))); err != nil {
+		return nil, fmt.Errorf("extracting %s: %w", ref, err)
+	}
+
+	args.JSFile = filepath.Join(tmp, filepath.FromSlash(strings.TrimSpace(prefix)), filepath.Base(args.JSFile))
+	if _, err := os.Stat(args.JSFile); err != nil {
+		return nil, fmt.Errorf("%s does not exist at %s", filepath.Base(args.JSFile), ref)
+	}
+
+	// require() finds files that don't start with "." in the current
+	// directory, so move to the same directory in the exported tree.
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	if rel, ok := insideDir(strings.TrimSpace(top), wd); ok {
+		if err := os.Chdir(filepath.Join(tmp, rel)); err == nil {
+			defer os.Chdir(wd)
+		}
+	}
+	return ExecuteDSL(args)
+}
+
+// insideDir returns the path of path relative to dir, if path is dir or in
+// it.
+func insideDir(dir, path string) (string, bool) {
+	if p, err := filepath.EvalSymlinks(path); err == nil {
+		path = p
+	}
+	if d, err := filepath.EvalSymlinks(dir); err == nil {
+		dir = d
+	}
+	rel, err := filepath.Rel(dir, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return rel, true
+}
+
+// git runs git in dir and returns its output.
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return string(out), nil
+}
+
+// untar extracts the directories, files and symlinks of a tar archive into
+// dir.
+func untar(dir string, r io.Reader) error {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		name := filepath.Join(dir, filepath.FromSlash(hdr.Name))
+		if !strings.HasPrefix(name, filepath.Clean(dir)+string(filepath.Separator)) {
+			return fmt.Errorf("invalid path in archive: %q", hdr.Name)
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(name, 0o755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+				return err
+			}
+			f, err := os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(f, tr)
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+			if err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			if err := os.Symlink(hdr.Linkname, name); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package commands
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/DNSControl/dnscontrol/v4/models"
+)
+
+func TestDiffZones(t *testing.T) {
+	config := func(target, pos, meta string) *models.DNSConfig {
+		return &models.DNSConfig{
+			Registrars:   []*models.RegistrarConfig{{Name: "none", Type: "NONE"}},
+			DNSProviders: []*models.DNSProviderConfig{{Name: "bind", Type: "BIND", Metadata: []byte(meta)}},
+			Domains: []*models.DomainConfig{
+				{Name: "a.com", UniqueName: "a.com", RegistrarName: "none", DNSProviderNames: map[string]int{"bind": -1},
+					RawRecords: []models.RawRecordConfig{{Type: "A", Args: []any{"www", target}, FilePos: pos}}},
+				{Name: "b.com", UniqueName: "b.com", RegistrarName: "none"},
+			},
+		}
+	}
+	old := config("192.0.2.1", "dnsconfig.js:3:1", `{}`)
+
+	for _, tc := range []struct {
+		name string
+		cur  *models.DNSConfig
+		want map[string]bool
+	}{
+		{"same", config("192.0.2.1", "dnsconfig.js:3:1", `{}`), map[string]bool{"a.com": false, "b.com": false}},
+		{"moved", config("192.0.2.1", "dnsconfig.js:9:1", `{}`), map[string]bool{"a.com": false, "b.com": false}},
+		{"record", config("192.0.2.2", "dnsconfig.js:3:1", `{}`), map[string]bool{"a.com": true, "b.com": false}},
+		{"provider", config("192.0.2.1", "dnsconfig.js:3:1", `{"default_ttl":60}`), map[string]bool{"a.com": true, "b.com": false}},
+		{"new zone", func() *models.DNSConfig {
+			cfg := config("192.0.2.1", "dnsconfig.js:3:1", `{}`)
+			cfg.Domains = append(cfg.Domains, &models.DomainConfig{Name: "c.com", UniqueName: "c.com"})
+			return cfg
+		}(), map[string]bool{"a.com": false, "b.com": false, "c.com": true}},
+		{"health check", func() *models.DNSConfig {
+			cfg := config("192.0.2.1", "dnsconfig.js:3:1", `{}`)
+			cfg.HealthChecks = []*models.HealthCheck{{Name: "web"}}
+			return cfg
+		}(), map[string]bool{"a.com": true, "b.com": true}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := diffZones(old, tc.cur)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tc.want) {
+				t.Fatalf("got %v, want %v", got, tc.want)
+			}
+			for k, v := range tc.want {
+				if got[k] != v {
+					t.Errorf("got %v, want %v", got, tc.want)
+				}
+			}
+		})
+	}
+}
+
+func TestDiffZonesAutoPTR(t *testing.T) {
+	// The PTR records of a reverse zone with AUTOPTR come from the forward
+	// zones, so it changes with them.
+	config := func(target string) *models.DNSConfig {
+		return &models.DNSConfig{
+			Domains: []*models.DomainConfig{
+				{Name: "a.com", UniqueName: "a.com",
+					RawRecords: []models.RawRecordConfig{{Type: "A", Args: []any{"www", target}}}},
+				{Name: "2.0.192.in-addr.arpa", UniqueName: "2.0.192.in-addr.arpa", Metadata: map[string]string{"autoptr": "true"}},
+				{Name: "100.51.198.in-addr.arpa", UniqueName: "100.51.198.in-addr.arpa"},
+			},
+		}
+	}
+	old := config("192.0.2.1")
+
+	got, err := diffZones(old, config("192.0.2.1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got["2.0.192.in-addr.arpa"] {
+		t.Errorf("unchanged forward zones: got %v", got)
+	}
+
+	got, err = diffZones(old, config("192.0.2.2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]bool{"a.com": true, "2.0.192.in-addr.arpa": true, "100.51.198.in-addr.arpa": false}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("changed forward zone: got %v, want %v", got, want)
+		}
+	}
+
+	removed := config("192.0.2.1")
+	removed.Domains = removed.Domains[1:]
+	got, err = diffZones(old, removed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got["2.0.192.in-addr.arpa"] {
+		t.Errorf("removed forward zone: got %v", got)
+	}
+}
+
+func TestDiffZonesImportTransform(t *testing.T) {
+	// The records of a zone with IMPORT_TRANSFORM come from the zone it
+	// imports from, so it changes with it, even through another import.
+	importFrom := func(name, from string) *models.DomainConfig {
+		rec := &models.RecordConfig{Type: "IMPORT_TRANSFORM", Metadata: map[string]string{}}
+		rec.SetLabel("@", name)
+		rec.MustSetTarget(from)
+		return &models.DomainConfig{Name: name, UniqueName: name, Records: models.Records{rec}}
+	}
+	config := func(target string) *models.DNSConfig {
+		return &models.DNSConfig{
+			Domains: []*models.DomainConfig{
+				{Name: "a.com", UniqueName: "a.com",
+					RawRecords: []models.RawRecordConfig{{Type: "A", Args: []any{"www", target}}}},
+				importFrom("b.com", "a.com"),
+				importFrom("c.com", "b.com"),
+				{Name: "d.com", UniqueName: "d.com"},
+			},
+		}
+	}
+	old := config("192.0.2.1")
+
+	got, err := diffZones(old, config("192.0.2.1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got["b.com"] || got["c.com"] {
+		t.Errorf("unchanged source zone: got %v", got)
+	}
+
+	got, err = diffZones(old, config("192.0.2.2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]bool{"a.com": true, "b.com": true, "c.com": true, "d.com": false}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("changed source zone: got %v, want %v", got, want)
+		}
+	}
+}
+
+func TestExecuteDSLAtRevision(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		if _, err := git(dir, args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	run("init", "-q")
+	run("config", "user.email", "test@example.com")
+	run("config", "user.name", "test")
+	write("dns/dnsconfig.js", `require("zones/a.js");`)
+	write("dns/zones/a.js", `D("a.com", NewRegistrar("none", "NONE"), A("www", "192.0.2.1"));`)
+	run("add", "-A")
+	run("commit", "-q", "-m", "first")
+	write("dns/zones/a.js", `D("a.com", NewRegistrar("none", "NONE"), A("www", "192.0.2.2"));`)
+
+	// require() without "./" is relative to the current directory.
+	t.Chdir(filepath.Join(dir, "dns"))
+	cfg, err := executeDSLAtRevision(ExecuteDSLArgs{JSFile: filepath.Join(dir, "dns", "dnsconfig.js")}, "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Domains) != 1 || len(cfg.Domains[0].Records) != 1 || cfg.Domains[0].Records[0].GetTargetField() != "192.0.2.1" {
+		t.Errorf("got %+v, want the config of HEAD", cfg.Domains)
+	}
+
+	if _, err := executeDSLAtRevision(ExecuteDSLArgs{JSFile: filepath.Join(dir, "dns", "dnsconfig.js")}, "no-such-ref"); err == nil {
+		t.Error("expected an error for an unknown revision")
+	}
+}
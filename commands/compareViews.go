@@ -0,0 +1,44 @@
+package commands
+
+import (
+	"strings"
+
+	"github.com/DNSControl/dnscontrol/v4/models"
+	"github.com/DNSControl/dnscontrol/v4/pkg/printer"
+	"github.com/DNSControl/dnscontrol/v4/pkg/views"
+)
+
+// printViewDiffs prints, for each split horizon zone, the labels whose
+// records differ between its views (preview --compare-views).
+func printViewDiffs(out printer.CLI, domains []*models.DomainConfig) {
+	zones := views.Zones(domains)
+	if len(zones) == 0 {
+		out.Printf("No zone has more than one view.\n")
+		return
+	}
+	for _, zone := range zones {
+		names := make([]string, len(zone))
+		width := 0
+		for i, dc := range zone {
+			names[i] = views.Name(dc)
+			width = max(width, len(names[i]))
+		}
+		out.Printf("******************** Views of %s: %s\n", zone[0].NameUnicode, strings.Join(names, " "))
+		diffs := views.Compare(zone, nil)
+		if len(diffs) == 0 {
+			out.Printf("  The views are identical.\n")
+			continue
+		}
+		for _, d := range diffs {
+			out.Printf("  %s\n", d.Label)
+			for i, recs := range d.Records {
+				if len(recs) == 0 {
+					out.Printf("    %-*s  (none)\n", width, names[i])
+				}
+				for _, r := range recs {
+					out.Printf("    %-*s  %s\n", width, names[i], r)
+				}
+			}
+		}
+	}
+}
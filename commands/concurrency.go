@@ -0,0 +1,87 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/DNSControl/dnscontrol/v4/pkg/notifications"
+	"github.com/DNSControl/dnscontrol/v4/pkg/printer"
+)
+
+// maxConcurrencyFieldName is the field of a creds.json entry that limits
+// how many zones preview/push process at the same time with it.
+const maxConcurrencyFieldName = "max_concurrency"
+
+// credLimiter limits the number of zones that use a creds.json entry at
+// the same time. Entries without "max_concurrency" are only limited by
+// --cmax.
+type credLimiter struct {
+	slots map[string]chan struct{}
+}
+
+// newCredLimiter returns a credLimiter for the "max_concurrency" fields
+// of providerConfigs.
+func newCredLimiter(providerConfigs map[string]map[string]string) (*credLimiter, error) {
+	l := &credLimiter{slots: map[string]chan struct{}{}}
+	for name, cfg := range providerConfigs {
+		v, ok := cfg[maxConcurrencyFieldName]
+		if !ok {
+			continue
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("creds.json entry %q: %s must be a positive integer, not %q", name, maxConcurrencyFieldName, v)
+		}
+		l.slots[name] = make(chan struct{}, n)
+	}
+	return l, nil
+}
+
+// do runs f once fewer than "max_concurrency" other calls for the
+// creds.json entry named name are running.
+func (l *credLimiter) do(name string, f func()) {
+	if slots := l.slots[name]; slots != nil {
+		slots <- struct{}{}
+		defer func() { <-slots }()
+	}
+	f()
+}
+
+// zoneOutput collects the output of a zone that is pushed concurrently
+// with others, so that it can be printed in one piece when the zone is done.
+type zoneOutput struct {
+	printer.ConsolePrinter
+	buf bytes.Buffer
+}
+
+// newZoneOutput returns a zoneOutput that prints like out. It returns
+// false if out is not a console printer.
+func newZoneOutput(out printer.CLI) (*zoneOutput, bool) {
+	var cp printer.ConsolePrinter
+	switch p := out.(type) {
+	case *printer.ConsolePrinter:
+		cp = *p
+	case printer.ConsolePrinter:
+		cp = p
+	default:
+		return nil, false
+	}
+	zo := &zoneOutput{ConsolePrinter: cp}
+	zo.Writer = &zo.buf
+	return zo, true
+}
+
+// lockedNotifier serializes the notifications of zones that are pushed
+// concurrently.
+type lockedNotifier struct {
+	mu sync.Mutex
+	notifications.Notifier
+}
+
+func (n *lockedNotifier) Notify(domain, provider string, message string, err error, preview bool) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.Notifier.Notify(domain, provider, message, err, preview)
+}
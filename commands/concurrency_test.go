@@ -0,0 +1,70 @@
+package commands
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/DNSControl/dnscontrol/v4/models"
+	"github.com/DNSControl/dnscontrol/v4/pkg/printer"
+)
+
+func TestCredLimiter(t *testing.T) {
+	limiter, err := newCredLimiter(map[string]map[string]string{
+		"r53":   {"TYPE": "ROUTE53", "max_concurrency": "2"},
+		"cloud": {"TYPE": "CLOUDFLAREAPI"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// At most 2 zones use "r53" at the same time; "cloud" is not limited.
+	for name, want := range map[string]int32{"r53": 2, "cloud": 10} {
+		var running, peak atomic.Int32
+		var wg sync.WaitGroup
+		for range 10 {
+			wg.Go(func() {
+				limiter.do(name, func() {
+					n := running.Add(1)
+					for {
+						p := peak.Load()
+						if n <= p || peak.CompareAndSwap(p, n) {
+							break
+						}
+					}
+					time.Sleep(20 * time.Millisecond)
+					running.Add(-1)
+				})
+			})
+		}
+		wg.Wait()
+		if got := peak.Load(); got > want || (name == "r53" && got != want) {
+			t.Errorf("%s: %d zones ran at the same time, want at most %d", name, got, want)
+		}
+	}
+
+	for _, bad := range []string{"0", "-1", "four"} {
+		if _, err := newCredLimiter(map[string]map[string]string{"r53": {"max_concurrency": bad}}); err == nil {
+			t.Errorf("max_concurrency %q: expected an error", bad)
+		}
+	}
+}
+
+func TestZoneOutput(t *testing.T) {
+	var stdout strings.Builder
+	out := &printer.ConsolePrinter{Writer: &stdout}
+	zo, ok := newZoneOutput(out)
+	if !ok {
+		t.Fatal("a console printer can be buffered")
+	}
+	zo.StartDomain(&models.DomainConfig{DisplayName: "example.com"})
+	zo.Printf("#1: CREATE A www\n")
+	if stdout.Len() != 0 {
+		t.Errorf("the zone output was printed before the zone was done: %q", stdout.String())
+	}
+	if want := "******************** Domain: example.com\n#1: CREATE A www\n"; zo.buf.String() != want {
+		t.Errorf("got %q, want %q", zo.buf.String(), want)
+	}
+}
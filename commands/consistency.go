@@ -0,0 +1,104 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/DNSControl/dnscontrol/v4/models"
+	"github.com/DNSControl/dnscontrol/v4/pkg/normalize"
+	"github.com/DNSControl/dnscontrol/v4/pkg/printer"
+	"github.com/DNSControl/dnscontrol/v4/pkg/rtypecontrol"
+	"github.com/DNSControl/dnscontrol/v4/pkg/views"
+)
+
+// checkConsistency compares the records that the DNS providers of each zone
+// with more than one would serve after a push (preview/push --consistency).
+// It prints the differences and reports whether there are any.
+func checkConsistency(out printer.CLI, zones []*models.DomainConfig) (bool, error) {
+	inconsistent := false
+	for _, zone := range zones {
+		if len(zone.DNSProviderInstances) < 2 {
+			continue
+		}
+		sets := make([]models.Records, len(zone.DNSProviderInstances))
+		for i, p := range zone.DNSProviderInstances {
+			recs, err := normalize.EffectiveRecords(zone, p)
+			if err != nil {
+				return inconsistent, fmt.Errorf("%s: %s: %w", zone.UniqueName, p.Name, err)
+			}
+			sets[i] = comparableRecords(recs)
+		}
+		if printProviderDiffs(out, zone, "would serve different records", sets) {
+			inconsistent = true
+		}
+	}
+	return inconsistent, nil
+}
+
+// verifyConsistency compares the records that the DNS providers of each
+// zone with more than one serve (push --verify-consistency). It prints the
+// differences and reports whether there are any.
+func verifyConsistency(out printer.CLI, zones []*models.DomainConfig) (bool, error) {
+	inconsistent := false
+	for _, zone := range zones {
+		if len(zone.DNSProviderInstances) < 2 {
+			continue
+		}
+		sets := make([]models.Records, len(zone.DNSProviderInstances))
+		for i, p := range zone.DNSProviderInstances {
+			recs, err := p.Driver.GetZoneRecords(zone)
+			if err != nil {
+				return inconsistent, fmt.Errorf("%s: %s: %w", zone.UniqueName, p.Name, err)
+			}
+			rtypecontrol.FixLegacyRecords(&recs)
+			models.Downcase(recs)
+			models.CanonicalizeTargets(recs, zone.Name)
+			sets[i] = comparableRecords(recs)
+		}
+		if printProviderDiffs(out, zone, "serve different records", sets) {
+			inconsistent = true
+		}
+	}
+	return inconsistent, nil
+}
+
+// comparableRecords returns the records without the SOA and apex NS
+// records, which legitimately differ between providers.
+func comparableRecords(recs models.Records) models.Records {
+	var result models.Records
+	for _, r := range recs {
+		if r.Type == "SOA" || (r.Type == "NS" && r.GetLabel() == "@") {
+			continue
+		}
+		result = append(result, r)
+	}
+	return result
+}
+
+// printProviderDiffs prints the labels whose records differ between the DNS
+// providers of a zone, if any, and reports whether there are any.
+func printProviderDiffs(out printer.CLI, zone *models.DomainConfig, what string, sets []models.Records) bool {
+	diffs := views.CompareRecords(sets, nil)
+	if len(diffs) == 0 {
+		return false
+	}
+	names := make([]string, len(zone.DNSProviderInstances))
+	width := 0
+	for i, p := range zone.DNSProviderInstances {
+		names[i] = p.Name
+		width = max(width, len(names[i]))
+	}
+	out.Warnf("The DNS providers of %s (%s) %s:\n", zone.UniqueName, strings.Join(names, ", "), what)
+	for _, d := range diffs {
+		out.Printf("  %s\n", d.Label)
+		for i, recs := range d.Records {
+			if len(recs) == 0 {
+				out.Printf("    %-*s  (none)\n", width, names[i])
+			}
+			for _, r := range recs {
+				out.Printf("    %-*s  %s\n", width, names[i], r)
+			}
+		}
+	}
+	return true
+}
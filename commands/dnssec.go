@@ -0,0 +1,353 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/DNSControl/dnscontrol/v4/models"
+	"github.com/DNSControl/dnscontrol/v4/pkg/credsfile"
+	"github.com/DNSControl/dnscontrol/v4/pkg/dnssec"
+	"github.com/DNSControl/dnscontrol/v4/pkg/domaintags"
+	"github.com/DNSControl/dnscontrol/v4/pkg/providers"
+	"github.com/DNSControl/dnscontrol/v4/pkg/rtypecontrol"
+	dnsv1 "github.com/miekg/dns"
+	"github.com/urfave/cli/v3"
+)
+
+var _ = cmd(catUtils, func() *cli.Command {
+	sub := func(name, usage, roles, description string, run func(DNSSECArgs) error) *cli.Command {
+		var args DNSSECArgs
+		return &cli.Command{
+			Name:  name,
+			Usage: usage,
+			Action: func(ctx context.Context, c *cli.Command) error {
+				if c.NArg() != 2 {
+					return cli.Exit("Arguments should be: credkey zone (Ex: my_bind example.com)", 1)
+				}
+				args.CredName = c.Args().Get(0)
+				args.Zone = c.Args().Get(1)
+				return exit(run(args))
+			},
+			Flags:       args.flags(roles),
+			UsageText:   "dnscontrol dnssec " + name + " [command options] credkey zone",
+			Description: description,
+		}
+	}
+	return &cli.Command{
+		Name:  "dnssec",
+		Usage: "Manages the DNSSEC keys of a zone (stand-alone)",
+		Description: `Manage the DNSSEC keys of zones that are signed by the provider.
+
+ARGUMENTS:
+   credkey:  The name used in creds.json
+   zone:     The zone (domain)
+
+EXAMPLES:
+   dnscontrol dnssec keygen my_bind example.com
+   dnscontrol dnssec status my_bind example.com
+   dnscontrol dnssec rollover --role=zsk my_bind example.com
+   dnscontrol dnssec rollover --role=ksk --ds-at-parent my_bind example.com
+
+Documentation: https://docs.dnscontrol.org/commands/dnssec`,
+		Commands: []*cli.Command{
+			sub("keygen", "Creates the first keys of a zone", "ksk,zsk",
+				`Create keys that sign the zone immediately. This fails if the zone
+already has an active key with the same role; use rollover to replace it.`,
+				DNSSECKeygen),
+			sub("rollover", "Runs the next step of a key rollover", "zsk",
+				`Run the next step of a key rollover: create and pre-publish a new key,
+activate it and retire the old one, then remove the old one. Each run does
+at most one step, once the TTLs of the zone allow it, and tells when the
+next step is due.`,
+				DNSSECRollover),
+			sub("status", "Shows the keys of a zone and the next rollover steps", "ksk,zsk,csk",
+				`Show the keys of a zone, their state, the next rollover step for each
+role and the DS records to publish at the parent.`,
+				DNSSECStatus),
+		},
+	}
+}())
+
+// DNSSECArgs args required for the dnssec subcommands.
+type DNSSECArgs struct {
+	GetCredentialsArgs        // Args related to creds.json
+	CredName           string // key in creds.json
+	Zone               string // The zone whose keys are managed
+	Roles              string // Comma-separated key roles (ksk, zsk, csk)
+	Algorithm          string // Algorithm of new keys
+	DSAtParent         bool   // The DS of the new KSK/CSK is at the parent
+}
+
+func (args *DNSSECArgs) flags(roles string) []cli.Flag {
+	flags := args.GetCredentialsArgs.flags()
+	flags = append(flags, &cli.StringFlag{
+		Name:        "role",
+		Destination: &args.Roles,
+		Value:       roles,
+		Usage:       `Key roles, comma separated: ksk, zsk, csk`,
+	})
+	flags = append(flags, &cli.StringFlag{
+		Name:        "algorithm",
+		Destination: &args.Algorithm,
+		Usage:       `Algorithm of new keys, by name or number (default: that of the current key, or ECDSAP256SHA256)`,
+	})
+	flags = append(flags, &cli.BoolFlag{
+		Name:        "ds-at-parent",
+		Destination: &args.DSAtParent,
+		Usage:       `rollover: the DS record of the new KSK or CSK has been published at the parent`,
+	})
+	return flags
+}
+
+// DNSSECKeygen contains all data/flags needed to run dnssec keygen, independently of CLI.
+func DNSSECKeygen(args DNSSECArgs) error {
+	provider, err := dnssecProvider(args)
+	if err != nil {
+		return err
+	}
+	mgr, ok := provider.(providers.DNSSECKeyManager)
+	if !ok {
+		return fmt.Errorf("%s manages the DNSSEC keys itself; only \"dnssec status\" is supported", args.CredName)
+	}
+	roles, err := parseRoles(args.Roles)
+	if err != nil {
+		return err
+	}
+	return dnssecKeygen(mgr, args.Zone, roles, args.Algorithm, time.Now().UTC(), os.Stdout)
+}
+
+// DNSSECRollover contains all data/flags needed to run dnssec rollover, independently of CLI.
+func DNSSECRollover(args DNSSECArgs) error {
+	provider, err := dnssecProvider(args)
+	if err != nil {
+		return err
+	}
+	mgr, ok := provider.(providers.DNSSECKeyManager)
+	if !ok {
+		return fmt.Errorf("%s manages the DNSSEC keys itself; only \"dnssec status\" is supported", args.CredName)
+	}
+	roles, err := parseRoles(args.Roles)
+	if err != nil {
+		return err
+	}
+	timing, err := zoneTiming(provider, args.Zone)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, role := range roles {
+		errs = append(errs, dnssecRollover(mgr, args.Zone, role, args.Algorithm, timing, time.Now().UTC(), args.DSAtParent, os.Stdout))
+	}
+	return errors.Join(errs...)
+}
+
+// DNSSECStatus contains all data/flags needed to run dnssec status, independently of CLI.
+func DNSSECStatus(args DNSSECArgs) error {
+	provider, err := dnssecProvider(args)
+	if err != nil {
+		return err
+	}
+	lister, ok := provider.(providers.DNSSECKeyLister)
+	if !ok {
+		return fmt.Errorf("%s does not support DNSSEC key management", args.CredName)
+	}
+	roles, err := parseRoles(args.Roles)
+	if err != nil {
+		return err
+	}
+	timing, err := zoneTiming(provider, args.Zone)
+	if err != nil {
+		return err
+	}
+	return dnssecStatus(lister, args.Zone, roles, timing, time.Now().UTC(), os.Stdout)
+}
+
+func dnssecProvider(args DNSSECArgs) (models.DNSProvider, error) {
+	providerConfigs, err := credsfile.LoadProviderConfigs(args.CredsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed LoadProviderConfigs(%q): %w", args.CredsFile, err)
+	}
+	return providers.CreateDNSProvider("", providerConfigs[args.CredName], nil)
+}
+
+// zoneTiming derives the rollover timing from the records of the zone.
+func zoneTiming(provider models.DNSProvider, zone string) (dnssec.Timing, error) {
+	ff := domaintags.MakeDomainNameVarieties(zone)
+	recs, err := provider.GetZoneRecords(&models.DomainConfig{
+		Name: ff.NameASCII,
+		Metadata: map[string]string{
+			models.DomainUniqueName:  ff.UniqueName,
+			models.DomainNameRaw:     ff.NameRaw,
+			models.DomainNameUnicode: ff.NameUnicode,
+		},
+	})
+	if err != nil {
+		return dnssec.Timing{}, err
+	}
+	rtypecontrol.FixLegacyRecords(&recs)
+	return dnssec.TimingFromRecords(recs), nil
+}
+
+func parseRoles(s string) ([]dnssec.Role, error) {
+	var roles []dnssec.Role
+	for r := range strings.SplitSeq(s, ",") {
+		role, err := dnssec.ParseRole(strings.TrimSpace(r))
+		if err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+	return roles, nil
+}
+
+// newKeyAlgorithm returns the algorithm of a new key: the one requested, or
+// else that of the current keys.
+func newKeyAlgorithm(requested string, keys []dnssec.Key) (uint8, error) {
+	if requested != "" {
+		return dnssec.ParseAlgorithm(requested)
+	}
+	for _, k := range keys {
+		if k.State(time.Now()) == dnssec.StateActive {
+			return k.Algorithm, nil
+		}
+	}
+	return dnsv1.ECDSAP256SHA256, nil
+}
+
+func dnssecKeygen(mgr providers.DNSSECKeyManager, zone string, roles []dnssec.Role, algorithm string, now time.Time, out io.Writer) error {
+	keys, err := mgr.DNSSECKeys(zone)
+	if err != nil {
+		return err
+	}
+	alg, err := newKeyAlgorithm(algorithm, keys)
+	if err != nil {
+		return err
+	}
+	for _, role := range roles {
+		for _, k := range keys {
+			if k.Role == role && k.State(now) == dnssec.StateActive {
+				return fmt.Errorf("%s already has an active %s (%d); use \"dnscontrol dnssec rollover\" to replace it", zone, role, k.KeyTag)
+			}
+		}
+	}
+	for _, role := range roles {
+		k, err := mgr.DNSSECCreateKey(zone, dnssec.Key{Role: role, Algorithm: alg, Published: now, Activated: now})
+		if err != nil {
+			return fmt.Errorf("creating %s: %w", role, err)
+		}
+		fmt.Fprintf(out, "Created %s %d (algorithm %d)\n", role, k.KeyTag, k.Algorithm)
+		printDS(out, zone, k)
+	}
+	return nil
+}
+
+func dnssecRollover(mgr providers.DNSSECKeyManager, zone string, role dnssec.Role, algorithm string, timing dnssec.Timing, now time.Time, dsAtParent bool, out io.Writer) error {
+	keys, err := mgr.DNSSECKeys(zone)
+	if err != nil {
+		return err
+	}
+	step := dnssec.NextStep(keys, role, timing, now, dsAtParent)
+	switch step.Action {
+	case dnssec.ActionNone:
+		fmt.Fprintf(out, "%s: nothing to do: %s\n", role, step.Reason)
+		return nil
+
+	case dnssec.ActionCreate:
+		alg, err := newKeyAlgorithm(algorithm, keys)
+		if err != nil {
+			return err
+		}
+		k, err := mgr.DNSSECCreateKey(zone, dnssec.Key{Role: role, Algorithm: alg, Published: now})
+		if err != nil {
+			return fmt.Errorf("creating %s: %w", role, err)
+		}
+		fmt.Fprintf(out, "%s: %s: published %s %d\n", role, step.Reason, role, k.KeyTag)
+		printDS(out, zone, k)
+
+	case dnssec.ActionActivate:
+		k := step.Key
+		k.Activated = now
+		if err := mgr.DNSSECUpdateKey(zone, k); err != nil {
+			return err
+		}
+		for _, old := range step.Retire {
+			old.Retired = now
+			if err := mgr.DNSSECUpdateKey(zone, old); err != nil {
+				return err
+			}
+		}
+		fmt.Fprintf(out, "%s: %s: activated %s %d\n", role, step.Reason, role, k.KeyTag)
+
+	case dnssec.ActionRemove:
+		k := step.Key
+		k.Removed = now
+		if err := mgr.DNSSECUpdateKey(zone, k); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "%s: %s: removed %s %d\n", role, step.Reason, role, k.KeyTag)
+	}
+
+	keys, err = mgr.DNSSECKeys(zone)
+	if err != nil {
+		return err
+	}
+	if next := dnssec.NextStep(keys, role, timing, now, false); next.Action == dnssec.ActionNone {
+		fmt.Fprintf(out, "%s: next: %s\n", role, next.Reason)
+	}
+	return nil
+}
+
+func dnssecStatus(lister providers.DNSSECKeyLister, zone string, roles []dnssec.Role, timing dnssec.Timing, now time.Time, out io.Writer) error {
+	keys, err := lister.DNSSECKeys(zone)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "%-4s %6s %4s %-12s %-20s %-20s %-20s %s\n", "ROLE", "TAG", "ALG", "STATE", "PUBLISHED", "ACTIVATED", "RETIRED", "REMOVED")
+	for _, k := range keys {
+		fmt.Fprintf(out, "%-4s %6d %4d %-12s %-20s %-20s %-20s %s\n", k.Role, k.KeyTag, k.Algorithm, k.State(now),
+			formatKeyTime(k.Published), formatKeyTime(k.Activated), formatKeyTime(k.Retired), formatKeyTime(k.Removed))
+	}
+	fmt.Fprintf(out, "\nTiming: DNSKEY TTL %s, max TTL %s, propagation %s\n", timing.DNSKEYTTL, timing.MaxTTL, timing.Propagation)
+
+	for _, role := range roles {
+		if !slices.ContainsFunc(keys, func(k dnssec.Key) bool { return k.Role == role }) {
+			continue
+		}
+		step := dnssec.NextStep(keys, role, timing, now, false)
+		fmt.Fprintf(out, "%s: %s\n", role, step.Reason)
+	}
+	for _, k := range keys {
+		if k.Role != dnssec.ZSK {
+			if st := k.State(now); st == dnssec.StatePrePublish || st == dnssec.StateActive {
+				printDS(out, zone, k)
+			}
+		}
+	}
+	return nil
+}
+
+// printDS prints the DS records to publish at the parent for a KSK or CSK.
+func printDS(out io.Writer, zone string, k dnssec.Key) {
+	if k.Role == dnssec.ZSK {
+		return
+	}
+	for _, ds := range k.DSRecords(zone) {
+		fmt.Fprintf(out, "  DS for the parent zone: %s. IN DS %s\n", strings.TrimSuffix(zone, "."), ds)
+	}
+}
+
+func formatKeyTime(t time.Time) string {
+	switch {
+	case t.IsZero():
+		return "-"
+	case t.Equal(dnssec.Unknown):
+		return "unknown"
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/DNSControl/dnscontrol/v4/models"
@@ -285,6 +286,16 @@ func GetZone(args GetZoneArgs) error {
 				o = append(o, fmt.Sprintf("DefaultTTL(%d)", defaultTTL))
 			}
 
+			if exporter, ok := provider.(providers.ZoneSettingsExporter); ok {
+				// The records are still useful without the settings, for
+				// example if the API key can't read them.
+				if settings, err := exporter.ExportZoneSettings(zoneName); err != nil {
+					fmt.Fprintf(os.Stderr, "WARNING: can't get the zone settings of %s: %v\n", zoneName, err)
+				} else {
+					o = append(o, settings...)
+				}
+			}
+
 			// Check if any records have comments or tags, and add management flags if so
 			hasComments := false
 			hasTags := false
@@ -361,6 +372,17 @@ func GetZone(args GetZoneArgs) error {
 						providerMeta += ",hedns_ddns_key=" + key
 					}
 				}
+				// Route 53 routing metadata
+				var r53keys []string
+				for k := range rec.Metadata {
+					if strings.HasPrefix(k, "r53_") {
+						r53keys = append(r53keys, k)
+					}
+				}
+				slices.Sort(r53keys)
+				for _, k := range r53keys {
+					providerMeta += "," + k + "=" + rec.Metadata[k]
+				}
 				if providerMeta != "" {
 					providerMeta = "\t" + providerMeta[1:] // Remove leading comma, add tab
 				}
@@ -461,9 +483,14 @@ func formatDsl(rec *models.RecordConfig, defaultTTL uint32) string {
 		}
 	}
 
+	r53routing := ""
+	if r := makeR53routing(rec); len(r) > 0 {
+		r53routing = ", " + strings.Join(r, ", ")
+	}
+
 	switch rec.Type { // #rtype_variations
 	case "CAA":
-		return makeCaa(rec, ttlop)
+		return makeCaa(rec, r53routing, ttlop)
 	case "DS":
 		target = fmt.Sprintf(`%d, %d, %d, "%s"`, rec.DsKeyTag, rec.DsAlgorithm, rec.DsDigestType, rec.DsDigest)
 	case "DNSKEY":
@@ -527,23 +554,42 @@ func formatDsl(rec *models.RecordConfig, defaultTTL uint32) string {
 		}
 	case "R53_ALIAS":
 		return makeR53alias(rec, ttl)
+	case "CF_LOAD_BALANCER":
+		return makeCfLoadBalancer(rec, cfproxy, ttlop)
 	case "UNKNOWN":
 		return makeUknown(rec, ttl)
 	default:
 		target = `"` + target + `"`
 	}
 
-	return fmt.Sprintf(`%s("%s", %s%s%s%s%s%s%s%s)`, rec.Type, rec.Name, target, cfproxy, cfflatten, cfcomment, cftags, mtmeta, hednsDynamic, ttlop)
+	return fmt.Sprintf(`%s("%s", %s%s%s%s%s%s%s%s%s)`, rec.Type, rec.Name, target, cfproxy, cfflatten, cfcomment, cftags, mtmeta, hednsDynamic, r53routing, ttlop)
+}
+
+// makeCfLoadBalancer generates the CF_LOAD_BALANCER() for a load balancer.
+// The pools are output as JSON, which is also valid JavaScript.
+func makeCfLoadBalancer(rec *models.RecordConfig, cfproxy, ttlop string) string {
+	b, err := json.Marshal(rec.F)
+	if err != nil {
+		panic(err)
+	}
+	var lb struct {
+		Steering string          `json:"steering"`
+		Pools    json.RawMessage `json:"pools"`
+	}
+	if err := json.Unmarshal(b, &lb); err != nil {
+		panic(err)
+	}
+	return fmt.Sprintf(`CF_LOAD_BALANCER("%s", %s, "%s"%s%s)`, rec.Name, lb.Pools, lb.Steering, cfproxy, ttlop)
 }
 
-func makeCaa(rec *models.RecordConfig, ttlop string) string {
+func makeCaa(rec *models.RecordConfig, r53routing, ttlop string) string {
 	var target string
 	if rec.CaaFlag == 128 {
 		target = fmt.Sprintf(`"%s", "%s", CAA_CRITICAL`, rec.CaaTag, rec.GetTargetField())
 	} else {
 		target = fmt.Sprintf(`"%s", "%s"`, rec.CaaTag, rec.GetTargetField())
 	}
-	return fmt.Sprintf(`%s("%s", %s%s)`, rec.Type, rec.Name, target, ttlop)
+	return fmt.Sprintf(`%s("%s", %s%s%s)`, rec.Type, rec.Name, target, r53routing, ttlop)
 
 	// TODO(tlim): Generate a CAA_BUILDER() instead?
 }
@@ -560,12 +606,61 @@ func makeR53alias(rec *models.RecordConfig, ttl uint32) string {
 	if e, ok := rec.R53Alias["evaluate_target_health"]; ok && e == "true" {
 		items = append(items, "R53_EVALUATE_TARGET_HEALTH(true)")
 	}
+	items = append(items, makeR53routing(rec)...)
 	if ttl != 0 {
 		items = append(items, fmt.Sprintf("TTL(%d)", ttl))
 	}
 	return rec.Type + "(" + strings.Join(items, ", ") + ")"
 }
 
+// makeR53routing returns the modifiers for the Route 53 routing policy and
+// health check of rec.
+func makeR53routing(rec *models.RecordConfig) []string {
+	m := rec.Metadata
+	sid := m["r53_set_identifier"]
+	if sid == "" {
+		return nil
+	}
+	qsid := jsonQuoted(sid)
+
+	var items []string
+	switch {
+	case m["r53_weight"] != "":
+		items = append(items, fmt.Sprintf("R53_WEIGHT(%s, %s)", m["r53_weight"], qsid))
+	case m["r53_region"] != "":
+		items = append(items, fmt.Sprintf("R53_LATENCY(%s, %s)", jsonQuoted(m["r53_region"]), qsid))
+	case m["r53_geo_continent"] != "":
+		items = append(items, fmt.Sprintf("R53_GEO({continent: %s}, %s)", jsonQuoted(m["r53_geo_continent"]), qsid))
+	case m["r53_geo_country"] != "":
+		loc := "country: " + jsonQuoted(m["r53_geo_country"])
+		if sub := m["r53_geo_subdivision"]; sub != "" {
+			loc += ", subdivision: " + jsonQuoted(sub)
+		}
+		items = append(items, fmt.Sprintf("R53_GEO({%s}, %s)", loc, qsid))
+	case m["r53_geoproximity_region"] != "" || m["r53_geoproximity_local_zone_group"] != "" || m["r53_geoproximity_coordinates"] != "":
+		loc := jsonQuoted(m["r53_geoproximity_region"])
+		if g := m["r53_geoproximity_local_zone_group"]; g != "" {
+			loc = "{local_zone_group: " + jsonQuoted(g) + "}"
+		} else if c := m["r53_geoproximity_coordinates"]; c != "" {
+			lat, long, _ := strings.Cut(c, ",")
+			loc = "{latitude: " + jsonQuoted(lat) + ", longitude: " + jsonQuoted(long) + "}"
+		}
+		bias := m["r53_geoproximity_bias"]
+		if bias == "" {
+			bias = "0"
+		}
+		items = append(items, fmt.Sprintf("R53_GEOPROXIMITY(%s, %s, %s)", loc, bias, qsid))
+	case m["r53_failover"] != "":
+		items = append(items, fmt.Sprintf("R53_FAILOVER(%s, %s)", jsonQuoted(m["r53_failover"]), qsid))
+	case m["r53_multivalue"] == "true":
+		items = append(items, fmt.Sprintf("R53_MULTIVALUE(%s)", qsid))
+	}
+	if hc := m["r53_health_check_id"]; hc != "" {
+		items = append(items, fmt.Sprintf("R53_HEALTH_CHECK_ID(%s)", jsonQuoted(hc)))
+	}
+	return items
+}
+
 func makeUknown(rc *models.RecordConfig, ttl uint32) string {
 	return fmt.Sprintf(`// %s("%s", TTL(%d))`, rc.UnknownTypeName, rc.GetTargetField(), ttl)
 }
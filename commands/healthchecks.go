@@ -0,0 +1,48 @@
+package commands
+
+import (
+	"github.com/DNSControl/dnscontrol/v4/models"
+	"github.com/DNSControl/dnscontrol/v4/pkg/normalize"
+	"github.com/DNSControl/dnscontrol/v4/pkg/providers"
+)
+
+// healthCheckPlan holds the health check corrections of one DNS provider.
+// The deletions are separate because records may use the health checks
+// until the zones are updated.
+type healthCheckPlan struct {
+	provider    string
+	corrections []*models.Correction
+	deletions   []*models.Correction
+}
+
+// gatherHealthChecks asks each DNS provider of zones that manages health
+// checks, and is not skipped by filter, for the corrections that turn its
+// health checks into the HEALTH_CHECK()s that the records using it refer to.
+// Each provider is asked once, with the health checks of all zones in cfg,
+// so that a check used by a zone that is not processed is not deleted.
+func gatherHealthChecks(cfg *models.DNSConfig, zones []*models.DomainConfig, filter string) ([]healthCheckPlan, error) {
+	var plans []healthCheckPlan
+	seen := map[string]bool{}
+	for _, zone := range zones {
+		providersToProcess := whichProvidersToProcess(zone.DNSProviderInstances, filter)
+		for _, provider := range zone.DNSProviderInstances {
+			if seen[provider.Name] || skipProvider(provider.Name, providersToProcess) {
+				continue
+			}
+			seen[provider.Name] = true
+			manager, ok := provider.Driver.(providers.HealthCheckManager)
+			if !ok {
+				continue
+			}
+			corrections, deletions, err := manager.GetHealthCheckCorrections(normalize.HealthChecksFor(cfg, provider.Name))
+			if err != nil {
+				return nil, err
+			}
+			if len(corrections) == 0 && len(deletions) == 0 {
+				continue
+			}
+			plans = append(plans, healthCheckPlan{provider: provider.Name, corrections: corrections, deletions: deletions})
+		}
+	}
+	return plans, nil
+}
@@ -0,0 +1,195 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/netip"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/DNSControl/dnscontrol/v4/models"
+	"github.com/DNSControl/dnscontrol/v4/pkg/catalogzone"
+	"github.com/DNSControl/dnscontrol/v4/pkg/normalize"
+	"github.com/DNSControl/dnscontrol/v4/pkg/pdnslua"
+	"github.com/urfave/cli/v3"
+)
+
+var _ = cmd(catUtils, func() *cli.Command {
+	var args LuaEvalArgs
+	return &cli.Command{
+		Name:  "lua-eval",
+		Usage: "Evaluates the LUA() records of a name for a simulated client",
+		Action: func(ctx context.Context, c *cli.Command) error {
+			if c.NArg() < 2 || c.NArg() > 3 {
+				return cli.Exit("Arguments should be: zone name [rtype] (Ex: example.com www A)", 1)
+			}
+			args.Zone = c.Args().Get(0)
+			args.Name = c.Args().Get(1)
+			args.RType = c.Args().Get(2)
+			return exit(LuaEval(args))
+		},
+		Flags:     args.flags(),
+		UsageText: "dnscontrol lua-eval [command options] zone name [rtype]",
+		Description: `Run the LUA() records of a name the way PowerDNS does, for a simulated
+client, and print the records PowerDNS would answer with. PowerDNS is not
+contacted: the health checks of ifportup() and ifurlup() are stubbed with
+--up and --down, and the location of the client is given with flags.
+
+ARGUMENTS:
+   zone:     The zone (domain), as in D()
+   name:     The label of the records ("@" for the apex), or an FQDN
+   rtype:    Only evaluate the LUA() records that emit this type
+
+EXAMPLES:
+   dnscontrol lua-eval example.com www
+   dnscontrol lua-eval --client-ip=198.51.100.7 --country=NL --continent=EU example.com www A
+   dnscontrol lua-eval --down=192.0.2.1 example.com www
+
+Documentation: https://docs.dnscontrol.org/commands/lua-eval`,
+	}
+}())
+
+// LuaEvalArgs args required for the lua-eval subcommand.
+type LuaEvalArgs struct {
+	GetDNSConfigArgs
+	Zone      string   // The zone of the records
+	Name      string   // The label (or FQDN) of the records
+	RType     string   // Only records that emit this type. Empty means all.
+	ClientIP  string   // The address of the simulated client
+	Up        []string // Only these addresses are up
+	Down      []string // These addresses are down
+	Country   string   // The country of the client
+	Continent string   // The continent of the client
+	Region    string   // The region of the client
+	ASN       int      // The AS number of the client
+}
+
+func (args *LuaEvalArgs) flags() []cli.Flag {
+	flags := args.GetDNSConfigArgs.flags()
+	flags = append(flags, &cli.StringFlag{
+		Name:        "client-ip",
+		Destination: &args.ClientIP,
+		Value:       "192.0.2.53",
+		Usage:       `Address of the client (resolver) that queries`,
+	})
+	flags = append(flags, &cli.StringSliceFlag{
+		Name:        "up",
+		Destination: &args.Up,
+		Usage:       `Only these addresses pass health checks (default: all addresses)`,
+	})
+	flags = append(flags, &cli.StringSliceFlag{
+		Name:        "down",
+		Destination: &args.Down,
+		Usage:       `These addresses fail health checks`,
+	})
+	flags = append(flags, &cli.StringFlag{
+		Name:        "country",
+		Destination: &args.Country,
+		Usage:       `ISO code of the client's country (ex: NL)`,
+	})
+	flags = append(flags, &cli.StringFlag{
+		Name:        "continent",
+		Destination: &args.Continent,
+		Usage:       `Code of the client's continent (ex: EU)`,
+	})
+	flags = append(flags, &cli.StringFlag{
+		Name:        "region",
+		Destination: &args.Region,
+		Usage:       `Code of the client's region`,
+	})
+	flags = append(flags, &cli.IntFlag{
+		Name:        "asn",
+		Destination: &args.ASN,
+		Usage:       `AS number of the client`,
+	})
+	return flags
+}
+
+// LuaEval contains all data/flags needed to run lua-eval, independently of CLI.
+func LuaEval(args LuaEvalArgs) error {
+	cfg, err := GetDNSConfig(args.GetDNSConfigArgs)
+	if err != nil {
+		return err
+	}
+	if err := catalogzone.Generate(cfg); err != nil {
+		return err
+	}
+	errs := normalize.ValidateAndNormalizeConfig(cfg)
+	if PrintValidationErrors(errs) {
+		return errors.New("exiting due to validation errors")
+	}
+	var dc *models.DomainConfig
+	for _, d := range cfg.Domains {
+		if d.Name == args.Zone || d.GetUniqueName() == args.Zone {
+			dc = d
+		}
+	}
+	if dc == nil {
+		return fmt.Errorf("zone %q is not in dnsconfig.js", args.Zone)
+	}
+	who, err := netip.ParseAddr(args.ClientIP)
+	if err != nil {
+		return fmt.Errorf("--client-ip: %w", err)
+	}
+	env := pdnslua.Env{
+		Who:       who,
+		Country:   args.Country,
+		Continent: args.Continent,
+		Region:    args.Region,
+		ASN:       args.ASN,
+		Up:        luaHealth(args.Up, args.Down),
+	}
+	return luaEval(dc, args.Name, args.RType, env, os.Stdout)
+}
+
+// luaHealth returns the stubbed health check: an address is up if it is in
+// up (or up is empty), and not in down.
+func luaHealth(up, down []string) func(string) bool {
+	return func(addr string) bool {
+		return (len(up) == 0 || slices.Contains(up, addr)) && !slices.Contains(down, addr)
+	}
+}
+
+// luaEval evaluates the LUA() records of name in dc that emit rtype (or
+// any type if it is empty), and writes the records they answer with.
+func luaEval(dc *models.DomainConfig, name, rtype string, env pdnslua.Env, w io.Writer) error {
+	fqdn := strings.ToLower(strings.TrimSuffix(name, "."))
+	if name == "@" {
+		fqdn = dc.Name
+	} else if !strings.HasSuffix(fqdn, "."+dc.Name) && fqdn != dc.Name {
+		fqdn += "." + dc.Name
+	}
+	env.QName = fqdn + "."
+	env.Zone = dc.Name + "."
+
+	var errs []error
+	found := false
+	for _, rc := range dc.Records {
+		if rc.Type != "LUA" || rc.GetLabelFQDN() != fqdn || (rtype != "" && !strings.EqualFold(rc.LuaRType, rtype)) {
+			continue
+		}
+		found = true
+		values, err := pdnslua.Eval(rc.GetTargetField(), env)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: LUA %s %s: %w", rc.FilePos, fqdn, rc.LuaRType, err))
+			continue
+		}
+		fmt.Fprintf(w, "; %s\n", strings.ReplaceAll(rc.GetTargetField(), "\n", "\n; "))
+		if len(values) == 0 {
+			fmt.Fprintf(w, "; (no answer)\n")
+		}
+		for _, v := range values {
+			fmt.Fprintf(w, "%s.\t%d\tIN\t%s\t%s\n", fqdn, rc.TTL, rc.LuaRType, v)
+		}
+	}
+	if !found && rtype != "" {
+		return fmt.Errorf("%s has no LUA() records that emit %s", fqdn, strings.ToUpper(rtype))
+	}
+	if !found {
+		return fmt.Errorf("%s has no LUA() records", fqdn)
+	}
+	return errors.Join(errs...)
+}
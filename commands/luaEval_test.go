@@ -0,0 +1,85 @@
+package commands
+
+import (
+	"bytes"
+	"net/netip"
+	"testing"
+
+	"github.com/DNSControl/dnscontrol/v4/models"
+	"github.com/DNSControl/dnscontrol/v4/pkg/pdnslua"
+)
+
+func TestLuaEval(t *testing.T) {
+	rec := func(label, rtype, snippet string) *models.RecordConfig {
+		rc := &models.RecordConfig{Type: "LUA", LuaRType: rtype, TTL: 60}
+		rc.SetLabel(label, "example.com")
+		rc.MustSetTarget(snippet)
+		return rc
+	}
+	dc := &models.DomainConfig{
+		Name: "example.com",
+		Records: models.Records{
+			rec("www", "A", "ifportup(443, {'192.0.2.1', '192.0.2.2'}, {selector='all'})"),
+			rec("www", "AAAA", "'2001:db8::1'"),
+			rec("@", "TXT", "; return 'from ' .. who:toString()"),
+		},
+	}
+	env := pdnslua.Env{
+		Who: netip.MustParseAddr("198.51.100.7"),
+		Up:  luaHealth(nil, []string{"192.0.2.1"}),
+	}
+
+	tests := []struct {
+		name, label, rtype string
+		want, wantErr      string
+	}{
+		{
+			name:  "health check",
+			label: "www", rtype: "a",
+			want: "; ifportup(443, {'192.0.2.1', '192.0.2.2'}, {selector='all'})\n" +
+				"www.example.com.\t60\tIN\tA\t192.0.2.2\n",
+		},
+		{
+			name:  "all types",
+			label: "www.example.com.",
+			want: "; ifportup(443, {'192.0.2.1', '192.0.2.2'}, {selector='all'})\n" +
+				"www.example.com.\t60\tIN\tA\t192.0.2.2\n" +
+				"; '2001:db8::1'\n" +
+				"www.example.com.\t60\tIN\tAAAA\t2001:db8::1\n",
+		},
+		{
+			name:  "apex",
+			label: "@",
+			want: "; ; return 'from ' .. who:toString()\n" +
+				"example.com.\t60\tIN\tTXT\tfrom 198.51.100.7\n",
+		},
+		{
+			name:    "no records",
+			label:   "mail",
+			wantErr: "mail.example.com has no LUA() records",
+		},
+		{
+			name:  "no records of type",
+			label: "www", rtype: "txt",
+			wantErr: "www.example.com has no LUA() records that emit TXT",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := luaEval(dc, tt.label, tt.rtype, env, &out)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("got error %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", out.String(), tt.want)
+			}
+		})
+	}
+}
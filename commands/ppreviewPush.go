@@ -19,6 +19,7 @@ import (
 
 	"github.com/DNSControl/dnscontrol/v4/models"
 	"github.com/DNSControl/dnscontrol/v4/pkg/bindserial"
+	"github.com/DNSControl/dnscontrol/v4/pkg/catalogzone"
 	"github.com/DNSControl/dnscontrol/v4/pkg/credsfile"
 	"github.com/DNSControl/dnscontrol/v4/pkg/domaintags"
 	"github.com/DNSControl/dnscontrol/v4/pkg/nameservers"
@@ -27,6 +28,7 @@ import (
 	"github.com/DNSControl/dnscontrol/v4/pkg/printer"
 	"github.com/DNSControl/dnscontrol/v4/pkg/providers"
 	"github.com/DNSControl/dnscontrol/v4/pkg/rfc4183"
+	"github.com/DNSControl/dnscontrol/v4/pkg/zonecache"
 	"github.com/DNSControl/dnscontrol/v4/pkg/zonerecs"
 	"github.com/dustin/go-humanize"
 	"github.com/nozzle/throttler"
@@ -50,7 +52,15 @@ var _ = cmd(catMain, func() *cli.Command {
 		Action: func(ctx context.Context, c *cli.Command) error {
 			return exit(PPreview(args))
 		},
-		Flags: args.flags(),
+		Flags: append(args.flags(), &cli.BoolFlag{
+			Name:        "compare-views",
+			Destination: &args.CompareViews,
+			Usage:       `Show the differences between the views (tags) of split horizon zones, instead of the changes`,
+		}, &cli.StringFlag{
+			Name:        "changed-since",
+			Destination: &args.ChangedSince,
+			Usage:       `Only preview the zones whose desired state changed since this git revision`,
+		}),
 	}
 }())
 
@@ -67,6 +77,11 @@ type PPreviewArgs struct {
 	PopulateOnPreview bool
 	Report            string
 	Full              bool
+	CompareViews      bool   // Compare the views of split horizon zones instead of previewing.
+	ChangedSince      string // Only process the zones that changed since this git revision, or "" for all.
+	Consistency       string // What to do if the DNS providers of a zone would serve different records: warn, error, off.
+	VerifyConsistency bool   // After pushing, check that the DNS providers of each zone serve the same records.
+	ZoneCache         string // Directory of the on-disk zone cache, or "" for none.
 }
 
 // ReportItem is a record of corrections for a particular domain/provider/registrar.
@@ -147,6 +162,24 @@ func (args *PPreviewArgs) flags() []cli.Flag {
 		Destination: &bindserial.ForcedValue,
 		Usage:       `Force BIND serial numbers to this value (for reproducibility)`,
 	})
+	flags = append(flags, &cli.StringFlag{
+		Name:        "consistency",
+		Destination: &args.Consistency,
+		Value:       "warn",
+		Usage:       `What to do if the DNS providers of a zone would serve different records: warn, error, off`,
+		Action: func(ctx context.Context, c *cli.Command, s string) error {
+			if !slices.Contains([]string{"warn", "error", "off"}, s) {
+				fmt.Printf("%q is not a valid option for --consistency.  Values are: warn, error, off\n", s)
+				os.Exit(1)
+			}
+			return nil
+		},
+	})
+	flags = append(flags, &cli.StringFlag{
+		Name:        "zone-cache",
+		Destination: &args.ZoneCache,
+		Usage:       `Directory in which to keep the records of zones between runs, for the providers that can tell if a zone changed`,
+	})
 	flags = append(flags, &cli.StringFlag{
 		Name:        "report",
 		Destination: &args.Report,
@@ -163,7 +196,11 @@ var _ = cmd(catMain, func() *cli.Command {
 		Action: func(ctx context.Context, c *cli.Command) error {
 			return exit(PPush(args))
 		},
-		Flags: args.flags(),
+		Flags: append(args.flags(), &cli.BoolFlag{
+			Name:        "verify-consistency",
+			Destination: &args.VerifyConsistency,
+			Usage:       `After pushing, check that the DNS providers of each zone serve the same records`,
+		}),
 	}
 }())
 
@@ -211,12 +248,31 @@ func prun(args PPreviewArgs, push bool, interactive bool, out printer.CLI, repor
 		return err
 	}
 
+	// The IR is compared before it is normalized. diffZones accounts for
+	// the PTR records that AUTOPTR adds during normalization.
+	var changed map[string]bool
+	if args.ChangedSince != "" {
+		if args.JSONFile != "" {
+			return errors.New("--changed-since can not be used with --ir")
+		}
+		out.PrintfIf(fullMode, "Reading dnsconfig.js at %s\n", args.ChangedSince)
+		changed, err = changedZones(cfg, args.ExecuteDSLArgs, args.ChangedSince)
+		if err != nil {
+			printer.Warnf("Previewing all zones: %s\n", err)
+		}
+	}
+
 	out.PrintfIf(fullMode, "Reading creds: %q\n", args.CredsFile)
 	providerConfigs, err := credsfile.LoadProviderConfigs(args.CredsFile)
 	if err != nil {
 		return err
 	}
 
+	limiter, err := newCredLimiter(providerConfigs)
+	if err != nil {
+		return err
+	}
+
 	var notify = args.Notify
 
 	// We want to notify if args.Notify OR notify_on_*
@@ -245,6 +301,10 @@ func prun(args PPreviewArgs, push bool, interactive bool, out printer.CLI, repor
 		return err
 	}
 
+	if err := catalogzone.Generate(cfg); err != nil {
+		return err
+	}
+
 	out.PrintfIf(fullMode, "Normalizing and validating 'desired'..\n")
 	errs := normalize.ValidateAndNormalizeConfig(cfg)
 	if PrintValidationErrors(errs) {
@@ -255,6 +315,29 @@ func prun(args PPreviewArgs, push bool, interactive bool, out printer.CLI, repor
 
 	// Loop over all (or some) zones:
 	zonesToProcess := whichZonesToProcess(cfg.Domains, args.Domains)
+	if changed != nil {
+		n := len(zonesToProcess)
+		zonesToProcess = onlyChangedZones(zonesToProcess, changed)
+		out.Printf("%d of %d zone(s) changed since %s\n", len(zonesToProcess), n, args.ChangedSince)
+	}
+	if args.ZoneCache != "" {
+		for _, name := range zoneCacheUnsupported(zonesToProcess) {
+			printer.Warnf("--zone-cache has no effect for %s: the provider can't tell whether a zone changed, so its zones are always downloaded\n", name)
+		}
+	}
+	if args.CompareViews {
+		printViewDiffs(out, zonesToProcess)
+		return nil
+	}
+	if args.Consistency != "off" {
+		inconsistent, err := checkConsistency(out, zonesToProcess)
+		if err != nil {
+			return err
+		}
+		if inconsistent && args.Consistency == "error" {
+			return errors.New("exiting due to inconsistent DNS providers")
+		}
+	}
 	zonesSerial, zonesConcurrent := splitConcurrent(zonesToProcess, args.ConcurMode)
 	zonesConcurrent = optimizeOrder(zonesConcurrent)
 
@@ -262,6 +345,7 @@ func prun(args PPreviewArgs, push bool, interactive bool, out printer.CLI, repor
 	var reportItems []*ReportItem
 	var anyErrors bool
 	var concurrentErrors atomic.Bool
+	failed := &failedZones{}
 
 	// Populate the zones (if desired/needed/able):
 	if !args.NoPopulate {
@@ -333,6 +417,23 @@ func prun(args PPreviewArgs, push bool, interactive bool, out printer.CLI, repor
 		}
 	}
 
+	// Health checks are created before the zones are gathered, so that
+	// the records can refer to them by ID.
+	healthCheckPlans, err := gatherHealthChecks(cfg, zonesToProcess, args.Providers)
+	if err != nil {
+		return err
+	}
+	if len(healthCheckPlans) > 0 {
+		out.Printf("******************** Health checks\n")
+	}
+	for _, plan := range healthCheckPlans {
+		out.StartDNSProvider(plan.provider, false)
+		totalCorrections += len(plan.corrections)
+		out.EndProvider2(plan.provider, len(plan.corrections))
+		reportItems = append(reportItems, genReportItem("HEALTH_CHECK", plan.corrections, plan.provider, ""))
+		anyErrors = cmp.Or(anyErrors, pprintOrRunCorrections("HEALTH_CHECK", plan.provider, plan.corrections, out, push, interactive, notifier, report))
+	}
+
 	out.PrintfIf(fullMode, "PHASE 2: GATHERING data\n")
 	t := throttler.New(args.ConcurMax, len(zonesConcurrent))
 	out.Printf("CONCURRENTLY gathering records of %d zone(s)\n", len(zonesConcurrent))
@@ -340,9 +441,10 @@ func prun(args PPreviewArgs, push bool, interactive bool, out printer.CLI, repor
 		out.PrintfIf(fullMode, "Concurrently gathering: %q\n", zone.UniqueName)
 		go func(zone *models.DomainConfig, args PPreviewArgs, zcache *CmdZoneCache) {
 			start := time.Now()
-			err := oneZone(zone, args)
+			err := oneZone(zone, args, limiter)
 			if err != nil {
 				concurrentErrors.Store(true)
+				failed.add(zone)
 			}
 			out.Debugf("...DONE: %q (%.1fs)\n", zone.Name, time.Since(start).Seconds())
 			t.Done(err)
@@ -358,8 +460,9 @@ func prun(args PPreviewArgs, push bool, interactive bool, out printer.CLI, repor
 	out.Printf("SERIALLY gathering records of %d zone(s)\n", len(zonesSerial))
 	for _, zone := range zonesSerial {
 		out.Printf("Serially Gathering: %q\n", zone.UniqueName)
-		if err := oneZone(zone, args); err != nil {
+		if err := oneZone(zone, args, limiter); err != nil {
 			anyErrors = true
+			failed.add(zone)
 		}
 	}
 
@@ -380,37 +483,81 @@ func prun(args PPreviewArgs, push bool, interactive bool, out printer.CLI, repor
 
 	// Now we know what to do, print or do the tasks.
 	out.PrintfIf(fullMode, "PHASE 3: CORRECTIONS\n")
-	for _, zone := range zonesToProcess {
-		out.StartDomain(zone)
-
-		// Process DNS provider changes:
-		providersToProcess := whichProvidersToProcess(zone.DNSProviderInstances, args.Providers)
-		for _, provider := range zone.DNSProviderInstances {
-			skip := skipProvider(provider.Name, providersToProcess)
-			out.StartDNSProvider(provider.Name, skip)
-			if !skip {
-				corrections := zone.GetCorrections(provider.Name)
-				numActions := zone.GetChangeCount(provider.Name)
-				totalCorrections += numActions
-				out.EndProvider2(provider.Name, numActions)
-				reportItems = append(reportItems, genReportItem(zone.Name, corrections, provider.Name, ""))
-				anyErrors = cmp.Or(anyErrors, pprintOrRunCorrections(zone.Name, provider.Name, corrections, out, push, interactive, notifier, report))
-			}
+	zonesInOrder := zonesToProcess
+	if _, buffered := newZoneOutput(out); buffered && push && !interactive && len(zonesConcurrent) > 0 {
+		// Push the zones that support it concurrently. The corrections of
+		// a zone still run in order, and its output is printed in one piece
+		// when it is done.
+		var mu sync.Mutex
+		lockedNotify := &lockedNotifier{Notifier: notifier}
+		t := throttler.New(args.ConcurMax, len(zonesConcurrent))
+		out.Printf("CONCURRENTLY pushing %d zone(s)\n", len(zonesConcurrent))
+		for _, zone := range zonesConcurrent {
+			zo, _ := newZoneOutput(out)
+			go func(zone *models.DomainConfig) {
+				n, items, zoneErrors := correctZone(zone, args, zo, push, interactive, lockedNotify, report, limiter)
+				if zoneErrors {
+					failed.add(zone)
+				}
+				mu.Lock()
+				out.Printf("%s", zo.buf.String())
+				totalCorrections += n
+				reportItems = append(reportItems, items...)
+				anyErrors = cmp.Or(anyErrors, zoneErrors)
+				mu.Unlock()
+				t.Done(nil)
+			}(zone)
+			t.Throttle()
 		}
+		zonesInOrder = zonesSerial
+		if len(zonesSerial) > 0 {
+			out.Printf("SERIALLY pushing %d zone(s)\n", len(zonesSerial))
+		}
+	}
+	for _, zone := range zonesInOrder {
+		n, items, zoneErrors := correctZone(zone, args, out, push, interactive, notifier, report, limiter)
+		if zoneErrors {
+			failed.add(zone)
+		}
+		totalCorrections += n
+		reportItems = append(reportItems, items...)
+		anyErrors = cmp.Or(anyErrors, zoneErrors)
+	}
 
-		// Process Registrar changes:
-		skip := skipProvider(zone.RegistrarInstance.Name, providersToProcess)
-		out.StartRegistrar(zone.RegistrarName, !skip)
-		if skip {
-			corrections := zone.GetCorrections(zone.RegistrarInstance.Name)
-			numActions := zone.GetChangeCount(zone.RegistrarInstance.Name)
-			out.EndProvider2(zone.RegistrarName, numActions)
-			totalCorrections += numActions
-			reportItems = append(reportItems, genReportItem(zone.Name, corrections, "", zone.RegistrarName))
-			anyErrors = cmp.Or(anyErrors, pprintOrRunCorrections(zone.Name, zone.RegistrarInstance.Name, corrections, out, push, interactive, notifier, report))
+	// The addresses of ALLOC() are only recorded when they are pushed.
+	if push && cfg.IPAM != nil {
+		if err := cfg.IPAM.Save(ipamPushed(cfg.Domains, zonesToProcess, failed)); err != nil {
+			out.Errorf("Could not save the IPAM lock file: %s\n", err)
+			anyErrors = true
 		}
 	}
 
+	// Health checks are deleted after the zones no longer use them.
+	started := false
+	for _, plan := range healthCheckPlans {
+		if len(plan.deletions) == 0 {
+			continue
+		}
+		if !started {
+			out.Printf("******************** Health checks (deletions)\n")
+			started = true
+		}
+		out.StartDNSProvider(plan.provider, false)
+		totalCorrections += len(plan.deletions)
+		out.EndProvider2(plan.provider, len(plan.deletions))
+		reportItems = append(reportItems, genReportItem("HEALTH_CHECK", plan.deletions, plan.provider, ""))
+		anyErrors = cmp.Or(anyErrors, pprintOrRunCorrections("HEALTH_CHECK", plan.provider, plan.deletions, out, push, interactive, notifier, report))
+	}
+
+	if push && args.VerifyConsistency {
+		out.PrintfIf(fullMode, "PHASE 4: VERIFYING consistency\n")
+		inconsistent, err := verifyConsistency(out, zonesToProcess)
+		if err != nil {
+			out.Errorf("%s\n", err)
+		}
+		anyErrors = cmp.Or(anyErrors, inconsistent, err != nil)
+	}
+
 	if os.Getenv("TEAMCITY_VERSION") != "" {
 		fmt.Fprintf(os.Stderr, "##teamcity[buildStatus status='SUCCESS' text='%d corrections']", totalCorrections)
 	}
@@ -521,12 +668,12 @@ func splitConcurrent(domains []*models.DomainConfig, filter string) (serial []*m
 // allConcur returns true if its registrar and all DNS providers support
 // concurrency.  Otherwise false is returned.
 func allConcur(dc *models.DomainConfig) bool {
-	if !providers.ProviderHasCapability(dc.RegistrarInstance.ProviderType, providers.CanConcur) {
+	if !providers.InstanceHasCapability(dc.RegistrarInstance.ProviderType, dc.RegistrarInstance.Driver, providers.CanConcur) {
 		// fmt.Printf("WHY? %q: %+v\n", dc.Name, dc.RegistrarInstance)
 		return false
 	}
 	for _, p := range dc.DNSProviderInstances {
-		if !providers.ProviderHasCapability(p.ProviderType, providers.CanConcur) {
+		if !providers.InstanceHasCapability(p.ProviderType, p.Driver, providers.CanConcur) {
 			// fmt.Printf("WHY? %q: %+v\n", dc.Name, p)
 			return false
 		}
@@ -567,7 +714,7 @@ func oneZonePopulate(zone *models.DomainConfig, zc *CmdZoneCache) error {
 	return errors.Join(errs...)
 }
 
-func oneZone(zone *models.DomainConfig, args PPreviewArgs) error {
+func oneZone(zone *models.DomainConfig, args PPreviewArgs, limiter *credLimiter) error {
 	var errs []error
 	// Fix the parent zone's delegation: (if able/needed)
 	delegationCorrections, dcCount, err := generateDelegationCorrections(zone, zone.DNSProviderInstances, zone.RegistrarInstance)
@@ -575,17 +722,24 @@ func oneZone(zone *models.DomainConfig, args PPreviewArgs) error {
 		errs = append(errs, err)
 	}
 
+	var cache *zonecache.Disk
+	if args.ZoneCache != "" {
+		cache = zonecache.NewDisk(args.ZoneCache)
+	}
+
 	// Loop over the (selected) providers configured for that zone:
 	providersToProcess := whichProvidersToProcess(zone.DNSProviderInstances, args.Providers)
 	for _, provider := range providersToProcess {
 		// Update the zone's records at the provider:
-		zoneCor, rep, actualChangeCount, err := generateZoneCorrections(zone, provider)
-		zone.StoreCorrections(provider.Name, rep)
-		zone.StoreCorrections(provider.Name, zoneCor)
-		zone.IncrementChangeCount(provider.Name, actualChangeCount)
-		if err != nil {
-			errs = append(errs, err)
-		}
+		limiter.do(provider.Name, func() {
+			zoneCor, rep, actualChangeCount, err := generateZoneCorrections(zone, provider, cache)
+			zone.StoreCorrections(provider.Name, rep)
+			zone.StoreCorrections(provider.Name, zoneCor)
+			zone.IncrementChangeCount(provider.Name, actualChangeCount)
+			if err != nil {
+				errs = append(errs, err)
+			}
+		})
 	}
 
 	// Do the delegation corrections after the zones are updated.
@@ -594,6 +748,45 @@ func oneZone(zone *models.DomainConfig, args PPreviewArgs) error {
 	return errors.Join(errs...)
 }
 
+// correctZone prints the corrections of a zone, and runs them if push is
+// true. It returns the number of corrections, the report items and whether
+// any correction failed.
+func correctZone(zone *models.DomainConfig, args PPreviewArgs, out printer.CLI, push bool, interactive bool, notifier notifications.Notifier, report string, limiter *credLimiter) (totalCorrections int, reportItems []*ReportItem, anyErrors bool) {
+	out.StartDomain(zone)
+
+	// Process DNS provider changes:
+	providersToProcess := whichProvidersToProcess(zone.DNSProviderInstances, args.Providers)
+	for _, provider := range zone.DNSProviderInstances {
+		skip := skipProvider(provider.Name, providersToProcess)
+		out.StartDNSProvider(provider.Name, skip)
+		if !skip {
+			corrections := zone.GetCorrections(provider.Name)
+			numActions := zone.GetChangeCount(provider.Name)
+			totalCorrections += numActions
+			out.EndProvider2(provider.Name, numActions)
+			reportItems = append(reportItems, genReportItem(zone.Name, corrections, provider.Name, ""))
+			limiter.do(provider.Name, func() {
+				anyErrors = cmp.Or(anyErrors, pprintOrRunCorrections(zone.Name, provider.Name, corrections, out, push, interactive, notifier, report))
+			})
+		}
+	}
+
+	// Process Registrar changes:
+	skip := skipProvider(zone.RegistrarInstance.Name, providersToProcess)
+	out.StartRegistrar(zone.RegistrarName, !skip)
+	if skip {
+		corrections := zone.GetCorrections(zone.RegistrarInstance.Name)
+		numActions := zone.GetChangeCount(zone.RegistrarInstance.Name)
+		out.EndProvider2(zone.RegistrarName, numActions)
+		totalCorrections += numActions
+		reportItems = append(reportItems, genReportItem(zone.Name, corrections, "", zone.RegistrarName))
+		limiter.do(zone.RegistrarInstance.Name, func() {
+			anyErrors = cmp.Or(anyErrors, pprintOrRunCorrections(zone.Name, zone.RegistrarInstance.Name, corrections, out, push, interactive, notifier, report))
+		})
+	}
+	return totalCorrections, reportItems, anyErrors
+}
+
 func whichProvidersToProcess(providers []*models.DNSProviderInstance, filter string) []*models.DNSProviderInstance {
 	if filter == "all" { // all
 		return providers
@@ -774,14 +967,108 @@ func generatePopulateCorrections(provider *models.DNSProviderInstance, zone *mod
 	}}, nil
 }
 
-func generateZoneCorrections(zone *models.DomainConfig, provider *models.DNSProviderInstance) ([]*models.Correction, []*models.Correction, int, error) {
-	reports, zoneCorrections, actualChangeCount, err := zonerecs.CorrectZoneRecords(provider.Driver, zone)
+func generateZoneCorrections(zone *models.DomainConfig, provider *models.DNSProviderInstance, cache *zonecache.Disk) ([]*models.Correction, []*models.Correction, int, error) {
+	existing, err := zoneRecords(zone, provider, cache)
+	if err != nil {
+		return []*models.Correction{{Msg: fmt.Sprintf("Domain %q provider %s Error: %s", zone.Name, provider.Name, err)}}, nil, 0, err
+	}
+	reports, zoneCorrections, actualChangeCount, err := zonerecs.CorrectZoneRecordsFrom(provider.Driver, zone, provider.ProviderType, existing)
 	if err != nil {
 		return []*models.Correction{{Msg: fmt.Sprintf("Domain %q provider %s Error: %s", zone.Name, provider.Name, err)}}, nil, 0, err
 	}
 	return zoneCorrections, reports, actualChangeCount, nil
 }
 
+// zoneRecords returns the records of zone at provider. If the provider can
+// tell whether the zone changed, they are read from cache when it did not,
+// and stored in it when it did.
+func zoneRecords(zone *models.DomainConfig, provider *models.DNSProviderInstance, cache *zonecache.Disk) (models.Records, error) {
+	versioner, ok := provider.Driver.(providers.ZoneVersioner)
+	if cache == nil || !ok {
+		return provider.Driver.GetZoneRecords(zone)
+	}
+	version, err := versioner.GetZoneVersion(zone)
+	if err != nil {
+		return nil, err
+	}
+	if recs, ok := cache.Get(provider.Name, zone, version); ok {
+		printer.Debugf("Using the cached records of %q at %s (version %s)\n", zone.UniqueName, provider.Name, version)
+		return recs, nil
+	}
+	recs, err := provider.Driver.GetZoneRecords(zone)
+	if err != nil {
+		return nil, err
+	}
+	if err := cache.Put(provider.Name, zone, version, recs); err != nil {
+		printer.Warnf("Could not cache the records of %q at %s: %s\n", zone.UniqueName, provider.Name, err)
+	}
+	return recs, nil
+}
+
+// failedZones is the set of zones whose records could not be gathered or
+// whose corrections failed.
+type failedZones struct {
+	mu    sync.Mutex
+	zones map[string]bool
+}
+
+func (f *failedZones) add(zone *models.DomainConfig) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.zones == nil {
+		f.zones = map[string]bool{}
+	}
+	f.zones[zone.UniqueName] = true
+}
+
+// ipamPushed returns the function that tells ipam.Allocator.Save whether
+// the ALLOC() of a name was pushed: the zones the name is in (those with
+// the longest name that it ends with) were all processed without errors.
+// Names that are in no zone count as pushed.
+func ipamPushed(all, processed []*models.DomainConfig, failed *failedZones) func(name string) bool {
+	pushed := map[string]bool{}
+	for _, zone := range processed {
+		pushed[zone.UniqueName] = !failed.zones[zone.UniqueName]
+	}
+	return func(name string) bool {
+		var in []*models.DomainConfig
+		for _, zone := range all {
+			if name != zone.Name && !strings.HasSuffix(name, "."+zone.Name) {
+				continue
+			}
+			if len(in) > 0 && len(zone.Name) < len(in[0].Name) {
+				continue
+			}
+			if len(in) > 0 && len(zone.Name) > len(in[0].Name) {
+				in = nil
+			}
+			in = append(in, zone)
+		}
+		for _, zone := range in {
+			if !pushed[zone.UniqueName] {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// zoneCacheUnsupported returns the names of the DNS providers of zones that
+// do not implement providers.ZoneVersioner, for which the on-disk zone cache
+// does nothing.
+func zoneCacheUnsupported(zones []*models.DomainConfig) []string {
+	var names []string
+	for _, zone := range zones {
+		for _, p := range zone.DNSProviderInstances {
+			if _, ok := p.Driver.(providers.ZoneVersioner); !ok && !slices.Contains(names, p.Name) {
+				names = append(names, p.Name)
+			}
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
 func generateDelegationCorrections(zone *models.DomainConfig, providers []*models.DNSProviderInstance, _ *models.RegistrarInstance) ([]*models.Correction, int, error) {
 	// fmt.Printf("DEBUG: generateDelegationCorrections start zone=%q nsList = %v\n", zone.Name, zone.Nameservers)
 	nsList, err := nameservers.DetermineNameserversForProviders(zone, providers, true)
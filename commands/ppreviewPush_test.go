@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"slices"
 	"testing"
 
 	"github.com/DNSControl/dnscontrol/v4/models"
@@ -181,3 +182,50 @@ func Test_whichZonesToProcess(t *testing.T) {
 		})
 	}
 }
+
+// versionedProvider is a DNS provider that implements ZoneVersioner.
+type versionedProvider struct{ pruneProvider }
+
+func (p *versionedProvider) GetZoneVersion(*models.DomainConfig) (string, error) { return "1", nil }
+
+func Test_zoneCacheUnsupported(t *testing.T) {
+	pdns := &models.DNSProviderInstance{Driver: &versionedProvider{}}
+	pdns.Name = "pdns"
+	cf := &models.DNSProviderInstance{Driver: &pruneProvider{}}
+	cf.Name = "cloudflare"
+	zones := []*models.DomainConfig{
+		{Name: "example.com", DNSProviderInstances: []*models.DNSProviderInstance{pdns, cf}},
+		{Name: "example.net", DNSProviderInstances: []*models.DNSProviderInstance{cf}},
+	}
+	if got, want := zoneCacheUnsupported(zones), []string{"cloudflare"}; !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func Test_ipamPushed(t *testing.T) {
+	zone := func(name string) *models.DomainConfig {
+		return &models.DomainConfig{Name: name, UniqueName: name}
+	}
+	com, sub, net := zone("example.com"), zone("sub.example.com"), zone("example.net")
+	viewA, viewB := zone("example.org"), zone("example.org")
+	viewA.UniqueName, viewB.UniqueName = "example.org!a", "example.org!b"
+	all := []*models.DomainConfig{com, sub, net, viewA, viewB}
+
+	failed := &failedZones{}
+	failed.add(net)
+	// example.com was not selected with --domains.
+	pushed := ipamPushed(all, []*models.DomainConfig{sub, net, viewA}, failed)
+
+	for name, want := range map[string]bool{
+		"www.sub.example.com": true,
+		"sub.example.com":     true,
+		"www.example.com":     false,
+		"www.example.net":     false,
+		"www.example.org":     false, // Only one of the views was pushed.
+		"www.example.info":    true,
+	} {
+		if got := pushed(name); got != want {
+			t.Errorf("%s: got %v, want %v", name, got, want)
+		}
+	}
+}
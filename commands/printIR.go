@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/DNSControl/dnscontrol/v4/models"
+	"github.com/DNSControl/dnscontrol/v4/pkg/catalogzone"
 	"github.com/DNSControl/dnscontrol/v4/pkg/js"
 	"github.com/DNSControl/dnscontrol/v4/pkg/normalize"
 	"github.com/DNSControl/dnscontrol/v4/pkg/rfc4183"
@@ -94,6 +95,9 @@ func PrintIR(args PrintIRArgs) error {
 		return err
 	}
 	if !args.Raw {
+		if err := catalogzone.Generate(cfg); err != nil {
+			return err
+		}
 		errs := normalize.ValidateAndNormalizeConfig(cfg)
 		if PrintValidationErrors(errs) {
 			return errors.New("exiting due to validation errors")
@@ -0,0 +1,251 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/DNSControl/dnscontrol/v4/models"
+	"github.com/DNSControl/dnscontrol/v4/pkg/credsfile"
+	"github.com/DNSControl/dnscontrol/v4/pkg/domaintags"
+	"github.com/DNSControl/dnscontrol/v4/pkg/prettyzone"
+	"github.com/DNSControl/dnscontrol/v4/pkg/providers"
+	"github.com/DNSControl/dnscontrol/v4/pkg/rtypecontrol"
+	"github.com/urfave/cli/v3"
+)
+
+var _ = cmd(catMain, func() *cli.Command {
+	var args PruneZonesArgs
+	return &cli.Command{
+		Name:  "prune-zones",
+		Usage: "Lists (and optionally deletes) zones at DNS providers that are not in dnsconfig.js",
+		Action: func(ctx context.Context, c *cli.Command) error {
+			args.CredKeys = c.Args().Slice()
+			return exit(PruneZones(args))
+		},
+		Flags:     args.flags(),
+		UsageText: "dnscontrol prune-zones [command options] [credkey ...]",
+		Description: `List the zones at each DNS provider that no D() in dnsconfig.js uses.
+
+By default this only lists the orphaned zones. With --delete, each
+orphaned zone is first exported to the snapshot directory, then deleted
+after confirmation (type the zone's name, or use --yes).
+
+ARGUMENTS:
+   credkey:  The DNS providers to check (names used in creds.json).
+             Default: all the DNS providers declared in dnsconfig.js.
+
+EXAMPLES:
+   dnscontrol prune-zones
+   dnscontrol prune-zones my_cloudflare
+   dnscontrol prune-zones --delete --snapshot-dir=backups my_cloudflare
+
+Documentation: https://docs.dnscontrol.org/commands/prune-zones`,
+	}
+}())
+
+// PruneZonesArgs args required for the prune-zones subcommand.
+type PruneZonesArgs struct {
+	GetDNSConfigArgs
+	GetCredentialsArgs
+	CredKeys    []string // Providers to check. Empty means all.
+	Delete      bool     // Delete the orphaned zones (after confirmation).
+	Yes         bool     // Do not ask for confirmation.
+	SnapshotDir string   // Where orphaned zones are exported before deletion.
+}
+
+func (args *PruneZonesArgs) flags() []cli.Flag {
+	flags := args.GetDNSConfigArgs.flags()
+	flags = append(flags, args.GetCredentialsArgs.flags()...)
+	flags = append(flags, &cli.BoolFlag{
+		Name:        "delete",
+		Destination: &args.Delete,
+		Usage:       `Delete the orphaned zones (each one is exported to --snapshot-dir first)`,
+	})
+	flags = append(flags, &cli.BoolFlag{
+		Name:        "yes",
+		Destination: &args.Yes,
+		Usage:       `With --delete, do not ask for confirmation`,
+	})
+	flags = append(flags, &cli.StringFlag{
+		Name:        "snapshot-dir",
+		Destination: &args.SnapshotDir,
+		Value:       "zone-snapshots",
+		Usage:       `Directory where zones are exported before they are deleted`,
+	})
+	return flags
+}
+
+// PruneZones contains all data/flags needed to run prune-zones, independently of CLI.
+func PruneZones(args PruneZonesArgs) error {
+	cfg, err := GetDNSConfig(args.GetDNSConfigArgs)
+	if err != nil {
+		return err
+	}
+	providerConfigs, err := credsfile.LoadProviderConfigs(args.CredsFile)
+	if err != nil {
+		return err
+	}
+	if _, err := InitializeProviders(cfg, providerConfigs, false); err != nil {
+		return err
+	}
+	return pruneZones(cfg, providerConfigs, args, os.Stdin, os.Stdout)
+}
+
+func pruneZones(cfg *models.DNSConfig, providerConfigs map[string]map[string]string, args PruneZonesArgs, in io.Reader, out io.Writer) error {
+	for _, key := range args.CredKeys {
+		if !slices.ContainsFunc(cfg.DNSProviders, func(p *models.DNSProviderConfig) bool { return p.Name == key }) {
+			return fmt.Errorf("%q is not a DNS provider in dnsconfig.js", key)
+		}
+	}
+
+	reader := bufio.NewReader(in)
+	var errs []error
+	for _, pc := range cfg.DNSProviders {
+		if len(args.CredKeys) != 0 && !slices.Contains(args.CredKeys, pc.Name) {
+			continue
+		}
+		driver, configured, err := providerZones(cfg, providerConfigs, pc)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", pc.Name, err))
+			continue
+		}
+
+		lister, ok := driver.(providers.ZoneLister)
+		if !ok {
+			fmt.Fprintf(out, "%s: skipped (provider cannot list zones)\n", pc.Name)
+			continue
+		}
+		zones, err := lister.ListZones()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: listing zones: %w", pc.Name, err))
+			continue
+		}
+		orphans := orphanedZones(zones, configured)
+		if len(orphans) == 0 {
+			fmt.Fprintf(out, "%s: no orphaned zones\n", pc.Name)
+			continue
+		}
+		fmt.Fprintf(out, "%s: %d orphaned zone(s):\n", pc.Name, len(orphans))
+		for _, zone := range orphans {
+			fmt.Fprintf(out, "  %s\n", zone)
+		}
+		if !args.Delete {
+			continue
+		}
+
+		deleter, ok := driver.(providers.ZoneDeleter)
+		if !ok {
+			fmt.Fprintf(out, "%s: cannot delete zones (not supported by the provider)\n", pc.Name)
+			continue
+		}
+		for _, zone := range orphans {
+			if err := pruneZone(driver, deleter, pc.Name, zone, args, reader, out); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %s: %w", pc.Name, zone, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// providerZones returns the driver for a DNS provider and the zones that
+// use it in dnsconfig.js.
+func providerZones(cfg *models.DNSConfig, providerConfigs map[string]map[string]string, pc *models.DNSProviderConfig) (models.DNSProvider, map[string]bool, error) {
+	var driver models.DNSProvider
+	configured := map[string]bool{}
+	for _, dc := range cfg.Domains {
+		for _, pi := range dc.DNSProviderInstances {
+			if pi.Name == pc.Name {
+				driver = pi.Driver
+				configured[dc.Name] = true
+			}
+		}
+	}
+	if driver == nil {
+		// No D() uses the provider; every zone there is an orphan.
+		d, err := providers.CreateDNSProvider(pc.Type, providerConfigs[pc.Name], pc.Metadata)
+		if err != nil {
+			return nil, nil, err
+		}
+		driver = d
+	}
+	return driver, configured, nil
+}
+
+// orphanedZones returns the zones that are not configured, sorted.
+func orphanedZones(zones []string, configured map[string]bool) []string {
+	var orphans []string
+	for _, zone := range zones {
+		name := domaintags.MakeDomainNameVarieties(strings.TrimSuffix(zone, ".")).NameASCII
+		if !configured[name] {
+			orphans = append(orphans, zone)
+		}
+	}
+	slices.Sort(orphans)
+	return orphans
+}
+
+// pruneZone exports a zone to the snapshot directory, asks for
+// confirmation and deletes it. Nothing is deleted if the export fails.
+func pruneZone(driver models.DNSProvider, deleter providers.ZoneDeleter, credKey, zone string, args PruneZonesArgs, in *bufio.Reader, out io.Writer) error {
+	fname, err := snapshotZone(driver, credKey, zone, args.SnapshotDir)
+	if err != nil {
+		return fmt.Errorf("snapshot failed, zone not deleted: %w", err)
+	}
+	fmt.Fprintf(out, "Exported %s to %s\n", zone, fname)
+
+	if !args.Yes {
+		fmt.Fprintf(out, "Delete zone %q at %q? Type the zone name to confirm: ", zone, credKey)
+		txt, _ := in.ReadString('\n')
+		if strings.TrimSpace(txt) != zone {
+			fmt.Fprintln(out, "Skipping")
+			return nil
+		}
+	}
+	if err := deleter.DeleteZone(zone); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Deleted %s\n", zone)
+	return nil
+}
+
+// snapshotZone writes the records of a zone to a zonefile in dir and returns
+// its name.
+func snapshotZone(driver models.DNSProvider, credKey, zone, dir string) (string, error) {
+	ff := domaintags.MakeDomainNameVarieties(zone)
+	recs, err := driver.GetZoneRecords(&models.DomainConfig{
+		Name: ff.NameASCII,
+		Metadata: map[string]string{
+			models.DomainUniqueName:  ff.UniqueName,
+			models.DomainNameRaw:     ff.NameRaw,
+			models.DomainNameUnicode: ff.NameUnicode,
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	rtypecontrol.FixLegacyRecords(&recs)
+
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return "", err
+	}
+	fname := filepath.Join(dir, fmt.Sprintf("%s_%s_%s.zone", credKey, ff.NameASCII, time.Now().UTC().Format("20060102T150405Z")))
+	f, err := os.Create(fname)
+	if err != nil {
+		return "", err
+	}
+	z := prettyzone.PrettySort(recs, ff.NameASCII, 0, nil)
+	fmt.Fprintf(f, "$ORIGIN %s.\n", ff.NameASCII)
+	if err := prettyzone.WriteZoneFileRC(f, z.Records, ff.NameASCII, 0, []string{"snapshot taken by dnscontrol prune-zones before deleting the zone"}); err != nil {
+		f.Close()
+		return "", err
+	}
+	return fname, f.Close()
+}
@@ -0,0 +1,94 @@
+package commands
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/DNSControl/dnscontrol/v4/models"
+)
+
+func TestOrphanedZones(t *testing.T) {
+	configured := map[string]bool{"example.com": true, "xn--bcher-kva.example": true}
+	zones := []string{"example.net.", "example.com", "Example.ORG", "bücher.example", "a.example"}
+
+	got := orphanedZones(zones, configured)
+	want := []string{"Example.ORG", "a.example", "example.net."}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+// pruneProvider is a DNS provider and ZoneDeleter that records the zones it
+// deletes.
+type pruneProvider struct {
+	recordsErr error // Returned by GetZoneRecords, to make the snapshot fail.
+	deleted    []string
+}
+
+func (p *pruneProvider) GetNameservers(string) ([]*models.Nameserver, error) { return nil, nil }
+
+func (p *pruneProvider) GetZoneRecords(dc *models.DomainConfig) (models.Records, error) {
+	if p.recordsErr != nil {
+		return nil, p.recordsErr
+	}
+	rc := &models.RecordConfig{Type: "A", TTL: 300, Metadata: map[string]string{}}
+	rc.SetLabel("www", dc.Name)
+	if err := rc.SetTarget("192.0.2.1"); err != nil {
+		return nil, err
+	}
+	return models.Records{rc}, nil
+}
+
+func (p *pruneProvider) GetZoneRecordsCorrections(*models.DomainConfig, models.Records) ([]*models.Correction, int, error) {
+	return nil, 0, nil
+}
+
+func (p *pruneProvider) DeleteZone(domain string) error {
+	p.deleted = append(p.deleted, domain)
+	return nil
+}
+
+func TestPruneZone(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		recordsErr  error
+		yes         bool
+		answer      string
+		wantErr     bool
+		wantDeleted bool
+	}{
+		{name: "snapshot failure", recordsErr: errors.New("API down"), yes: true, wantErr: true},
+		{name: "wrong confirmation", answer: "example.org\n"},
+		{name: "no confirmation", answer: ""},
+		{name: "confirmed", answer: "example.net\n", wantDeleted: true},
+		{name: "yes", yes: true, wantDeleted: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &pruneProvider{recordsErr: tc.recordsErr}
+			dir := filepath.Join(t.TempDir(), "snapshots")
+			args := PruneZonesArgs{Delete: true, Yes: tc.yes, SnapshotDir: dir}
+			var out bytes.Buffer
+			err := pruneZone(p, p, "fake", "example.net", args, bufio.NewReader(strings.NewReader(tc.answer)), &out)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tc.wantErr)
+			}
+			if got := len(p.deleted) == 1; got != tc.wantDeleted {
+				t.Errorf("deleted %v, want deleted %v\n%s", p.deleted, tc.wantDeleted, out.String())
+			}
+			if tc.yes && strings.Contains(out.String(), "Type the zone name") {
+				t.Errorf("--yes asked for confirmation:\n%s", out.String())
+			}
+
+			snapshots, _ := os.ReadDir(dir)
+			if tc.recordsErr == nil && len(snapshots) != 1 {
+				t.Errorf("got %d snapshots, want 1", len(snapshots))
+			}
+		})
+	}
+}
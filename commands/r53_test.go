@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/DNSControl/dnscontrol/v4/models"
@@ -135,3 +136,57 @@ func TestR53Test_4ttl(t *testing.T) {
 		t.Errorf("makeR53alias failure: got `%s` want `%s`", g, w)
 	}
 }
+
+func TestR53Test_5routing(t *testing.T) {
+	rec := models.RecordConfig{
+		Type:     "R53_ALIAS",
+		Name:     "foo",
+		NameFQDN: "foo.domain.tld",
+		Metadata: map[string]string{"r53_set_identifier": "main", "r53_failover": "PRIMARY", "r53_health_check_id": "hc-1"},
+	}
+	rec.MustSetTarget("bar")
+	rec.R53Alias = make(map[string]string)
+	rec.R53Alias["type"] = "A"
+	w := `R53_ALIAS("foo", "A", "bar", R53_FAILOVER("PRIMARY", "main"), R53_HEALTH_CHECK_ID("hc-1"))`
+	if g := makeR53alias(&rec, 0); g != w {
+		t.Errorf("makeR53alias failure: got `%s` want `%s`", g, w)
+	}
+}
+
+func TestR53Routing(t *testing.T) {
+	tests := []struct {
+		meta map[string]string
+		want string
+	}{
+		{map[string]string{}, ``},
+		{map[string]string{"r53_set_identifier": "a", "r53_weight": "70"}, `R53_WEIGHT(70, "a")`},
+		{map[string]string{"r53_set_identifier": "a", "r53_region": "us-east-1"}, `R53_LATENCY("us-east-1", "a")`},
+		{map[string]string{"r53_set_identifier": "a", "r53_geo_continent": "EU"}, `R53_GEO({continent: "EU"}, "a")`},
+		{map[string]string{"r53_set_identifier": "a", "r53_geo_country": "US", "r53_geo_subdivision": "CA"}, `R53_GEO({country: "US", subdivision: "CA"}, "a")`},
+		{map[string]string{"r53_set_identifier": "a", "r53_geoproximity_region": "eu-west-1", "r53_geoproximity_bias": "-20"}, `R53_GEOPROXIMITY("eu-west-1", -20, "a")`},
+		{map[string]string{"r53_set_identifier": "a", "r53_geoproximity_coordinates": "49.22,-74.01", "r53_geoproximity_bias": "0"}, `R53_GEOPROXIMITY({latitude: "49.22", longitude: "-74.01"}, 0, "a")`},
+		{map[string]string{"r53_set_identifier": "a", "r53_multivalue": "true", "r53_health_check_id": "hc"}, `R53_MULTIVALUE("a"), R53_HEALTH_CHECK_ID("hc")`},
+	}
+	for _, tt := range tests {
+		rec := models.RecordConfig{Metadata: tt.meta}
+		if g := strings.Join(makeR53routing(&rec), ", "); g != tt.want {
+			t.Errorf("makeR53routing(%v): got `%s` want `%s`", tt.meta, g, tt.want)
+		}
+	}
+}
+
+func TestR53RoutingCAA(t *testing.T) {
+	rec := models.RecordConfig{
+		Type:     "CAA",
+		Name:     "foo",
+		NameFQDN: "foo.domain.tld",
+		TTL:      300,
+		CaaTag:   "issue",
+		Metadata: map[string]string{"r53_set_identifier": "a", "r53_weight": "70"},
+	}
+	rec.MustSetTarget("letsencrypt.org")
+	w := `CAA("foo", "issue", "letsencrypt.org", R53_WEIGHT(70, "a"), TTL(300))`
+	if g := formatDsl(&rec, 3600); g != w {
+		t.Errorf("formatDsl failure: got `%s` want `%s`", g, w)
+	}
+}
@@ -99,6 +99,11 @@ interface FetchResponse {
     json(): Promise<any>;
 }
 
+interface Pool {
+    readonly ipam_pool: string;
+    readonly exclude: string[];
+}
+
 interface ResponseHeaders {
     get(name: string): string | undefined;
     getAll(name: string): string[];
@@ -330,6 +335,38 @@ declare function AKAMAITLC(name: string, answer_type: "DUAL" | "A" | "AAAA", tar
  */
 declare function ALIAS(name: string, target: string, ...modifiers: RecordModifier[]): DomainModifier;
 
+/**
+ * `ALLOC` allocates an address from a [`POOL`](../top-level-functions/POOL.md) to a name and adds an `A` record (or an `AAAA` record for an IPv6 pool) for it. The name is relative to the domain, like the name of [`A`](A.md). Modifiers are applied to the record.
+ *
+ * ```javascript
+ * var LAB = POOL("10.1.0.0/24", { exclude: ["10.1.0.1"] });
+ *
+ * D("example.com", REG_MY_PROVIDER, DnsProvider(DSP_MY_PROVIDER),
+ *   ALLOC(LAB, "printer"),
+ *   ALLOC(LAB, "nas", TTL(300)),
+ * );
+ *
+ * D(REV("10.1.0.0/24"), REG_MY_PROVIDER, DnsProvider(DSP_MY_PROVIDER),
+ *   AUTOPTR,
+ * );
+ * ```
+ *
+ * ## Reverse records
+ *
+ * The `PTR` records of the allocated addresses are added by [`AUTOPTR`](AUTOPTR.md), like those of any other `A` and `AAAA` records: add `AUTOPTR` to the reverse zones that should get them. The most specific reverse zone that contains the address is used, including RFC 2317 and RFC 4183 classless zones.
+ *
+ * ## The lock file
+ *
+ * The address a name gets is derived from a hash of the name and the domain, so it does not depend on the order of the `ALLOC`s. The allocations are recorded in a lock file next to `dnsconfig.js`, `dnsconfig.ipam.json`, and later runs reuse them. Only `push` writes the lock file: `check` and `preview` show the addresses new `ALLOC`s would get without recording them.
+ *
+ * Commit the lock file with `dnsconfig.js`. Without it, a name whose first choice was taken may get a different address.
+ *
+ * Removing an `ALLOC` frees its address: the next `push` drops it from the lock file.
+ *
+ * @see https://docs.dnscontrol.org/language-reference/domain-modifiers/alloc
+ */
+declare function ALLOC(pool: Pool, name: string, ...modifiers: RecordModifier[]): DomainModifier;
+
 /**
  * `AUTODNSSEC_OFF` tells the provider to disable AutoDNSSEC. It takes no
  * parameters.
@@ -370,6 +407,46 @@ declare const AUTODNSSEC_OFF: DomainModifier;
  */
 declare const AUTODNSSEC_ON: DomainModifier;
 
+/**
+ * `AUTOPTR` adds `PTR` records to a reverse zone for the `A` and `AAAA` records of the other zones. It saves writing a [`PTR`](PTR.md) for every address by hand.
+ *
+ * ```javascript
+ * D("example.com", REG_MY_PROVIDER, DnsProvider(DSP_MY_PROVIDER),
+ *   A("www", "10.0.0.80"),
+ *   A("db", "10.0.1.5"),
+ *   AAAA("www", "2001:db8::80"),
+ * );
+ *
+ * D(REV("10.0.0.0/24"), REG_MY_PROVIDER, DnsProvider(DSP_MY_PROVIDER), AUTOPTR);
+ * D(REV("10.0.1.0/26"), REG_MY_PROVIDER, DnsProvider(DSP_MY_PROVIDER), AUTOPTR);
+ * D(REV("2001:db8::/32"), REG_MY_PROVIDER, DnsProvider(DSP_MY_PROVIDER), AUTOPTR);
+ * ```
+ *
+ * This adds `80 PTR www.example.com.` to `0.0.10.in-addr.arpa`, `5 PTR db.example.com.` to the classless zone of `10.0.1.0/26`, and a `PTR` for `2001:db8::80` to `8.b.d.0.1.0.0.2.ip6.arpa`.
+ *
+ * An address belongs to the most specific reverse zone that contains it, including RFC 2317 and RFC 4183 classless zones (see [`REVCOMPAT`](../top-level-functions/REVCOMPAT.md)). If that zone does not have `AUTOPTR`, no `PTR` is added, even if a larger zone has it. Wildcard names are skipped.
+ *
+ * If the reverse zone has views (see [split horizon](../top-level-functions/D.md#split-horizon-dns)), the `PTR` goes to the view with the same tag as the forward zone, or to every view if none has it.
+ *
+ * ## Conflicts
+ *
+ * A `PTR()` in the reverse zone takes precedence over `AUTOPTR`. If several names have the same address and there is no `PTR()` for it, validation fails and lists the names. Add a `PTR()` to choose one:
+ *
+ * ```javascript
+ * D("example.com", REG_MY_PROVIDER, DnsProvider(DSP_MY_PROVIDER),
+ *   A("mail", "10.0.0.25"),
+ *   A("smtp", "10.0.0.25"),
+ * );
+ *
+ * D(REV("10.0.0.0/24"), REG_MY_PROVIDER, DnsProvider(DSP_MY_PROVIDER), AUTOPTR,
+ *   PTR("10.0.0.25", "mail.example.com."),
+ * );
+ * ```
+ *
+ * @see https://docs.dnscontrol.org/language-reference/domain-modifiers/autoptr
+ */
+declare const AUTOPTR: DomainModifier;
+
 /**
  * AZURE_ALIAS is a Azure specific virtual record type that points a record at either another record or an Azure entity.
  * It is analogous to a CNAME, but is usually resolved at request-time and served as an A record.
@@ -558,6 +635,110 @@ declare function CAA(name: string, tag: "issue" | "issuewild" | "iodef" | "conta
  */
 declare function CAA_BUILDER(opts: { label?: string; iodef?: string; iodef_critical?: boolean; issue?: string[]|'none'; issue_critical?: boolean; issuewild?: string[]|'none'; issuewild_critical?: boolean; issuevmc?: string[]|'none'; issuevmc_critical?: boolean; issuemail?: string[]|'none'; issuemail_critical?: boolean; ttl?: Duration }): DomainModifier;
 
+/**
+ * `CDNSKEY` adds a [Child DNSKEY record](https://www.rfc-editor.org/rfc/rfc7344) to a domain. It tells the parent zone which DNSKEY to use for the DS record of this zone (RFC 7344, RFC 8078).
+ *
+ * The parameters are those of a [`DNSKEY`](https://docs.dnscontrol.org/language-reference/domain-modifiers/dnskey) record.
+ *
+ * ```javascript
+ * D("example.com", REG_MY_PROVIDER, DnsProvider(DSP_MY_PROVIDER),
+ *   CDNSKEY("@", 257, 3, 13, "mdsswUyr3DPW132mOi8V9xESWE8jTo0dxCjjnopKl+GqJxpVXckHAeF+KkxLbxILfDLUT0rAK9iUzy1L53eKGQ=="),
+ * );
+ * ```
+ *
+ * Only `BIND`, `AXFRDDNS` and `POWERDNS` support `CDNSKEY`.
+ *
+ * @see https://docs.dnscontrol.org/language-reference/domain-modifiers/cdnskey
+ */
+declare function CDNSKEY(name: string, flags: number, protocol: number, algorithm: number, publickey: string, ...modifiers: RecordModifier[]): DomainModifier;
+
+/**
+ * `CDS` adds a [Child DS record](https://www.rfc-editor.org/rfc/rfc7344) to a domain. It tells the parent zone which DS record to publish for this zone (RFC 7344, RFC 8078).
+ *
+ * The parameters are those of a [`DS`](https://docs.dnscontrol.org/language-reference/domain-modifiers/ds) record.
+ *
+ * ```javascript
+ * D("example.com", REG_MY_PROVIDER, DnsProvider(DSP_MY_PROVIDER),
+ *   CDS("@", 60485, 5, 1, "2BB183AF5F22588179A53B0A98631FAD1A292118"),
+ * );
+ * ```
+ *
+ * To ask the parent to remove the DS records (turning DNSSEC off), publish `CDS("@", 0, 0, 0, "00")`.
+ *
+ * Only `BIND`, `AXFRDDNS` and `POWERDNS` support `CDS`.
+ *
+ * @see https://docs.dnscontrol.org/language-reference/domain-modifiers/cds
+ */
+declare function CDS(name: string, keytag: number, algorithm: number, digesttype: number, digest: string, ...modifiers: RecordModifier[]): DomainModifier;
+
+/**
+ * `CERT` adds a [Certificate record](https://www.rfc-editor.org/rfc/rfc4398) to a domain.
+ *
+ * * `certtype` is the certificate type, as a number or a mnemonic (`PKIX`, `PGP`, `IPGP`, ...).
+ * * `keytag` is the key tag of the key, or 0.
+ * * `algorithm` is the DNSSEC algorithm, as a number or a mnemonic (`RSASHA256`, ...), or 0.
+ * * `cert` is the certificate in base64.
+ *
+ * ```javascript
+ * D("example.com", REG_MY_PROVIDER, DnsProvider(DSP_MY_PROVIDER),
+ *   CERT("@", "PGP", 0, "0", "AAECAw=="),
+ * );
+ * ```
+ *
+ * Only `BIND`, `AXFRDDNS` and `POWERDNS` support `CERT`.
+ *
+ * @see https://docs.dnscontrol.org/language-reference/domain-modifiers/cert
+ */
+declare function CERT(name: string, certtype: string, keytag: number, algorithm: string, cert: string, ...modifiers: RecordModifier[]): DomainModifier;
+
+/**
+ * `CF_LOAD_BALANCER` is a [Cloudflare](../../provider/cloudflareapi.md)-specific feature for managing load balancers, together with their pools and health monitors. It requires `manage_load_balancers` in the provider metadata, and `accountid` in `creds.json`. See [Load balancers](../../provider/cloudflareapi.md#load-balancers).
+ *
+ * Cloudflare documentation: <https://developers.cloudflare.com/load-balancing/>
+ *
+ * ```javascript
+ * var DSP_CLOUDFLARE = NewDnsProvider("cloudflare", {"manage_load_balancers": true});
+ *
+ * D("example.com", REG_MY_PROVIDER, DnsProvider(DSP_CLOUDFLARE),
+ *   CF_LOAD_BALANCER("www", [
+ *     {
+ *       name: "www-eu",
+ *       origins: [
+ *         {name: "web1", address: "192.0.2.1"},
+ *         {name: "web2", address: "192.0.2.2", weight: 0.5},
+ *       ],
+ *       monitor: {type: "https", path: "/health"},
+ *       regions: ["WEU", "EEU"],
+ *     },
+ *     {
+ *       name: "www-us",
+ *       origins: [{name: "web3", address: "198.51.100.3"}],
+ *       monitor: {type: "https", path: "/health"},
+ *     },
+ *   ], "geo", CF_PROXY_ON),
+ * );
+ * ```
+ *
+ * The arguments are:
+ *
+ * * name: The label of the load balancer, like the label of a record.
+ * * pools: The pools, in order of preference. The last pool is the fallback pool.
+ * * steering: How traffic is distributed among the pools: `"off"` (failover in the order of the pools), `"random"`, `"geo"`, `"dynamic_latency"`, `"least_outstanding_requests"` or `"least_connections"`.
+ *
+ * The fields of a pool are:
+ *
+ * * `name`: The name of the pool. Pool names are unique in the Cloudflare account, so a pool used by several load balancers must be defined the same way in each.
+ * * `origins`: The servers of the pool. Each has a `name`, an `address` (an IP address or hostname), a `weight` (default 1) and may be `disabled: true`.
+ * * `monitor`: The health check of the origins (optional). `type` is `"http"` (the default), `"https"`, `"tcp"`, `"udp_icmp"`, `"icmp_ping"` or `"smtp"`. For HTTP(S), `method` (default `"GET"`), `path` (default `"/"`) and `expected_codes` (default `"200"`) may be set. `port`, `interval` (default 60 seconds), `timeout` (default 5 seconds) and `retries` (default 2) are optional.
+ * * `regions`: The Cloudflare regions (`"WNAM"`, `"ENAM"`, `"WEU"`, `"EEU"`, ...) that this pool serves. Only used by the `"geo"` steering.
+ * * `weight`: The weight of the pool, for the `"random"` and `"least_*"` steerings (optional).
+ *
+ * The load balancer is proxied if `CF_PROXY_ON` is given. `CF_PROXY_FULL` is not supported. Unknown fields are an error, to catch typos.
+ *
+ * @see https://docs.dnscontrol.org/language-reference/domain-modifiers/cf_load_balancer
+ */
+declare function CF_LOAD_BALANCER(name: string, pools: object[], steering: string, ...modifiers: RecordModifier[]): DomainModifier;
+
 /**
  * **WARNING:** Cloudflare is removing this feature and replacing it with a new
  * feature called "Dynamic Single Redirect". DNSControl will automatically
@@ -673,6 +854,53 @@ declare function CF_TEMP_REDIRECT(source: string, destination: string, ...modifi
  */
 declare function CF_WORKER_ROUTE(pattern: string, script: string): DomainModifier;
 
+/**
+ * `CF_ZONE_SETTINGS` manages the [zone settings](https://developers.cloudflare.com/api/resources/zones/subresources/settings/) of a [Cloudflare](../../provider/cloudflareapi.md) zone, such as the SSL mode or the minimum TLS version. `preview` lists each setting that is different at Cloudflare, and `push` changes it. Settings that are not listed are left alone.
+ *
+ * ```javascript
+ * D("example.com", REG_MY_PROVIDER, DnsProvider(DSP_MY_PROVIDER),
+ *   CF_ZONE_SETTINGS({
+ *     ssl: "strict",
+ *     min_tls_version: "1.2",
+ *     always_use_https: "on",
+ *     hsts: {enabled: true, max_age: 31536000, include_subdomains: true},
+ *     dnssec: "on",
+ *     cname_flattening: "flatten_at_root",
+ *     ipv6: "on",
+ *   }),
+ *   A("@", "192.0.2.1", CF_PROXY_ON),
+ * );
+ * ```
+ *
+ * ```text
+ * ******************** Domain: example.com
+ * 1 correction (cloudflare)
+ * #1: Zone setting min_tls_version: "1.0" -> "1.2"
+ * ```
+ *
+ * The names are Cloudflare's setting IDs, and the values are the ones of the Cloudflare API. These values are checked:
+ *
+ * * `ssl`: `"off"`, `"flexible"`, `"full"` or `"strict"`.
+ * * `min_tls_version`: `"1.0"`, `"1.1"`, `"1.2"` or `"1.3"`.
+ * * `always_use_https`, `automatic_https_rewrites`, `ipv6`: `"on"` or `"off"`.
+ * * `tls_1_3`: `"on"`, `"off"` or `"zrt"`.
+ * * `cname_flattening`: `"flatten_at_root"` or `"flatten_all"`.
+ *
+ * Two names are not Cloudflare setting IDs:
+ *
+ * * `dnssec`: `"on"` or `"off"`, the DNSSEC state of the zone. After turning it on, add the DS record that Cloudflare shows at the registrar.
+ * * `hsts`: the HSTS fields of the `security_header` setting: `enabled`, `max_age`, `include_subdomains`, `preload` and `nosniff`.
+ *
+ * For settings whose value is an object, such as `hsts`, only the fields that are given are compared and changed.
+ *
+ * Several `CF_ZONE_SETTINGS()` are merged, so common settings can be kept in a variable and others added per zone. If a setting is given twice, the last value wins.
+ *
+ * `dnscontrol get-zones` outputs the current values of the settings above as a `CF_ZONE_SETTINGS()`.
+ *
+ * @see https://docs.dnscontrol.org/language-reference/domain-modifiers/cf_zone_settings
+ */
+declare function CF_ZONE_SETTINGS(settings: Record<string, unknown>): DomainModifier;
+
 /**
  * Documentation needed.
  *
@@ -1304,6 +1532,36 @@ declare function FRAME(name: string, target: string, ...modifiers: RecordModifie
  */
 declare function HASH(algorithm: "SHA1" | "SHA256" | "SHA512", value: string): string;
 
+/**
+ * `HEALTH_CHECK` declares a health check that DNSControl manages like the records of a zone. Records refer to it by name, with [`R53_HEALTH_CHECK_ID`](../record-modifiers/R53_HEALTH_CHECK_ID.md) or the `health_check` of [`STEER`](../record-modifiers/STEER.md). Each DNS provider that supports health checks creates the ones that the records of its zones refer to, and `preview` shows the changes before `push` makes them.
+ *
+ * The options are:
+ *
+ * * `type`: `HTTP`, `HTTPS` or `TCP`.
+ * * `host`: the host name to check. For `HTTP` and `HTTPS` it is also sent as the `Host` header.
+ * * `ip`: the IP address to check. One of `host` and `ip` is required.
+ * * `port`: defaults to 80 for `HTTP` and 443 for `HTTPS`. Required for `TCP`.
+ * * `path`: the path requested by `HTTP` and `HTTPS` checks. Defaults to `/`.
+ * * `interval`: the number of seconds between checks. Defaults to 30.
+ * * `failure_threshold`: the number of failed checks after which the endpoint is unhealthy. Defaults to 3.
+ *
+ * ```javascript
+ * HEALTH_CHECK("web", { type: "HTTPS", host: "www.example.com", path: "/health", interval: 10 });
+ *
+ * D("example.com", REG_MY_PROVIDER, DnsProvider(DSP_R53),
+ *   A("www", "192.0.2.1", STEER({ id: "main", failover: "primary", health_check: "web" })),
+ *   A("www", "192.0.2.2", STEER({ id: "backup", failover: "secondary" })),
+ * );
+ * ```
+ *
+ * A health check that no record refers to is deleted, after the records that used it are updated. Health checks that DNSControl did not create are left alone.
+ *
+ * Providers may restrict the options. For example, Route 53 only supports an `interval` of 10 or 30 seconds, and can not change the `type` or `interval` of an existing health check: give it a new name to replace it.
+ *
+ * @see https://docs.dnscontrol.org/language-reference/top-level-functions/health_check
+ */
+declare function HEALTH_CHECK(name: string, opts: { type: "HTTP" | "HTTPS" | "TCP"; host?: string; ip?: string; port?: number; path?: string; interval?: number; failure_threshold?: number }): void;
+
 /**
  * `HEDNS_DDNS_KEY` enables Dynamic DNS on a record managed by the Hurricane Electric DNS provider and sets a specific DDNS key (token). This implies [`HEDNS_DYNAMIC_ON`](HEDNS_DYNAMIC_ON.md).
  *
@@ -1355,6 +1613,26 @@ declare const HEDNS_DYNAMIC_OFF: RecordModifier;
  */
 declare const HEDNS_DYNAMIC_ON: RecordModifier;
 
+/**
+ * `HINFO` adds a [Host Information record](https://www.rfc-editor.org/rfc/rfc1035#section-3.3.2) to a domain.
+ *
+ * * `cpu` is the CPU type of the host.
+ * * `os` is its operating system.
+ *
+ * ```javascript
+ * D("example.com", REG_MY_PROVIDER, DnsProvider(DSP_MY_PROVIDER),
+ *   HINFO("host", "INTEL-386", "Windows"),
+ * );
+ * ```
+ *
+ * [RFC 8482](https://www.rfc-editor.org/rfc/rfc8482) uses `HINFO` records in answers to `ANY` queries. A zone does not need to contain one for that to work.
+ *
+ * Only `BIND`, `AXFRDDNS` and `POWERDNS` support `HINFO`.
+ *
+ * @see https://docs.dnscontrol.org/language-reference/domain-modifiers/hinfo
+ */
+declare function HINFO(name: string, cpu: string, os: string, ...modifiers: RecordModifier[]): DomainModifier;
+
 /**
  * HTTPS adds an HTTPS record to a domain. The name should be the relative label for the record. Use `@` for the domain apex. The HTTPS record is a special form of the SVCB resource record.
  *
@@ -1899,6 +2177,28 @@ declare function INCLUDE(domain: string): DomainModifier;
  */
 declare function IP(ip: string): number;
 
+/**
+ * `IPSECKEY` adds an [IPsec keying material record](https://www.rfc-editor.org/rfc/rfc4025) to a domain.
+ *
+ * * `precedence` orders the gateways; lower values are preferred.
+ * * `gatewaytype` is 0 (no gateway), 1 (IPv4 address), 2 (IPv6 address) or 3 (domain name).
+ * * `algorithm` is the algorithm of the public key: 0 (none), 1 (DSA), 2 (RSA) or 3 (ECDSA).
+ * * `gateway` is the gateway. With gateway type 0 it is `""` or `"."`. With gateway type 3 a short name is relative to the domain.
+ * * `publickey` is the public key in base64.
+ *
+ * ```javascript
+ * D("example.com", REG_MY_PROVIDER, DnsProvider(DSP_MY_PROVIDER),
+ *   IPSECKEY("38.2.0.192", 10, 1, 2, "192.0.2.38", "AQNRU3mG7TVTO2BkR47usntb102uFJtugbo6BSGvgqt4AQ=="),
+ *   IPSECKEY("gw", 10, 3, 2, "gateway", "AQNRU3mG7TVTO2BkR47usntb102uFJtugbo6BSGvgqt4AQ=="),
+ * );
+ * ```
+ *
+ * Only `BIND`, `AXFRDDNS` and `POWERDNS` support `IPSECKEY`.
+ *
+ * @see https://docs.dnscontrol.org/language-reference/domain-modifiers/ipseckey
+ */
+declare function IPSECKEY(name: string, precedence: number, gatewaytype: number, algorithm: number, gateway: string, publickey: string, ...modifiers: RecordModifier[]): DomainModifier;
+
 /**
  * `LOC` add a [Location record](https://www.rfc-editor.org/rfc/rfc1876) to the domain.
  *
@@ -2948,6 +3248,145 @@ declare function OPENPGPKEY(name: string, target: string, ...modifiers: RecordMo
  */
 declare function PANIC(message: string): never;
 
+/**
+ * `PDNS_ALLOW_AXFR_FROM` sets the `ALLOW-AXFR-FROM` metadata of a [PowerDNS](../../provider/powerdns.md) zone: the networks that may transfer the zone, such as `"192.0.2.0/24"`, or `"AUTO-NS"` for the nameservers of the zone. The order does not matter. `PDNS_ALLOW_AXFR_FROM()` without sources removes the metadata.
+ *
+ * ```javascript
+ * D("example.com", REG_NONE, DnsProvider(DSP_POWERDNS),
+ *   PDNS_ALLOW_AXFR_FROM("AUTO-NS", "198.51.100.0/24"),
+ * );
+ * ```
+ *
+ * See [Zone settings and metadata](../../provider/powerdns.md#zone-settings-and-metadata).
+ *
+ * @see https://docs.dnscontrol.org/language-reference/domain-modifiers/pdns_allow_axfr_from
+ */
+declare function PDNS_ALLOW_AXFR_FROM(...sources: string[]): DomainModifier;
+
+/**
+ * `PDNS_AXFR_MASTER_TSIG` sets the `AXFR-MASTER-TSIG` metadata of a `Slave` [PowerDNS](../../provider/powerdns.md) zone: the name of the TSIG key used to transfer the zone from its masters. `PDNS_AXFR_MASTER_TSIG()` without a key removes the metadata.
+ *
+ * ```javascript
+ * D("example.com", REG_NONE, DnsProvider(DSP_POWERDNS),
+ *   PDNS_ZONE_KIND("Slave"),
+ *   PDNS_MASTERS("192.0.2.1"),
+ *   PDNS_AXFR_MASTER_TSIG("transfer-key"),
+ * );
+ * ```
+ *
+ * See [Zone settings and metadata](../../provider/powerdns.md#zone-settings-and-metadata).
+ *
+ * @see https://docs.dnscontrol.org/language-reference/domain-modifiers/pdns_axfr_master_tsig
+ */
+declare function PDNS_AXFR_MASTER_TSIG(key: string): DomainModifier;
+
+/**
+ * `PDNS_MASTERS` sets the primary servers that a `Slave` [PowerDNS](../../provider/powerdns.md) zone is transferred from. Each is an IP address, optionally followed by a port: `"192.0.2.1"`, `"192.0.2.2:5300"` or `"[2001:db8::1]:5300"`. The order is kept. `PDNS_MASTERS()` without servers removes them.
+ *
+ * ```javascript
+ * D("example.com", REG_NONE, DnsProvider(DSP_POWERDNS),
+ *   PDNS_ZONE_KIND("Slave"),
+ *   PDNS_MASTERS("192.0.2.1", "192.0.2.2"),
+ *   PDNS_AXFR_MASTER_TSIG("transfer-key"),
+ * );
+ * ```
+ *
+ * See [Zone settings and metadata](../../provider/powerdns.md#zone-settings-and-metadata).
+ *
+ * @see https://docs.dnscontrol.org/language-reference/domain-modifiers/pdns_masters
+ */
+declare function PDNS_MASTERS(...servers: string[]): DomainModifier;
+
+/**
+ * `PDNS_NSEC3PARAM` sets the NSEC3 parameters of a DNSSEC-signed [PowerDNS](../../provider/powerdns.md) zone, such as `"1 0 0 -"` (SHA-1, no opt-out, no extra iterations, no salt, as recommended by RFC 9276). `""` makes the zone use NSEC instead of NSEC3.
+ *
+ * ```javascript
+ * D("example.com", REG_NONE, DnsProvider(DSP_POWERDNS),
+ *   AUTODNSSEC_ON,
+ *   PDNS_NSEC3PARAM("1 0 0 -"),
+ * );
+ * ```
+ *
+ * See [Zone settings and metadata](../../provider/powerdns.md#zone-settings-and-metadata).
+ *
+ * @see https://docs.dnscontrol.org/language-reference/domain-modifiers/pdns_nsec3param
+ */
+declare function PDNS_NSEC3PARAM(param: string): DomainModifier;
+
+/**
+ * `PDNS_SOA_EDIT_API` sets how the serial of a [PowerDNS](../../provider/powerdns.md) zone changes when the zone is changed through the API: `DEFAULT`, `INCREASE`, `EPOCH`, `SOA-EDIT` or `SOA-EDIT-INCREASE`. `""` turns it off. See the [PowerDNS documentation](https://doc.powerdns.com/authoritative/dnsupdate.html#soa-edit-dnsupdate-settings).
+ *
+ * Unlike the `soa_edit_api` provider metadata, which is only used when a zone is created, existing zones are changed too.
+ *
+ * ```javascript
+ * D("example.com", REG_NONE, DnsProvider(DSP_POWERDNS),
+ *   PDNS_SOA_EDIT_API("DEFAULT"),
+ * );
+ * ```
+ *
+ * See [Zone settings and metadata](../../provider/powerdns.md#zone-settings-and-metadata).
+ *
+ * @see https://docs.dnscontrol.org/language-reference/domain-modifiers/pdns_soa_edit_api
+ */
+declare function PDNS_SOA_EDIT_API(value: "DEFAULT" | "INCREASE" | "EPOCH" | "SOA-EDIT" | "SOA-EDIT-INCREASE" | ""): DomainModifier;
+
+/**
+ * `PDNS_TSIG_ALLOW_AXFR` sets the `TSIG-ALLOW-AXFR` metadata of a [PowerDNS](../../provider/powerdns.md) zone: the names of the TSIG keys that may transfer the zone. The keys themselves are created with `pdnsutil` or the API. `PDNS_TSIG_ALLOW_AXFR()` without keys removes the metadata.
+ *
+ * ```javascript
+ * D("example.com", REG_NONE, DnsProvider(DSP_POWERDNS),
+ *   PDNS_ZONE_KIND("Master"),
+ *   PDNS_TSIG_ALLOW_AXFR("secondary-1", "secondary-2"),
+ * );
+ * ```
+ *
+ * See [Zone settings and metadata](../../provider/powerdns.md#zone-settings-and-metadata).
+ *
+ * @see https://docs.dnscontrol.org/language-reference/domain-modifiers/pdns_tsig_allow_axfr
+ */
+declare function PDNS_TSIG_ALLOW_AXFR(...keys: string[]): DomainModifier;
+
+/**
+ * `PDNS_ZONE_KIND` sets the [kind](https://doc.powerdns.com/authoritative/modes-of-operation.html) of a [PowerDNS](../../provider/powerdns.md) zone: `Native`, `Master`, `Slave`, `Producer` or `Consumer`. `Primary` and `Secondary` may be used for `Master` and `Slave`. The case does not matter.
+ *
+ * Unlike the `zone_kind` provider metadata, which is only used when a zone is created, the kind of existing zones is changed too.
+ *
+ * ```javascript
+ * D("example.com", REG_NONE, DnsProvider(DSP_POWERDNS),
+ *   PDNS_ZONE_KIND("Master"),
+ *   PDNS_ALLOW_AXFR_FROM("192.0.2.53"),
+ *   A("www", "192.0.2.1"),
+ * );
+ * ```
+ *
+ * See [Zone settings and metadata](../../provider/powerdns.md#zone-settings-and-metadata).
+ *
+ * @see https://docs.dnscontrol.org/language-reference/domain-modifiers/pdns_zone_kind
+ */
+declare function PDNS_ZONE_KIND(kind: "Native" | "Master" | "Slave" | "Producer" | "Consumer"): DomainModifier;
+
+/**
+ * `POOL` defines a range of IP addresses that [`ALLOC`](../domain-modifiers/ALLOC.md) allocates from. The range is an IPv4 or IPv6 network in CIDR notation, such as `10.1.0.0/24` or `2001:db8:1::/64`.
+ *
+ * The first address of the network and, for IPv4, the last one (the broadcast address) are never allocated. Use `exclude` to keep other addresses out of the pool, such as the gateway or a DHCP range. It takes addresses and networks.
+ *
+ * ```javascript
+ * var LAB = POOL("10.1.0.0/24", { exclude: ["10.1.0.1", "10.1.0.240/28"] });
+ * var LAB6 = POOL("2001:db8:1::/64");
+ *
+ * D("example.com", REG_MY_PROVIDER, DnsProvider(DSP_MY_PROVIDER),
+ *   ALLOC(LAB, "printer"),
+ *   ALLOC(LAB, "nas"),
+ *   ALLOC(LAB6, "nas"),
+ * );
+ * ```
+ *
+ * Adding an exclusion later moves the names whose address it covers to new addresses. The other names keep theirs.
+ *
+ * @see https://docs.dnscontrol.org/language-reference/top-level-functions/pool
+ */
+declare function POOL(cidr: string, options?: { exclude?: string | string[] }): Pool;
+
 /**
  * **DEPRECATED**: This record type is deprecated. Please use `URL` (for temporary redirects) or `URL301` (for permanent redirects) instead. PORKBUN_URLFWD will continue to work but is no longer recommended for new configurations.
  *
@@ -3136,10 +3575,82 @@ declare function R53_ALIAS(name: string, type: "SOA" | "A" | "TXT" | "CNAME" | "
  */
 declare function R53_EVALUATE_TARGET_HEALTH(enabled: boolean): RecordModifier;
 
+/**
+ * `R53_FAILOVER` configures [Route 53 failover routing](https://docs.aws.amazon.com/Route53/latest/DeveloperGuide/routing-policy-failover.html) for a record. Route 53 answers with the `PRIMARY` record while it is healthy, and with the `SECONDARY` record otherwise.
+ *
+ * `role` is `"PRIMARY"` or `"SECONDARY"`. A name and type has at most one of each.
+ *
+ * `set_identifier` is a unique string that differentiates this record from the other failover record with the same name and type.
+ *
+ * The primary record should have a health check, associated using [`R53_HEALTH_CHECK_ID()`](R53_HEALTH_CHECK_ID.md) (or [`R53_EVALUATE_TARGET_HEALTH()`](R53_EVALUATE_TARGET_HEALTH.md) for an `R53_ALIAS()`).
+ *
+ * ```javascript
+ * D("example.com", REG_MY_PROVIDER, DnsProvider("ROUTE53"),
+ *   A("www", "1.2.3.4", R53_FAILOVER("PRIMARY", "web-primary"), R53_HEALTH_CHECK_ID("12345678-1234-1234-1234-123456789012")),
+ *   A("www", "5.6.7.8", R53_FAILOVER("SECONDARY", "web-secondary")),
+ * );
+ * ```
+ *
+ * @see https://docs.dnscontrol.org/language-reference/record-modifiers/service-provider-specific/amazon-route-53/r53_failover
+ */
+declare function R53_FAILOVER(role: "PRIMARY" | "SECONDARY", set_identifier: string): RecordModifier;
+
+/**
+ * `R53_GEO` configures [Route 53 geolocation routing](https://docs.aws.amazon.com/Route53/latest/DeveloperGuide/routing-policy-geo.html) for a record. Route 53 answers with the record for the location of the user.
+ *
+ * `location` is one of:
+ *
+ * * `{continent: "EU"}`: a continent code (`AF`, `AN`, `AS`, `EU`, `NA`, `OC` or `SA`).
+ * * `{country: "FR"}`: a two-letter country code.
+ * * `{country: "US", subdivision: "CA"}`: a country and a subdivision, such as a US state.
+ * * `{country: "*"}`: the default location, for users that match no other record.
+ *
+ * `set_identifier` is a unique string that differentiates this record from other geolocation records with the same name and type. Each location can only be used once for a name and type.
+ *
+ * You can optionally associate a health check using [`R53_HEALTH_CHECK_ID()`](R53_HEALTH_CHECK_ID.md).
+ *
+ * ```javascript
+ * D("example.com", REG_MY_PROVIDER, DnsProvider("ROUTE53"),
+ *   A("www", "1.2.3.4", R53_GEO({continent: "EU"}, "web-europe")),
+ *   A("www", "5.6.7.8", R53_GEO({country: "US", subdivision: "CA"}, "web-california")),
+ *   A("www", "9.9.9.9", R53_GEO({country: "*"}, "web-default")),
+ * );
+ * ```
+ *
+ * @see https://docs.dnscontrol.org/language-reference/record-modifiers/service-provider-specific/amazon-route-53/r53_geo
+ */
+declare function R53_GEO(location: { continent?: string, country?: string, subdivision?: string }, set_identifier: string): RecordModifier;
+
+/**
+ * `R53_GEOPROXIMITY` configures [Route 53 geoproximity routing](https://docs.aws.amazon.com/Route53/latest/DeveloperGuide/routing-policy-geoproximity.html) for a record. Route 53 answers with the record whose resource is closest to the user.
+ *
+ * `location` is the location of the resource, one of:
+ *
+ * * an AWS region, such as `"us-east-1"`.
+ * * `{local_zone_group: "us-east-1-bue-1"}`: an AWS Local Zone group.
+ * * `{latitude: "49.22", longitude: "-74.01"}`: coordinates, for resources outside of AWS.
+ *
+ * `bias` is an integer between -99 and 99 that grows (positive) or shrinks (negative) the area routed to the resource. Use `0` for no bias.
+ *
+ * `set_identifier` is a unique string that differentiates this record from other geoproximity records with the same name and type.
+ *
+ * You can optionally associate a health check using [`R53_HEALTH_CHECK_ID()`](R53_HEALTH_CHECK_ID.md).
+ *
+ * ```javascript
+ * D("example.com", REG_MY_PROVIDER, DnsProvider("ROUTE53"),
+ *   A("www", "1.2.3.4", R53_GEOPROXIMITY("us-east-1", 0, "web-us")),
+ *   A("www", "5.6.7.8", R53_GEOPROXIMITY({latitude: "48.86", longitude: "2.35"}, 20, "web-paris")),
+ * );
+ * ```
+ *
+ * @see https://docs.dnscontrol.org/language-reference/record-modifiers/service-provider-specific/amazon-route-53/r53_geoproximity
+ */
+declare function R53_GEOPROXIMITY(location: string | { local_zone_group: string } | { latitude: string, longitude: string }, bias: number, set_identifier: string): RecordModifier;
+
 /**
  * `R53_HEALTH_CHECK_ID` associates a [Route 53 health check](https://docs.aws.amazon.com/Route53/latest/DeveloperGuide/health-checks-creating.html) with a record. This is typically used with [`R53_WEIGHT()`](R53_WEIGHT.md) so that Route 53 stops routing traffic to unhealthy endpoints.
  *
- * The `health_check_id` is the ID of a Route 53 health check that you create separately (e.g. via the AWS Console, CLI, or Terraform). DNSControl does not manage the health checks themselves, only their association with DNS records.
+ * The `health_check_id` is either the ID of a Route 53 health check that you create separately (e.g. via the AWS Console, CLI, or Terraform), or the name of a [`HEALTH_CHECK()`](../top-level-functions/HEALTH_CHECK.md), which DNSControl creates and keeps up to date.
  *
  * ```javascript
  * D("example.com", REG_MY_PROVIDER, DnsProvider("ROUTE53"),
@@ -3148,10 +3659,57 @@ declare function R53_EVALUATE_TARGET_HEALTH(enabled: boolean): RecordModifier;
  * );
  * ```
  *
+ * ```javascript
+ * HEALTH_CHECK("www", { type: "HTTPS", host: "www.example.com", path: "/health" });
+ *
+ * D("example.com", REG_MY_PROVIDER, DnsProvider("ROUTE53"),
+ *   A("www", "1.2.3.4", R53_FAILOVER("PRIMARY", "primary"), R53_HEALTH_CHECK_ID("www")),
+ *   A("www", "5.6.7.8", R53_FAILOVER("SECONDARY", "secondary")),
+ * );
+ * ```
+ *
  * @see https://docs.dnscontrol.org/language-reference/record-modifiers/service-provider-specific/amazon-route-53/r53_health_check_id
  */
 declare function R53_HEALTH_CHECK_ID(health_check_id: string): RecordModifier;
 
+/**
+ * `R53_LATENCY` configures [Route 53 latency routing](https://docs.aws.amazon.com/Route53/latest/DeveloperGuide/routing-policy-latency.html) for a record. Route 53 answers with the record whose AWS region has the lowest latency for the user.
+ *
+ * `region` is the AWS region of the resource, such as `us-east-1`.
+ *
+ * `set_identifier` is a unique string that differentiates this record from other latency records with the same name and type.
+ *
+ * You can optionally associate a health check using [`R53_HEALTH_CHECK_ID()`](R53_HEALTH_CHECK_ID.md).
+ *
+ * ```javascript
+ * D("example.com", REG_MY_PROVIDER, DnsProvider("ROUTE53"),
+ *   A("www", "1.2.3.4", R53_LATENCY("us-east-1", "web-us")),
+ *   A("www", "5.6.7.8", R53_LATENCY("eu-west-1", "web-eu")),
+ * );
+ * ```
+ *
+ * @see https://docs.dnscontrol.org/language-reference/record-modifiers/service-provider-specific/amazon-route-53/r53_latency
+ */
+declare function R53_LATENCY(region: string, set_identifier: string): RecordModifier;
+
+/**
+ * `R53_MULTIVALUE` configures [Route 53 multivalue answer routing](https://docs.aws.amazon.com/Route53/latest/DeveloperGuide/routing-policy-multivalue.html) for a record. Route 53 answers with up to eight healthy records, chosen at random.
+ *
+ * `set_identifier` is a unique string that differentiates this record from other multivalue records with the same name and type.
+ *
+ * Associate a health check using [`R53_HEALTH_CHECK_ID()`](R53_HEALTH_CHECK_ID.md) so that unhealthy records are not returned.
+ *
+ * ```javascript
+ * D("example.com", REG_MY_PROVIDER, DnsProvider("ROUTE53"),
+ *   A("www", "1.2.3.4", R53_MULTIVALUE("web-1"), R53_HEALTH_CHECK_ID("12345678-1234-1234-1234-123456789012")),
+ *   A("www", "5.6.7.8", R53_MULTIVALUE("web-2"), R53_HEALTH_CHECK_ID("87654321-4321-4321-4321-210987654321")),
+ * );
+ * ```
+ *
+ * @see https://docs.dnscontrol.org/language-reference/record-modifiers/service-provider-specific/amazon-route-53/r53_multivalue
+ */
+declare function R53_MULTIVALUE(set_identifier: string): RecordModifier;
+
 /**
  * `R53_WEIGHT` configures [Route 53 weighted routing](https://docs.aws.amazon.com/Route53/latest/DeveloperGuide/routing-policy-weighted.html) for a record. It distributes traffic across multiple resources based on the weights you assign.
  *
@@ -3293,6 +3851,31 @@ declare function REV(address: string): string;
  */
 declare function REVCOMPAT(rfc: string): string;
 
+/**
+ * `RAW` adds a record of a type that DNSControl does not know, such as a private-use type (65280-65534), using the generic notation of [RFC 3597](https://www.rfc-editor.org/rfc/rfc3597#section-5).
+ *
+ * * `type` is the type number in the form `TYPEnnn`. Types that DNSControl knows (for example `TYPE1`, which is `A`) are rejected: use their own function.
+ * * `rdata` is `\# ` followed by the length of the rdata in bytes and the rdata in hex. Remember to double the backslash in JavaScript strings.
+ *
+ * ```javascript
+ * D("example.com", REG_MY_PROVIDER, DnsProvider(DSP_MY_PROVIDER),
+ *   RAW("inventory", "TYPE65534", "\\# 4 0a000001"),
+ *   RAW("empty", "TYPE65400", "\\# 0"),
+ * );
+ * ```
+ *
+ * The record's type is shown as `TYPEnnn` in previews and zone files:
+ *
+ * ```text
+ * inventory  300  IN  TYPE65534  \# 4 0a000001
+ * ```
+ *
+ * Only providers that accept raw rdata support `RAW`: `BIND`, `AXFRDDNS` and `POWERDNS`.
+ *
+ * @see https://docs.dnscontrol.org/language-reference/domain-modifiers/raw
+ */
+declare function RAW(name: string, type: string, rdata: string, ...modifiers: RecordModifier[]): DomainModifier;
+
 /**
  * `RP` adds an [Responsible Person record](https://www.rfc-editor.org/rfc/rfc1183) to a domain.
  *
@@ -3640,6 +4223,41 @@ declare function SRV(name: string, priority: number, weight: number, port: numbe
  */
 declare function SSHFP(name: string, algorithm: 0 | 1 | 2 | 3 | 4, type: 0 | 1 | 2, value: string, ...modifiers: RecordModifier[]): DomainModifier;
 
+/**
+ * `STEER` configures traffic steering for a record, in a way that does not depend on the DNS provider. Each provider that supports it translates the policy to its own routing feature. Zones using `STEER` are rejected for DNS providers that do not support it (see the `STEER` column of the [provider list](../../provider/index.md)).
+ *
+ * `policy` is an object with:
+ *
+ * * `id`: a unique string that differentiates this record from the other steered records with the same name and type. Records with the same name, type and `id` form one group and must have the same policy.
+ * * exactly one of:
+ *   * `weight`: a non-negative integer. Traffic is distributed between the groups in proportion to their weights.
+ *   * `geo`: the location served by the group: `{continent: "EU"}` (`AF`, `AN`, `AS`, `EU`, `NA`, `OC` or `SA`), `{country: "FR"}`, `{country: "US", subdivision: "CA"}` or `{country: "*"}` for everywhere else. Each location is used once for a name and type.
+ *   * `failover`: `"primary"` or `"secondary"`. The secondary group is used when the primary one is unhealthy. A name and type has at most one of each.
+ * * optionally `health_check`: the name of a [`HEALTH_CHECK()`](../top-level-functions/HEALTH_CHECK.md), or the provider's ID of a health check, for the group.
+ *
+ * All the groups of a name and type must use the same kind of policy, and can not be mixed with records without `STEER`.
+ *
+ * ```javascript
+ * D("example.com", REG_MY_PROVIDER, DnsProvider(DSP_MY_PROVIDER),
+ *   A("www", "1.2.3.4", STEER({id: "east", weight: 70})),
+ *   A("www", "5.6.7.8", STEER({id: "west", weight: 30})),
+ *
+ *   A("app", "1.2.3.4", STEER({id: "europe", geo: {continent: "EU"}})),
+ *   A("app", "5.6.7.8", STEER({id: "default", geo: {country: "*"}})),
+ *
+ *   A("api", "10.0.1.1", STEER({id: "main", failover: "primary", health_check: "12345678-1234-1234-1234-123456789012"})),
+ *   A("api", "10.0.2.1", STEER({id: "backup", failover: "secondary"})),
+ * );
+ * ```
+ *
+ * | Provider | Translation |
+ * | -------- | ----------- |
+ * | [`ROUTE53`](../../provider/route53.md) | Weighted, geolocation and failover routing. The `id` is the set identifier and `health_check` the health check ID. The weight is 0 to 255. |
+ *
+ * @see https://docs.dnscontrol.org/language-reference/record-modifiers/steer
+ */
+declare function STEER(policy: { id: string, weight?: number, geo?: { continent?: string, country?: string, subdivision?: string }, failover?: "primary" | "secondary", health_check?: string }): RecordModifier;
+
 /**
  * `SVCB` adds a [Service Binding record](https://www.rfc-editor.org/rfc/rfc9460) to a domain. The name should be the relative label for the record. Use `@` for the domain apex.
  *
@@ -3771,6 +4389,24 @@ declare function TTL(ttl: Duration): RecordModifier;
  */
 declare function TXT(name: string, contents: string | string[], ...modifiers: RecordModifier[]): DomainModifier;
 
+/**
+ * `URI` adds a [Uniform Resource Identifier record](https://www.rfc-editor.org/rfc/rfc7553) to a domain.
+ *
+ * * `priority` and `weight` are used like those of an [`SRV`](https://docs.dnscontrol.org/language-reference/domain-modifiers/srv) record.
+ * * `target` is the URI.
+ *
+ * ```javascript
+ * D("example.com", REG_MY_PROVIDER, DnsProvider(DSP_MY_PROVIDER),
+ *   URI("_ftp._tcp", 10, 1, "ftp://ftp1.example.com/public"),
+ * );
+ * ```
+ *
+ * Only `BIND`, `AXFRDDNS` and `POWERDNS` support `URI`.
+ *
+ * @see https://docs.dnscontrol.org/language-reference/domain-modifiers/uri
+ */
+declare function URI(name: string, priority: number, weight: number, target: string, ...modifiers: RecordModifier[]): DomainModifier;
+
 /**
  * This is provider specific type of record and not a DNS standard. It may behave differently for each provider that handles it.
  *
@@ -3821,6 +4457,86 @@ declare function URL(name: string, target: string, ...modifiers: RecordModifier[
  */
 declare function URL301(name: string, target: string, ...modifiers: RecordModifier[]): DomainModifier;
 
+/**
+ * `VIEW_MUST_MATCH` requires the records at the given labels to be identical (same types, targets and TTLs) in every view of a [split horizon](../top-level-functions/D.md#split-horizon-dns) zone. If they differ, validation fails and the differences are listed.
+ *
+ * Use it for the records that must never differ between views, such as the apex or the mail servers. It is enough to use it in one view; the labels of all views are combined.
+ *
+ * ```javascript
+ * D("example.com!internal", REG_NONE, DnsProvider(DNS_INSIDE),
+ *   VIEW_MUST_MATCH("@", "mail"),
+ *   A("@", "192.0.2.1"),
+ *   MX("@", 10, "mail"),
+ *   A("mail", "192.0.2.25"),
+ *   A("www", "10.0.0.80"),
+ * );
+ *
+ * D("example.com!external", REG_NONE, DnsProvider(DNS_OUTSIDE),
+ *   A("@", "192.0.2.1"),
+ *   MX("@", 10, "mail"),
+ *   A("mail", "192.0.2.25"),
+ *   A("www", "192.0.2.80"),
+ * );
+ * ```
+ *
+ * Labels are short names (`"@"`, `"www"`), as in the records. A label that has no records in any view matches trivially.
+ *
+ * To see all the differences between the views of a zone, use [`dnscontrol preview --compare-views`](../../commands/preview-push.md).
+ *
+ * @see https://docs.dnscontrol.org/language-reference/domain-modifiers/view_must_match
+ */
+declare function VIEW_MUST_MATCH(...labels: string[]): DomainModifier;
+
+/**
+ * `VIEW_ONLY` keeps a record only in the views of a [split horizon](../top-level-functions/D.md#split-horizon-dns) zone with the given tags. It is dropped from the other views.
+ *
+ * This makes it safe to share a list of records between views:
+ *
+ * ```javascript
+ * var COMMON = [
+ *   A("@", "192.0.2.1"),
+ *   A("db", "10.0.0.5", VIEW_ONLY("internal")),
+ *   A("vpn", "10.0.0.6", VIEW_ONLY("internal", "vpn")),
+ * ];
+ *
+ * D("example.com!internal", REG_NONE, DnsProvider(DNS_INSIDE), COMMON);
+ * D("example.com!external", REG_NONE, DnsProvider(DNS_OUTSIDE), COMMON);
+ * D("example.com!vpn", REG_NONE, DnsProvider(DNS_VPN), COMMON);
+ * ```
+ *
+ * Here `db` is only in `example.com!internal`, and `vpn` in `example.com!internal` and `example.com!vpn`.
+ *
+ * `VIEW_ONLY` also guards against leaks. Validation fails if:
+ *
+ * * another view has the same record (same label, type and target), for example because it was also added with [`D_EXTEND`](../top-level-functions/D_EXTEND.md);
+ * * a tag does not name a view of the zone (for example, a typo).
+ *
+ * @see https://docs.dnscontrol.org/language-reference/record-modifiers/view_only
+ */
+declare function VIEW_ONLY(...tags: string[]): RecordModifier;
+
+/**
+ * `ZONEMD` adds a [Message Digest for DNS Zones record](https://www.rfc-editor.org/rfc/rfc8976) to a domain.
+ *
+ * * `serial` is the SOA serial of the zone the digest was computed for.
+ * * `scheme` is 1 (SIMPLE).
+ * * `hashalgorithm` is 1 (SHA-384) or 2 (SHA-512).
+ * * `digest` is the digest in hex.
+ *
+ * ```javascript
+ * D("example.com", REG_MY_PROVIDER, DnsProvider(DSP_MY_PROVIDER),
+ *   ZONEMD("@", 2018031900, 1, 1, "c68090d90a7aed716bc459f9340e3d7c1370d4d24b7e2fc3a1ddc0b9a87153b9a9713b3c9ae5cc27777f98b8e730044c"),
+ * );
+ * ```
+ *
+ * A `ZONEMD` record is only useful if its digest matches the zone. It is normally added by the software that signs or publishes the zone, not written by hand.
+ *
+ * Only `BIND`, `AXFRDDNS` and `POWERDNS` support `ZONEMD`.
+ *
+ * @see https://docs.dnscontrol.org/language-reference/domain-modifiers/zonemd
+ */
+declare function ZONEMD(name: string, serial: number, scheme: number, hashalgorithm: number, digest: string, ...modifiers: RecordModifier[]): DomainModifier;
+
 /**
  * `getConfiguredDomains` getConfiguredDomains is a helper function that returns the domain names
  * configured at the time the function is called. Calling this function early or later in
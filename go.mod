@@ -108,6 +108,7 @@ require (
 	github.com/urfave/cli/v3 v3.10.0
 	github.com/vercel/terraform-provider-vercel v1.14.1
 	github.com/vultr/govultr/v2 v2.17.2
+	github.com/yuin/gopher-lua v1.1.1
 	golang.org/x/text v0.38.0
 	golang.org/x/time v0.15.0
 	gopkg.in/yaml.v3 v3.0.1
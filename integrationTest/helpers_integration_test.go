@@ -216,7 +216,7 @@ func makeChanges(t *testing.T, prv providers.DNSServiceProvider, dc *models.Doma
 		//fmt.Printf("DEBUG: Running test %q: Names %q %q %q\n", desc, dom.Name, dom.NameRaw, dom.NameUnicode)
 
 		// get and run corrections for first time
-		_, corrections, actualChangeCount, err := zonerecs.CorrectZoneRecords(prv, dom)
+		_, corrections, actualChangeCount, err := zonerecs.CorrectZoneRecords(prv, dom, *providerFlag)
 		if err != nil {
 			t.Fatal(fmt.Errorf("runTests: %w", err))
 		}
@@ -249,7 +249,7 @@ func makeChanges(t *testing.T, prv providers.DNSServiceProvider, dc *models.Doma
 		}
 
 		// run a second time and expect zero corrections
-		_, corrections, actualChangeCount, err = zonerecs.CorrectZoneRecords(prv, dom2)
+		_, corrections, actualChangeCount, err = zonerecs.CorrectZoneRecords(prv, dom2, *providerFlag)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -614,6 +614,17 @@ func r53weighted(name, target, rtype string, weight int, setID string) *models.R
 	return r
 }
 
+// r53routed returns a record with a Route 53 routing policy. meta is a list
+// of metadata key/value pairs.
+func r53routed(name, target, rtype, setID string, meta ...string) *models.RecordConfig {
+	r := makeRec(name, target, rtype)
+	r.Metadata = map[string]string{"r53_set_identifier": setID}
+	for i := 0; i+1 < len(meta); i += 2 {
+		r.Metadata[meta[i]] = meta[i+1]
+	}
+	return r
+}
+
 func rp(name string, m, t string) *models.RecordConfig {
 	rec, err := rtypecontrol.NewRecordConfigFromRaw(rtypecontrol.FromRawOpts{
 		Type: "RP",
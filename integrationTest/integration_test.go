@@ -1250,6 +1250,37 @@ func makeTests() []*TestGroup {
 			),
 		),
 
+		// Route 53 latency, geolocation, geoproximity, failover and
+		// multivalue routing
+		testgroup("R53_ROUTING",
+			only("ROUTE53"),
+			tc("create latency records",
+				r53routed("lat", "1.2.3.4", "A", "us", "r53_region", "us-east-1"),
+				r53routed("lat", "5.6.7.8", "A", "eu", "r53_region", "eu-west-1"),
+			),
+			tc("change region",
+				r53routed("lat", "1.2.3.4", "A", "us", "r53_region", "us-west-2"),
+				r53routed("lat", "5.6.7.8", "A", "eu", "r53_region", "eu-west-1"),
+			),
+			tc("create geolocation records",
+				r53routed("geo", "1.2.3.4", "A", "europe", "r53_geo_continent", "EU"),
+				r53routed("geo", "5.6.7.8", "A", "california", "r53_geo_country", "US", "r53_geo_subdivision", "CA"),
+				r53routed("geo", "9.10.11.12", "A", "default", "r53_geo_country", "*"),
+			),
+			tc("create geoproximity records",
+				r53routed("prox", "1.2.3.4", "A", "east", "r53_geoproximity_region", "us-east-1", "r53_geoproximity_bias", "10"),
+				r53routed("prox", "5.6.7.8", "A", "paris", "r53_geoproximity_coordinates", "48.86,2.35", "r53_geoproximity_bias", "0"),
+			),
+			tc("create failover records",
+				r53routed("fo", "1.2.3.4", "A", "main", "r53_failover", "PRIMARY"),
+				r53routed("fo", "5.6.7.8", "A", "backup", "r53_failover", "SECONDARY"),
+			),
+			tc("create multivalue records",
+				r53routed("mv", "1.2.3.4", "A", "one", "r53_multivalue", "true"),
+				r53routed("mv", "5.6.7.8", "A", "two", "r53_multivalue", "true"),
+			),
+		),
+
 		// R53_WEIGHT_HEALTH_CHECK: Not included as an integration test because
 		// health checks are external AWS resources that must be pre-provisioned.
 		// The R53_HEALTH_CHECK_ID modifier is tested implicitly through the
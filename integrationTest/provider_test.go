@@ -33,7 +33,7 @@ func TestDualProviders(t *testing.T) {
 	run := func() {
 		dom, _ := dc.Copy()
 
-		rs, cs, _, err := zonerecs.CorrectZoneRecords(p, dom)
+		rs, cs, _, err := zonerecs.CorrectZoneRecords(p, dom, *providerFlag)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -58,7 +58,7 @@ func TestDualProviders(t *testing.T) {
 	run()
 	// run again to make sure no corrections
 	t.Log("Running again to ensure stability")
-	rs, cs, actualChangeCount, err := zonerecs.CorrectZoneRecords(p, dc)
+	rs, cs, actualChangeCount, err := zonerecs.CorrectZoneRecords(p, dc, *providerFlag)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -150,7 +150,7 @@ func TestDuplicateNameservers(t *testing.T) {
 	run := func(expectedChangeCount int, msg string, ignoreExpected bool) {
 		dom, _ := dc.Copy()
 
-		rs, cs, actualChangeCount, err := zonerecs.CorrectZoneRecords(p, dom)
+		rs, cs, actualChangeCount, err := zonerecs.CorrectZoneRecords(p, dom, *providerFlag)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+
+	"github.com/DNSControl/dnscontrol/v4/pkg/ipam"
 )
 
 // DefaultTTL is applied to any DNS record without an explicit TTL.
@@ -15,9 +17,13 @@ type DNSConfig struct {
 	Registrars         []*RegistrarConfig            `json:"registrars"`
 	DNSProviders       []*DNSProviderConfig          `json:"dns_providers"`
 	Domains            []*DomainConfig               `json:"domains"`
+	HealthChecks       []*HealthCheck                `json:"health_checks,omitempty"`
 	RegistrarsByName   map[string]*RegistrarConfig   `json:"-"`
 	DNSProvidersByName map[string]*DNSProviderConfig `json:"-"`
 	SkipRecordAudit    bool                          `json:"skiprecordaudit,omitempty"`
+
+	// IPAM has the addresses of ALLOC(). push saves them in the lock file.
+	IPAM *ipam.Allocator `json:"-"`
 }
 
 // FindDomain returns the *DomainConfig for domain query in config.
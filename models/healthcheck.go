@@ -0,0 +1,29 @@
+package models
+
+import "fmt"
+
+// HealthCheck describes a HEALTH_CHECK(): a health check that the DNS
+// providers with the CanUseHealthChecks capability create, update and
+// delete. Records refer to it by name.
+type HealthCheck struct {
+	Name             string `json:"name"`
+	Type             string `json:"type"`                        // HTTP, HTTPS or TCP.
+	Host             string `json:"host,omitempty"`              // The host name to check (and the HTTP Host header).
+	IP               string `json:"ip,omitempty"`                // The IP address to check, if not that of Host.
+	Port             int    `json:"port,omitempty"`              // The port to check.
+	Path             string `json:"path,omitempty"`              // The path to request (HTTP and HTTPS).
+	Interval         int    `json:"interval,omitempty"`          // The number of seconds between checks.
+	FailureThreshold int    `json:"failure_threshold,omitempty"` // The number of failed checks before the endpoint is unhealthy.
+}
+
+// String returns a description of the health check, for messages.
+func (hc *HealthCheck) String() string {
+	target := hc.Host
+	if hc.IP != "" {
+		if target != "" {
+			target += "@"
+		}
+		target += hc.IP
+	}
+	return fmt.Sprintf("%s %s:%d%s every %ds, unhealthy after %d failures", hc.Type, target, hc.Port, hc.Path, hc.Interval, hc.FailureThreshold)
+}
@@ -361,6 +361,9 @@ func (rc *RecordConfig) ToComparableNoTTL() string {
 func (rc *RecordConfig) ToRR() dnsv1.RR {
 	// Function is not valid on pseudo-types.
 	rdtype, ok := dnsv1.StringToType[rc.Type]
+	if !ok {
+		rdtype, ok = GenericType(rc.Type)
+	}
 	if !ok {
 		log.Fatalf("No such DNS type as (%#v)\n", rc.Type)
 	}
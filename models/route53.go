@@ -0,0 +1,30 @@
+package models
+
+// R53RoutingKey is a metadata key of a Route 53 routing policy.
+type R53RoutingKey struct {
+	Key    string // The metadata key.
+	Policy string // The routing policy that the key selects, or "" for any policy.
+	Desc   string // The plural used in error messages.
+}
+
+// R53RoutingKeys are the metadata keys of the Route 53 routing policies, in
+// the order they are compared. The route53 provider and the validation of
+// dnsconfig.js both use this list.
+var R53RoutingKeys = []R53RoutingKey{
+	{"r53_weight", "weighted", "weights"},
+	{"r53_region", "latency", "regions"},
+	{"r53_geo_continent", "geolocation", "continents"},
+	{"r53_geo_country", "geolocation", "countries"},
+	{"r53_geo_subdivision", "geolocation", "subdivisions"},
+	{"r53_geoproximity_region", "geoproximity", "geoproximity regions"},
+	{"r53_geoproximity_local_zone_group", "geoproximity", "geoproximity local zone groups"},
+	{"r53_geoproximity_coordinates", "geoproximity", "geoproximity coordinates"},
+	{"r53_geoproximity_bias", "geoproximity", "geoproximity biases"},
+	{"r53_failover", "failover", "failover roles"},
+	{"r53_multivalue", "multivalue", "multivalue settings"},
+	{"r53_health_check_id", "", "health check IDs"},
+}
+
+// Continents are the continent codes of geolocation routing. STEER() accepts
+// them and the route53 provider passes them on unchanged.
+var Continents = []string{"AF", "AN", "AS", "EU", "NA", "OC", "SA"}
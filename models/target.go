@@ -48,7 +48,8 @@ func (rc *RecordConfig) GetTargetCombinedFunc(encodeFn func(s string) string) st
 // code depends on the bugs. Use Get GetTargetCombinedFunc() instead.
 func (rc *RecordConfig) GetTargetCombined() string {
 	// Pseudo records:
-	if _, ok := dnsv1.StringToType[rc.Type]; !ok {
+	_, generic := GenericType(rc.Type)
+	if _, ok := dnsv1.StringToType[rc.Type]; !ok && !generic {
 		switch rc.Type { // #rtype_variations
 		case "LUA":
 			return rc.luaCombined()
@@ -1,5 +1,10 @@
 package models
 
+import (
+	"strconv"
+	"strings"
+)
+
 // MakeUnknown turns an RecordConfig into an UNKNOWN type.
 func MakeUnknown(rc *RecordConfig, rtype string, contents string, origin string) error {
 	rc.Type = "UNKNOWN"
@@ -8,3 +13,27 @@ func MakeUnknown(rc *RecordConfig, rtype string, contents string, origin string)
 
 	return nil
 }
+
+// GenericType returns the type number of a record type written in the
+// generic notation of RFC 3597 section 5 ("TYPE65534"). ok is false if t is
+// not in that notation.
+func GenericType(t string) (n uint16, ok bool) {
+	s, found := strings.CutPrefix(t, "TYPE")
+	if !found || s == "" || s[0] == '+' || s[0] == '-' {
+		return 0, false
+	}
+	i, err := strconv.ParseUint(s, 10, 16)
+	if err != nil {
+		return 0, false
+	}
+	return uint16(i), true
+}
+
+// GenericRdata returns rdata, in hex, in the generic notation of RFC 3597
+// section 5 ("\# 4 0a000001").
+func GenericRdata(rdata string) string {
+	if rdata == "" {
+		return `\# 0`
+	}
+	return `\# ` + strconv.Itoa(len(rdata)/2) + " " + strings.ToLower(rdata)
+}
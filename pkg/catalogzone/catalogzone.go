@@ -0,0 +1,146 @@
+// Package catalogzone generates catalog zones (RFC 9432).
+//
+// A catalog zone lists the member zones that a set of secondaries should
+// serve. When a provider maintains one (see providers.CatalogZoner), its
+// records are generated from dnsconfig.js: every zone that uses the
+// provider is a member. Adding a D() to dnsconfig.js thus provisions the
+// zone on the secondaries that consume the catalog.
+package catalogzone
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/DNSControl/dnscontrol/v4/models"
+	"github.com/DNSControl/dnscontrol/v4/pkg/providers"
+	dnsv1 "github.com/miekg/dns"
+)
+
+// Metadata keys that set the properties of a member zone. They are set in
+// dnsconfig.js: D("example.com", REG, DnsProvider(DSP), {catalog_group: "x"}).
+const (
+	// MetaGroup is the key of the "group" property (RFC 9432 section 4.4.2).
+	MetaGroup = "catalog_group"
+	// MetaCoo is the key of the "coo" (change of ownership) property
+	// (RFC 9432 section 4.4.1): the catalog zone the member is moving to.
+	MetaCoo = "catalog_coo"
+)
+
+// schemaVersion is the catalog zone schema version (RFC 9432 section 4.2).
+const schemaVersion = "2"
+
+// Member is a zone listed in a catalog zone.
+type Member struct {
+	Zone  string // Without the trailing dot.
+	Group string // Optional.
+	Coo   string // Optional. Without the trailing dot.
+}
+
+// MemberLabel returns the unique label of a member zone: the hex SHA-1 of
+// the zone's name in canonical wire format. It depends only on the name,
+// so that a member keeps its label (and thus its state on the secondaries)
+// when other members are added or removed.
+func MemberLabel(zone string) string {
+	name := dnsv1.CanonicalName(zone)
+	buf := make([]byte, 255)
+	n, err := dnsv1.PackDomainName(name, buf, 0, nil, false)
+	if err != nil {
+		// Not a valid name; hash the text instead so that the result is
+		// still stable.
+		buf, n = []byte(name), len(name)
+	}
+	sum := sha1.Sum(buf[:n])
+	return hex.EncodeToString(sum[:])
+}
+
+// Records returns the records of the catalog zone listing members, other
+// than the SOA and the NS records. The apex NS should be "invalid." (see
+// Generate).
+func Records(catalog string, members []Member) models.Records {
+	var recs models.Records
+	add := func(label, rtype, target string) {
+		rc := &models.RecordConfig{Type: rtype}
+		rc.SetLabel(label, catalog)
+		if rtype == "TXT" {
+			_ = rc.SetTargetTXT(target)
+		} else {
+			_ = rc.SetTarget(target)
+		}
+		recs = append(recs, rc)
+	}
+
+	add("version", "TXT", schemaVersion)
+	members = slices.Clone(members)
+	slices.SortFunc(members, func(a, b Member) int { return strings.Compare(a.Zone, b.Zone) })
+	for _, m := range members {
+		label := MemberLabel(m.Zone) + ".zones"
+		add(label, "PTR", strings.TrimSuffix(m.Zone, ".")+".")
+		if m.Group != "" {
+			add("group."+label, "TXT", m.Group)
+		}
+		if m.Coo != "" {
+			add("coo."+label, "PTR", strings.TrimSuffix(m.Coo, ".")+".")
+		}
+	}
+	return recs
+}
+
+// Generate fills in the catalog zones of the providers that maintain one.
+// The members of a catalog zone are the zones that use the same provider
+// instance. The catalog zone itself must be in dnsconfig.js, using that
+// provider.
+//
+// It must be called after the providers are initialized and before the
+// configuration is normalized.
+func Generate(cfg *models.DNSConfig) error {
+	type catalog struct {
+		name    string
+		members []Member
+		seen    map[string]bool
+	}
+	catalogs := map[string]*catalog{} // Key: provider instance name.
+
+	for _, dc := range cfg.Domains {
+		for _, pi := range dc.DNSProviderInstances {
+			cz, ok := pi.Driver.(providers.CatalogZoner)
+			if !ok || cz.CatalogZone() == "" {
+				continue
+			}
+			c := catalogs[pi.Name]
+			if c == nil {
+				c = &catalog{name: strings.ToLower(strings.TrimSuffix(cz.CatalogZone(), ".")), seen: map[string]bool{}}
+				catalogs[pi.Name] = c
+			}
+			// Split horizon views of a zone are one member.
+			if dc.Name == c.name || c.seen[dc.Name] {
+				continue
+			}
+			c.seen[dc.Name] = true
+			c.members = append(c.members, Member{
+				Zone:  dc.Name,
+				Group: dc.Metadata[MetaGroup],
+				Coo:   dc.Metadata[MetaCoo],
+			})
+		}
+	}
+
+	for pname, c := range catalogs {
+		i := slices.IndexFunc(cfg.Domains, func(dc *models.DomainConfig) bool {
+			return dc.Name == c.name && slices.ContainsFunc(dc.DNSProviderInstances, func(pi *models.DNSProviderInstance) bool {
+				return pi.Name == pname
+			})
+		})
+		if i == -1 {
+			return fmt.Errorf("provider %q maintains the catalog zone %q, but it is not in dnsconfig.js: add D(%q, REG_NONE, DnsProvider(...%s...))", pname, c.name, c.name, pname)
+		}
+		dc := cfg.Domains[i]
+		dc.Records = append(dc.Records, Records(c.name, c.members)...)
+		if !slices.ContainsFunc(dc.Nameservers, func(ns *models.Nameserver) bool { return ns.Name == "invalid" }) {
+			dc.Nameservers = append(dc.Nameservers, &models.Nameserver{Name: "invalid"})
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package catalogzone
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/DNSControl/dnscontrol/v4/models"
+)
+
+// stubDriver is a DNS provider that maintains a catalog zone.
+type stubDriver struct {
+	models.DNSProvider
+	catalog string
+}
+
+func (s stubDriver) CatalogZone() string { return s.catalog }
+
+func TestMemberLabel(t *testing.T) {
+	// The label must not change between releases: secondaries use it to
+	// track the member zone.
+	if got, want := MemberLabel("example.com"), "c5e4b4da1e5a620ddaa3635e55c3732a5b49c7f4"; got != want {
+		t.Errorf("MemberLabel: got %q, want %q", got, want)
+	}
+	if MemberLabel("Example.COM.") != MemberLabel("example.com") {
+		t.Error("MemberLabel is not case-insensitive")
+	}
+	if MemberLabel("example.com") == MemberLabel("example.net") {
+		t.Error("MemberLabel collision")
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	withCatalog := &models.DNSProviderInstance{Driver: stubDriver{catalog: "catalog.example."}}
+	withCatalog.Name = "bind"
+	without := &models.DNSProviderInstance{Driver: stubDriver{}}
+	without.Name = "other"
+
+	cat := &models.DomainConfig{Name: "catalog.example", DNSProviderInstances: []*models.DNSProviderInstance{withCatalog}}
+	cfg := &models.DNSConfig{Domains: []*models.DomainConfig{
+		cat,
+		{Name: "b.example", DNSProviderInstances: []*models.DNSProviderInstance{withCatalog}, Metadata: map[string]string{MetaGroup: "signed"}},
+		{Name: "a.example", DNSProviderInstances: []*models.DNSProviderInstance{withCatalog}, Metadata: map[string]string{MetaCoo: "new-catalog.example"}},
+		{Name: "a.example", Tag: "inside", DNSProviderInstances: []*models.DNSProviderInstance{withCatalog}},
+		{Name: "c.example", DNSProviderInstances: []*models.DNSProviderInstance{without}},
+	}}
+
+	if err := Generate(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, rc := range cat.Records {
+		got = append(got, rc.GetLabel()+" "+rc.Type+" "+rc.GetTargetCombined())
+	}
+	la, lb := MemberLabel("a.example"), MemberLabel("b.example")
+	want := []string{
+		`version TXT "2"`,
+		la + ".zones PTR a.example.",
+		"coo." + la + ".zones PTR new-catalog.example.",
+		lb + ".zones PTR b.example.",
+		"group." + lb + `.zones TXT "signed"`,
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("got\n%q\nwant\n%q", got, want)
+	}
+	if len(cat.Nameservers) != 1 || cat.Nameservers[0].Name != "invalid" {
+		t.Errorf("got nameservers %v, want invalid", cat.Nameservers)
+	}
+}
+
+func TestGenerate_MissingCatalog(t *testing.T) {
+	pi := &models.DNSProviderInstance{Driver: stubDriver{catalog: "catalog.example"}}
+	pi.Name = "bind"
+	cfg := &models.DNSConfig{Domains: []*models.DomainConfig{
+		{Name: "a.example", DNSProviderInstances: []*models.DNSProviderInstance{pi}},
+	}}
+	if err := Generate(cfg); err == nil {
+		t.Error("expected an error")
+	}
+}
@@ -3,6 +3,9 @@
 // their environment variable equivalents. To reference an environment variable in your json file, simply use values in this format:
 //
 //	"key"="$ENV_VAR_NAME"
+//
+// Values can also refer to a secret backend, such as "file:///run/secrets/cf"
+// or "sops://creds.enc.json#cloudflare.apitoken". See RegisterSecretResolver.
 package credsfile
 
 import (
@@ -19,7 +22,8 @@ import (
 	"github.com/google/shlex"
 )
 
-// LoadProviderConfigs will open or execute the specified file name, and parse its contents. It will replace environment variables it finds if any value matches $[A-Za-z_-0-9]+.
+// LoadProviderConfigs will open or execute the specified file name, and parse its contents. It will replace environment variables it finds if any value matches $[A-Za-z_-0-9]+,
+// then the values that refer to a secret backend.
 func LoadProviderConfigs(fname string) (map[string]map[string]string, error) {
 	results := map[string]map[string]string{}
 
@@ -54,6 +58,9 @@ func LoadProviderConfigs(fname string) (map[string]map[string]string, error) {
 	if err = replaceEnvVars(results); err != nil {
 		return nil, err
 	}
+	if err = replaceSecrets(results); err != nil {
+		return nil, err
+	}
 
 	// For backwards compatibility, insert NONE and BIND entries if
 	// they do not exist. These are the only providers that previously
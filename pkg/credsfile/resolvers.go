@@ -0,0 +1,198 @@
+package credsfile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/shlex"
+)
+
+// The built-in secret backends. Resolvers are called one at a time (see
+// resolveSecret), so their caches need no locking.
+
+func init() {
+	RegisterSecretResolver("file", resolveFile)
+	RegisterSecretResolver("cmd", resolveCmd)
+	RegisterSecretResolver("sops", resolveSops)
+	RegisterSecretResolver("vault", resolveVault)
+}
+
+// resolveFile reads a secret from a file: "file:///run/secrets/cf". With a
+// fragment ("file:///etc/dns.json#cloudflare.apitoken") the file is parsed
+// as JSON and the fragment selects the value.
+func resolveFile(ref string) (string, error) {
+	fname, path, _ := strings.Cut(ref, "#")
+	dat, err := os.ReadFile(fname)
+	if err != nil {
+		return "", err
+	}
+	if path == "" {
+		return trimNewline(string(dat)), nil
+	}
+	var doc any
+	if err := json.Unmarshal(dat, &doc); err != nil {
+		return "", fmt.Errorf("parsing %s: %w", fname, err)
+	}
+	return jsonPath(doc, path)
+}
+
+// resolveCmd runs a command and returns its output: "cmd://pass show dns/r53".
+func resolveCmd(ref string) (string, error) {
+	args, err := shlex.Split(ref)
+	if err != nil {
+		return "", err
+	}
+	if len(args) == 0 {
+		return "", errors.New("no command")
+	}
+	out, err := exec.Command(args[0], args[1:]...).Output()
+	if err != nil {
+		return "", commandError(args[0], err)
+	}
+	return trimNewline(string(out)), nil
+}
+
+// sopsDocuments caches the files decrypted by resolveSops, so that each file
+// is decrypted once per run however many values it provides.
+var sopsDocuments = map[string]any{}
+
+// resolveSops decrypts a file with sops and returns one of its values:
+// "sops://creds.enc.json#cloudflare.apitoken". Any format that sops can
+// decrypt (JSON, YAML, dotenv, INI) can be used.
+func resolveSops(ref string) (string, error) {
+	fname, path, _ := strings.Cut(ref, "#")
+	if path == "" {
+		return "", errors.New("missing #path of the value in the decrypted file")
+	}
+	doc, ok := sopsDocuments[fname]
+	if !ok {
+		out, err := exec.Command("sops", "--decrypt", "--output-type", "json", fname).Output()
+		if err != nil {
+			return "", commandError("sops", err)
+		}
+		if err := json.Unmarshal(out, &doc); err != nil {
+			return "", fmt.Errorf("parsing decrypted %s: %w", fname, err)
+		}
+		sopsDocuments[fname] = doc
+	}
+	return jsonPath(doc, path)
+}
+
+// resolveVault reads a field of a secret in a HashiCorp Vault (or OpenBao)
+// KV secrets engine: "vault://kv/dns#token" is the field "token" of the
+// secret "dns" in the engine mounted at "kv". Both versions of the KV engine
+// are supported. The server and token are taken from VAULT_ADDR and
+// VAULT_TOKEN (or ~/.vault-token), like the vault CLI.
+func resolveVault(ref string) (string, error) {
+	secret, field, _ := strings.Cut(ref, "#")
+	mount, path, ok := strings.Cut(secret, "/")
+	if !ok || field == "" {
+		return "", errors.New(`expected "vault://mount/path#field"`)
+	}
+	addr := strings.TrimSuffix(os.Getenv("VAULT_ADDR"), "/")
+	if addr == "" {
+		return "", errors.New("VAULT_ADDR is not set")
+	}
+	token := os.Getenv("VAULT_TOKEN")
+	if token == "" {
+		home, _ := os.UserHomeDir()
+		if dat, err := os.ReadFile(filepath.Join(home, ".vault-token")); err == nil {
+			token = strings.TrimSpace(string(dat))
+		}
+	}
+
+	// KV version 2 first, then version 1.
+	var resp struct {
+		Data map[string]any `json:"data"`
+	}
+	found, err := vaultGet(addr+"/v1/"+mount+"/data/"+path, token, &resp)
+	if err != nil {
+		return "", err
+	}
+	data := resp.Data
+	if found {
+		data, _ = resp.Data["data"].(map[string]any)
+	} else {
+		found, err = vaultGet(addr+"/v1/"+mount+"/"+path, token, &resp)
+		if err != nil {
+			return "", err
+		}
+		if !found {
+			return "", fmt.Errorf("secret %q not found", secret)
+		}
+		data = resp.Data
+	}
+	return jsonPath(data, field)
+}
+
+// vaultGet reads a Vault API path into v. found is false if there is no
+// secret at that path.
+func vaultGet(url, token string, v any) (found bool, err error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if ns := os.Getenv("VAULT_NAMESPACE"); ns != "" {
+		req.Header.Set("X-Vault-Namespace", ns)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return false, err
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return false, nil
+	case resp.StatusCode != http.StatusOK:
+		return false, fmt.Errorf("vault: %s", resp.Status)
+	}
+	return true, json.Unmarshal(body, v)
+}
+
+// jsonPath returns the value at a dotted path ("cloudflare.apitoken") in a
+// decoded JSON document.
+func jsonPath(doc any, path string) (string, error) {
+	v := doc
+	for part := range strings.SplitSeq(path, ".") {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return "", fmt.Errorf("%q not found", path)
+		}
+		if v, ok = m[part]; !ok {
+			return "", fmt.Errorf("%q not found", path)
+		}
+	}
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case float64, bool:
+		return fmt.Sprint(v), nil
+	default:
+		return "", fmt.Errorf("%q is not a string", path)
+	}
+}
+
+// commandError adds the standard error output of a failed command to err.
+func commandError(name string, err error) error {
+	var ee *exec.ExitError
+	if errors.As(err, &ee) && len(ee.Stderr) != 0 {
+		return fmt.Errorf("%s: %w: %s", name, err, strings.TrimSpace(string(ee.Stderr)))
+	}
+	return fmt.Errorf("%s: %w", name, err)
+}
+
+func trimNewline(s string) string {
+	return strings.TrimSuffix(strings.TrimSuffix(s, "\n"), "\r")
+}
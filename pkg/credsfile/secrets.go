@@ -0,0 +1,86 @@
+package credsfile
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/DNSControl/dnscontrol/v4/pkg/printer"
+)
+
+// SecretResolver returns the secret that ref refers to. ref is the part of
+// the value after "scheme://". For example, the value
+// "sops://creds.enc.json#cloudflare.apitoken" calls the "sops" resolver with
+// ref "creds.enc.json#cloudflare.apitoken".
+type SecretResolver func(ref string) (string, error)
+
+var secretResolvers = map[string]SecretResolver{}
+
+// RegisterSecretResolver makes a secret backend available to creds.json
+// values of the form "scheme://ref".
+func RegisterSecretResolver(scheme string, r SecretResolver) {
+	if _, ok := secretResolvers[scheme]; ok {
+		panic(fmt.Sprintf("secret resolver %q registered twice", scheme))
+	}
+	secretResolvers[scheme] = r
+}
+
+// secretCache holds the secrets resolved during this run, so that a value
+// used by several creds.json entries is only fetched once.
+var secretCache = struct {
+	sync.Mutex
+	m map[string]string
+}{m: map[string]string{}}
+
+// secretRef splits a creds.json value into a registered scheme and the
+// reference. ok is false if the value does not refer to a secret backend.
+func secretRef(v string) (scheme, ref string, ok bool) {
+	scheme, ref, found := strings.Cut(v, "://")
+	if !found {
+		return "", "", false
+	}
+	if _, ok := secretResolvers[scheme]; !ok {
+		return "", "", false
+	}
+	return scheme, ref, true
+}
+
+// resolveSecret returns the secret that value refers to.
+func resolveSecret(scheme, ref string) (string, error) {
+	key := scheme + "://" + ref
+	secretCache.Lock()
+	defer secretCache.Unlock()
+	if s, ok := secretCache.m[key]; ok {
+		return s, nil
+	}
+	s, err := secretResolvers[scheme](ref)
+	if err != nil {
+		return "", err
+	}
+	secretCache.m[key] = s
+	printer.AddSecret(s)
+	return s, nil
+}
+
+// replaceSecrets replaces the values that refer to a secret backend with the
+// secret. Errors name the entry and subkey, never the secret.
+func replaceSecrets(m map[string]map[string]string) error {
+	// Sorted, so that errors are reported in a stable order.
+	for _, name := range slices.Sorted(maps.Keys(m)) {
+		keys := m[name]
+		for _, k := range slices.Sorted(maps.Keys(keys)) {
+			scheme, ref, ok := secretRef(keys[k])
+			if !ok {
+				continue
+			}
+			s, err := resolveSecret(scheme, ref)
+			if err != nil {
+				return fmt.Errorf("creds.json entry %q: resolving %q: %w", name, k, err)
+			}
+			keys[k] = s
+		}
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package credsfile
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/DNSControl/dnscontrol/v4/pkg/printer"
+)
+
+func TestReplaceSecrets(t *testing.T) {
+	dir := t.TempDir()
+	token := filepath.Join(dir, "token")
+	if err := os.WriteFile(token, []byte("file-secret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	doc := filepath.Join(dir, "creds.json")
+	if err := os.WriteFile(doc, []byte(`{"cloudflare": {"apitoken": "json-secret", "port": 53}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	m := map[string]map[string]string{
+		"cf": {
+			"TYPE":      "CLOUDFLAREAPI",
+			"apitoken":  "file://" + token,
+			"accountid": "file://" + doc + "#cloudflare.apitoken",
+			"port":      "file://" + doc + "#cloudflare.port",
+		},
+		"r53": {
+			"TYPE":    "ROUTE53",
+			"KeyId":   "cmd://echo 'cmd secret'",
+			"website": "https://example.com", // Not a registered scheme.
+		},
+	}
+	if err := replaceSecrets(m); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]map[string]string{
+		"cf": {
+			"TYPE":      "CLOUDFLAREAPI",
+			"apitoken":  "file-secret",
+			"accountid": "json-secret",
+			"port":      "53",
+		},
+		"r53": {
+			"TYPE":    "ROUTE53",
+			"KeyId":   "cmd secret",
+			"website": "https://example.com",
+		},
+	}
+	for name, keys := range want {
+		for k, v := range keys {
+			if got := m[name][k]; got != v {
+				t.Errorf("%s.%s = %q, want %q", name, k, got, v)
+			}
+		}
+	}
+
+	if got := printer.Redact("token=file-secret"); got != "token=[REDACTED]" {
+		t.Errorf("secret not redacted: %q", got)
+	}
+}
+
+func TestReplaceSecrets_cache(t *testing.T) {
+	calls := 0
+	RegisterSecretResolver("testcount", func(ref string) (string, error) {
+		calls++
+		return "counted-" + ref, nil
+	})
+	defer delete(secretResolvers, "testcount")
+
+	m := map[string]map[string]string{
+		"a": {"key": "testcount://x"},
+		"b": {"key": "testcount://x", "other": "testcount://y"},
+	}
+	if err := replaceSecrets(m); err != nil {
+		t.Fatal(err)
+	}
+	if m["a"]["key"] != "counted-x" || m["b"]["key"] != "counted-x" || m["b"]["other"] != "counted-y" {
+		t.Errorf("unexpected values: %v", m)
+	}
+	if calls != 2 {
+		t.Errorf("resolver called %d times, want 2", calls)
+	}
+}
+
+func TestReplaceSecrets_error(t *testing.T) {
+	m := map[string]map[string]string{
+		"cf": {"apitoken": "file:///nonexistent/dnscontrol-secret"},
+	}
+	err := replaceSecrets(m)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if !strings.Contains(err.Error(), `"cf"`) || !strings.Contains(err.Error(), `"apitoken"`) {
+		t.Errorf("error does not name the entry and key: %v", err)
+	}
+}
@@ -26,12 +26,12 @@ func helperRRtoRC(rr dnsv1.RR, origin string, fixBug bool) (models.RecordConfig,
 	// Convert's dns.RR into DNSControl's models.RecordConfig struct.
 
 	header := rr.Header()
-	ty := dnsv1.TypeToString[header.Rrtype]
+	ty := dnsv1.Type(header.Rrtype).String() // Unknown types are "TYPEnnn" (RFC 3597).
 
 	if rtypeinfo.IsModernType(ty) {
 		switch v := rr.(type) {
 		default:
-			rec, err := rtypecontrol.NewRecordConfigFromStruct(strings.TrimSuffix(header.Name, origin), header.Ttl, dnsv1.TypeToString[header.Rrtype], v, domaintags.MakeDomainNameVarieties(origin))
+			rec, err := rtypecontrol.NewRecordConfigFromStruct(strings.TrimSuffix(header.Name, origin), header.Ttl, ty, v, domaintags.MakeDomainNameVarieties(origin))
 			return *rec, err
 		}
 	}
@@ -0,0 +1,341 @@
+// Package dnssec tracks the life cycle of the DNSSEC keys of a zone for the
+// "dnssec" commands. Providers store the keys (see
+// providers.DNSSECKeyManager); this package decides when a key is published,
+// activated, retired and removed.
+//
+// Rollovers follow RFC 7583: a new key is published before it is used
+// (pre-publish), and a retired key stays published until the signatures and
+// DNSKEY RRsets that refer to it have expired from caches. The intervals are
+// derived from the TTLs of the zone.
+package dnssec
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DNSControl/dnscontrol/v4/models"
+	dnsv1 "github.com/miekg/dns"
+)
+
+// Role is the role of a key.
+type Role string
+
+// The roles of keys.
+const (
+	KSK Role = "KSK" // Key signing key: signs the DNSKEY RRset; the parent has its DS.
+	ZSK Role = "ZSK" // Zone signing key: signs the other RRsets.
+	CSK Role = "CSK" // Combined signing key: both.
+)
+
+// ParseRole parses "ksk", "zsk" or "csk".
+func ParseRole(s string) (Role, error) {
+	r := Role(strings.ToUpper(s))
+	switch r {
+	case KSK, ZSK, CSK:
+		return r, nil
+	}
+	return "", fmt.Errorf("unknown key role %q (expected ksk, zsk or csk)", s)
+}
+
+// Flags returns the flags of the DNSKEY record of a key with this role.
+func (r Role) Flags() uint16 {
+	if r == ZSK {
+		return dnsv1.ZONE
+	}
+	return dnsv1.ZONE | dnsv1.SEP
+}
+
+// hasDS reports whether the parent has a DS record for keys with this role.
+func (r Role) hasDS() bool {
+	return r != ZSK
+}
+
+// State is the state of a key at a given time.
+type State string
+
+// The states of a key.
+const (
+	StateCreated    State = "created"     // Generated, not published yet.
+	StatePrePublish State = "pre-publish" // In the DNSKEY RRset, not signing.
+	StateActive     State = "active"      // Signing.
+	StateRetired    State = "retired"     // No longer signing, still in the DNSKEY RRset.
+	StateRemoved    State = "removed"     // No longer in the DNSKEY RRset.
+)
+
+// Unknown stands for a transition that happened at an unknown time, for
+// keys that a provider reports without their history.
+var Unknown = time.Unix(0, 0).UTC()
+
+// Key is a DNSSEC key of a zone.
+type Key struct {
+	ID        string // Identifies the key at the provider.
+	Role      Role
+	Algorithm uint8
+	KeyTag    uint16
+	DNSKEY    string   // The rdata of the DNSKEY record ("257 3 13 ..."), if known.
+	DS        []string // The rdata of the DS records reported by the provider, if any.
+
+	// The times at which the key enters each state. A zero time means that
+	// the transition is not scheduled.
+	Published time.Time
+	Activated time.Time
+	Retired   time.Time
+	Removed   time.Time
+}
+
+// State returns the state of the key at time now.
+func (k Key) State(now time.Time) State {
+	switch {
+	case reached(k.Removed, now):
+		return StateRemoved
+	case reached(k.Retired, now):
+		return StateRetired
+	case reached(k.Activated, now):
+		return StateActive
+	case reached(k.Published, now):
+		return StatePrePublish
+	}
+	return StateCreated
+}
+
+func reached(t, now time.Time) bool {
+	return !t.IsZero() && !now.Before(t)
+}
+
+// DSRecords returns the DS records (SHA-256) to publish at the parent for
+// the key: those reported by the provider or, if none, computed from the
+// DNSKEY.
+func (k Key) DSRecords(zone string) []string {
+	if len(k.DS) != 0 || k.DNSKEY == "" {
+		return k.DS
+	}
+	rr, err := dnsv1.NewRR(dnsv1.Fqdn(zone) + " IN DNSKEY " + k.DNSKEY)
+	if err != nil {
+		return nil
+	}
+	dnskey, ok := rr.(*dnsv1.DNSKEY)
+	if !ok {
+		return nil
+	}
+	ds := dnskey.ToDS(dnsv1.SHA256)
+	if ds == nil {
+		return nil
+	}
+	return []string{fmt.Sprintf("%d %d %d %s", ds.KeyTag, ds.Algorithm, ds.DigestType, strings.ToUpper(ds.Digest))}
+}
+
+// ParseDNSKEY fills in the DNSKEY, role, algorithm and key tag of k from the
+// rdata of its DNSKEY record.
+func (k *Key) ParseDNSKEY(rdata string) error {
+	rr, err := dnsv1.NewRR(". IN DNSKEY " + rdata)
+	if err != nil {
+		return err
+	}
+	dnskey, ok := rr.(*dnsv1.DNSKEY)
+	if !ok {
+		return fmt.Errorf("not a DNSKEY: %q", rdata)
+	}
+	k.SetDNSKEY(dnskey)
+	return nil
+}
+
+// SetDNSKEY fills in the DNSKEY, role, algorithm and key tag of k. The role
+// is only set if it is unknown.
+func (k *Key) SetDNSKEY(dnskey *dnsv1.DNSKEY) {
+	k.DNSKEY = fmt.Sprintf("%d %d %d %s", dnskey.Flags, dnskey.Protocol, dnskey.Algorithm, dnskey.PublicKey)
+	k.Algorithm = dnskey.Algorithm
+	k.KeyTag = dnskey.KeyTag()
+	if k.Role == "" {
+		k.Role = ZSK
+		if dnskey.Flags&dnsv1.SEP != 0 {
+			k.Role = KSK
+		}
+	}
+}
+
+// Generate creates a DNSKEY and its private key for a new key. The private
+// key is returned in the format of dnssec-keygen's .private files.
+func Generate(zone string, role Role, algorithm uint8) (*dnsv1.DNSKEY, string, error) {
+	bits, ok := keySizes[algorithm]
+	if !ok {
+		return nil, "", fmt.Errorf("unsupported DNSSEC algorithm %d", algorithm)
+	}
+	dnskey := &dnsv1.DNSKEY{
+		Hdr:       dnsv1.RR_Header{Name: dnsv1.Fqdn(zone), Rrtype: dnsv1.TypeDNSKEY, Class: dnsv1.ClassINET},
+		Flags:     role.Flags(),
+		Protocol:  3,
+		Algorithm: algorithm,
+	}
+	priv, err := dnskey.Generate(bits)
+	if err != nil {
+		return nil, "", err
+	}
+	return dnskey, dnskey.PrivateKeyString(priv), nil
+}
+
+// keySizes are the key sizes used by Generate.
+var keySizes = map[uint8]int{
+	dnsv1.RSASHA256:       2048,
+	dnsv1.RSASHA512:       2048,
+	dnsv1.ECDSAP256SHA256: 256,
+	dnsv1.ECDSAP384SHA384: 384,
+	dnsv1.ED25519:         256,
+}
+
+// ParseAlgorithm parses a DNSSEC algorithm given by number ("13") or
+// mnemonic ("ECDSAP256SHA256").
+func ParseAlgorithm(s string) (uint8, error) {
+	if n, err := strconv.ParseUint(s, 10, 8); err == nil {
+		if _, ok := keySizes[uint8(n)]; ok {
+			return uint8(n), nil
+		}
+	}
+	if n, ok := dnsv1.StringToAlgorithm[strings.ToUpper(s)]; ok {
+		if _, ok := keySizes[n]; ok {
+			return n, nil
+		}
+	}
+	return 0, fmt.Errorf("unsupported DNSSEC algorithm %q", s)
+}
+
+// Timing holds the values that determine how long each phase of a rollover
+// lasts (RFC 7583 section 3.3).
+type Timing struct {
+	DNSKEYTTL   time.Duration // TTL of the DNSKEY RRset.
+	MaxTTL      time.Duration // Largest TTL in the zone, which bounds the TTL of signatures.
+	Propagation time.Duration // Time for a change to reach all the nameservers.
+}
+
+// defaultTTL is used when a zone's records give no better value.
+const defaultTTL = time.Hour
+
+// TimingFromRecords derives the timing from the records of a zone. The
+// DNSKEY TTL is taken from the zone's DNSKEY records or, failing that, its
+// SOA; the propagation delay is the SOA refresh interval, the longest time
+// that a secondary may take to notice a change.
+func TimingFromRecords(records models.Records) Timing {
+	t := Timing{Propagation: defaultTTL}
+	var soaTTL uint32
+	for _, r := range records {
+		ttl := time.Duration(r.TTL) * time.Second
+		t.MaxTTL = max(t.MaxTTL, ttl)
+		switch {
+		case r.Type == "DNSKEY":
+			t.DNSKEYTTL = max(t.DNSKEYTTL, ttl)
+		case r.Type == "SOA" && r.Name == "@":
+			soaTTL = r.TTL
+			if r.SoaRefresh != 0 {
+				t.Propagation = time.Duration(r.SoaRefresh) * time.Second
+			}
+		}
+	}
+	if t.DNSKEYTTL == 0 {
+		t.DNSKEYTTL = time.Duration(soaTTL) * time.Second
+	}
+	if t.DNSKEYTTL == 0 {
+		t.DNSKEYTTL = defaultTTL
+	}
+	t.MaxTTL = max(t.MaxTTL, t.DNSKEYTTL)
+	return t
+}
+
+// PublishInterval is how long a new key must be published before it can be
+// used, so that every cached DNSKEY RRset includes it.
+func (t Timing) PublishInterval() time.Duration {
+	return t.DNSKEYTTL + t.Propagation
+}
+
+// RetireInterval is how long a retired key must stay published. For a ZSK
+// (or CSK) that is until the signatures it made have expired from caches;
+// for a KSK, until the DNSKEY RRsets it signed have.
+func (t Timing) RetireInterval(role Role) time.Duration {
+	if role == KSK {
+		return t.DNSKEYTTL + t.Propagation
+	}
+	return t.MaxTTL + t.Propagation
+}
+
+// Action is what the next step of a rollover does.
+type Action int
+
+// The actions of a rollover step.
+const (
+	ActionNone     Action = iota // Nothing can be done now.
+	ActionCreate                 // Create and publish a new key.
+	ActionActivate               // Activate Step.Key and retire Step.Retire.
+	ActionRemove                 // Remove Step.Key.
+)
+
+// Step is the next step of a rollover.
+type Step struct {
+	Action Action
+	Key    Key       // The key to activate or remove.
+	Retire []Key     // The keys retired when Key is activated.
+	Wait   time.Time // With ActionNone: when the next step is due, if known.
+	Reason string    // Explains the step.
+}
+
+// NextStep returns the next step of the rollover of the keys with the given
+// role. A rollover goes through these steps, one per call:
+//
+//  1. A new key is created and published (pre-publish).
+//  2. After the publish interval, the new key is activated and the old one
+//     retired. For a KSK or CSK, the DS of the new key must be at the
+//     parent first: dsAtParent says that it is.
+//  3. After the retire interval, the old key is removed.
+//
+// When no rollover is in progress the next step starts one.
+func NextStep(keys []Key, role Role, t Timing, now time.Time, dsAtParent bool) Step {
+	var active, prepub, retired []Key
+	for _, k := range keys {
+		if k.Role != role {
+			continue
+		}
+		switch k.State(now) {
+		case StateActive:
+			active = append(active, k)
+		case StateCreated, StatePrePublish:
+			prepub = append(prepub, k)
+		case StateRetired:
+			retired = append(retired, k)
+		}
+	}
+
+	if len(retired) != 0 {
+		slices.SortFunc(retired, func(a, b Key) int { return a.Retired.Compare(b.Retired) })
+		k := retired[0]
+		due := k.Retired.Add(t.RetireInterval(role))
+		if now.Before(due) {
+			return Step{Wait: due, Reason: fmt.Sprintf("%s %d is retired; it can be removed after %s", role, k.KeyTag, due.Format(time.RFC3339))}
+		}
+		reason := fmt.Sprintf("%s %d has been retired for %s", role, k.KeyTag, t.RetireInterval(role))
+		if role.hasDS() {
+			reason += "; remove its DS record from the parent zone"
+		}
+		return Step{Action: ActionRemove, Key: k, Reason: reason}
+	}
+
+	if len(prepub) != 0 {
+		k := prepub[0]
+		if k.Published.IsZero() {
+			return Step{Reason: fmt.Sprintf("%s %d is not published", role, k.KeyTag)}
+		}
+		due := k.Published.Add(t.PublishInterval())
+		if now.Before(due) {
+			return Step{Wait: due, Reason: fmt.Sprintf("%s %d is pre-published; it can be activated after %s", role, k.KeyTag, due.Format(time.RFC3339))}
+		}
+		if role.hasDS() && !dsAtParent {
+			return Step{Reason: fmt.Sprintf("%s %d is ready; add its DS record to the parent zone, wait for the parent's DS TTL, then run rollover with --ds-at-parent", role, k.KeyTag)}
+		}
+		return Step{Action: ActionActivate, Key: k, Retire: active,
+			Reason: fmt.Sprintf("%s %d has been published for %s", role, k.KeyTag, t.PublishInterval())}
+	}
+
+	if len(active) == 0 {
+		return Step{Reason: fmt.Sprintf("there is no active %s; create one with \"dnscontrol dnssec keygen\"", role)}
+	}
+	return Step{Action: ActionCreate, Reason: fmt.Sprintf("start a rollover of %s %d", role, active[0].KeyTag)}
+}
@@ -0,0 +1,140 @@
+package dnssec
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DNSControl/dnscontrol/v4/models"
+)
+
+func TestKeyState(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	k := Key{Published: t0, Activated: t0.Add(time.Hour), Retired: t0.Add(2 * time.Hour), Removed: t0.Add(3 * time.Hour)}
+	tests := []struct {
+		at   time.Duration
+		want State
+	}{
+		{-time.Second, StateCreated},
+		{0, StatePrePublish},
+		{time.Hour, StateActive},
+		{2 * time.Hour, StateRetired},
+		{3 * time.Hour, StateRemoved},
+	}
+	for _, tt := range tests {
+		if got := k.State(t0.Add(tt.at)); got != tt.want {
+			t.Errorf("State(t0+%s) = %s, want %s", tt.at, got, tt.want)
+		}
+	}
+}
+
+func TestTimingFromRecords(t *testing.T) {
+	soa := &models.RecordConfig{Type: "SOA", Name: "@", TTL: 600, SoaRefresh: 1800}
+	a := &models.RecordConfig{Type: "A", Name: "www", TTL: 86400}
+	got := TimingFromRecords(models.Records{soa, a})
+	want := Timing{DNSKEYTTL: 600 * time.Second, MaxTTL: 24 * time.Hour, Propagation: 30 * time.Minute}
+	if got != want {
+		t.Errorf("TimingFromRecords() = %+v, want %+v", got, want)
+	}
+
+	got = TimingFromRecords(nil)
+	want = Timing{DNSKEYTTL: time.Hour, MaxTTL: time.Hour, Propagation: time.Hour}
+	if got != want {
+		t.Errorf("TimingFromRecords(nil) = %+v, want %+v", got, want)
+	}
+}
+
+func TestNextStep(t *testing.T) {
+	timing := Timing{DNSKEYTTL: time.Hour, MaxTTL: 2 * time.Hour, Propagation: time.Hour}
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	old := Key{Role: ZSK, KeyTag: 1, Published: t0, Activated: t0}
+
+	// Nothing in progress: start a rollover.
+	step := NextStep([]Key{old}, ZSK, timing, t0.Add(time.Hour), false)
+	if step.Action != ActionCreate {
+		t.Fatalf("idle: Action = %v (%s), want ActionCreate", step.Action, step.Reason)
+	}
+
+	// Pre-published for less than DNSKEY TTL + propagation: wait.
+	t1 := t0.Add(24 * time.Hour)
+	next := Key{Role: ZSK, KeyTag: 2, Published: t1}
+	keys := []Key{old, next}
+	step = NextStep(keys, ZSK, timing, t1.Add(time.Hour), false)
+	if step.Action != ActionNone || !step.Wait.Equal(t1.Add(2*time.Hour)) {
+		t.Fatalf("pre-publish: got %v until %s (%s)", step.Action, step.Wait, step.Reason)
+	}
+
+	// Then activate the new key and retire the old one.
+	t2 := t1.Add(2 * time.Hour)
+	step = NextStep(keys, ZSK, timing, t2, false)
+	if step.Action != ActionActivate || step.Key.KeyTag != 2 || len(step.Retire) != 1 || step.Retire[0].KeyTag != 1 {
+		t.Fatalf("activate: got %+v", step)
+	}
+	keys[0].Retired, keys[1].Activated = t2, t2
+
+	// The old ZSK is removed after max TTL + propagation.
+	step = NextStep(keys, ZSK, timing, t2.Add(2*time.Hour), false)
+	if step.Action != ActionNone || !step.Wait.Equal(t2.Add(3*time.Hour)) {
+		t.Fatalf("retired: got %v until %s (%s)", step.Action, step.Wait, step.Reason)
+	}
+	step = NextStep(keys, ZSK, timing, t2.Add(3*time.Hour), false)
+	if step.Action != ActionRemove || step.Key.KeyTag != 1 {
+		t.Fatalf("remove: got %+v", step)
+	}
+}
+
+func TestNextStepKSKNeedsDS(t *testing.T) {
+	timing := Timing{DNSKEYTTL: time.Hour, MaxTTL: time.Hour, Propagation: time.Hour}
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	keys := []Key{
+		{Role: KSK, KeyTag: 1, Published: t0, Activated: t0},
+		{Role: KSK, KeyTag: 2, Published: t0.Add(time.Hour)},
+	}
+	now := t0.Add(10 * time.Hour)
+	step := NextStep(keys, KSK, timing, now, false)
+	if step.Action != ActionNone || !strings.Contains(step.Reason, "--ds-at-parent") {
+		t.Errorf("without DS: got %v (%s)", step.Action, step.Reason)
+	}
+	if step := NextStep(keys, KSK, timing, now, true); step.Action != ActionActivate {
+		t.Errorf("with DS: got %v (%s)", step.Action, step.Reason)
+	}
+	if step := NextStep(nil, KSK, timing, now, false); step.Action != ActionNone || !strings.Contains(step.Reason, "keygen") {
+		t.Errorf("no keys: got %v (%s)", step.Action, step.Reason)
+	}
+}
+
+func TestKeyDSRecords(t *testing.T) {
+	// The example of RFC 6605 section 6.1.
+	var k Key
+	if err := k.ParseDNSKEY("257 3 13 GojIhhXUN/u4v54ZQqGSnyhWJwaubCvTmeexv7bR6edbkrSqQpF64cYbcB7wNcP+e+MAnLr+Wi9xMWyQLc8NAA=="); err != nil {
+		t.Fatal(err)
+	}
+	if k.Role != KSK || k.KeyTag != 55648 || k.Algorithm != 13 {
+		t.Errorf("ParseDNSKEY() = %+v", k)
+	}
+	want := "55648 13 2 B4C8C1FE2E7477127B27115656AD6256F424625BF5C1E2770CE6D6E37DF61D17"
+	if got := k.DSRecords("example.net"); len(got) != 1 || got[0] != want {
+		t.Errorf("DSRecords() = %v, want %s", got, want)
+	}
+
+	// DS records reported by the provider are returned as they are.
+	k.DS = []string{"1 2 3 ABCD"}
+	if got := k.DSRecords("example.net"); len(got) != 1 || got[0] != "1 2 3 ABCD" {
+		t.Errorf("DSRecords() = %v", got)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	for _, role := range []Role{KSK, ZSK} {
+		dnskey, private, err := Generate("example.com", role, 13)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if dnskey.Flags != role.Flags() || !strings.Contains(private, "Algorithm: 13") {
+			t.Errorf("Generate(%s) = %v, %q", role, dnskey, private)
+		}
+	}
+	if _, _, err := Generate("example.com", ZSK, 5); err == nil {
+		t.Error("Generate(RSASHA1) should fail")
+	}
+}
@@ -0,0 +1,273 @@
+// Package ipam allocates addresses from CIDR pools for POOL() and ALLOC()
+// in dnsconfig.js.
+//
+// Allocations are deterministic: a name is first offered the address at a
+// position derived from a hash of the name, then the following ones, so the
+// address a name gets does not depend on the order of the ALLOC()s. Once
+// made, an allocation is recorded in a lock file next to dnsconfig.js and
+// kept as long as the name is allocated, even if the pool changes.
+package ipam
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LockFileName returns the name of the lock file for a configuration file:
+// dnsconfig.js uses dnsconfig.ipam.json.
+func LockFileName(configFile string) string {
+	return strings.TrimSuffix(configFile, filepath.Ext(configFile)) + ".ipam.json"
+}
+
+// Pool is a range of addresses to allocate from.
+type Pool struct {
+	Prefix  netip.Prefix
+	Exclude []netip.Prefix // Addresses that are never allocated.
+}
+
+// ParsePool parses a pool given as a CIDR and the addresses or CIDRs to
+// exclude from it.
+func ParsePool(cidr string, exclude []string) (Pool, error) {
+	p, err := netip.ParsePrefix(cidr)
+	if err != nil {
+		return Pool{}, fmt.Errorf("POOL: %w", err)
+	}
+	if p != p.Masked() {
+		return Pool{}, fmt.Errorf("POOL(%q): not a network address (did you mean %q?)", cidr, p.Masked())
+	}
+	pool := Pool{Prefix: p}
+	for _, e := range exclude {
+		ep, err := netip.ParsePrefix(e)
+		if err != nil {
+			a, err2 := netip.ParseAddr(e)
+			if err2 != nil {
+				return Pool{}, fmt.Errorf("POOL(%q): exclude: %w", cidr, err)
+			}
+			ep = netip.PrefixFrom(a, a.BitLen())
+		}
+		pool.Exclude = append(pool.Exclude, ep.Masked())
+	}
+	return pool, nil
+}
+
+// Contains reports whether an address may be allocated from the pool. The
+// first address of the prefix (the network address) and, for IPv4, the
+// last one (the broadcast address) are never allocated.
+func (p Pool) Contains(a netip.Addr) bool {
+	if !p.Prefix.Contains(a) || a == p.Prefix.Addr() {
+		return false
+	}
+	if a.Is4() && p.Prefix.Bits() < 31 && a == lastAddr(p.Prefix) {
+		return false
+	}
+	for _, e := range p.Exclude {
+		if e.Contains(a) {
+			return false
+		}
+	}
+	return true
+}
+
+// lockFile is the format of the lock file.
+type lockFile struct {
+	// Pools maps each pool (CIDR) to its allocations, by name.
+	Pools map[string]map[string]string `json:"pools"`
+}
+
+// Allocator allocates addresses and tracks them in a lock file.
+type Allocator struct {
+	file  string
+	lock  lockFile
+	used  map[string]map[string]bool // Allocations made or confirmed in this run.
+	owner map[netip.Addr]string      // Allocated addresses, in any pool, and their names.
+	saved []byte
+	prev  lockFile // The allocations in the lock file.
+}
+
+// Load returns an allocator that uses the allocations of the lock file. If
+// file is empty, nothing is persisted.
+func Load(file string) (*Allocator, error) {
+	a := &Allocator{
+		file:  file,
+		lock:  lockFile{Pools: map[string]map[string]string{}},
+		used:  map[string]map[string]bool{},
+		owner: map[netip.Addr]string{},
+	}
+	if file == "" {
+		return a, nil
+	}
+	b, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return a, nil
+	} else if err != nil {
+		return nil, err
+	}
+	a.saved = b
+	if err := json.Unmarshal(b, &a.lock); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	if a.lock.Pools == nil {
+		a.lock.Pools = map[string]map[string]string{}
+	}
+	a.prev = clone(a.lock)
+	for pool, allocs := range a.lock.Pools {
+		for name, s := range allocs {
+			addr, err := netip.ParseAddr(s)
+			if err != nil {
+				return nil, fmt.Errorf("%s: pool %s: %s: %w", file, pool, name, err)
+			}
+			if other, ok := a.owner[addr]; ok {
+				return nil, fmt.Errorf("%s: %s is allocated to both %s and %s", file, addr, other, name)
+			}
+			a.owner[addr] = name
+		}
+	}
+	return a, nil
+}
+
+// Alloc returns the address allocated to name in the pool, allocating one
+// if needed.
+func (a *Allocator) Alloc(pool Pool, name string) (netip.Addr, error) {
+	key := pool.Prefix.String()
+	allocs := a.lock.Pools[key]
+	if allocs == nil {
+		allocs = map[string]string{}
+		a.lock.Pools[key] = allocs
+	}
+	if a.used[key] == nil {
+		a.used[key] = map[string]bool{}
+	}
+
+	if s, ok := allocs[name]; ok {
+		addr := netip.MustParseAddr(s)
+		if pool.Contains(addr) {
+			a.used[key][name] = true
+			return addr, nil
+		}
+		// The pool changed (for example an exclusion was added): move.
+		delete(allocs, name)
+		delete(a.owner, addr)
+	}
+
+	hostBits := pool.Prefix.Addr().BitLen() - pool.Prefix.Bits()
+	sum := sha256.Sum256([]byte(name))
+	start := binary.BigEndian.Uint64(sum[:8])
+	// Give up after this many tries; only small pools can be full.
+	tries := uint64(1) << min(hostBits, 20)
+	for i := range tries {
+		off := start + i
+		if hostBits < 64 {
+			off %= uint64(1) << hostBits
+		}
+		addr := addOffset(pool.Prefix.Addr(), off)
+		if _, taken := a.owner[addr]; taken || !pool.Contains(addr) {
+			continue
+		}
+		allocs[name] = addr.String()
+		a.owner[addr] = name
+		a.used[key][name] = true
+		return addr, nil
+	}
+	return netip.Addr{}, fmt.Errorf("ALLOC(%s, %q): the pool is full", key, name)
+}
+
+// Save writes the lock file if the allocations changed. Only the
+// allocations of the names for which pushed returns true are updated: the
+// others keep the address they had in the lock file, if any. Allocations
+// that were not requested in this run are dropped, so that removing an
+// ALLOC() frees its address. The file is not created if there are no
+// allocations.
+func (a *Allocator) Save(pushed func(name string) bool) error {
+	if a.file == "" {
+		return nil
+	}
+	next := lockFile{Pools: map[string]map[string]string{}}
+	taken := map[string]bool{}
+	add := func(pool, name, addr string) {
+		if taken[addr] {
+			return
+		}
+		if next.Pools[pool] == nil {
+			next.Pools[pool] = map[string]string{}
+		}
+		next.Pools[pool][name] = addr
+		taken[addr] = true
+	}
+	for pool, allocs := range a.lock.Pools {
+		for name, addr := range allocs {
+			if a.used[pool][name] && pushed(name) {
+				add(pool, name, addr)
+			}
+		}
+	}
+	// Added last, so that the address of a name that was not pushed is
+	// dropped if a pushed name got it.
+	for pool, allocs := range a.prev.Pools {
+		for name, addr := range allocs {
+			if !pushed(name) {
+				add(pool, name, addr)
+			}
+		}
+	}
+	if len(next.Pools) == 0 && a.saved == nil {
+		return nil
+	}
+	b, err := json.MarshalIndent(next, "", "  ")
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+	if bytes.Equal(b, a.saved) {
+		return nil
+	}
+	if err := os.WriteFile(a.file, b, 0o644); err != nil {
+		return err
+	}
+	a.saved = b
+	a.prev = next
+	return nil
+}
+
+// clone returns a deep copy of a lock file.
+func clone(l lockFile) lockFile {
+	c := lockFile{Pools: map[string]map[string]string{}}
+	for pool, allocs := range l.Pools {
+		c.Pools[pool] = maps.Clone(allocs)
+	}
+	return c
+}
+
+// addOffset returns base + off.
+func addOffset(base netip.Addr, off uint64) netip.Addr {
+	b := base.As16()
+	carry := off
+	for i := 15; i >= 0 && carry != 0; i-- {
+		sum := uint64(b[i]) + carry&0xff
+		b[i] = byte(sum)
+		carry = carry>>8 + sum>>8
+	}
+	addr := netip.AddrFrom16(b)
+	if base.Is4() {
+		return addr.Unmap()
+	}
+	return addr
+}
+
+// lastAddr returns the last address of a prefix.
+func lastAddr(p netip.Prefix) netip.Addr {
+	b := p.Masked().Addr().AsSlice()
+	for bit := p.Bits(); bit < len(b)*8; bit++ {
+		b[bit/8] |= 0x80 >> (bit % 8)
+	}
+	addr, _ := netip.AddrFromSlice(b)
+	return addr
+}
@@ -0,0 +1,164 @@
+package ipam
+
+import (
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAllocDeterministic(t *testing.T) {
+	pool, err := ParsePool("10.0.0.0/16", []string{"10.0.0.1", "10.0.240.0/20"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := []string{"a.example.com", "b.example.com", "c.example.com", "d.example.com"}
+
+	got := map[string]netip.Addr{}
+	a, _ := Load("")
+	for _, n := range names {
+		addr, err := a.Alloc(pool, n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !pool.Contains(addr) {
+			t.Errorf("%s got %s, which is not available in the pool", n, addr)
+		}
+		got[n] = addr
+	}
+
+	// In the reverse order, without a lock file, the addresses are the same
+	// (unless two names hash to the same address, which they do not here).
+	a, _ = Load("")
+	for i := len(names) - 1; i >= 0; i-- {
+		addr, _ := a.Alloc(pool, names[i])
+		if addr != got[names[i]] {
+			t.Errorf("%s: got %s, then %s", names[i], got[names[i]], addr)
+		}
+	}
+	if addr, _ := a.Alloc(pool, names[0]); addr != got[names[0]] {
+		t.Errorf("second Alloc(%s) = %s, want %s", names[0], addr, got[names[0]])
+	}
+}
+
+func TestAllocFull(t *testing.T) {
+	pool, _ := ParsePool("192.0.2.0/30", nil)
+	a, _ := Load("")
+	seen := map[netip.Addr]bool{}
+	for _, n := range []string{"one", "two"} {
+		addr, err := a.Alloc(pool, n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if seen[addr] || (addr != netip.MustParseAddr("192.0.2.1") && addr != netip.MustParseAddr("192.0.2.2")) {
+			t.Errorf("%s got %s", n, addr)
+		}
+		seen[addr] = true
+	}
+	if _, err := a.Alloc(pool, "three"); err == nil || !strings.Contains(err.Error(), "full") {
+		t.Errorf("Alloc in a full pool: err = %v", err)
+	}
+}
+
+func TestAllocIPv6(t *testing.T) {
+	pool, _ := ParsePool("2001:db8:1::/64", nil)
+	a, _ := Load("")
+	addr, err := a.Alloc(pool, "host.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pool.Prefix.Contains(addr) || addr == pool.Prefix.Addr() {
+		t.Errorf("got %s", addr)
+	}
+}
+
+// all is the pushed function of Save for a push of all zones.
+func all(string) bool { return true }
+
+func TestLockFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "dnsconfig.ipam.json")
+	pool, _ := ParsePool("10.0.0.0/24", nil)
+
+	a, _ := Load(file)
+	if err := a.Save(all); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(file); !os.IsNotExist(err) {
+		t.Fatal("Save() without allocations created the lock file")
+	}
+	x, _ := a.Alloc(pool, "x")
+	y, _ := a.Alloc(pool, "y")
+	if err := a.Save(all); err != nil {
+		t.Fatal(err)
+	}
+
+	// The lock file wins over the hash: move y elsewhere by hand.
+	b, _ := os.ReadFile(file)
+	moved := "10.0.0.250"
+	if y.String() == moved {
+		moved = "10.0.0.251"
+	}
+	if err := os.WriteFile(file, []byte(strings.Replace(string(b), `"`+y.String()+`"`, `"`+moved+`"`, 1)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	a, err := Load(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := a.Alloc(pool, "y"); got.String() != moved {
+		t.Errorf("Alloc(y) = %s, want %s from the lock file", got, moved)
+	}
+
+	// x is no longer allocated: it is dropped from the lock file.
+	if err := a.Save(all); err != nil {
+		t.Fatal(err)
+	}
+	b, _ = os.ReadFile(file)
+	if strings.Contains(string(b), `"x"`) || strings.Contains(string(b), x.String()) {
+		t.Errorf("lock file still has x:\n%s", b)
+	}
+}
+
+func TestLockFileNotPushed(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "dnsconfig.ipam.json")
+	pool, _ := ParsePool("10.0.0.0/24", nil)
+
+	a, _ := Load(file)
+	x, _ := a.Alloc(pool, "x.example.com")
+	if err := a.Save(all); err != nil {
+		t.Fatal(err)
+	}
+
+	// The zones were not pushed (for example, their push failed): neither
+	// the new allocation of y nor the removal of x is recorded.
+	a, _ = Load(file)
+	a.Alloc(pool, "y.example.net")
+	if err := a.Save(func(string) bool { return false }); err != nil {
+		t.Fatal(err)
+	}
+	b, _ := os.ReadFile(file)
+	if strings.Contains(string(b), "y.example.net") || !strings.Contains(string(b), x.String()) {
+		t.Errorf("lock file after a failed push:\n%s", b)
+	}
+
+	// Once pushed, both are.
+	if err := a.Save(all); err != nil {
+		t.Fatal(err)
+	}
+	b, _ = os.ReadFile(file)
+	if !strings.Contains(string(b), "y.example.net") || strings.Contains(string(b), "x.example.com") {
+		t.Errorf("lock file after a successful push:\n%s", b)
+	}
+}
+
+func TestParsePool(t *testing.T) {
+	for _, bad := range []string{"10.0.0.1/24", "10.0.0.0", "nonsense"} {
+		if _, err := ParsePool(bad, nil); err == nil {
+			t.Errorf("ParsePool(%q) should fail", bad)
+		}
+	}
+	if _, err := ParsePool("10.0.0.0/24", []string{"bad"}); err == nil {
+		t.Error("ParsePool with a bad exclusion should fail")
+	}
+}
@@ -24,6 +24,7 @@ var conf = {
     dns_providers: [],
     domains: [],
     domain_names: [],
+    health_checks: [],
 };
 
 var defaultArgs = [];
@@ -33,6 +34,7 @@ function initialize() {
         registrars: [],
         dns_providers: [],
         domains: [],
+        health_checks: [],
     };
     defaultArgs = [];
 }
@@ -447,7 +449,209 @@ function R53_WEIGHT(weight, set_identifier) {
     };
 }
 
-// R53_HEALTH_CHECK_ID(health_check_id) associates a Route 53 health check with the record.
+// r53SetIdentifier checks the set_identifier of a Route 53 routing policy
+// modifier.
+function r53SetIdentifier(name, set_identifier) {
+    if (!_.isString(set_identifier) || set_identifier === '') {
+        throw name + ': set_identifier must be a non-empty string';
+    }
+    return set_identifier;
+}
+
+// metaModifier returns a record modifier that sets the metadata in meta,
+// such as that of a routing policy.
+function metaModifier(meta) {
+    return function (r) {
+        if (!_.isObject(r.meta)) {
+            r.meta = {};
+        }
+        for (var k in meta) {
+            r.meta[k] = meta[k];
+        }
+    };
+}
+
+// R53_LATENCY(region, set_identifier) configures Route 53 latency routing.
+// region: the AWS region of the resource, such as "us-east-1".
+function R53_LATENCY(region, set_identifier) {
+    if (!_.isString(region) || region === '') {
+        throw 'R53_LATENCY: region must be a non-empty string';
+    }
+    return metaModifier({
+        r53_region: region,
+        r53_set_identifier: r53SetIdentifier('R53_LATENCY', set_identifier),
+    });
+}
+
+// R53_GEO(location, set_identifier) configures Route 53 geolocation routing.
+// location: {continent: "EU"}, {country: "US"}, {country: "US", subdivision: "CA"},
+// or {country: "*"} for the default location.
+function R53_GEO(location, set_identifier) {
+    if (!_.isObject(location) || (!location.continent && !location.country)) {
+        throw 'R53_GEO: location must be an object with a continent or a country';
+    }
+    if (location.continent && (location.country || location.subdivision)) {
+        throw 'R53_GEO: location can not have both a continent and a country';
+    }
+    if (location.subdivision && !location.country) {
+        throw 'R53_GEO: a subdivision requires a country';
+    }
+    var meta = { r53_set_identifier: r53SetIdentifier('R53_GEO', set_identifier) };
+    if (location.continent) {
+        meta.r53_geo_continent = location.continent;
+    }
+    if (location.country) {
+        meta.r53_geo_country = location.country;
+    }
+    if (location.subdivision) {
+        meta.r53_geo_subdivision = location.subdivision;
+    }
+    return metaModifier(meta);
+}
+
+// R53_GEOPROXIMITY(location, bias, set_identifier) configures Route 53
+// geoproximity routing. location: an AWS region such as "us-east-1",
+// {local_zone_group: "us-east-1-bue-1"} or {latitude: "49.22", longitude: "-74.01"}.
+// bias: integer -99 to 99.
+function R53_GEOPROXIMITY(location, bias, set_identifier) {
+    var meta = { r53_set_identifier: r53SetIdentifier('R53_GEOPROXIMITY', set_identifier) };
+    if (_.isString(location) && location !== '') {
+        meta.r53_geoproximity_region = location;
+    } else if (_.isObject(location) && _.isString(location.local_zone_group)) {
+        meta.r53_geoproximity_local_zone_group = location.local_zone_group;
+    } else if (_.isObject(location) && location.latitude !== undefined && location.longitude !== undefined) {
+        meta.r53_geoproximity_coordinates = location.latitude + ',' + location.longitude;
+    } else {
+        throw 'R53_GEOPROXIMITY: location must be a region, {local_zone_group: ...} or {latitude: ..., longitude: ...}';
+    }
+    if (!_.isNumber(bias) || bias < -99 || bias > 99) {
+        throw 'R53_GEOPROXIMITY: bias must be a number between -99 and 99';
+    }
+    meta.r53_geoproximity_bias = bias.toString();
+    return metaModifier(meta);
+}
+
+// R53_FAILOVER(role, set_identifier) configures Route 53 failover routing.
+// role: "PRIMARY" or "SECONDARY".
+function R53_FAILOVER(role, set_identifier) {
+    if (role !== 'PRIMARY' && role !== 'SECONDARY') {
+        throw 'R53_FAILOVER: role must be "PRIMARY" or "SECONDARY"';
+    }
+    return metaModifier({
+        r53_failover: role,
+        r53_set_identifier: r53SetIdentifier('R53_FAILOVER', set_identifier),
+    });
+}
+
+// R53_MULTIVALUE(set_identifier) configures Route 53 multivalue answer routing.
+function R53_MULTIVALUE(set_identifier) {
+    return metaModifier({
+        r53_multivalue: 'true',
+        r53_set_identifier: r53SetIdentifier('R53_MULTIVALUE', set_identifier),
+    });
+}
+
+// STEER(policy) configures provider-neutral traffic steering. policy is an
+// object with an id and one of weight, geo or failover:
+//   {id: "east", weight: 70}
+//   {id: "eu", geo: {continent: "EU"}}  (or {country: "US", subdivision: "CA"}, {country: "*"})
+//   {id: "main", failover: "primary", health_check: "..."}
+function STEER(policy) {
+    if (!_.isObject(policy) || !_.isString(policy.id) || policy.id === '') {
+        throw 'STEER: policy must be an object with a non-empty id';
+    }
+    var n = 0;
+    var meta = { steer_id: policy.id };
+    if (policy.weight !== undefined) {
+        if (!_.isNumber(policy.weight) || policy.weight < 0 || policy.weight % 1 !== 0) {
+            throw 'STEER: weight must be a non-negative integer';
+        }
+        meta.steer_weight = policy.weight.toString();
+        n++;
+    }
+    if (policy.geo !== undefined) {
+        var geo = policy.geo;
+        if (!_.isObject(geo) || (!geo.continent && !geo.country)) {
+            throw 'STEER: geo must be an object with a continent or a country';
+        }
+        if (geo.continent && (geo.country || geo.subdivision)) {
+            throw 'STEER: geo can not have both a continent and a country';
+        }
+        if (geo.subdivision && !geo.country) {
+            throw 'STEER: a geo subdivision requires a country';
+        }
+        if (geo.continent) {
+            meta.steer_geo_continent = geo.continent;
+        }
+        if (geo.country) {
+            meta.steer_geo_country = geo.country;
+        }
+        if (geo.subdivision) {
+            meta.steer_geo_subdivision = geo.subdivision;
+        }
+        n++;
+    }
+    if (policy.failover !== undefined) {
+        var role = _.isString(policy.failover) ? policy.failover.toUpperCase() : '';
+        if (role !== 'PRIMARY' && role !== 'SECONDARY') {
+            throw 'STEER: failover must be "primary" or "secondary"';
+        }
+        meta.steer_failover = role;
+        n++;
+    }
+    if (n !== 1) {
+        throw 'STEER: policy must have exactly one of weight, geo or failover';
+    }
+    if (policy.health_check !== undefined) {
+        if (!_.isString(policy.health_check) || policy.health_check === '') {
+            throw 'STEER: health_check must be a non-empty string';
+        }
+        meta.steer_health_check = policy.health_check;
+    }
+    return metaModifier(meta);
+}
+
+// HEALTH_CHECK(name, opts) declares a health check, which providers that
+// manage health checks create for the records that refer to it by name.
+function HEALTH_CHECK(name, opts) {
+    if (!_.isString(name) || name === '') {
+        throw 'HEALTH_CHECK: name must be a non-empty string';
+    }
+    if (!_.isObject(opts) || !_.isString(opts.type)) {
+        throw 'HEALTH_CHECK ' + name + ': opts must be an object with a type';
+    }
+    var known = ['type', 'host', 'ip', 'port', 'path', 'interval', 'failure_threshold'];
+    for (var k in opts) {
+        if (known.indexOf(k) === -1) {
+            throw 'HEALTH_CHECK ' + name + ': unknown option ' + k;
+        }
+    }
+    var hc = { name: name, type: opts.type };
+    if (opts.host !== undefined) {
+        hc.host = opts.host;
+    }
+    if (opts.ip !== undefined) {
+        hc.ip = opts.ip;
+    }
+    var numbers = ['port', 'interval', 'failure_threshold'];
+    for (var i = 0; i < numbers.length; i++) {
+        var v = opts[numbers[i]];
+        if (v === undefined) {
+            continue;
+        }
+        if (!_.isNumber(v) || v <= 0 || v % 1 !== 0) {
+            throw 'HEALTH_CHECK ' + name + ': ' + numbers[i] + ' must be a positive integer';
+        }
+        hc[numbers[i]] = v;
+    }
+    if (opts.path !== undefined) {
+        hc.path = opts.path;
+    }
+    conf.health_checks.push(hc);
+}
+
+// R53_HEALTH_CHECK_ID(health_check_id) associates a Route 53 health check,
+// by ID or by the name of a HEALTH_CHECK(), with the record.
 function R53_HEALTH_CHECK_ID(health_check_id) {
     if (!_.isString(health_check_id) || health_check_id === '') {
         throw 'R53_HEALTH_CHECK_ID: health_check_id must be a non-empty string';
@@ -1212,6 +1416,89 @@ function IGNORE_EXTERNAL_DNS(prefix) {
     };
 }
 
+// AUTOPTR: Add PTR records to this reverse zone for the A and AAAA records
+// of the other zones.
+var AUTOPTR = { autoptr: 'true' };
+
+// POOL(cidr, options): A pool of addresses for ALLOC().
+// options.exclude: addresses or CIDRs that are never allocated.
+// Usage: var LAB = POOL("10.0.0.0/24", { exclude: ["10.0.0.1"] });
+function POOL(cidr, options) {
+    if (!_.isString(cidr)) {
+        throw 'POOL: the first argument must be a CIDR string';
+    }
+    var exclude = [];
+    if (options && options.exclude !== undefined) {
+        exclude = _.flatten([options.exclude]);
+    }
+    return { ipam_pool: cidr, exclude: exclude };
+}
+
+// ALLOC(pool, name, modifiers...): Allocate an address to name from a
+// POOL() and add an A or AAAA record for it. Reverse zones with AUTOPTR get
+// its PTR record.
+// Usage: ALLOC(LAB, "printer", TTL(300))
+function ALLOC(pool, name) {
+    if (!_.isObject(pool) || !_.isString(pool.ipam_pool)) {
+        throw 'ALLOC: the first argument must be a POOL()';
+    }
+    if (!_.isString(name) || name === '') {
+        throw 'ALLOC: the second argument must be a name';
+    }
+    var modifiers = Array.prototype.slice.call(arguments, 2);
+    return function (d) {
+        var parts = d.name.split('!');
+        var fqdn;
+        if (name.charAt(name.length - 1) === '.') {
+            fqdn = name.slice(0, -1);
+        } else {
+            fqdn = d.subdomain ? d.subdomain + '.' + parts[0] : parts[0];
+            if (name !== '@') {
+                fqdn = name + '.' + fqdn;
+            }
+        }
+        var ip = _ipam_alloc(pool.ipam_pool, pool.exclude, fqdn.toLowerCase());
+        var builder = ip.indexOf(':') === -1 ? A : AAAA;
+        builder.apply(null, [name, ip].concat(modifiers))(d);
+    };
+}
+
+// VIEW_MUST_MATCH(labels...)
+// Requires the records at these labels to be identical in all the views
+// (D("example.com!tag")) of the zone.
+// Usage: VIEW_MUST_MATCH("@", "www") or VIEW_MUST_MATCH(["@", "www"])
+function VIEW_MUST_MATCH() {
+    var labels = _.flatten(arguments);
+    if (labels.length === 0) {
+        throw 'VIEW_MUST_MATCH: at least one label is required';
+    }
+    _.each(labels, function (l) {
+        if (!_.isString(l) || l === '' || l.indexOf(',') !== -1) {
+            throw 'VIEW_MUST_MATCH: labels must be non-empty strings without commas';
+        }
+    });
+    return function (d) {
+        var prev = d.meta.view_must_match;
+        d.meta.view_must_match = (prev ? prev + ',' : '') + labels.join(',');
+    };
+}
+
+// VIEW_ONLY(tags...)
+// Keeps the record only in the views with these tags.
+// Usage: A("db", "10.0.0.5", VIEW_ONLY("internal"))
+function VIEW_ONLY() {
+    var tags = _.flatten(arguments);
+    if (tags.length === 0) {
+        throw 'VIEW_ONLY: at least one tag is required';
+    }
+    _.each(tags, function (t) {
+        if (!_.isString(t) || t.indexOf(',') !== -1 || t.indexOf('!') !== -1) {
+            throw 'VIEW_ONLY: tags must be strings without commas or "!"';
+        }
+    });
+    return { view_only: tags.join(',') };
+}
+
 // ENSURE_ABSENT_REC()
 // Usage: A("foo", "1.2.3.4", ENSURE_ABSENT_REC())
 function ENSURE_ABSENT_REC() {
@@ -1503,6 +1790,21 @@ var CF_PROXY_DEFAULT_ON = { cloudflare_proxy_default: 'on' };
 var CF_UNIVERSALSSL_OFF = { cloudflare_universalssl: 'off' };
 // UniversalSSL on for entire domain:
 var CF_UNIVERSALSSL_ON = { cloudflare_universalssl: 'on' };
+// Zone settings for entire domain, such as {ssl: 'strict', min_tls_version: '1.2'}.
+// Settings of several CF_ZONE_SETTINGS() are merged.
+function CF_ZONE_SETTINGS(settings) {
+    if (!_.isObject(settings) || _.isArray(settings)) {
+        throw 'CF_ZONE_SETTINGS wants an object, such as {ssl: "strict"}';
+    }
+    return function (d) {
+        var prev = d.meta.cloudflare_zone_settings;
+        var all = prev ? JSON.parse(prev) : {};
+        _.each(settings, function (v, k) {
+            all[k] = v;
+        });
+        d.meta.cloudflare_zone_settings = JSON.stringify(all);
+    };
+}
 // Per-record comment (works on all plans):
 function CF_COMMENT(comment) {
     return { cloudflare_comment: comment };
@@ -1527,6 +1829,109 @@ function HEDNS_DDNS_KEY(key) {
     return { hedns_dynamic: 'on', hedns_ddns_key: key };
 }
 
+// PowerDNS zone settings and metadata:
+
+// pdnsList returns the arguments of a PDNS_* function as a comma-separated
+// list, after checking them.
+function pdnsList(name, args) {
+    var list = _.flatten(args);
+    _.each(list, function (v) {
+        if (!_.isString(v) || v === '' || v.indexOf(',') !== -1) {
+            throw name + ': each value must be a non-empty string without commas';
+        }
+    });
+    return list.join(',');
+}
+// pdnsOneOf returns value after checking that it is one of allowed
+// (upper case), ignoring case.
+function pdnsOneOf(name, value, allowed) {
+    if (!_.isString(value) || !_.contains(allowed, value.toUpperCase())) {
+        throw name + ': "' + value + '" is not one of ' + allowed.join(', ');
+    }
+    return value;
+}
+// pdnsIsIP reports whether s is an IPv4 or IPv6 address.
+function pdnsIsIP(s) {
+    var v4 = /^(25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)(\.(25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)){3}$/;
+    if (v4.test(s)) {
+        return true;
+    }
+    var halves = s.split('::');
+    if (halves.length > 2) {
+        return false;
+    }
+    var groups = 0;
+    for (var i = 0; i < halves.length; i++) {
+        if (halves[i] === '') {
+            continue;
+        }
+        var parts = halves[i].split(':');
+        for (var j = 0; j < parts.length; j++) {
+            // An IPv4 address may end the address, as two groups.
+            if (i === halves.length - 1 && j === parts.length - 1 && v4.test(parts[j])) {
+                groups += 2;
+            } else if (/^[0-9A-Fa-f]{1,4}$/.test(parts[j])) {
+                groups++;
+            } else {
+                return false;
+            }
+        }
+    }
+    return halves.length === 2 ? groups < 8 : groups === 8;
+}
+// pdnsCheckMaster checks a server of PDNS_MASTERS: "IP", "IP:port" or
+// "[IPv6]:port".
+function pdnsCheckMaster(v) {
+    var ip = v;
+    var m = /^\[(.*)\]:(\d{1,5})$/.exec(v) || /^([^:]*):(\d{1,5})$/.exec(v);
+    if (m) {
+        ip = m[1];
+    }
+    if (!pdnsIsIP(ip) || (m && (Number(m[2]) < 1 || Number(m[2]) > 65535))) {
+        throw 'PDNS_MASTERS: "' + v + '" is not an IP address with an optional port (IP, IP:port or [IPv6]:port)';
+    }
+}
+// Zone kind: "Native", "Master", "Slave", "Producer" or "Consumer":
+function PDNS_ZONE_KIND(kind) {
+    var kinds = ['NATIVE', 'MASTER', 'SLAVE', 'PRODUCER', 'CONSUMER', 'PRIMARY', 'SECONDARY'];
+    return { pdns_zone_kind: pdnsOneOf('PDNS_ZONE_KIND', kind, kinds) };
+}
+// Primary servers of a Slave zone, such as "192.0.2.1" or "192.0.2.1:5300":
+function PDNS_MASTERS() {
+    var list = pdnsList('PDNS_MASTERS', arguments);
+    if (list !== '') {
+        _.each(list.split(','), pdnsCheckMaster);
+    }
+    return { pdns_masters: list };
+}
+// Networks and names allowed to transfer the zone (ALLOW-AXFR-FROM):
+function PDNS_ALLOW_AXFR_FROM() {
+    return { pdns_allow_axfr_from: pdnsList('PDNS_ALLOW_AXFR_FROM', arguments) };
+}
+// TSIG keys allowed to transfer the zone (TSIG-ALLOW-AXFR):
+function PDNS_TSIG_ALLOW_AXFR() {
+    return { pdns_tsig_allow_axfr: pdnsList('PDNS_TSIG_ALLOW_AXFR', arguments) };
+}
+// TSIG key used to transfer the zone from its masters (AXFR-MASTER-TSIG):
+function PDNS_AXFR_MASTER_TSIG() {
+    if (arguments.length > 1) {
+        throw 'PDNS_AXFR_MASTER_TSIG: PowerDNS uses only one key';
+    }
+    return { pdns_axfr_master_tsig: pdnsList('PDNS_AXFR_MASTER_TSIG', arguments) };
+}
+// SOA-EDIT-API: "DEFAULT", "INCREASE", "EPOCH", "SOA-EDIT", "SOA-EDIT-INCREASE" or "" (off):
+function PDNS_SOA_EDIT_API(value) {
+    var values = ['DEFAULT', 'INCREASE', 'EPOCH', 'SOA-EDIT', 'SOA-EDIT-INCREASE', 'NONE'];
+    if (value !== '') {
+        pdnsOneOf('PDNS_SOA_EDIT_API', value, values);
+    }
+    return { pdns_soa_edit_api: value };
+}
+// NSEC3 parameters, such as "1 0 0 -", or "" for NSEC (DNSSEC zones only):
+function PDNS_NSEC3PARAM(param) {
+    return { pdns_nsec3param: param };
+}
+
 // Gidinet aliases:
 
 // GIDINET_PREMIUM_NS(): Emit NAMESERVER records for Gidinet premium DNS
@@ -2558,8 +2963,8 @@ function rawrecordBuilder(type) {
             };
 
             // Process the args: Functions are executed, objects are assumed to
-            // be meta and stored, strings are assumed to be args and are
-            // stored.
+            // be meta and stored, strings and arrays are assumed to be args and
+            // are stored.
             // NB(tlim): Allowing for the intermixing of args and meta seems
             // bad.  It might be better to simply preserve the first n items as
             // args, then assume the rest are metas. That would be more similar
@@ -2571,6 +2976,8 @@ function rawrecordBuilder(type) {
                 var r = rawArgs[i];
                 if (_.isFunction(r)) {
                     r(record);
+                } else if (_.isArray(r)) {
+                    processedArgs.push(r);
                 } else if (_.isObject(r)) {
                     processedMetas.push(r);
                 } else {
@@ -2591,8 +2998,17 @@ function rawrecordBuilder(type) {
 
 // PLEASE KEEP THIS LIST ALPHABETICAL!
 
+var CF_LOAD_BALANCER = rawrecordBuilder('CF_LOAD_BALANCER');
 var CF_REDIRECT = rawrecordBuilder('CF_REDIRECT');
 var CF_SINGLE_REDIRECT = rawrecordBuilder('CLOUDFLAREAPI_SINGLE_REDIRECT');
 var CF_TEMP_REDIRECT = rawrecordBuilder('CF_TEMP_REDIRECT');
+var CDNSKEY = rawrecordBuilder('CDNSKEY');
+var CDS = rawrecordBuilder('CDS');
+var CERT = rawrecordBuilder('CERT');
 var DS = rawrecordBuilder('DS');
+var HINFO = rawrecordBuilder('HINFO');
+var IPSECKEY = rawrecordBuilder('IPSECKEY');
+var RAW = rawrecordBuilder('RAW');
 var RP = rawrecordBuilder('RP');
+var URI = rawrecordBuilder('URI');
+var ZONEMD = rawrecordBuilder('ZONEMD');
@@ -0,0 +1,43 @@
+package js
+
+import (
+	"github.com/DNSControl/dnscontrol/v4/pkg/ipam"
+	"github.com/robertkrimen/otto"
+)
+
+// ipamAlloc returns the implementation of _ipam_alloc(cidr, exclude, name),
+// which returns the address that alloc allocates to name in a POOL().
+func ipamAlloc(alloc *ipam.Allocator) func(otto.FunctionCall) otto.Value {
+	return func(call otto.FunctionCall) otto.Value {
+		if len(call.ArgumentList) != 3 {
+			throw(call.Otto, "_ipam_alloc takes exactly three arguments")
+		}
+		exported, err := call.Argument(1).Export()
+		if err != nil {
+			throw(call.Otto, err.Error())
+		}
+		var exclude []string
+		switch e := exported.(type) {
+		case []string:
+			exclude = e
+		case []any:
+			for _, x := range e {
+				s, ok := x.(string)
+				if !ok {
+					throw(call.Otto, "POOL: exclude must be a list of addresses or CIDRs")
+				}
+				exclude = append(exclude, s)
+			}
+		}
+		pool, err := ipam.ParsePool(call.Argument(0).String(), exclude)
+		if err != nil {
+			throw(call.Otto, err.Error())
+		}
+		addr, err := alloc.Alloc(pool, call.Argument(2).String())
+		if err != nil {
+			throw(call.Otto, err.Error())
+		}
+		v, _ := otto.ToValue(addr.String())
+		return v
+	}
+}
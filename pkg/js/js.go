@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/DNSControl/dnscontrol/v4/models"
+	"github.com/DNSControl/dnscontrol/v4/pkg/ipam"
 	"github.com/DNSControl/dnscontrol/v4/pkg/printer"
 	"github.com/DNSControl/dnscontrol/v4/pkg/rfc4183"
 	"github.com/DNSControl/dnscontrol/v4/pkg/rtypecontrol"
@@ -47,11 +48,23 @@ func ExecuteJavaScript(file string, devMode bool, variables map[string]string) (
 	// Record the directory path leading up to this file.
 	currentDirectory = filepath.Dir(file)
 
-	return ExecuteJavascriptString(script, devMode, variables)
+	// ALLOC()s use the lock file next to this file.
+	return executeJavascript(script, devMode, variables, ipam.LockFileName(file))
 }
 
 // ExecuteJavascriptString accepts a string containing javascript and runs it, returning the resulting dnsConfig.
 func ExecuteJavascriptString(script []byte, devMode bool, variables map[string]string) (*models.DNSConfig, error) {
+	return executeJavascript(script, devMode, variables, "")
+}
+
+// executeJavascript runs script. The addresses of ALLOC() are allocated using
+// ipamLockFile (if not ""), which is not written: see models.DNSConfig.IPAM.
+func executeJavascript(script []byte, devMode bool, variables map[string]string, ipamLockFile string) (*models.DNSConfig, error) {
+	alloc, err := ipam.Load(ipamLockFile)
+	if err != nil {
+		return nil, err
+	}
+
 	vm := otto.New()
 	l := loop.New(vm)
 
@@ -77,6 +90,8 @@ func ExecuteJavascriptString(script []byte, devMode bool, variables map[string]s
 		"glob":      listFiles, // used for require_glob()
 		"PANIC":     jsPanic,
 		"HASH":      hashFunc,
+
+		"_ipam_alloc": ipamAlloc(alloc),
 	}
 	for name, fn := range functions {
 		if err := vm.Set(name, fn); err != nil {
@@ -131,6 +146,7 @@ func ExecuteJavascriptString(script []byte, devMode bool, variables map[string]s
 		return nil, err
 	}
 
+	conf.IPAM = alloc
 	return conf, nil
 }
 
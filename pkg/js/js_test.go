@@ -164,6 +164,10 @@ func TestErrors(t *testing.T) {
 		{"Dup domains", `D("example.org", "reg"); D("example.org", "reg")`},
 		{"Bad NAMESERVER", `D("example.com","reg", NAMESERVER("@","ns1.foo.com."))`},
 		{"Bad Hash function", `D(HASH("123", "abc"),"reg")`},
+		{"Bad PDNS_ZONE_KIND", `D("example.com","reg",PDNS_ZONE_KIND("Stub"))`},
+		{"Bad PDNS_SOA_EDIT_API", `D("example.com","reg",PDNS_SOA_EDIT_API("SOMETIMES"))`},
+		{"Bad PDNS_MASTERS", `D("example.com","reg",PDNS_MASTERS("ns1.example.com"))`},
+		{"Bad PDNS_MASTERS port", `D("example.com","reg",PDNS_MASTERS("192.0.2.1:70000"))`},
 	}
 	for _, tst := range tests {
 		t.Run(tst.desc, func(t *testing.T) {
@@ -173,3 +177,28 @@ func TestErrors(t *testing.T) {
 		})
 	}
 }
+
+func TestALLOCDoesNotWriteLockFile(t *testing.T) {
+	// Running dnsconfig.js (check, preview) must not write the lock file;
+	// only push saves conf.IPAM.
+	dir := t.TempDir()
+	file := filepath.Join(dir, "dnsconfig.js")
+	script := `D("example.com", NewRegistrar("none"), ALLOC(POOL("10.1.0.0/24"), "printer"))`
+	if err := os.WriteFile(file, []byte(script), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	conf, err := ExecuteJavaScript(file, true, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lock := filepath.Join(dir, "dnsconfig.ipam.json")
+	if _, err := os.Stat(lock); !os.IsNotExist(err) {
+		t.Fatalf("the lock file was written by ExecuteJavaScript: %v", err)
+	}
+	if err := conf.IPAM.Save(func(string) bool { return true }); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(lock); err != nil {
+		t.Fatalf("IPAM.Save() did not write the lock file: %v", err)
+	}
+}
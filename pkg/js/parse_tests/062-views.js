@@ -0,0 +1,23 @@
+var REG = NewRegistrar("none");
+var COMMON = [
+    A("@", "192.0.2.1"),
+    MX("@", 10, "mail"),
+    A("mail", "192.0.2.25"),
+    A("db", "10.0.0.5", VIEW_ONLY("internal")),
+    A("vpn", "10.0.0.6", VIEW_ONLY("internal", "vpn")),
+];
+
+D("example.com!internal", REG,
+    VIEW_MUST_MATCH("@", "mail"),
+    COMMON,
+    A("www", "10.0.0.80")
+);
+
+D("example.com!external", REG,
+    COMMON,
+    A("www", "192.0.2.80")
+);
+
+D("example.com!vpn", REG,
+    COMMON
+);
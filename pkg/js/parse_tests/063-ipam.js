@@ -0,0 +1,12 @@
+var REG = NewRegistrar("none");
+var LAB = POOL("10.1.0.0/24", { exclude: ["10.1.0.1", "10.1.0.240/28"] });
+var LAB6 = POOL("2001:db8:1::/64");
+
+D("example.com", REG,
+    ALLOC(LAB, "printer"),
+    ALLOC(LAB, "nas", TTL(300)),
+    ALLOC(LAB6, "nas")
+);
+
+D(REV("10.1.0.0/24"), REG, AUTOPTR);
+D(REV("2001:db8::/32"), REG, AUTOPTR);
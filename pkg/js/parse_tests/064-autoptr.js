@@ -0,0 +1,15 @@
+var REG = NewRegistrar("none");
+
+D("example.com", REG,
+    A("www", "10.0.0.80"),
+    A("db", "10.0.1.5"),
+    A("mail", "10.0.0.25"),
+    A("smtp", "10.0.0.25"),
+    AAAA("www", "2001:db8::80")
+);
+
+D(REV("10.0.0.0/24"), REG, AUTOPTR,
+    PTR("10.0.0.25", "mail.example.com.")
+);
+D(REV("10.0.1.0/26"), REG, AUTOPTR);
+D(REV("2001:db8::/32"), REG, AUTOPTR);
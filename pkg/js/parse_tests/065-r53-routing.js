@@ -0,0 +1,15 @@
+var REG = NewRegistrar("none");
+
+D("example.com", REG,
+    A("lat", "10.0.0.1", R53_LATENCY("us-east-1", "us")),
+    A("lat", "10.0.0.2", R53_LATENCY("eu-west-1", "eu")),
+    A("geo", "10.0.1.1", R53_GEO({continent: "EU"}, "europe")),
+    A("geo", "10.0.1.2", R53_GEO({country: "US", subdivision: "CA"}, "california")),
+    A("geo", "10.0.1.3", R53_GEO({country: "*"}, "default")),
+    A("prox", "10.0.2.1", R53_GEOPROXIMITY("us-east-1", 10, "east")),
+    A("prox", "10.0.2.2", R53_GEOPROXIMITY({latitude: "49.22", longitude: "-74.01"}, -5, "coords")),
+    A("fo", "10.0.3.1", R53_FAILOVER("PRIMARY", "main"), R53_HEALTH_CHECK_ID("hc-1")),
+    A("fo", "10.0.3.2", R53_FAILOVER("SECONDARY", "backup")),
+    A("mv", "10.0.4.1", R53_MULTIVALUE("one")),
+    A("mv", "10.0.4.2", R53_MULTIVALUE("two"))
+);
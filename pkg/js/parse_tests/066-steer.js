@@ -0,0 +1,11 @@
+var REG = NewRegistrar("none");
+
+D("example.com", REG,
+    A("www", "10.0.0.1", STEER({id: "east", weight: 70})),
+    A("www", "10.0.0.2", STEER({id: "west", weight: 30})),
+    A("geo", "10.0.1.1", STEER({id: "eu", geo: {continent: "EU"}})),
+    A("geo", "10.0.1.2", STEER({id: "ca", geo: {country: "US", subdivision: "CA"}})),
+    A("geo", "10.0.1.3", STEER({id: "default", geo: {country: "*"}})),
+    A("fo", "10.0.2.1", STEER({id: "main", failover: "primary", health_check: "hc-1"})),
+    A("fo", "10.0.2.2", STEER({id: "backup", failover: "secondary"}))
+);
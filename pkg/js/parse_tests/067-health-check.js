@@ -0,0 +1,10 @@
+var REG = NewRegistrar("none");
+
+HEALTH_CHECK("web", {type: "HTTPS", host: "www.example.com", path: "/health", interval: 10});
+HEALTH_CHECK("db", {type: "TCP", ip: "10.0.0.5", port: 5432, failure_threshold: 2});
+
+D("example.com", REG,
+    A("www", "10.0.0.1", R53_FAILOVER("PRIMARY", "main"), R53_HEALTH_CHECK_ID("web")),
+    A("www", "10.0.0.2", R53_FAILOVER("SECONDARY", "backup")),
+    A("db", "10.0.0.5", STEER({id: "db", weight: 1, health_check: "db"}))
+);
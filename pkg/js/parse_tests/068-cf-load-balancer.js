@@ -0,0 +1,17 @@
+D("foo.com", "none",
+    CF_LOAD_BALANCER("www", [
+        {
+            name: "eu",
+            origins: [
+                {name: "web1", address: "192.0.2.1"},
+                {name: "web2", address: "192.0.2.2", weight: 0.5},
+            ],
+            monitor: {type: "https", path: "/health"},
+            regions: ["WEU", "EEU"],
+        },
+        {name: "fallback", origins: [{name: "dr", address: "198.51.100.1"}]},
+    ], "geo", CF_PROXY_ON),
+    CF_LOAD_BALANCER("api", [
+        {name: "api", origins: [{name: "api1", address: "api1.example.net"}], monitor: {type: "tcp", port: 443}},
+    ], "off", TTL(300)),
+);
@@ -0,0 +1,5 @@
+D("foo.com", "none",
+    CF_ZONE_SETTINGS({ssl: "strict", min_tls_version: "1.2", hsts: {enabled: true, max_age: 31536000}}),
+    CF_ZONE_SETTINGS({always_use_https: "on", ssl: "full"}),
+    A("@", "1.2.3.4")
+);
@@ -0,0 +1,14 @@
+var REG = NewRegistrar("none");
+
+D("example.com", REG,
+    PDNS_ZONE_KIND("Slave"),
+    PDNS_MASTERS("192.0.2.1", "192.0.2.2:5300"),
+    PDNS_AXFR_MASTER_TSIG("transfer-key"),
+    PDNS_SOA_EDIT_API("")
+);
+D("example.net", REG,
+    PDNS_ZONE_KIND("Master"),
+    PDNS_ALLOW_AXFR_FROM(["198.51.100.0/24", "AUTO-NS"]),
+    PDNS_TSIG_ALLOW_AXFR("key1", "key2"),
+    PDNS_NSEC3PARAM("1 0 0 -")
+);
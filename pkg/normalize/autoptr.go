@@ -0,0 +1,138 @@
+package normalize
+
+import (
+	"fmt"
+	"net/netip"
+	"slices"
+	"strings"
+
+	"github.com/DNSControl/dnscontrol/v4/models"
+	"github.com/DNSControl/dnscontrol/v4/pkg/transform"
+)
+
+// IsReverseZone reports whether a zone is a reverse lookup zone.
+func IsReverseZone(name string) bool {
+	return strings.HasSuffix(name, ".in-addr.arpa") || strings.HasSuffix(name, ".ip6.arpa")
+}
+
+// processAutoPTR adds PTR records to the reverse zones with AUTOPTR, for
+// the A and AAAA records of the forward zones.
+//
+// An address belongs to the most specific reverse zone that contains it,
+// including RFC 2317 and RFC 4183 classless zones. If that zone does not
+// have AUTOPTR, no PTR is added. If the zone has views, the PTR goes to the
+// view with the same tag as the forward zone if there is one, else to all
+// of them.
+//
+// A PTR() in the reverse zone takes precedence. Otherwise it is an error
+// for several names to have the same address, since only one can be
+// chosen.
+func processAutoPTR(config *models.DNSConfig) (errs []error) {
+	var reverse []*models.DomainConfig
+	auto := false
+	for _, dc := range config.Domains {
+		if IsReverseZone(dc.Name) {
+			reverse = append(reverse, dc)
+			auto = auto || dc.Metadata["autoptr"] == "true"
+		} else if dc.Metadata["autoptr"] == "true" {
+			errs = append(errs, fmt.Errorf("AUTOPTR: %s is not a reverse zone", dc.UniqueName))
+		}
+	}
+	if !auto {
+		return errs
+	}
+
+	type claim struct {
+		addr  netip.Addr
+		names []string
+		ttl   uint32
+		pos   string
+	}
+	claims := map[*models.DomainConfig]map[string]*claim{} // By reverse view, then label.
+	var order []*models.DomainConfig
+
+	for _, dc := range config.Domains {
+		if IsReverseZone(dc.Name) {
+			continue
+		}
+		for _, rec := range dc.Records {
+			if rec.Type != "A" && rec.Type != "AAAA" {
+				continue
+			}
+			name := rec.GetLabelFQDN()
+			if strings.HasPrefix(name, "*.") {
+				continue
+			}
+			addr, err := netip.ParseAddr(rec.GetTargetField())
+			if err != nil {
+				continue
+			}
+
+			// Find the most specific reverse zone for the address.
+			zone := ""
+			var views []*models.DomainConfig
+			for _, rz := range reverse {
+				if addr.Is4() != strings.HasSuffix(rz.Name, ".in-addr.arpa") {
+					continue
+				}
+				if _, err := transform.PtrNameMagic(addr.String(), rz.Name); err != nil || len(rz.Name) < len(zone) {
+					continue
+				}
+				if len(rz.Name) > len(zone) {
+					zone, views = rz.Name, nil
+				}
+				views = append(views, rz)
+			}
+			if sameTag := slices.DeleteFunc(slices.Clone(views), func(v *models.DomainConfig) bool { return v.Tag != dc.Tag }); len(sameTag) > 0 {
+				views = sameTag
+			}
+
+			for _, rz := range views {
+				if rz.Metadata["autoptr"] != "true" {
+					continue
+				}
+				label, _ := transform.PtrNameMagic(addr.String(), rz.Name)
+				if claims[rz] == nil {
+					claims[rz] = map[string]*claim{}
+					order = append(order, rz)
+				}
+				c := claims[rz][label]
+				if c == nil {
+					c = &claim{addr: addr, ttl: rec.TTL, pos: rec.FilePos}
+					claims[rz][label] = c
+				}
+				if !slices.Contains(c.names, name) {
+					c.names = append(c.names, name)
+				}
+			}
+		}
+	}
+
+	for _, rz := range order {
+		var labels []string
+		for label := range claims[rz] {
+			labels = append(labels, label)
+		}
+		slices.Sort(labels)
+		for _, label := range labels {
+			c := claims[rz][label]
+			if rz.Records.HasRecordTypeName("PTR", label) {
+				continue
+			}
+			if len(c.names) > 1 {
+				slices.Sort(c.names)
+				errs = append(errs, fmt.Errorf("AUTOPTR: %s in %s is the address of %s; add a PTR() to choose one",
+					c.addr, rz.UniqueName, strings.Join(c.names, ", ")))
+				continue
+			}
+			r := &models.RecordConfig{Type: "PTR", TTL: c.ttl, Metadata: map[string]string{}, FilePos: c.pos}
+			r.SetLabel(label, rz.Name)
+			if err := r.SetTarget(c.names[0] + "."); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			rz.Records = append(rz.Records, r)
+		}
+	}
+	return errs
+}
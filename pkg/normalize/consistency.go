@@ -0,0 +1,24 @@
+package normalize
+
+import (
+	"github.com/DNSControl/dnscontrol/v4/models"
+	"github.com/DNSControl/dnscontrol/v4/pkg/providers"
+)
+
+// EffectiveRecords returns the records that one of the DNS providers of dc
+// would serve after a push: dc.Records adjusted to the provider's TTL
+// policy, with the changes the provider makes itself (see
+// providers.RecordTransformer). dc is not modified.
+func EffectiveRecords(dc *models.DomainConfig, provider *models.DNSProviderInstance) (models.Records, error) {
+	dc, err := dc.Copy()
+	if err != nil {
+		return nil, err
+	}
+	ApplyTTLPolicy(dc, provider.ProviderType)
+	if t, ok := provider.Driver.(providers.RecordTransformer); ok {
+		if err := t.TransformRecords(dc); err != nil {
+			return nil, err
+		}
+	}
+	return dc.Records, nil
+}
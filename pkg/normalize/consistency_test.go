@@ -0,0 +1,47 @@
+package normalize
+
+import (
+	"testing"
+
+	"github.com/DNSControl/dnscontrol/v4/models"
+	"github.com/DNSControl/dnscontrol/v4/pkg/providers"
+)
+
+// aliasFlattener converts all ALIAS records into CNAMEs.
+type aliasFlattener struct{ providers.None }
+
+func (aliasFlattener) TransformRecords(dc *models.DomainConfig) error {
+	for _, rec := range dc.Records {
+		if rec.Type == "ALIAS" {
+			rec.ChangeType("CNAME", dc.Name)
+		}
+	}
+	return nil
+}
+
+func TestEffectiveRecords(t *testing.T) {
+	providers.TTLPolicies["TEST_TTL_MIN600"] = providers.TTLPolicy{Min: 600}
+	defer delete(providers.TTLPolicies, "TEST_TTL_MIN600")
+
+	dc := &models.DomainConfig{
+		Name: "example.com",
+		Records: models.Records{
+			makeRC("www", "example.com", "1.2.3.4", models.RecordConfig{Type: "A", TTL: 300}),
+			makeRC("cdn", "example.com", "cdn.example.net.", models.RecordConfig{Type: "ALIAS", TTL: 300}),
+		},
+	}
+	p := &models.DNSProviderInstance{
+		ProviderBase: models.ProviderBase{Name: "test", ProviderType: "TEST_TTL_MIN600"},
+		Driver:       aliasFlattener{},
+	}
+	recs, err := EffectiveRecords(dc, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recs[0].TTL != 600 || recs[1].Type != "CNAME" {
+		t.Errorf("got %s %d, %s %d; want A 600, CNAME 600", recs[0].Type, recs[0].TTL, recs[1].Type, recs[1].TTL)
+	}
+	if dc.Records[0].TTL != 300 || dc.Records[1].Type != "ALIAS" {
+		t.Errorf("dc was modified")
+	}
+}
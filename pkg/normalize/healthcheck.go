@@ -0,0 +1,144 @@
+package normalize
+
+import (
+	"fmt"
+	"net/netip"
+	"slices"
+	"strings"
+
+	"github.com/DNSControl/dnscontrol/v4/models"
+)
+
+// healthCheckRefKeys are the metadata keys with which records refer to a
+// health check, by ID or by the name of a HEALTH_CHECK().
+var healthCheckRefKeys = []string{"r53_health_check_id", "steer_health_check"}
+
+// processHealthChecks validates the HEALTH_CHECK() declarations and fills
+// in their defaults. Records that refer to one by name get the
+// "health_check" metadata, so that providers can replace the name with
+// the health check's ID. A health check no record refers to is not
+// created by any provider, and is reported with a warning.
+func processHealthChecks(config *models.DNSConfig) (errs []error) {
+	byName := map[string]*models.HealthCheck{}
+	for _, hc := range config.HealthChecks {
+		if hc.Name == "" {
+			errs = append(errs, fmt.Errorf("HEALTH_CHECK: the name can not be empty"))
+			continue
+		}
+		if byName[hc.Name] != nil {
+			errs = append(errs, fmt.Errorf("HEALTH_CHECK %q is declared more than once", hc.Name))
+			continue
+		}
+		byName[hc.Name] = hc
+		for _, err := range checkHealthCheck(hc) {
+			errs = append(errs, fmt.Errorf("HEALTH_CHECK %q: %w", hc.Name, err))
+		}
+	}
+	if len(byName) == 0 {
+		return errs
+	}
+
+	used := map[string]bool{}
+	for _, dc := range config.Domains {
+		for _, rec := range dc.Records {
+			for _, k := range healthCheckRefKeys {
+				if name := rec.Metadata[k]; byName[name] != nil {
+					rec.Metadata["health_check"] = name
+					used[name] = true
+				}
+			}
+		}
+	}
+	for _, hc := range config.HealthChecks {
+		if byName[hc.Name] == hc && !used[hc.Name] {
+			errs = append(errs, Warning{fmt.Errorf("HEALTH_CHECK %q is not used by any record, so no provider will create it", hc.Name)})
+		}
+	}
+	return errs
+}
+
+// checkHealthCheck validates a health check and fills in its defaults.
+func checkHealthCheck(hc *models.HealthCheck) (errs []error) {
+	hc.Type = strings.ToUpper(hc.Type)
+	switch hc.Type {
+	case "HTTP", "HTTPS":
+		if hc.Path == "" {
+			hc.Path = "/"
+		}
+		if !strings.HasPrefix(hc.Path, "/") {
+			errs = append(errs, fmt.Errorf("path %q must start with /", hc.Path))
+		}
+		if hc.Port == 0 {
+			hc.Port = map[string]int{"HTTP": 80, "HTTPS": 443}[hc.Type]
+		}
+	case "TCP":
+		if hc.Path != "" {
+			errs = append(errs, fmt.Errorf("a TCP health check can not have a path"))
+		}
+		if hc.Port == 0 {
+			errs = append(errs, fmt.Errorf("a TCP health check needs a port"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("type %q must be one of HTTP, HTTPS or TCP", hc.Type))
+	}
+
+	if hc.Host == "" && hc.IP == "" {
+		errs = append(errs, fmt.Errorf("a host or an ip is required"))
+	}
+	if hc.IP != "" {
+		if _, err := netip.ParseAddr(hc.IP); err != nil {
+			errs = append(errs, fmt.Errorf("ip %q is not an IP address", hc.IP))
+		}
+	}
+	hc.Host = strings.TrimSuffix(hc.Host, ".")
+	if hc.Port < 0 || hc.Port > 65535 {
+		errs = append(errs, fmt.Errorf("port %d must be between 1 and 65535", hc.Port))
+	}
+
+	if hc.Interval == 0 {
+		hc.Interval = 30
+	}
+	if hc.FailureThreshold == 0 {
+		hc.FailureThreshold = 3
+	}
+	if hc.Interval < 0 || hc.FailureThreshold < 0 {
+		errs = append(errs, fmt.Errorf("interval and failure_threshold must be positive"))
+	}
+	return errs
+}
+
+// unsupportedHealthCheck returns the error for a zone whose records use a
+// HEALTH_CHECK() while its DNS provider of type pType can not manage them.
+// Only Route 53 can, so far: NS1 monitoring jobs and Cloudflare load
+// balancer monitors are not implemented.
+func unsupportedHealthCheck(dc *models.DomainConfig, pType string) error {
+	for _, rec := range dc.Records {
+		if name := rec.Metadata["health_check"]; name != "" {
+			return fmt.Errorf("domain %s: %s %s uses HEALTH_CHECK %q, but DNS provider type %s can not manage health checks", dc.Name, rec.Type, rec.GetLabelFQDN(), name, pType)
+		}
+	}
+	return nil
+}
+
+// HealthChecksFor returns the health checks that the records of the zones
+// using the DNS provider named provider refer to, in declaration order.
+func HealthChecksFor(config *models.DNSConfig, provider string) []*models.HealthCheck {
+	used := map[string]bool{}
+	for _, dc := range config.Domains {
+		if !slices.ContainsFunc(dc.DNSProviderInstances, func(p *models.DNSProviderInstance) bool { return p.Name == provider }) {
+			continue
+		}
+		for _, rec := range dc.Records {
+			if name := rec.Metadata["health_check"]; name != "" {
+				used[name] = true
+			}
+		}
+	}
+	var checks []*models.HealthCheck
+	for _, hc := range config.HealthChecks {
+		if used[hc.Name] {
+			checks = append(checks, hc)
+		}
+	}
+	return checks
+}
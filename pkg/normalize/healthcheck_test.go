@@ -0,0 +1,104 @@
+package normalize
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/DNSControl/dnscontrol/v4/models"
+)
+
+func TestProcessHealthChecks(t *testing.T) {
+	web := &models.HealthCheck{Name: "web", Type: "https", Host: "www.example.com."}
+	db := &models.HealthCheck{Name: "db", Type: "TCP", IP: "10.0.0.5", Port: 5432, Interval: 10}
+	unused := &models.HealthCheck{Name: "unused", Type: "HTTP", IP: "10.0.0.6"}
+
+	r53 := &models.DNSProviderInstance{ProviderBase: models.ProviderBase{Name: "r53"}}
+	other := &models.DNSProviderInstance{ProviderBase: models.ProviderBase{Name: "other"}}
+	config := &models.DNSConfig{
+		HealthChecks: []*models.HealthCheck{web, db, unused},
+		Domains: []*models.DomainConfig{
+			{
+				Name:                 "example.com",
+				DNSProviderInstances: []*models.DNSProviderInstance{r53},
+				Records: models.Records{
+					makeRC("www", "example.com", "1.2.3.4", models.RecordConfig{Type: "A", Metadata: map[string]string{"r53_set_identifier": "a", "r53_failover": "PRIMARY", "r53_health_check_id": "web"}}),
+					makeRC("www", "example.com", "5.6.7.8", models.RecordConfig{Type: "A", Metadata: map[string]string{"r53_set_identifier": "b", "r53_failover": "SECONDARY", "r53_health_check_id": "0a1b2c3d-id"}}),
+				},
+			},
+			{
+				Name:                 "example.net",
+				DNSProviderInstances: []*models.DNSProviderInstance{other},
+				Records: models.Records{
+					makeRC("db", "example.net", "10.0.0.5", models.RecordConfig{Type: "A", Metadata: map[string]string{"steer_id": "a", "steer_weight": "1", "steer_health_check": "db"}}),
+				},
+			},
+		},
+	}
+
+	errs := processHealthChecks(config)
+	if len(errs) != 1 || !errors.As(errs[0], &Warning{}) || !strings.Contains(errs[0].Error(), `"unused"`) {
+		t.Fatalf("expected a warning about the unused health check, got %v", errs)
+	}
+
+	// Defaults.
+	if web.Type != "HTTPS" || web.Port != 443 || web.Path != "/" || web.Host != "www.example.com" || web.Interval != 30 || web.FailureThreshold != 3 {
+		t.Errorf("unexpected defaults: %+v", web)
+	}
+	if db.Interval != 10 || db.Port != 5432 {
+		t.Errorf("unexpected defaults: %+v", db)
+	}
+
+	// References.
+	recs := config.Domains[0].Records
+	if recs[0].Metadata["health_check"] != "web" || recs[1].Metadata["health_check"] != "" {
+		t.Errorf("unexpected references: %v, %v", recs[0].Metadata, recs[1].Metadata)
+	}
+	if got := HealthChecksFor(config, "r53"); len(got) != 1 || got[0] != web {
+		t.Errorf("HealthChecksFor(r53) = %v, want [web]", got)
+	}
+	if got := HealthChecksFor(config, "other"); len(got) != 1 || got[0] != db {
+		t.Errorf("HealthChecksFor(other) = %v, want [db]", got)
+	}
+}
+
+func TestCheckHealthCheck(t *testing.T) {
+	tests := []struct {
+		hc   models.HealthCheck
+		want string
+	}{
+		{models.HealthCheck{Type: "HTTP", Host: "example.com"}, ""},
+		{models.HealthCheck{Type: "PING", Host: "example.com"}, "must be one of"},
+		{models.HealthCheck{Type: "HTTP"}, "a host or an ip"},
+		{models.HealthCheck{Type: "HTTP", IP: "not-an-ip"}, "not an IP address"},
+		{models.HealthCheck{Type: "HTTP", Host: "example.com", Path: "health"}, "must start with /"},
+		{models.HealthCheck{Type: "TCP", Host: "example.com"}, "needs a port"},
+		{models.HealthCheck{Type: "TCP", Host: "example.com", Port: 22, Path: "/"}, "can not have a path"},
+		{models.HealthCheck{Type: "HTTPS", Host: "example.com", Port: 70000}, "between 1 and 65535"},
+	}
+	for _, tst := range tests {
+		errs := checkHealthCheck(&tst.hc)
+		switch {
+		case tst.want == "" && len(errs) != 0:
+			t.Errorf("%+v: unexpected errors: %v", tst.hc, errs)
+		case tst.want != "" && (len(errs) != 1 || !strings.Contains(errs[0].Error(), tst.want)):
+			t.Errorf("%+v: expected an error containing %q, got %v", tst.hc, tst.want, errs)
+		}
+	}
+}
+
+func TestHealthCheckCapability(t *testing.T) {
+	dc := &models.DomainConfig{
+		Name: "example.com",
+		Records: models.Records{
+			makeRC("www", "example.com", "1.2.3.4", models.RecordConfig{Type: "A", Metadata: map[string]string{"steer_id": "a", "steer_health_check": "web", "health_check": "web"}}),
+		},
+		DNSProviderInstances: []*models.DNSProviderInstance{
+			{ProviderBase: models.ProviderBase{ProviderType: "NS1"}},
+		},
+	}
+	want := `domain example.com: A www.example.com uses HEALTH_CHECK "web", but DNS provider type NS1 can not manage health checks`
+	if err := checkProviderCapabilities(dc); err == nil || err.Error() != want {
+		t.Errorf("got %v, want %q", err, want)
+	}
+}
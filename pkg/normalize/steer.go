@@ -0,0 +1,80 @@
+package normalize
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/DNSControl/dnscontrol/v4/models"
+)
+
+// steering describes the provider-neutral traffic steering policies of
+// STEER(). Providers with the CanUseSteering capability translate them to
+// their own routing policies in their RecordAuditor.
+var steering = routingScheme{
+	name:  "STEER",
+	idKey: "steer_id",
+	keys: []routingKey{
+		{"steer_weight", "weight", "weights"},
+		{"steer_geo_continent", "geo", "continents"},
+		{"steer_geo_country", "geo", "countries"},
+		{"steer_geo_subdivision", "geo", "subdivisions"},
+		{"steer_failover", "failover", "failover roles"},
+		{"steer_health_check", "", "health checks"},
+	},
+	failoverKey: "steer_failover",
+	geoKeys:     []string{"steer_geo_continent", "steer_geo_country", "steer_geo_subdivision"},
+}
+
+// checkSteering validates the STEER() metadata of records: each record
+// has an id and exactly one policy with a valid value, and the groups are
+// consistent.
+func checkSteering(records []*models.RecordConfig) (errs []error) {
+	for _, rc := range records {
+		m := rc.Metadata
+		var policies []string
+		for _, k := range steering.keys {
+			if k.policy != "" && m[k.key] != "" && !slices.Contains(policies, k.policy) {
+				policies = append(policies, k.policy)
+			}
+		}
+		id := m[steering.idKey]
+		if id == "" && len(policies) == 0 && m["steer_health_check"] == "" {
+			continue
+		}
+
+		where := rc.Type + " " + rc.GetLabelFQDN()
+		switch {
+		case id == "":
+			errs = append(errs, fmt.Errorf("STEER: %s has a steering policy but no id", where))
+		case len(policies) == 0:
+			errs = append(errs, fmt.Errorf("STEER: %s needs one of weight, geo or failover", where))
+		case len(policies) > 1:
+			errs = append(errs, fmt.Errorf("STEER: %s can only use one of weight, geo or failover", where))
+		}
+
+		if w := m["steer_weight"]; w != "" {
+			if _, err := strconv.ParseUint(w, 10, 32); err != nil {
+				errs = append(errs, fmt.Errorf("STEER: weight %q of %s is not a non-negative integer", w, where))
+			}
+		}
+		if c := m["steer_geo_continent"]; c != "" {
+			if !slices.Contains(models.Continents, c) {
+				errs = append(errs, fmt.Errorf("STEER: continent %q of %s is not one of %v", c, where, models.Continents))
+			}
+			if m["steer_geo_country"] != "" {
+				errs = append(errs, fmt.Errorf("STEER: %s can not have both a continent and a country", where))
+			}
+		}
+		if m["steer_geo_subdivision"] != "" && m["steer_geo_country"] == "" {
+			errs = append(errs, fmt.Errorf("STEER: the subdivision of %s requires a country", where))
+		}
+		if f := m["steer_failover"]; f != "" && f != "PRIMARY" && f != "SECONDARY" {
+			errs = append(errs, fmt.Errorf("STEER: failover %q of %s must be PRIMARY or SECONDARY", f, where))
+		}
+	}
+	if len(errs) != 0 {
+		return errs
+	}
+	return steering.checkGroupConsistency(records)
+}
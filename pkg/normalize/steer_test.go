@@ -0,0 +1,124 @@
+package normalize
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/DNSControl/dnscontrol/v4/models"
+)
+
+func TestCheckSteering(t *testing.T) {
+	steered := func(target string, meta map[string]string) *models.RecordConfig {
+		return makeRC("www", "example.com", target, models.RecordConfig{Type: "A", Metadata: meta})
+	}
+	tests := []struct {
+		name    string
+		records []*models.RecordConfig
+		want    []string
+	}{
+		{
+			name: "weights",
+			records: []*models.RecordConfig{
+				steered("1.2.3.4", map[string]string{"steer_id": "east", "steer_weight": "70"}),
+				steered("5.6.7.8", map[string]string{"steer_id": "west", "steer_weight": "30"}),
+			},
+		},
+		{
+			name: "geo",
+			records: []*models.RecordConfig{
+				steered("1.2.3.4", map[string]string{"steer_id": "eu", "steer_geo_continent": "EU"}),
+				steered("5.6.7.8", map[string]string{"steer_id": "ca", "steer_geo_country": "US", "steer_geo_subdivision": "CA"}),
+				steered("9.9.9.9", map[string]string{"steer_id": "default", "steer_geo_country": "*"}),
+			},
+		},
+		{
+			name: "failover",
+			records: []*models.RecordConfig{
+				steered("1.2.3.4", map[string]string{"steer_id": "main", "steer_failover": "PRIMARY", "steer_health_check": "hc"}),
+				steered("5.6.7.8", map[string]string{"steer_id": "backup", "steer_failover": "SECONDARY"}),
+			},
+		},
+		{
+			name: "no id",
+			records: []*models.RecordConfig{
+				steered("1.2.3.4", map[string]string{"steer_weight": "70"}),
+			},
+			want: []string{"no id"},
+		},
+		{
+			name: "no policy",
+			records: []*models.RecordConfig{
+				steered("1.2.3.4", map[string]string{"steer_id": "a"}),
+			},
+			want: []string{"needs one of"},
+		},
+		{
+			name: "two policies",
+			records: []*models.RecordConfig{
+				steered("1.2.3.4", map[string]string{"steer_id": "a", "steer_weight": "1", "steer_failover": "PRIMARY"}),
+			},
+			want: []string{"only use one of"},
+		},
+		{
+			name: "bad values",
+			records: []*models.RecordConfig{
+				steered("1.2.3.4", map[string]string{"steer_id": "a", "steer_weight": "-1"}),
+				steered("5.6.7.8", map[string]string{"steer_id": "b", "steer_geo_continent": "XX"}),
+				steered("9.9.9.9", map[string]string{"steer_id": "c", "steer_failover": "TERTIARY"}),
+			},
+			want: []string{"not a non-negative integer", "is not one of", "must be PRIMARY or SECONDARY"},
+		},
+		{
+			name: "mixed policies",
+			records: []*models.RecordConfig{
+				steered("1.2.3.4", map[string]string{"steer_id": "a", "steer_weight": "1"}),
+				steered("5.6.7.8", map[string]string{"steer_id": "b", "steer_geo_country": "US"}),
+			},
+			want: []string{"uses geo routing, but other groups use weight routing"},
+		},
+		{
+			name: "two primaries",
+			records: []*models.RecordConfig{
+				steered("1.2.3.4", map[string]string{"steer_id": "a", "steer_failover": "PRIMARY"}),
+				steered("5.6.7.8", map[string]string{"steer_id": "b", "steer_failover": "PRIMARY"}),
+			},
+			want: []string{"are both PRIMARY"},
+		},
+		{
+			name: "mixed with plain records",
+			records: []*models.RecordConfig{
+				steered("1.2.3.4", map[string]string{"steer_id": "a", "steer_weight": "1"}),
+				steered("5.6.7.8", nil),
+			},
+			want: []string{"with and without a routing policy"},
+		},
+	}
+	for _, tst := range tests {
+		t.Run(tst.name, func(t *testing.T) {
+			errs := checkSteering(tst.records)
+			if len(errs) != len(tst.want) {
+				t.Fatalf("got %d errors, want %d: %v", len(errs), len(tst.want), errs)
+			}
+			for i, e := range errs {
+				if !strings.Contains(e.Error(), tst.want[i]) {
+					t.Errorf("error %q does not contain %q", e, tst.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSteeringCapability(t *testing.T) {
+	dc := &models.DomainConfig{
+		Name: "example.com",
+		Records: models.Records{
+			makeRC("www", "example.com", "1.2.3.4", models.RecordConfig{Type: "A", Metadata: map[string]string{"steer_id": "a", "steer_weight": "1"}}),
+		},
+		DNSProviderInstances: []*models.DNSProviderInstance{
+			{ProviderBase: models.ProviderBase{ProviderType: "TEST_NO_STEERING"}},
+		},
+	}
+	if err := checkProviderCapabilities(dc); err == nil || !strings.Contains(err.Error(), "STEER") {
+		t.Errorf("expected a STEER capability error, got %v", err)
+	}
+}
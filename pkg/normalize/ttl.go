@@ -0,0 +1,88 @@
+package normalize
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/DNSControl/dnscontrol/v4/models"
+	"github.com/DNSControl/dnscontrol/v4/pkg/providers"
+)
+
+// ApplyTTLPolicy adjusts the TTLs of dc.Records to the TTL policy of the
+// provider type, if it has one. dc should be the provider's own copy of
+// the zone. It returns a message for each adjustment, to be shown in
+// preview.
+func ApplyTTLPolicy(dc *models.DomainConfig, providerType string) []string {
+	policy, ok := providers.TTLPolicies[providerType]
+	if !ok {
+		return nil
+	}
+
+	type change struct{ from, to uint32 }
+	var order []change
+	names := map[change][]string{}
+	for _, rec := range dc.Records {
+		ttl := policy.Apply(rec.TTL)
+		if ttl == rec.TTL {
+			continue
+		}
+		c := change{rec.TTL, ttl}
+		if names[c] == nil {
+			order = append(order, c)
+		}
+		if name := rec.GetLabelFQDN() + " " + rec.Type; !slices.Contains(names[c], name) {
+			names[c] = append(names[c], name)
+		}
+		rec.TTL = ttl
+	}
+
+	var msgs []string
+	for _, c := range order {
+		msgs = append(msgs, fmt.Sprintf("TTL %d adjusted to %d (%s TTL policy): %s", c.from, c.to, providerType, strings.Join(names[c], ", ")))
+	}
+	return msgs
+}
+
+// checkTTLPolicies warns about the records of a zone with several DNS
+// providers whose TTL the providers' TTL policies would adjust
+// differently. The providers would then serve different TTLs.
+func checkTTLPolicies(dc *models.DomainConfig) (errs []error) {
+	var types []string
+	for _, p := range dc.DNSProviderInstances {
+		// "-" means the type is not known yet (see checkProviderCapabilities).
+		if p.ProviderType != "-" && !slices.Contains(types, p.ProviderType) {
+			types = append(types, p.ProviderType)
+		}
+	}
+	if len(types) < 2 {
+		return nil
+	}
+
+	seen := map[string]bool{}
+	for _, rec := range dc.Records {
+		var ttls []string
+		diverge := false
+		var first uint32
+		for i, t := range types {
+			ttl := rec.TTL
+			if policy, ok := providers.TTLPolicies[t]; ok {
+				ttl = policy.Apply(ttl)
+			}
+			if i == 0 {
+				first = ttl
+			} else if ttl != first {
+				diverge = true
+			}
+			ttls = append(ttls, fmt.Sprintf("%s %d", t, ttl))
+		}
+		key := fmt.Sprintf("%s %s %d", rec.GetLabelFQDN(), rec.Type, rec.TTL)
+		if !diverge || seen[key] {
+			continue
+		}
+		seen[key] = true
+		errs = append(errs, Warning{fmt.Errorf("TTL %d of %s %s is adjusted differently by the DNS providers of %s: %s",
+			rec.TTL, rec.GetLabelFQDN(), rec.Type, dc.UniqueName, strings.Join(ttls, ", "))})
+	}
+	return errs
+}
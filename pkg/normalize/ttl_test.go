@@ -0,0 +1,74 @@
+package normalize
+
+import (
+	"testing"
+
+	"github.com/DNSControl/dnscontrol/v4/models"
+	"github.com/DNSControl/dnscontrol/v4/pkg/providers"
+)
+
+func TestTTLPolicyApply(t *testing.T) {
+	tests := []struct {
+		policy providers.TTLPolicy
+		ttl    uint32
+		want   uint32
+	}{
+		{providers.TTLPolicy{}, 30, 30},
+		{providers.TTLPolicy{Min: 60, Max: 86400, Auto: 1}, 1, 1},
+		{providers.TTLPolicy{Min: 60, Max: 86400, Auto: 1}, 30, 60},
+		{providers.TTLPolicy{Min: 60, Max: 86400, Auto: 1}, 100000, 86400},
+		{providers.TTLPolicy{Allowed: []uint32{300, 3600, 86400}}, 300, 300},
+		{providers.TTLPolicy{Allowed: []uint32{300, 3600, 86400}}, 600, 3600},
+		{providers.TTLPolicy{Allowed: []uint32{300, 3600, 86400}}, 30, 300},
+		{providers.TTLPolicy{Allowed: []uint32{300, 3600, 86400}}, 100000, 86400},
+	}
+	for _, tst := range tests {
+		if got := tst.policy.Apply(tst.ttl); got != tst.want {
+			t.Errorf("%+v.Apply(%d) = %d, want %d", tst.policy, tst.ttl, got, tst.want)
+		}
+	}
+}
+
+func TestApplyTTLPolicy(t *testing.T) {
+	providers.TTLPolicies["TEST_TTL_MIN600"] = providers.TTLPolicy{Min: 600}
+	providers.TTLPolicies["TEST_TTL_MIN300"] = providers.TTLPolicy{Min: 300}
+	defer delete(providers.TTLPolicies, "TEST_TTL_MIN600")
+	defer delete(providers.TTLPolicies, "TEST_TTL_MIN300")
+
+	dc := &models.DomainConfig{
+		Name: "example.com",
+		Records: models.Records{
+			makeRC("www", "example.com", "1.2.3.4", models.RecordConfig{Type: "A", TTL: 300}),
+			makeRC("www", "example.com", "1.2.3.5", models.RecordConfig{Type: "A", TTL: 300}),
+			makeRC("mail", "example.com", "1.2.3.6", models.RecordConfig{Type: "A", TTL: 3600}),
+		},
+	}
+
+	t.Run("apply", func(t *testing.T) {
+		dc, _ := dc.Copy()
+		msgs := ApplyTTLPolicy(dc, "TEST_TTL_MIN600")
+		if len(msgs) != 1 || msgs[0] != "TTL 300 adjusted to 600 (TEST_TTL_MIN600 TTL policy): www.example.com A" {
+			t.Errorf("got %q", msgs)
+		}
+		for _, r := range dc.Records {
+			if r.TTL < 600 {
+				t.Errorf("%s: TTL %d not adjusted", r.GetLabelFQDN(), r.TTL)
+			}
+		}
+		if msgs := ApplyTTLPolicy(dc, "NONE"); msgs != nil {
+			t.Errorf("no policy: got %q", msgs)
+		}
+	})
+
+	t.Run("dual host", func(t *testing.T) {
+		dc.DNSProviderInstances = []*models.DNSProviderInstance{
+			{ProviderBase: models.ProviderBase{ProviderType: "TEST_TTL_MIN600"}},
+			{ProviderBase: models.ProviderBase{ProviderType: "TEST_TTL_MIN300"}},
+		}
+		if errs := checkTTLPolicies(dc); len(errs) != 1 {
+			t.Errorf("got %v, want 1 warning", errs)
+		} else if _, ok := errs[0].(Warning); !ok {
+			t.Errorf("got %v, want a warning", errs[0])
+		}
+	})
+}
@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/DNSControl/dnscontrol/v4/models"
+	"github.com/DNSControl/dnscontrol/v4/pkg/pdnslua"
 	"github.com/DNSControl/dnscontrol/v4/pkg/providers"
 	"github.com/DNSControl/dnscontrol/v4/pkg/transform"
 	dnsv1 "github.com/miekg/dns"
@@ -245,6 +246,11 @@ func checkTargets(rec *models.RecordConfig, domain string) (errs []error) {
 			check(fmt.Errorf("LUA emitted rtype (%s) is not a valid DNS type", rec.LuaRType))
 		}
 		rec.LuaRType = upper
+		warnings, err := pdnslua.Check(target)
+		check(err)
+		for _, w := range warnings {
+			check(Warning{errors.New(w)})
+		}
 	case "CAA", "DHCID", "DNSKEY", "DS", "HTTPS", "IMPORT_TRANSFORM", "OPENPGPKEY", "SMIMEA", "SSHFP", "SVCB", "TLSA", "TXT":
 	default:
 		if rec.Metadata["orig_custom_type"] != "" {
@@ -554,6 +560,12 @@ func ValidateAndNormalizeConfig(config *models.DNSConfig) (errs []error) {
 			errs = append(errs, err)
 		}
 	}
+	// Apply VIEW_ONLY and check VIEW_MUST_MATCH across split horizon views
+	errs = append(errs, processViews(config)...)
+	// Add the PTRs of the reverse zones with AUTOPTR
+	errs = append(errs, processAutoPTR(config)...)
+	// Validate the HEALTH_CHECK()s and find the records that use them
+	errs = append(errs, processHealthChecks(config)...)
 
 	for _, d := range config.Domains {
 		// Check that CNAMES don't have to co-exist with any other records
@@ -567,8 +579,12 @@ func ValidateAndNormalizeConfig(config *models.DNSConfig) (errs []error) {
 		errs = append(errs, checkDuplicates(d.Records)...)
 		// Check for different TTLs under the same label
 		errs = append(errs, checkRecordSetHasMultipleTTLs(d.Records)...)
-		// Check for inconsistent R53 weighted routing metadata within a group
-		errs = append(errs, checkR53WeightedGroupConsistency(d.Records)...)
+		// Check for TTLs that the DNS providers would adjust differently
+		errs = append(errs, checkTTLPolicies(d)...)
+		// Check for inconsistent R53 routing metadata within a group
+		errs = append(errs, checkR53RoutingGroupConsistency(d.Records)...)
+		// Check the STEER() policies
+		errs = append(errs, checkSteering(d.Records)...)
 		// Validate FQDN consistency
 		for _, r := range d.Records {
 			if r.NameFQDN == "" || !strings.HasSuffix(r.NameFQDN, d.Name) {
@@ -767,39 +783,138 @@ func commaSepInts(list []int) string {
 	return strings.Join(slist, ",")
 }
 
-// checkR53WeightedGroupConsistency validates that all records sharing the same
-// label+type+set_identifier have identical weight and health_check_id, since
-// they map to a single Route 53 ResourceRecordSet.
-func checkR53WeightedGroupConsistency(records []*models.RecordConfig) (errs []error) {
-	type groupMeta struct {
-		weight      string
-		healthCheck string
+// routingKey is a metadata key of a routing policy.
+type routingKey struct {
+	key    string // The metadata key.
+	policy string // The policy it belongs to, or "" if it can be used with any policy.
+	desc   string // The plural used in error messages.
+}
+
+// routingScheme describes the metadata of a family of routing policies
+// (traffic steering), so that checkGroupConsistency can validate them.
+type routingScheme struct {
+	name        string // Used in error messages.
+	idKey       string // The metadata key of the group (set) identifier.
+	keys        []routingKey
+	failoverKey string   // The key whose value is PRIMARY or SECONDARY.
+	geoKeys     []string // The keys that together make a geo location.
+}
+
+// r53Routing describes the Route 53 routing policies.
+var r53Routing = routingScheme{
+	name:        "R53 routing",
+	idKey:       "r53_set_identifier",
+	keys:        r53RoutingKeys(),
+	failoverKey: "r53_failover",
+	geoKeys:     []string{"r53_geo_continent", "r53_geo_country", "r53_geo_subdivision"},
+}
+
+// r53RoutingKeys returns the keys of the Route 53 routing policies.
+func r53RoutingKeys() []routingKey {
+	keys := make([]routingKey, len(models.R53RoutingKeys))
+	for i, k := range models.R53RoutingKeys {
+		keys[i] = routingKey{k.Key, k.Policy, k.Desc}
+	}
+	return keys
+}
+
+// policy returns the routing policy of a record, or "" if it has none.
+func (s routingScheme) policy(rc *models.RecordConfig) string {
+	for _, k := range s.keys {
+		if k.policy != "" && rc.Metadata[k.key] != "" {
+			return k.policy
+		}
+	}
+	return ""
+}
+
+// checkGroupConsistency validates the routing policies of records. All
+// records sharing the same label+type+identifier form one group (one
+// provider record set), so their routing metadata must be identical. The
+// groups at a label+type must all use the same policy, and can not be
+// mixed with records without a routing policy. A failover pair has one
+// PRIMARY and one SECONDARY, and each geo location is used once.
+func (s routingScheme) checkGroupConsistency(records []*models.RecordConfig) (errs []error) {
+	groups := map[string]*models.RecordConfig{}
+
+	type rrset struct {
+		policy    string
+		simple    bool
+		failover  map[string]string // Role to group identifier.
+		locations map[string]string // Geo location to group identifier.
+		reported  bool
 	}
-	groups := map[string]groupMeta{}
+	rrsets := map[string]*rrset{}
 
 	for _, rc := range records {
-		sid := rc.Metadata["r53_set_identifier"]
-		if sid == "" {
+		name := rc.GetLabelFQDN() + ":" + rc.Type
+		rs := rrsets[name]
+		if rs == nil {
+			rs = &rrset{failover: map[string]string{}, locations: map[string]string{}}
+			rrsets[name] = rs
+		}
+
+		id := rc.Metadata[s.idKey]
+		if id == "" {
+			rs.simple = true
+			if rs.policy != "" && !rs.reported {
+				rs.reported = true
+				errs = append(errs, fmt.Errorf("%s at %s %s mixes records with and without a routing policy", s.name, rc.Type, rc.GetLabelFQDN()))
+			}
 			continue
 		}
-		key := rc.GetLabelFQDN() + ":" + rc.Type + "!" + sid
-		w := rc.Metadata["r53_weight"]
-		hc := rc.Metadata["r53_health_check_id"]
 
+		key := name + "!" + id
 		if existing, ok := groups[key]; ok {
-			if existing.weight != w {
-				errs = append(errs, fmt.Errorf("R53 weighted group %q at %s %s has inconsistent weights (%s vs %s)", sid, rc.Type, rc.GetLabelFQDN(), existing.weight, w))
+			for _, k := range s.keys {
+				if a, b := existing.Metadata[k.key], rc.Metadata[k.key]; a != b {
+					errs = append(errs, fmt.Errorf("%s group %q at %s %s has inconsistent %s (%s vs %s)", s.name, id, rc.Type, rc.GetLabelFQDN(), k.desc, a, b))
+				}
+			}
+			continue
+		}
+		groups[key] = rc
+
+		policy := s.policy(rc)
+		switch {
+		case rs.simple && !rs.reported:
+			rs.reported = true
+			errs = append(errs, fmt.Errorf("%s at %s %s mixes records with and without a routing policy", s.name, rc.Type, rc.GetLabelFQDN()))
+		case rs.policy == "":
+			rs.policy = policy
+		case rs.policy != policy:
+			errs = append(errs, fmt.Errorf("%s group %q at %s %s uses %s routing, but other groups use %s routing", s.name, id, rc.Type, rc.GetLabelFQDN(), policy, rs.policy))
+			continue
+		}
+
+		if role := rc.Metadata[s.failoverKey]; role != "" {
+			if other, ok := rs.failover[role]; ok {
+				errs = append(errs, fmt.Errorf("%s failover groups %q and %q at %s %s are both %s", s.name, other, id, rc.Type, rc.GetLabelFQDN(), role))
+			} else {
+				rs.failover[role] = id
 			}
-			if existing.healthCheck != hc {
-				errs = append(errs, fmt.Errorf("R53 weighted group %q at %s %s has inconsistent health check IDs (%s vs %s)", sid, rc.Type, rc.GetLabelFQDN(), existing.healthCheck, hc))
+		}
+		var loc []string
+		for _, k := range s.geoKeys {
+			loc = append(loc, rc.Metadata[k])
+		}
+		if l := strings.Join(loc, "/"); strings.Trim(l, "/") != "" {
+			if other, ok := rs.locations[l]; ok {
+				errs = append(errs, fmt.Errorf("%s geo groups %q and %q at %s %s have the same location", s.name, other, id, rc.Type, rc.GetLabelFQDN()))
+			} else {
+				rs.locations[l] = id
 			}
-		} else {
-			groups[key] = groupMeta{weight: w, healthCheck: hc}
 		}
 	}
 	return errs
 }
 
+// checkR53RoutingGroupConsistency validates the Route 53 routing policies
+// of records (see routingScheme.checkGroupConsistency).
+func checkR53RoutingGroupConsistency(records []*models.RecordConfig) []error {
+	return r53Routing.checkGroupConsistency(records)
+}
+
 // We pull this out of checkProviderCapabilities() so that it's visible within
 // the package elsewhere, so that our test suite can look at the list of
 // capabilities we're checking and make sure that it's up-to-date.
@@ -813,22 +928,32 @@ var providerCapabilityChecks = []pairTypeCapability{
 	capabilityCheck("AUTODNSSEC", providers.CanAutoDNSSEC),
 	capabilityCheck("AZURE_ALIAS", providers.CanUseAzureAlias),
 	capabilityCheck("CAA", providers.CanUseCAA),
+	capabilityCheck("CDNSKEY", providers.CanUseCDNSKEY),
+	capabilityCheck("CDS", providers.CanUseCDS),
+	capabilityCheck("CERT", providers.CanUseCERT),
 	capabilityCheck("DHCID", providers.CanUseDHCID),
 	capabilityCheck("DNAME", providers.CanUseDNAME),
 	capabilityCheck("DNSKEY", providers.CanUseDNSKEY),
+	capabilityCheck("HEALTH_CHECK", providers.CanUseHealthChecks),
+	capabilityCheck("HINFO", providers.CanUseHINFO),
 	capabilityCheck("HTTPS", providers.CanUseHTTPS),
+	capabilityCheck("IPSECKEY", providers.CanUseIPSECKEY),
 	capabilityCheck("LOC", providers.CanUseLOC),
 	capabilityCheck("NAPTR", providers.CanUseNAPTR),
 	capabilityCheck("OPENPGPKEY", providers.CanUseOPENPGPKEY),
 	capabilityCheck("PTR", providers.CanUsePTR),
 	capabilityCheck("R53_ALIAS", providers.CanUseRoute53Alias),
+	capabilityCheck("RAW", providers.CanUseRAW),
 	capabilityCheck("RP", providers.CanUseRP),
 	capabilityCheck("SMIMEA", providers.CanUseSMIMEA),
 	capabilityCheck("SOA", providers.CanUseSOA),
 	capabilityCheck("SRV", providers.CanUseSRV),
 	capabilityCheck("SSHFP", providers.CanUseSSHFP),
+	capabilityCheck("STEER", providers.CanUseSteering),
 	capabilityCheck("SVCB", providers.CanUseSVCB),
 	capabilityCheck("TLSA", providers.CanUseTLSA),
+	capabilityCheck("URI", providers.CanUseURI),
+	capabilityCheck("ZONEMD", providers.CanUseZONEMD),
 
 	// DS needs special record-level checks
 	{
@@ -846,7 +971,7 @@ type pairTypeCapability struct {
 	caps []providers.Capability
 	// checkFunc provides additional checks of each provider. This function should be
 	// called if records of type rType are found in the zonefile.
-	checkFunc func(pType string, _ models.Records) error
+	checkFunc func(pType string, driver any, _ models.Records) error
 }
 
 func capabilityCheck(rType string, caps ...providers.Capability) pairTypeCapability {
@@ -856,9 +981,9 @@ func capabilityCheck(rType string, caps ...providers.Capability) pairTypeCapabil
 	}
 }
 
-func providerHasAtLeastOneCapability(pType string, caps ...providers.Capability) bool {
+func providerHasAtLeastOneCapability(pType string, driver any, caps ...providers.Capability) bool {
 	for _, cap := range caps {
-		if providers.ProviderHasCapability(pType, cap) {
+		if providers.InstanceHasCapability(pType, driver, cap) {
 			return true
 		}
 	}
@@ -866,12 +991,12 @@ func providerHasAtLeastOneCapability(pType string, caps ...providers.Capability)
 	return false
 }
 
-func checkProviderDS(pType string, records models.Records) error {
+func checkProviderDS(pType string, driver any, records models.Records) error {
 	switch {
-	case providers.ProviderHasCapability(pType, providers.CanUseDS):
+	case providers.InstanceHasCapability(pType, driver, providers.CanUseDS):
 		// The provider can use DS records anywhere, including at the root
 		return nil
-	case !providers.ProviderHasCapability(pType, providers.CanUseDSForChildren):
+	case !providers.InstanceHasCapability(pType, driver, providers.CanUseDSForChildren):
 		// Provider has no support for DS records
 		return fmt.Errorf("provider %s uses DS records but does not support them", pType)
 	default:
@@ -899,6 +1024,30 @@ func checkProviderCapabilities(dc *models.DomainConfig) error {
 			if dc.AutoDNSSEC != "" {
 				hasAny = true
 			}
+		case "HEALTH_CHECK":
+			// Records that use a HEALTH_CHECK() (see processHealthChecks).
+			for _, r := range dc.Records {
+				if r.Metadata["health_check"] != "" {
+					hasAny = true
+					break
+				}
+			}
+		case "STEER":
+			// STEER() is metadata on records of any type.
+			for _, r := range dc.Records {
+				if r.Metadata[steering.idKey] != "" {
+					hasAny = true
+					break
+				}
+			}
+		case "RAW":
+			// RAW records have the type they were given ("TYPE65534").
+			for _, r := range dc.Records {
+				if _, ok := models.GenericType(r.Type); ok {
+					hasAny = true
+					break
+				}
+			}
 		default:
 			for _, r := range dc.Records {
 				if r.Type == ty.rType {
@@ -921,12 +1070,15 @@ func checkProviderCapabilities(dc *models.DomainConfig) error {
 				continue
 			}
 			// fmt.Printf("  (checking if %q can %q for domain %q)\n", provider.ProviderType, ty.rType, dc.Name)
-			if !providerHasAtLeastOneCapability(provider.ProviderType, ty.caps...) {
+			if !providerHasAtLeastOneCapability(provider.ProviderType, provider.Driver, ty.caps...) {
+				if ty.rType == "HEALTH_CHECK" {
+					return unsupportedHealthCheck(dc, provider.ProviderType)
+				}
 				return fmt.Errorf("domain %s uses %s records, but DNS provider type %s does not support them", dc.Name, ty.rType, provider.ProviderType)
 			}
 
 			if ty.checkFunc != nil {
-				checkErr := ty.checkFunc(provider.ProviderType, dc.Records)
+				checkErr := ty.checkFunc(provider.ProviderType, provider.Driver, dc.Records)
 				if checkErr != nil {
 					return fmt.Errorf("while checking %s records in domain %s: %w", ty.rType, dc.Name, checkErr)
 				}
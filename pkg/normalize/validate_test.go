@@ -2,6 +2,7 @@ package normalize
 
 import (
 	"fmt"
+	"slices"
 	"testing"
 
 	"github.com/DNSControl/dnscontrol/v4/models"
@@ -274,6 +275,30 @@ func TestNSAtRoot(t *testing.T) {
 	}
 }
 
+func TestLuaSnippet(t *testing.T) {
+	rec := &models.RecordConfig{Type: "LUA", LuaRType: "a"}
+	rec.SetLabel("www", "foo.com")
+	rec.MustSetTarget("ifportup(443, {'192.0.2.1', '192.0.2.2'})")
+	if errs := checkTargets(rec, "foo.com"); len(errs) > 0 {
+		t.Errorf("Expect no error with a valid LUA record, got %v", errs)
+	}
+	if rec.LuaRType != "A" {
+		t.Errorf("Expect the emitted rtype to be upper case, got %q", rec.LuaRType)
+	}
+	rec.MustSetTarget("ifportup({'192.0.2.1', '192.0.2.2'})")
+	if errs := checkTargets(rec, "foo.com"); len(errs) != 1 {
+		t.Errorf("Expect an error with a LUA record with missing arguments, got %v", errs)
+	} else if _, ok := errs[0].(Warning); ok {
+		t.Errorf("Expect missing arguments to be an error, not a warning: %v", errs[0])
+	}
+	rec.MustSetTarget("newhelper({'192.0.2.1'})")
+	if errs := checkTargets(rec, "foo.com"); len(errs) != 1 {
+		t.Errorf("Expect a warning with a LUA record calling an unknown function, got %v", errs)
+	} else if _, ok := errs[0].(Warning); !ok {
+		t.Errorf("Expect an unknown function to be a warning, got %v", errs[0])
+	}
+}
+
 func TestTransforms(t *testing.T) {
 	tests := []struct {
 		givenIP         string
@@ -557,7 +582,7 @@ func init() {
 
 func Test_DSChecks(t *testing.T) {
 	t.Run("no DS support", func(t *testing.T) {
-		err := checkProviderDS(ProviderNoDS, nil)
+		err := checkProviderDS(ProviderNoDS, nil, nil)
 		if err == nil {
 			t.Errorf("Provider %s implements no DS capabilities, so should have failed the check", ProviderNoDS)
 		}
@@ -574,7 +599,7 @@ func Test_DSChecks(t *testing.T) {
 
 		// check permutations of ProviderCanDS and having both DS caps
 		for _, pType := range []string{ProviderFullDS, ProviderBothDSCaps} {
-			err := checkProviderDS(pType, records)
+			err := checkProviderDS(pType, nil, records)
 			if err != nil {
 				t.Errorf("Provider %s implements full DS capabilities and should process the provided records", ProviderFullDS)
 			}
@@ -595,7 +620,7 @@ func Test_DSChecks(t *testing.T) {
 
 		t.Run("accepts when child DS records only", func(t *testing.T) {
 			records := models.Records{&childDS, &apexA}
-			err := checkProviderDS(ProviderChildDSOnly, records)
+			err := checkProviderDS(ProviderChildDSOnly, nil, records)
 			if err != nil {
 				t.Errorf("Provider %s implements child DS support so the provided records should be accepted",
 					ProviderChildDSOnly,
@@ -605,7 +630,7 @@ func Test_DSChecks(t *testing.T) {
 
 		t.Run("fails with apex and child DS records", func(t *testing.T) {
 			records := models.Records{&apexDS, &childDS, &apexA}
-			err := checkProviderDS(ProviderChildDSOnly, records)
+			err := checkProviderDS(ProviderChildDSOnly, nil, records)
 			if err == nil {
 				t.Errorf("Provider %s does not implement DS support at the zone apex, so should reject provided records",
 					ProviderChildDSOnly,
@@ -615,7 +640,7 @@ func Test_DSChecks(t *testing.T) {
 	})
 }
 
-func TestCheckR53WeightedGroupConsistency_noerr_consistent(t *testing.T) {
+func TestCheckR53RoutingGroupConsistency_noerr_consistent(t *testing.T) {
 	records := []*models.RecordConfig{
 		makeRC("@", "example.com", "1.2.3.4", models.RecordConfig{
 			Type:     "A",
@@ -626,13 +651,13 @@ func TestCheckR53WeightedGroupConsistency_noerr_consistent(t *testing.T) {
 			Metadata: map[string]string{"r53_weight": "70", "r53_set_identifier": "primary", "r53_health_check_id": "hc-1"},
 		}),
 	}
-	errs := checkR53WeightedGroupConsistency(records)
+	errs := checkR53RoutingGroupConsistency(records)
 	if len(errs) != 0 {
 		t.Errorf("Expected 0 errors but got %d: %v", len(errs), errs)
 	}
 }
 
-func TestCheckR53WeightedGroupConsistency_noerr_different_set_ids(t *testing.T) {
+func TestCheckR53RoutingGroupConsistency_noerr_different_set_ids(t *testing.T) {
 	records := []*models.RecordConfig{
 		makeRC("@", "example.com", "1.2.3.4", models.RecordConfig{
 			Type:     "A",
@@ -643,24 +668,24 @@ func TestCheckR53WeightedGroupConsistency_noerr_different_set_ids(t *testing.T)
 			Metadata: map[string]string{"r53_weight": "30", "r53_set_identifier": "secondary"},
 		}),
 	}
-	errs := checkR53WeightedGroupConsistency(records)
+	errs := checkR53RoutingGroupConsistency(records)
 	if len(errs) != 0 {
 		t.Errorf("Expected 0 errors but got %d: %v", len(errs), errs)
 	}
 }
 
-func TestCheckR53WeightedGroupConsistency_noerr_no_metadata(t *testing.T) {
+func TestCheckR53RoutingGroupConsistency_noerr_no_metadata(t *testing.T) {
 	records := []*models.RecordConfig{
 		makeRC("@", "example.com", "1.2.3.4", models.RecordConfig{Type: "A"}),
 		makeRC("@", "example.com", "5.6.7.8", models.RecordConfig{Type: "A"}),
 	}
-	errs := checkR53WeightedGroupConsistency(records)
+	errs := checkR53RoutingGroupConsistency(records)
 	if len(errs) != 0 {
 		t.Errorf("Expected 0 errors but got %d: %v", len(errs), errs)
 	}
 }
 
-func TestCheckR53WeightedGroupConsistency_err_different_weights(t *testing.T) {
+func TestCheckR53RoutingGroupConsistency_err_different_weights(t *testing.T) {
 	records := []*models.RecordConfig{
 		makeRC("@", "example.com", "1.2.3.4", models.RecordConfig{
 			Type:     "A",
@@ -671,13 +696,13 @@ func TestCheckR53WeightedGroupConsistency_err_different_weights(t *testing.T) {
 			Metadata: map[string]string{"r53_weight": "50", "r53_set_identifier": "primary"},
 		}),
 	}
-	errs := checkR53WeightedGroupConsistency(records)
+	errs := checkR53RoutingGroupConsistency(records)
 	if len(errs) != 1 {
 		t.Errorf("Expected 1 error for inconsistent weights but got %d: %v", len(errs), errs)
 	}
 }
 
-func TestCheckR53WeightedGroupConsistency_err_different_health_checks(t *testing.T) {
+func TestCheckR53RoutingGroupConsistency_err_different_health_checks(t *testing.T) {
 	records := []*models.RecordConfig{
 		makeRC("@", "example.com", "1.2.3.4", models.RecordConfig{
 			Type:     "A",
@@ -688,13 +713,13 @@ func TestCheckR53WeightedGroupConsistency_err_different_health_checks(t *testing
 			Metadata: map[string]string{"r53_weight": "70", "r53_set_identifier": "primary", "r53_health_check_id": "hc-2"},
 		}),
 	}
-	errs := checkR53WeightedGroupConsistency(records)
+	errs := checkR53RoutingGroupConsistency(records)
 	if len(errs) != 1 {
 		t.Errorf("Expected 1 error for inconsistent health checks but got %d: %v", len(errs), errs)
 	}
 }
 
-func TestCheckR53WeightedGroupConsistency_err_both_inconsistent(t *testing.T) {
+func TestCheckR53RoutingGroupConsistency_err_both_inconsistent(t *testing.T) {
 	records := []*models.RecordConfig{
 		makeRC("@", "example.com", "1.2.3.4", models.RecordConfig{
 			Type:     "A",
@@ -705,12 +730,116 @@ func TestCheckR53WeightedGroupConsistency_err_both_inconsistent(t *testing.T) {
 			Metadata: map[string]string{"r53_weight": "50", "r53_set_identifier": "primary", "r53_health_check_id": "hc-2"},
 		}),
 	}
-	errs := checkR53WeightedGroupConsistency(records)
+	errs := checkR53RoutingGroupConsistency(records)
 	if len(errs) != 2 {
 		t.Errorf("Expected 2 errors (weight + health check) but got %d: %v", len(errs), errs)
 	}
 }
 
+func TestCheckR53RoutingGroupConsistency_noerr_failover_pair(t *testing.T) {
+	records := []*models.RecordConfig{
+		makeRC("www", "example.com", "1.2.3.4", models.RecordConfig{
+			Type:     "A",
+			Metadata: map[string]string{"r53_failover": "PRIMARY", "r53_set_identifier": "primary", "r53_health_check_id": "hc-1"},
+		}),
+		makeRC("www", "example.com", "5.6.7.8", models.RecordConfig{
+			Type:     "A",
+			Metadata: map[string]string{"r53_failover": "SECONDARY", "r53_set_identifier": "secondary"},
+		}),
+	}
+	errs := checkR53RoutingGroupConsistency(records)
+	if len(errs) != 0 {
+		t.Errorf("Expected 0 errors but got %d: %v", len(errs), errs)
+	}
+}
+
+func TestCheckR53RoutingGroupConsistency_err_two_primaries(t *testing.T) {
+	records := []*models.RecordConfig{
+		makeRC("www", "example.com", "1.2.3.4", models.RecordConfig{
+			Type:     "A",
+			Metadata: map[string]string{"r53_failover": "PRIMARY", "r53_set_identifier": "a"},
+		}),
+		makeRC("www", "example.com", "5.6.7.8", models.RecordConfig{
+			Type:     "A",
+			Metadata: map[string]string{"r53_failover": "PRIMARY", "r53_set_identifier": "b"},
+		}),
+	}
+	errs := checkR53RoutingGroupConsistency(records)
+	if len(errs) != 1 {
+		t.Errorf("Expected 1 error for two PRIMARY groups but got %d: %v", len(errs), errs)
+	}
+}
+
+func TestCheckR53RoutingGroupConsistency_err_mixed_policies(t *testing.T) {
+	records := []*models.RecordConfig{
+		makeRC("www", "example.com", "1.2.3.4", models.RecordConfig{
+			Type:     "A",
+			Metadata: map[string]string{"r53_region": "us-east-1", "r53_set_identifier": "us"},
+		}),
+		makeRC("www", "example.com", "5.6.7.8", models.RecordConfig{
+			Type:     "A",
+			Metadata: map[string]string{"r53_weight": "10", "r53_set_identifier": "eu"},
+		}),
+	}
+	errs := checkR53RoutingGroupConsistency(records)
+	if len(errs) != 1 {
+		t.Errorf("Expected 1 error for mixed routing policies but got %d: %v", len(errs), errs)
+	}
+}
+
+func TestCheckR53RoutingGroupConsistency_err_mixed_simple(t *testing.T) {
+	records := []*models.RecordConfig{
+		makeRC("www", "example.com", "1.2.3.4", models.RecordConfig{
+			Type:     "A",
+			Metadata: map[string]string{"r53_multivalue": "true", "r53_set_identifier": "one"},
+		}),
+		makeRC("www", "example.com", "5.6.7.8", models.RecordConfig{Type: "A"}),
+		makeRC("www", "example.com", "9.9.9.9", models.RecordConfig{Type: "A"}),
+	}
+	errs := checkR53RoutingGroupConsistency(records)
+	if len(errs) != 1 {
+		t.Errorf("Expected 1 error for records with and without routing but got %d: %v", len(errs), errs)
+	}
+}
+
+func TestCheckR53RoutingGroupConsistency_err_same_geolocation(t *testing.T) {
+	records := []*models.RecordConfig{
+		makeRC("www", "example.com", "1.2.3.4", models.RecordConfig{
+			Type:     "A",
+			Metadata: map[string]string{"r53_geo_country": "US", "r53_set_identifier": "us-1"},
+		}),
+		makeRC("www", "example.com", "5.6.7.8", models.RecordConfig{
+			Type:     "A",
+			Metadata: map[string]string{"r53_geo_country": "US", "r53_set_identifier": "us-2"},
+		}),
+		makeRC("www", "example.com", "9.9.9.9", models.RecordConfig{
+			Type:     "A",
+			Metadata: map[string]string{"r53_geo_country": "*", "r53_set_identifier": "default"},
+		}),
+	}
+	errs := checkR53RoutingGroupConsistency(records)
+	if len(errs) != 1 {
+		t.Errorf("Expected 1 error for a duplicate geolocation but got %d: %v", len(errs), errs)
+	}
+}
+
+func TestCheckR53RoutingGroupConsistency_err_different_regions(t *testing.T) {
+	records := []*models.RecordConfig{
+		makeRC("www", "example.com", "1.2.3.4", models.RecordConfig{
+			Type:     "A",
+			Metadata: map[string]string{"r53_region": "us-east-1", "r53_set_identifier": "us"},
+		}),
+		makeRC("www", "example.com", "2.3.4.5", models.RecordConfig{
+			Type:     "A",
+			Metadata: map[string]string{"r53_region": "us-west-2", "r53_set_identifier": "us"},
+		}),
+	}
+	errs := checkR53RoutingGroupConsistency(records)
+	if len(errs) != 1 {
+		t.Errorf("Expected 1 error for inconsistent regions but got %d: %v", len(errs), errs)
+	}
+}
+
 func Test_errorRepeat(t *testing.T) {
 	type args struct {
 		label  string
@@ -737,3 +866,141 @@ func Test_errorRepeat(t *testing.T) {
 		})
 	}
 }
+
+func TestProcessViews(t *testing.T) {
+	rec := func(label, target, viewOnly string) *models.RecordConfig {
+		r := &models.RecordConfig{Type: "A", TTL: 300, Metadata: map[string]string{}}
+		r.SetLabel(label, "example.com")
+		r.MustSetTarget(target)
+		if viewOnly != "" {
+			r.Metadata["view_only"] = viewOnly
+		}
+		return r
+	}
+	view := func(tag string, recs ...*models.RecordConfig) *models.DomainConfig {
+		return &models.DomainConfig{Name: "example.com", Tag: tag, UniqueName: "example.com!" + tag, Metadata: map[string]string{}, Records: recs}
+	}
+
+	t.Run("view only", func(t *testing.T) {
+		internal := view("internal", rec("www", "10.0.0.80", ""), rec("db", "10.0.0.5", "internal"))
+		external := view("external", rec("www", "192.0.2.80", ""), rec("db", "10.0.0.5", "internal"))
+		if errs := processViews(&models.DNSConfig{Domains: []*models.DomainConfig{internal, external}}); len(errs) != 0 {
+			t.Fatal(errs)
+		}
+		if len(internal.Records) != 2 || len(external.Records) != 1 {
+			t.Errorf("got %d and %d records, want 2 and 1", len(internal.Records), len(external.Records))
+		}
+	})
+
+	t.Run("leak", func(t *testing.T) {
+		internal := view("internal", rec("db", "10.0.0.5", "internal"))
+		external := view("external", rec("db", "10.0.0.5", ""))
+		if errs := processViews(&models.DNSConfig{Domains: []*models.DomainConfig{internal, external}}); len(errs) != 1 {
+			t.Errorf("got %v, want 1 error", errs)
+		}
+	})
+
+	t.Run("unknown view", func(t *testing.T) {
+		internal := view("internal", rec("db", "10.0.0.5", "intrnal"))
+		if errs := processViews(&models.DNSConfig{Domains: []*models.DomainConfig{internal}}); len(errs) != 1 {
+			t.Errorf("got %v, want 1 error", errs)
+		}
+	})
+
+	t.Run("must match", func(t *testing.T) {
+		internal := view("internal", rec("@", "192.0.2.1", ""), rec("www", "10.0.0.80", ""))
+		external := view("external", rec("@", "192.0.2.1", ""), rec("www", "192.0.2.80", ""))
+		internal.Metadata["view_must_match"] = "@"
+		if errs := processViews(&models.DNSConfig{Domains: []*models.DomainConfig{internal, external}}); len(errs) != 0 {
+			t.Errorf("@: got %v", errs)
+		}
+		external.Metadata["view_must_match"] = "www"
+		if errs := processViews(&models.DNSConfig{Domains: []*models.DomainConfig{internal, external}}); len(errs) != 1 {
+			t.Errorf("@, www: got %v, want 1 error", errs)
+		}
+	})
+}
+
+func TestProcessAutoPTR(t *testing.T) {
+	rec := func(rtype, label, domain, target string) *models.RecordConfig {
+		r := &models.RecordConfig{Type: rtype, TTL: 300, Metadata: map[string]string{}}
+		r.SetLabel(label, domain)
+		r.MustSetTarget(target)
+		return r
+	}
+	zone := func(name string, autoptr bool, recs ...*models.RecordConfig) *models.DomainConfig {
+		dc := &models.DomainConfig{Name: name, UniqueName: name, Metadata: map[string]string{}, Records: recs}
+		if autoptr {
+			dc.Metadata["autoptr"] = "true"
+		}
+		return dc
+	}
+	ptrs := func(dc *models.DomainConfig) []string {
+		var s []string
+		for _, r := range dc.Records {
+			s = append(s, r.GetLabel()+" "+r.GetTargetField())
+		}
+		return s
+	}
+
+	t.Run("classless", func(t *testing.T) {
+		fwd := zone("example.com", false,
+			rec("A", "www", "example.com", "10.0.0.80"),
+			rec("A", "db", "example.com", "10.0.1.5"),
+			rec("A", "far", "example.com", "192.0.2.1"),
+			rec("AAAA", "www", "example.com", "2001:db8::80"),
+		)
+		rev24 := zone("0.0.10.in-addr.arpa", true)
+		rev26 := zone("0-26.1.0.10.in-addr.arpa", true)
+		rev6 := zone("8.b.d.0.1.0.0.2.ip6.arpa", true)
+		config := &models.DNSConfig{Domains: []*models.DomainConfig{fwd, rev24, rev26, rev6}}
+		if errs := processAutoPTR(config); len(errs) != 0 {
+			t.Fatal(errs)
+		}
+		for dc, want := range map[*models.DomainConfig][]string{
+			rev24: {"80 www.example.com."},
+			rev26: {"5 db.example.com."},
+			rev6:  {"0.8.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0 www.example.com."},
+		} {
+			if got := ptrs(dc); !slices.Equal(got, want) {
+				t.Errorf("%s: got %v, want %v", dc.Name, got, want)
+			}
+		}
+	})
+
+	t.Run("most specific zone", func(t *testing.T) {
+		fwd := zone("example.com", false, rec("A", "www", "example.com", "10.0.0.80"))
+		rev8 := zone("10.in-addr.arpa", true)
+		rev24 := zone("0.0.10.in-addr.arpa", false)
+		if errs := processAutoPTR(&models.DNSConfig{Domains: []*models.DomainConfig{fwd, rev8, rev24}}); len(errs) != 0 {
+			t.Fatal(errs)
+		}
+		if len(rev8.Records) != 0 || len(rev24.Records) != 0 {
+			t.Errorf("got %v and %v, want no PTRs", ptrs(rev8), ptrs(rev24))
+		}
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		fwd := zone("example.com", false,
+			rec("A", "www", "example.com", "10.0.0.80"),
+			rec("A", "web", "example.com", "10.0.0.80"),
+		)
+		rev := zone("0.0.10.in-addr.arpa", true)
+		if errs := processAutoPTR(&models.DNSConfig{Domains: []*models.DomainConfig{fwd, rev}}); len(errs) != 1 {
+			t.Errorf("got %v, want 1 error", errs)
+		}
+		rev.Records = models.Records{rec("PTR", "80", rev.Name, "www.example.com.")}
+		if errs := processAutoPTR(&models.DNSConfig{Domains: []*models.DomainConfig{fwd, rev}}); len(errs) != 0 {
+			t.Errorf("with PTR: got %v", errs)
+		}
+		if len(rev.Records) != 1 {
+			t.Errorf("got %v, want the PTR only", ptrs(rev))
+		}
+	})
+
+	t.Run("not a reverse zone", func(t *testing.T) {
+		if errs := processAutoPTR(&models.DNSConfig{Domains: []*models.DomainConfig{zone("example.com", true)}}); len(errs) != 1 {
+			t.Errorf("got %v, want 1 error", errs)
+		}
+	})
+}
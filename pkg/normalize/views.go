@@ -0,0 +1,88 @@
+package normalize
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/DNSControl/dnscontrol/v4/models"
+	"github.com/DNSControl/dnscontrol/v4/pkg/views"
+)
+
+// processViews applies VIEW_ONLY() and checks VIEW_MUST_MATCH() across the
+// views of each split horizon zone.
+//
+// A record with VIEW_ONLY("tag") is dropped from the views with other tags.
+// So that the record cannot leak into another view by other means (for
+// example a copy in a D_EXTEND()), it is an error for any other view to
+// have the same record.
+func processViews(config *models.DNSConfig) (errs []error) {
+	tags := map[string][]string{}
+	for _, dc := range config.Domains {
+		tags[dc.Name] = append(tags[dc.Name], dc.Tag)
+	}
+
+	type viewOnly struct {
+		rec  *models.RecordConfig
+		view *models.DomainConfig
+		tags []string
+	}
+	var kept []viewOnly
+	for _, dc := range config.Domains {
+		var recs models.Records
+		for _, r := range dc.Records {
+			v, ok := r.Metadata["view_only"]
+			if !ok {
+				recs = append(recs, r)
+				continue
+			}
+			only := strings.Split(v, ",")
+			for _, t := range only {
+				if !slices.Contains(tags[dc.Name], t) {
+					errs = append(errs, fmt.Errorf("VIEW_ONLY(%q) on %s %s: there is no D(%q)", t, r.Type, r.GetLabelFQDN(), dc.Name+"!"+t))
+				}
+			}
+			if slices.Contains(only, dc.Tag) {
+				recs = append(recs, r)
+				kept = append(kept, viewOnly{r, dc, only})
+			}
+		}
+		dc.Records = recs
+	}
+
+	for _, vo := range kept {
+		want := views.Format(vo.rec)
+		for _, dc := range config.Domains {
+			if dc.Name != vo.view.Name || slices.Contains(vo.tags, dc.Tag) {
+				continue
+			}
+			for _, r := range dc.Records {
+				if r.GetLabel() == vo.rec.GetLabel() && r.Type == vo.rec.Type && r.ToComparableNoTTL() == vo.rec.ToComparableNoTTL() {
+					errs = append(errs, fmt.Errorf("%s %s is VIEW_ONLY(%q) in %s but is also in %s: %s",
+						r.Type, r.GetLabelFQDN(), strings.Join(vo.tags, ","), vo.view.UniqueName, dc.UniqueName, want))
+				}
+			}
+		}
+	}
+
+	for _, zone := range views.Zones(config.Domains) {
+		var labels []string
+		for _, dc := range zone {
+			if v := dc.Metadata["view_must_match"]; v != "" {
+				labels = append(labels, strings.Split(v, ",")...)
+			}
+		}
+		if labels == nil {
+			continue
+		}
+		for _, d := range views.Compare(zone, labels) {
+			var parts []string
+			for i, dc := range zone {
+				parts = append(parts, fmt.Sprintf("%s has [%s]", views.Name(dc), strings.Join(d.Records[i], "; ")))
+			}
+			errs = append(errs, fmt.Errorf("VIEW_MUST_MATCH: %s label %q differs between views: %s",
+				zone[0].Name, d.Label, strings.Join(parts, ", ")))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,291 @@
+// Package pdnslua parses and evaluates the Lua snippets of PowerDNS LUA
+// records, so that mistakes are found before PowerDNS fails to answer.
+package pdnslua
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/yuin/gopher-lua/ast"
+	"github.com/yuin/gopher-lua/parse"
+)
+
+// arity is the number of arguments a function takes.
+type arity struct{ min, max int }
+
+// functions are the functions PowerDNS provides to LUA records, with the
+// arguments they take. See
+// https://doc.powerdns.com/authoritative/lua-records/functions.html
+var functions = map[string]arity{
+	// Health checks.
+	"ifportup":         {2, 3},
+	"ifurlup":          {2, 3},
+	"ifurlextup":       {1, 2},
+	"pickselfweighted": {2, 3},
+
+	// Selection.
+	"all":              {1, 1},
+	"pickchashed":      {1, 1},
+	"pickclosest":      {1, 1},
+	"pickhashed":       {1, 1},
+	"picknamehashed":   {1, 1},
+	"pickrandom":       {1, 1},
+	"pickrandomsample": {2, 2},
+	"pickwhashed":      {1, 1},
+	"pickwrandom":      {1, 1},
+	"view":             {1, 1},
+
+	// Client location.
+	"asnum":         {1, 1},
+	"continent":     {1, 1},
+	"continentCode": {0, 0},
+	"country":       {1, 1},
+	"countryCode":   {0, 0},
+	"netmask":       {1, 1},
+	"region":        {1, 1},
+	"regionCode":    {0, 0},
+	"closestMagic":  {0, 0},
+	"latlon":        {0, 0},
+	"latlonloc":     {0, 0},
+	"latlonMagic":   {0, 0},
+
+	// Reverse and forward records.
+	"createForward":  {0, 0},
+	"createForward6": {0, 0},
+	"createReverse":  {1, 2},
+	"createReverse6": {1, 2},
+	"filterForward":  {2, 3},
+
+	// Other records.
+	"dblookup": {2, 2},
+	"include":  {1, 1},
+
+	// Objects.
+	"newCA":      {1, 1},
+	"newDN":      {1, 1},
+	"newNetmask": {1, 1},
+	"newNMG":     {0, 1},
+}
+
+// globals are the other names a snippet can use: the Lua standard library
+// and the variables PowerDNS sets.
+var globals = map[string]bool{
+	"assert": true, "error": true, "ipairs": true, "next": true, "pairs": true,
+	"pcall": true, "print": true, "rawequal": true, "rawget": true, "rawset": true,
+	"select": true, "tonumber": true, "tostring": true, "type": true, "unpack": true,
+	"xpcall": true, "getmetatable": true, "setmetatable": true,
+	"math": true, "os": true, "string": true, "table": true,
+
+	"bestwho": true, "dh": true, "ecswho": true, "qname": true, "who": true,
+	"zone": true, "zoneid": true,
+}
+
+// Chunk returns the Lua code that PowerDNS runs for a snippet. A snippet
+// that starts with ";" is a script; any other snippet is an expression
+// whose value is returned.
+func Chunk(snippet string) string {
+	if strings.HasPrefix(snippet, ";") {
+		return snippet[1:]
+	}
+	return "return " + snippet
+}
+
+// Check parses a snippet and checks that the PowerDNS functions it calls get
+// the right number of arguments. Calls of functions that are not known are
+// returned as warnings rather than errors, since PowerDNS may have functions
+// that are not listed here.
+func Check(snippet string) (warnings []string, err error) {
+	if strings.TrimSpace(strings.TrimPrefix(snippet, ";")) == "" {
+		return nil, errors.New("LUA snippet is empty")
+	}
+	stmts, err := parse.Parse(strings.NewReader(Chunk(snippet)), "LUA")
+	if err != nil {
+		var perr *parse.Error
+		if errors.As(err, &perr) && perr.Pos.Line == parse.EOF {
+			return nil, fmt.Errorf("LUA syntax error at the end of the snippet: %s", perr.Message)
+		}
+		if errors.As(err, &perr) {
+			return nil, fmt.Errorf("LUA syntax error on line %d near %q: %s", perr.Pos.Line, perr.Token, perr.Message)
+		}
+		return nil, fmt.Errorf("LUA syntax error: %w", err)
+	}
+
+	c := checker{defined: map[string]bool{}}
+	c.define(stmts)
+	c.stmts(stmts)
+	return c.warnings, errors.Join(c.errs...)
+}
+
+// checker walks the syntax tree of a snippet and collects the errors and
+// warnings.
+type checker struct {
+	defined  map[string]bool // Names that the snippet assigns.
+	errs     []error
+	warnings []string
+}
+
+// define records the names that the snippet assigns, so that calls to its
+// own functions are not reported.
+func (c *checker) define(stmts []ast.Stmt) {
+	for _, s := range stmts {
+		switch s := s.(type) {
+		case *ast.LocalAssignStmt:
+			for _, n := range s.Names {
+				c.defined[n] = true
+			}
+		case *ast.AssignStmt:
+			for _, e := range s.Lhs {
+				if id, ok := e.(*ast.IdentExpr); ok {
+					c.defined[id.Value] = true
+				}
+			}
+		case *ast.FuncDefStmt:
+			if id, ok := s.Name.Func.(*ast.IdentExpr); ok {
+				c.defined[id.Value] = true
+			}
+			c.define(s.Func.Stmts)
+		case *ast.DoBlockStmt:
+			c.define(s.Stmts)
+		case *ast.WhileStmt:
+			c.define(s.Stmts)
+		case *ast.RepeatStmt:
+			c.define(s.Stmts)
+		case *ast.IfStmt:
+			c.define(s.Then)
+			c.define(s.Else)
+		case *ast.NumberForStmt:
+			c.defined[s.Name] = true
+			c.define(s.Stmts)
+		case *ast.GenericForStmt:
+			for _, n := range s.Names {
+				c.defined[n] = true
+			}
+			c.define(s.Stmts)
+		}
+	}
+}
+
+func (c *checker) stmts(stmts []ast.Stmt) {
+	for _, s := range stmts {
+		switch s := s.(type) {
+		case *ast.AssignStmt:
+			c.exprs(s.Lhs)
+			c.exprs(s.Rhs)
+		case *ast.LocalAssignStmt:
+			c.exprs(s.Exprs)
+		case *ast.FuncCallStmt:
+			c.expr(s.Expr)
+		case *ast.DoBlockStmt:
+			c.stmts(s.Stmts)
+		case *ast.WhileStmt:
+			c.expr(s.Condition)
+			c.stmts(s.Stmts)
+		case *ast.RepeatStmt:
+			c.stmts(s.Stmts)
+			c.expr(s.Condition)
+		case *ast.IfStmt:
+			c.expr(s.Condition)
+			c.stmts(s.Then)
+			c.stmts(s.Else)
+		case *ast.NumberForStmt:
+			c.expr(s.Init)
+			c.expr(s.Limit)
+			c.expr(s.Step)
+			c.stmts(s.Stmts)
+		case *ast.GenericForStmt:
+			c.exprs(s.Exprs)
+			c.stmts(s.Stmts)
+		case *ast.FuncDefStmt:
+			c.expr(s.Func)
+		case *ast.ReturnStmt:
+			c.exprs(s.Exprs)
+		}
+	}
+}
+
+func (c *checker) exprs(exprs []ast.Expr) {
+	for _, e := range exprs {
+		c.expr(e)
+	}
+}
+
+func (c *checker) expr(e ast.Expr) {
+	switch e := e.(type) {
+	case *ast.FuncCallExpr:
+		c.call(e)
+		c.expr(e.Func)
+		c.expr(e.Receiver)
+		c.exprs(e.Args)
+	case *ast.AttrGetExpr:
+		c.expr(e.Object)
+		c.expr(e.Key)
+	case *ast.TableExpr:
+		for _, f := range e.Fields {
+			c.expr(f.Key)
+			c.expr(f.Value)
+		}
+	case *ast.LogicalOpExpr:
+		c.expr(e.Lhs)
+		c.expr(e.Rhs)
+	case *ast.RelationalOpExpr:
+		c.expr(e.Lhs)
+		c.expr(e.Rhs)
+	case *ast.StringConcatOpExpr:
+		c.expr(e.Lhs)
+		c.expr(e.Rhs)
+	case *ast.ArithmeticOpExpr:
+		c.expr(e.Lhs)
+		c.expr(e.Rhs)
+	case *ast.UnaryMinusOpExpr:
+		c.expr(e.Expr)
+	case *ast.UnaryNotOpExpr:
+		c.expr(e.Expr)
+	case *ast.UnaryLenOpExpr:
+		c.expr(e.Expr)
+	case *ast.FunctionExpr:
+		c.stmts(e.Stmts)
+	}
+}
+
+// call checks a call of a global function.
+func (c *checker) call(e *ast.FuncCallExpr) {
+	id, ok := e.Func.(*ast.IdentExpr)
+	if !ok || c.defined[id.Value] {
+		return
+	}
+	name := id.Value
+	a, ok := functions[name]
+	if !ok {
+		if !globals[name] {
+			c.warnings = append(c.warnings, fmt.Sprintf("LUA line %d: unknown function %s()", e.Line(), name))
+		}
+		return
+	}
+
+	n := len(e.Args)
+	// The last argument may be a call or "...", which passes any number of values.
+	open := false
+	if n > 0 {
+		switch e.Args[n-1].(type) {
+		case *ast.FuncCallExpr, *ast.Comma3Expr:
+			open = true
+		}
+	}
+	if n < a.min && !open || n > a.max {
+		c.errs = append(c.errs, fmt.Errorf("LUA line %d: %s() takes %s, got %d", e.Line(), name, a, n))
+	}
+}
+
+func (a arity) String() string {
+	plural := func(n int) string {
+		if n == 1 {
+			return "1 argument"
+		}
+		return fmt.Sprintf("%d arguments", n)
+	}
+	if a.min == a.max {
+		return plural(a.min)
+	}
+	return fmt.Sprintf("%d to %d arguments", a.min, a.max)
+}
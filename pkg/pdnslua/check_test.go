@@ -0,0 +1,100 @@
+package pdnslua
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name     string
+		snippet  string
+		wantErr  string
+		wantWarn string
+	}{
+		{
+			name:    "expression",
+			snippet: "ifportup(443, {'192.0.2.1', '192.0.2.2'})",
+		},
+		{
+			name:    "options",
+			snippet: "ifurlup('https://example.com/', {{'192.0.2.1'}, {'192.0.2.2'}}, {selector='all'})",
+		},
+		{
+			name:    "weighted",
+			snippet: "pickwrandom({{10, '192.0.2.1'}, {90, '192.0.2.2'}})",
+		},
+		{
+			name:    "script",
+			snippet: "; if continent('EU') then return {'198.51.100.1'} else return {'192.0.2.10'} end",
+		},
+		{
+			name:    "methods and libraries",
+			snippet: "; return string.format('%s from %s', qname:toString(), who:toString())",
+		},
+		{
+			name:    "own function",
+			snippet: "; local function pool() return {'192.0.2.1'} end return pickrandom(pool())",
+		},
+		{
+			name:    "call as last argument",
+			snippet: "pickrandomsample(unpack({2, {'192.0.2.1', '192.0.2.2'}}))",
+		},
+		{
+			name:    "empty",
+			snippet: ";  ",
+			wantErr: "LUA snippet is empty",
+		},
+		{
+			name:    "syntax error",
+			snippet: "pickrandom({'192.0.2.1',}",
+			wantErr: "LUA syntax error at the end of the snippet",
+		},
+		{
+			name:    "syntax error in script",
+			snippet: "; local x = = 1\nreturn x",
+			wantErr: `LUA syntax error on line 1 near "="`,
+		},
+		{
+			name:    "script without leading semicolon",
+			snippet: "if country('NL') then return '192.0.2.1' end",
+			wantErr: "LUA syntax error",
+		},
+		{
+			name:     "unknown function",
+			snippet:  "ifportupp(443, {'192.0.2.1'})",
+			wantWarn: "LUA line 1: unknown function ifportupp()",
+		},
+		{
+			name:    "too few arguments",
+			snippet: "ifportup({'192.0.2.1'})",
+			wantErr: "LUA line 1: ifportup() takes 2 to 3 arguments, got 1",
+		},
+		{
+			name:    "too many arguments",
+			snippet: "; local a = '192.0.2.1'\nreturn pickrandom({a}, {a})",
+			wantErr: "LUA line 2: pickrandom() takes 1 argument, got 2",
+		},
+		{
+			name:    "no arguments",
+			snippet: "latlonloc(-25.286, -57.645, 100)",
+			wantErr: "LUA line 1: latlonloc() takes 0 arguments, got 3",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warnings, err := Check(tt.snippet)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), tt.wantErr)
+			}
+			if tt.wantWarn == "" {
+				assert.Empty(t, warnings)
+			} else {
+				assert.Equal(t, []string{tt.wantWarn}, warnings)
+			}
+		})
+	}
+}
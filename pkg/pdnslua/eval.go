@@ -0,0 +1,767 @@
+package pdnslua
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"hash/fnv"
+	"math/rand/v2"
+	"net/netip"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// Env is what a snippet is evaluated with: the query, the client and the
+// results of the health checks. There is no GeoIP database, so the location
+// of the client is given too.
+type Env struct {
+	QName     string     // The name that is queried, as an FQDN.
+	Zone      string     // The zone, as an FQDN.
+	Who       netip.Addr // The address of the client (resolver).
+	Country   string     // ISO code of the client's country, such as "NL".
+	Continent string     // Code of the client's continent, such as "EU".
+	Region    string     // Code of the client's region.
+	ASN       int        // AS number of the client.
+
+	// Up reports whether an address passes the health checks of ifportup(),
+	// ifurlup() and ifurlextup(). If nil, all addresses are up.
+	Up func(addr string) bool
+
+	// Rand is used by the random selections. If nil, a random seed is used.
+	Rand *rand.Rand
+}
+
+// timeout limits how long a snippet may run.
+const timeout = 5 * time.Second
+
+// Eval runs a snippet the way PowerDNS does and returns the values it
+// produces, as record contents.
+//
+// The results of random selections, and of hashed ones, will differ from
+// what PowerDNS answers. Functions that need data that only PowerDNS has,
+// such as pickclosest() and include(), are simulated when that is possible
+// and are an error otherwise.
+func Eval(snippet string, env Env) ([]string, error) {
+	if env.Rand == nil {
+		env.Rand = rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+	}
+	L := lua.NewState(lua.Options{SkipOpenLibs: true})
+	defer L.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	L.SetContext(ctx)
+
+	e := &evaluator{L: L, env: env}
+	e.open()
+
+	fn, err := L.LoadString(Chunk(snippet))
+	if err != nil {
+		return nil, fmt.Errorf("LUA syntax error: %w", err)
+	}
+	L.Push(fn)
+	if err := L.PCall(0, 1, nil); err != nil {
+		return nil, err
+	}
+	return values(L.Get(-1))
+}
+
+// values returns the record contents of the value of a snippet.
+func values(v lua.LValue) ([]string, error) {
+	switch v := v.(type) {
+	case *lua.LNilType:
+		return nil, nil
+	case lua.LString, lua.LNumber:
+		return []string{lua.LVAsString(v)}, nil
+	case *lua.LTable:
+		var result []string
+		for i := 1; i <= v.Len(); i++ {
+			s, err := values(v.RawGetInt(i))
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, s...)
+		}
+		return result, nil
+	case *lua.LUserData:
+		return []string{tostring(v)}, nil
+	}
+	return nil, fmt.Errorf("LUA snippet returned a %s, not a string or a table of strings", v.Type())
+}
+
+// evaluator holds the Lua state that a snippet is run in.
+type evaluator struct {
+	L   *lua.LState
+	env Env
+}
+
+// open sets up the libraries, variables and functions that PowerDNS gives
+// to LUA records.
+func (e *evaluator) open() {
+	L := e.L
+	for _, lib := range []struct {
+		name string
+		fn   lua.LGFunction
+	}{
+		{lua.BaseLibName, lua.OpenBase},
+		{lua.TabLibName, lua.OpenTable},
+		{lua.StringLibName, lua.OpenString},
+		{lua.MathLibName, lua.OpenMath},
+		{lua.OsLibName, lua.OpenOs},
+	} {
+		L.Push(L.NewFunction(lib.fn))
+		L.Push(lua.LString(lib.name))
+		L.Call(1, 0)
+	}
+	// Snippets have no business with files or processes.
+	for _, name := range []string{"dofile", "loadfile", "require", "module"} {
+		L.SetGlobal(name, lua.LNil)
+	}
+	oslib := L.GetGlobal(lua.OsLibName)
+	for _, name := range []string{"execute", "exit", "getenv", "remove", "rename", "setenv", "tmpname"} {
+		L.SetField(oslib, name, lua.LNil)
+	}
+
+	e.openTypes()
+	L.SetGlobal("qname", e.newDN(e.env.QName))
+	L.SetGlobal("zone", e.newDN(e.env.Zone))
+	L.SetGlobal("zoneid", lua.LNumber(1))
+	L.SetGlobal("who", e.newCA(e.env.Who))
+	L.SetGlobal("bestwho", e.newCA(e.env.Who))
+
+	funcs := map[string]lua.LGFunction{
+		"ifportup":         func(L *lua.LState) int { return e.ifup(L.CheckAny(2), L.OptTable(3, nil)) },
+		"ifurlup":          func(L *lua.LState) int { return e.ifup(L.CheckAny(2), L.OptTable(3, nil)) },
+		"ifurlextup":       e.ifurlextup,
+		"pickselfweighted": func(L *lua.LState) int { return e.ifup(L.CheckAny(2), L.OptTable(3, nil)) },
+
+		"all":              e.all,
+		"pickchashed":      e.pickwhashed,
+		"pickclosest":      e.pickclosest,
+		"pickhashed":       e.pickhashed,
+		"picknamehashed":   e.picknamehashed,
+		"pickrandom":       e.pickrandom,
+		"pickrandomsample": e.pickrandomsample,
+		"pickwhashed":      e.pickwhashed,
+		"pickwrandom":      e.pickwrandom,
+		"view":             e.view,
+
+		"asnum":         e.asnum,
+		"continent":     e.matches(e.env.Continent),
+		"continentCode": e.code(e.env.Continent),
+		"country":       e.matches(e.env.Country),
+		"countryCode":   e.code(e.env.Country),
+		"netmask":       e.netmask,
+		"region":        e.matches(e.env.Region),
+		"regionCode":    e.code(e.env.Region),
+
+		"createForward": e.createForward,
+		"createReverse": e.createReverse,
+		"filterForward": e.filterForward,
+
+		"newCA":      func(L *lua.LState) int { return e.push(e.newCA(e.checkAddr(1))) },
+		"newDN":      func(L *lua.LState) int { return e.push(e.newDN(L.CheckString(1))) },
+		"newNetmask": func(L *lua.LState) int { return e.push(e.newNetmask(e.checkPrefix(L.CheckString(1)))) },
+		"newNMG":     e.newNMG,
+	}
+	for name := range functions {
+		if _, ok := funcs[name]; !ok {
+			funcs[name] = func(L *lua.LState) int {
+				L.RaiseError("%s() needs data that only PowerDNS has, and cannot be evaluated here", name)
+				return 0
+			}
+		}
+	}
+	for name, fn := range funcs {
+		L.SetGlobal(name, L.NewFunction(fn))
+	}
+}
+
+func (e *evaluator) push(v lua.LValue) int {
+	e.L.Push(v)
+	return 1
+}
+
+// strings returns the strings of a table.
+func (e *evaluator) strings(t *lua.LTable) []string {
+	var result []string
+	for i := 1; i <= t.Len(); i++ {
+		result = append(result, tostring(t.RawGetInt(i)))
+	}
+	return result
+}
+
+func (e *evaluator) table(values []string) *lua.LTable {
+	t := e.L.NewTable()
+	for _, v := range values {
+		t.Append(lua.LString(v))
+	}
+	return t
+}
+
+// hash returns an index into n values that is stable for s.
+func hash(s string, n int) int {
+	h := fnv.New32a()
+	h.Write([]byte(s))
+	return int(h.Sum32() % uint32(n))
+}
+
+// Health checks.
+
+// ifup implements ifportup() and ifurlup(). The addresses are a list, or a
+// list of lists of which the first one with an address that is up is used.
+func (e *evaluator) ifup(addresses lua.LValue, options *lua.LTable) int {
+	t, ok := addresses.(*lua.LTable)
+	if !ok {
+		e.L.ArgError(2, "table of addresses expected")
+	}
+	var sets [][]string
+	if _, nested := t.RawGetInt(1).(*lua.LTable); nested {
+		for i := 1; i <= t.Len(); i++ {
+			set, ok := t.RawGetInt(i).(*lua.LTable)
+			if !ok {
+				e.L.ArgError(2, "table of tables of addresses expected")
+			}
+			sets = append(sets, e.strings(set))
+		}
+	} else {
+		sets = [][]string{e.strings(t)}
+	}
+	return e.selectUp(sets, options)
+}
+
+// ifurlextup implements ifurlextup(), whose sets map addresses to URLs.
+func (e *evaluator) ifurlextup(L *lua.LState) int {
+	t := L.CheckTable(1)
+	var sets [][]string
+	for i := 1; i <= t.Len(); i++ {
+		group, ok := t.RawGetInt(i).(*lua.LTable)
+		if !ok {
+			L.ArgError(1, "table of tables of address/URL pairs expected")
+		}
+		var set []string
+		group.ForEach(func(k, _ lua.LValue) { set = append(set, tostring(k)) })
+		slices.Sort(set)
+		sets = append(sets, set)
+	}
+	return e.selectUp(sets, L.OptTable(2, nil))
+}
+
+// selectUp returns the addresses of the first set that has addresses that
+// are up, picked with the selector option. If none are up, all addresses
+// are picked from with the backupSelector option.
+func (e *evaluator) selectUp(sets [][]string, options *lua.LTable) int {
+	up := e.env.Up
+	if up == nil {
+		up = func(string) bool { return true }
+	}
+	option := func(name string) string {
+		if options == nil {
+			return "random"
+		}
+		if v := options.RawGetString(name); v != lua.LNil {
+			return tostring(v)
+		}
+		return "random"
+	}
+	for _, set := range sets {
+		available := slices.DeleteFunc(slices.Clone(set), func(a string) bool { return !up(a) })
+		if len(available) != 0 {
+			return e.push(e.table(e.selectWith(option("selector"), available)))
+		}
+	}
+	return e.push(e.table(e.selectWith(option("backupSelector"), slices.Concat(sets...))))
+}
+
+func (e *evaluator) selectWith(selector string, addresses []string) []string {
+	if len(addresses) == 0 {
+		return nil
+	}
+	switch selector {
+	case "all":
+		return addresses
+	case "empty":
+		return nil
+	case "hashed", "whashed":
+		return []string{addresses[hash(e.env.Who.String(), len(addresses))]}
+	case "pickclosest":
+		return addresses[:1]
+	}
+	return []string{addresses[e.env.Rand.IntN(len(addresses))]}
+}
+
+// Selection.
+
+func (e *evaluator) all(L *lua.LState) int {
+	return e.push(L.CheckTable(1))
+}
+
+func (e *evaluator) pickrandom(L *lua.LState) int {
+	values := e.nonEmpty(1)
+	return e.push(values.RawGetInt(1 + e.env.Rand.IntN(values.Len())))
+}
+
+func (e *evaluator) pickrandomsample(L *lua.LState) int {
+	n := L.CheckInt(1)
+	values := e.strings(e.nonEmpty(2))
+	e.env.Rand.Shuffle(len(values), func(i, j int) { values[i], values[j] = values[j], values[i] })
+	return e.push(e.table(values[:min(n, len(values))]))
+}
+
+func (e *evaluator) pickhashed(L *lua.LState) int {
+	values := e.nonEmpty(1)
+	return e.push(values.RawGetInt(1 + hash(e.env.Who.String(), values.Len())))
+}
+
+func (e *evaluator) picknamehashed(L *lua.LState) int {
+	values := e.nonEmpty(1)
+	return e.push(values.RawGetInt(1 + hash(strings.ToLower(e.env.QName), values.Len())))
+}
+
+// pickclosest picks the first address, as there is no GeoIP database to
+// find the closest one.
+func (e *evaluator) pickclosest(L *lua.LState) int {
+	return e.push(e.nonEmpty(1).RawGetInt(1))
+}
+
+func (e *evaluator) pickwrandom(L *lua.LState) int {
+	weights, values := e.weighted(1)
+	return e.push(pickWeighted(weights, values, e.env.Rand.IntN(sum(weights))))
+}
+
+func (e *evaluator) pickwhashed(L *lua.LState) int {
+	weights, values := e.weighted(1)
+	return e.push(pickWeighted(weights, values, hash(e.env.Who.String(), sum(weights))))
+}
+
+// weighted returns the weights and values of a list of {weight, value}.
+func (e *evaluator) weighted(n int) ([]int, []lua.LValue) {
+	t := e.nonEmpty(n)
+	var weights []int
+	var values []lua.LValue
+	for i := 1; i <= t.Len(); i++ {
+		var w lua.LNumber
+		pair, ok := t.RawGetInt(i).(*lua.LTable)
+		if ok {
+			w, ok = pair.RawGetInt(1).(lua.LNumber)
+		}
+		if !ok || w < 0 {
+			e.L.ArgError(n, "table of {weight, value} expected")
+		}
+		weights = append(weights, int(w))
+		values = append(values, pair.RawGetInt(2))
+	}
+	if sum(weights) == 0 {
+		e.L.ArgError(n, "the weights add up to 0")
+	}
+	return weights, values
+}
+
+func sum(weights []int) int {
+	total := 0
+	for _, w := range weights {
+		total += w
+	}
+	return total
+}
+
+// pickWeighted returns the value in whose weight n falls.
+func pickWeighted(weights []int, values []lua.LValue, n int) lua.LValue {
+	for i, w := range weights {
+		if n < w {
+			return values[i]
+		}
+		n -= w
+	}
+	return values[len(values)-1]
+}
+
+func (e *evaluator) nonEmpty(n int) *lua.LTable {
+	t := e.L.CheckTable(n)
+	if t.Len() == 0 {
+		e.L.ArgError(n, "table is empty")
+	}
+	return t
+}
+
+// view returns the values of the first {netmasks, values} pair whose
+// netmasks contain the client.
+func (e *evaluator) view(L *lua.LState) int {
+	t := L.CheckTable(1)
+	for i := 1; i <= t.Len(); i++ {
+		pair, ok := t.RawGetInt(i).(*lua.LTable)
+		if !ok {
+			L.ArgError(1, "table of {netmasks, values} expected")
+		}
+		nets, ok := pair.RawGetInt(1).(*lua.LTable)
+		if !ok {
+			L.ArgError(1, "table of {netmasks, values} expected")
+		}
+		if e.contains(e.strings(nets), e.env.Who) {
+			return e.push(pair.RawGetInt(2))
+		}
+	}
+	return e.push(L.NewTable())
+}
+
+// Client location.
+
+// matches returns a function that reports whether the code, or one of the
+// list of codes, it is called with is have.
+func (e *evaluator) matches(have string) lua.LGFunction {
+	return func(L *lua.LState) int {
+		var want []string
+		switch v := L.CheckAny(1).(type) {
+		case *lua.LTable:
+			want = e.strings(v)
+		default:
+			want = []string{tostring(v)}
+		}
+		return e.push(lua.LBool(have != "" && slices.ContainsFunc(want, func(w string) bool { return strings.EqualFold(w, have) })))
+	}
+}
+
+func (e *evaluator) code(have string) lua.LGFunction {
+	return func(L *lua.LState) int {
+		if have == "" {
+			return e.push(lua.LString("--"))
+		}
+		return e.push(lua.LString(have))
+	}
+}
+
+func (e *evaluator) asnum(L *lua.LState) int {
+	var want []string
+	switch v := L.CheckAny(1).(type) {
+	case *lua.LTable:
+		want = e.strings(v)
+	default:
+		want = []string{tostring(v)}
+	}
+	return e.push(lua.LBool(e.env.ASN != 0 && slices.Contains(want, strconv.Itoa(e.env.ASN))))
+}
+
+func (e *evaluator) netmask(L *lua.LState) int {
+	return e.push(lua.LBool(e.contains(e.strings(L.CheckTable(1)), e.env.Who)))
+}
+
+// contains reports whether one of the netmasks contains addr.
+func (e *evaluator) contains(netmasks []string, addr netip.Addr) bool {
+	for _, n := range netmasks {
+		if e.checkPrefix(n).Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// Reverse and forward records.
+
+var octetsPattern = regexp.MustCompile(`(\d{1,3})[-.](\d{1,3})[-.](\d{1,3})[-.](\d{1,3})`)
+
+// createReverse returns the name for the address in an in-addr.arpa qname.
+// %1% to %4% are the octets, %5% the address with dashes and %6% the
+// address in hex.
+func (e *evaluator) createReverse(L *lua.LState) int {
+	format := L.CheckString(1)
+	labels := strings.Split(strings.TrimSuffix(strings.ToLower(e.env.QName), "."), ".")
+	if len(labels) != 6 || labels[4] != "in-addr" || labels[5] != "arpa" {
+		L.RaiseError("createReverse(): %s is not an IPv4 reverse name", e.env.QName)
+	}
+	octets := []string{labels[3], labels[2], labels[1], labels[0]}
+	addr, err := netip.ParseAddr(strings.Join(octets, "."))
+	if err != nil {
+		L.RaiseError("createReverse(): %s", err)
+	}
+	if exceptions := L.OptTable(2, nil); exceptions != nil {
+		if v := exceptions.RawGetString(addr.String()); v != lua.LNil {
+			return e.push(v)
+		}
+	}
+	b := addr.As4()
+	r := strings.NewReplacer(
+		"%1%", octets[0], "%2%", octets[1], "%3%", octets[2], "%4%", octets[3],
+		"%5%", strings.Join(octets, "-"), "%6%", hex.EncodeToString(b[:]),
+	)
+	return e.push(lua.LString(r.Replace(format)))
+}
+
+// createForward returns the address in the qname, such as 192-0-2-1 or
+// ip-192.0.2.1 or c0000201 (hex). It is 0.0.0.0 if there is none.
+func (e *evaluator) createForward(L *lua.LState) int {
+	name := strings.TrimSuffix(strings.ToLower(e.env.QName), ".")
+	name = strings.TrimSuffix(strings.TrimSuffix(name, strings.TrimSuffix(strings.ToLower(e.env.Zone), ".")), ".")
+	if m := octetsPattern.FindAllStringSubmatch(name, -1); m != nil {
+		last := m[len(m)-1]
+		if addr, err := netip.ParseAddr(strings.Join(last[1:], ".")); err == nil {
+			return e.push(lua.LString(addr.String()))
+		}
+	}
+	label, _, _ := strings.Cut(name, ".")
+	if b, err := hex.DecodeString(label); err == nil && len(b) == 4 {
+		return e.push(lua.LString(netip.AddrFrom4([4]byte(b)).String()))
+	}
+	return e.push(lua.LString("0.0.0.0"))
+}
+
+// filterForward returns the address if it is in the netmasks, and the
+// fallback otherwise.
+func (e *evaluator) filterForward(L *lua.LState) int {
+	address := L.CheckString(1)
+	addr, err := netip.ParseAddr(address)
+	var in bool
+	switch masks := L.CheckAny(2).(type) {
+	case *lua.LTable:
+		in = err == nil && e.contains(e.strings(masks), addr)
+	case *lua.LUserData:
+		nmg, ok := masks.Value.(*netmaskGroup)
+		if !ok {
+			L.ArgError(2, "NMG expected")
+		}
+		in = err == nil && nmg.match(addr)
+	default:
+		L.ArgError(2, "NMG expected")
+	}
+	if in {
+		return e.push(lua.LString(address))
+	}
+	return e.push(lua.LString(L.OptString(3, "0.0.0.0")))
+}
+
+// Objects.
+
+// The objects are user data whose metatable gives them the methods that
+// PowerDNS has. See
+// https://doc.powerdns.com/authoritative/lua-reference/index.html
+const (
+	typeDNSName      = "DNSName"
+	typeComboAddress = "ComboAddress"
+	typeNetmask      = "Netmask"
+	typeNMG          = "NetmaskGroup"
+)
+
+type netmaskGroup struct{ prefixes []netip.Prefix }
+
+func (g *netmaskGroup) match(addr netip.Addr) bool {
+	return slices.ContainsFunc(g.prefixes, func(p netip.Prefix) bool { return p.Contains(addr) })
+}
+
+func (e *evaluator) openTypes() {
+	L := e.L
+	method := func(typ string, fn func(L *lua.LState, self *lua.LUserData) int) lua.LGFunction {
+		return func(L *lua.LState) int {
+			self := L.CheckUserData(1)
+			if L.GetMetatable(self) != L.GetTypeMetatable(typ) {
+				L.ArgError(1, typ+" expected")
+			}
+			return fn(L, self)
+		}
+	}
+	dn := func(self *lua.LUserData) string { return self.Value.(string) }
+	ca := func(self *lua.LUserData) netip.Addr { return self.Value.(netip.Addr) }
+	nm := func(self *lua.LUserData) netip.Prefix { return self.Value.(netip.Prefix) }
+
+	mt := L.NewTypeMetatable(typeDNSName)
+	L.SetField(mt, "__tostring", L.NewFunction(method(typeDNSName, func(L *lua.LState, self *lua.LUserData) int {
+		return e.push(lua.LString(dn(self)))
+	})))
+	L.SetField(mt, "__eq", L.NewFunction(func(L *lua.LState) int {
+		return e.push(lua.LBool(strings.EqualFold(tostring(L.Get(1)), tostring(L.Get(2)))))
+	}))
+	L.SetField(mt, "__index", L.SetFuncs(L.NewTable(), map[string]lua.LGFunction{
+		"toString": method(typeDNSName, func(L *lua.LState, self *lua.LUserData) int {
+			return e.push(lua.LString(dn(self)))
+		}),
+		"toStringNoDot": method(typeDNSName, func(L *lua.LState, self *lua.LUserData) int {
+			return e.push(lua.LString(strings.TrimSuffix(dn(self), ".")))
+		}),
+		"countLabels": method(typeDNSName, func(L *lua.LState, self *lua.LUserData) int {
+			return e.push(lua.LNumber(len(labels(dn(self)))))
+		}),
+		"getRawLabels": method(typeDNSName, func(L *lua.LState, self *lua.LUserData) int {
+			return e.push(e.table(labels(dn(self))))
+		}),
+		"equal": method(typeDNSName, func(L *lua.LState, self *lua.LUserData) int {
+			return e.push(lua.LBool(strings.EqualFold(dn(self), fqdn(tostring(L.CheckAny(2))))))
+		}),
+		"isPartOf": method(typeDNSName, func(L *lua.LState, self *lua.LUserData) int {
+			parent := strings.ToLower(fqdn(tostring(L.CheckAny(2))))
+			name := strings.ToLower(dn(self))
+			return e.push(lua.LBool(parent == "." || name == parent || strings.HasSuffix(name, "."+parent)))
+		}),
+	}))
+
+	mt = L.NewTypeMetatable(typeComboAddress)
+	L.SetField(mt, "__tostring", L.NewFunction(method(typeComboAddress, func(L *lua.LState, self *lua.LUserData) int {
+		return e.push(lua.LString(ca(self).String()))
+	})))
+	L.SetField(mt, "__index", L.SetFuncs(L.NewTable(), map[string]lua.LGFunction{
+		"toString": method(typeComboAddress, func(L *lua.LState, self *lua.LUserData) int {
+			return e.push(lua.LString(ca(self).String()))
+		}),
+		"toStringWithPort": method(typeComboAddress, func(L *lua.LState, self *lua.LUserData) int {
+			return e.push(lua.LString(netip.AddrPortFrom(ca(self), 53).String()))
+		}),
+		"getPort": method(typeComboAddress, func(L *lua.LState, self *lua.LUserData) int {
+			return e.push(lua.LNumber(53))
+		}),
+		"isIPv4": method(typeComboAddress, func(L *lua.LState, self *lua.LUserData) int {
+			return e.push(lua.LBool(ca(self).Is4()))
+		}),
+		"isIPv6": method(typeComboAddress, func(L *lua.LState, self *lua.LUserData) int {
+			return e.push(lua.LBool(ca(self).Is6()))
+		}),
+	}))
+
+	mt = L.NewTypeMetatable(typeNetmask)
+	L.SetField(mt, "__tostring", L.NewFunction(method(typeNetmask, func(L *lua.LState, self *lua.LUserData) int {
+		return e.push(lua.LString(nm(self).String()))
+	})))
+	L.SetField(mt, "__index", L.SetFuncs(L.NewTable(), map[string]lua.LGFunction{
+		"toString": method(typeNetmask, func(L *lua.LState, self *lua.LUserData) int {
+			return e.push(lua.LString(nm(self).String()))
+		}),
+		"getBits": method(typeNetmask, func(L *lua.LState, self *lua.LUserData) int {
+			return e.push(lua.LNumber(nm(self).Bits()))
+		}),
+		"getNetwork": method(typeNetmask, func(L *lua.LState, self *lua.LUserData) int {
+			return e.push(e.newCA(nm(self).Addr()))
+		}),
+		"isIPv4": method(typeNetmask, func(L *lua.LState, self *lua.LUserData) int {
+			return e.push(lua.LBool(nm(self).Addr().Is4()))
+		}),
+		"isIPv6": method(typeNetmask, func(L *lua.LState, self *lua.LUserData) int {
+			return e.push(lua.LBool(nm(self).Addr().Is6()))
+		}),
+		"match": method(typeNetmask, func(L *lua.LState, self *lua.LUserData) int {
+			return e.push(lua.LBool(nm(self).Contains(e.checkAddr(2))))
+		}),
+	}))
+
+	mt = L.NewTypeMetatable(typeNMG)
+	nmg := func(self *lua.LUserData) *netmaskGroup { return self.Value.(*netmaskGroup) }
+	L.SetField(mt, "__index", L.SetFuncs(L.NewTable(), map[string]lua.LGFunction{
+		"addMask": method(typeNMG, func(L *lua.LState, self *lua.LUserData) int {
+			g := nmg(self)
+			g.prefixes = append(g.prefixes, e.checkPrefix(L.CheckString(2)))
+			return 0
+		}),
+		"addMasks": method(typeNMG, func(L *lua.LState, self *lua.LUserData) int {
+			g := nmg(self)
+			for _, m := range e.strings(L.CheckTable(2)) {
+				g.prefixes = append(g.prefixes, e.checkPrefix(m))
+			}
+			return 0
+		}),
+		"match": method(typeNMG, func(L *lua.LState, self *lua.LUserData) int {
+			return e.push(lua.LBool(nmg(self).match(e.checkAddr(2))))
+		}),
+	}))
+}
+
+func (e *evaluator) newDN(name string) lua.LValue {
+	ud := e.L.NewUserData()
+	ud.Value = fqdn(name)
+	e.L.SetMetatable(ud, e.L.GetTypeMetatable(typeDNSName))
+	return ud
+}
+
+func (e *evaluator) newCA(addr netip.Addr) lua.LValue {
+	if !addr.IsValid() {
+		return lua.LNil
+	}
+	ud := e.L.NewUserData()
+	ud.Value = addr
+	e.L.SetMetatable(ud, e.L.GetTypeMetatable(typeComboAddress))
+	return ud
+}
+
+func (e *evaluator) newNetmask(p netip.Prefix) lua.LValue {
+	ud := e.L.NewUserData()
+	ud.Value = p
+	e.L.SetMetatable(ud, e.L.GetTypeMetatable(typeNetmask))
+	return ud
+}
+
+func (e *evaluator) newNMG(L *lua.LState) int {
+	g := &netmaskGroup{}
+	if t := L.OptTable(1, nil); t != nil {
+		for _, m := range e.strings(t) {
+			g.prefixes = append(g.prefixes, e.checkPrefix(m))
+		}
+	}
+	ud := L.NewUserData()
+	ud.Value = g
+	L.SetMetatable(ud, L.GetTypeMetatable(typeNMG))
+	return e.push(ud)
+}
+
+// checkAddr returns the address that argument n is, as a string or a
+// ComboAddress.
+func (e *evaluator) checkAddr(n int) netip.Addr {
+	v := e.L.CheckAny(n)
+	if ud, ok := v.(*lua.LUserData); ok {
+		if addr, ok := ud.Value.(netip.Addr); ok {
+			return addr
+		}
+	}
+	s := tostring(v)
+	if ap, err := netip.ParseAddrPort(s); err == nil {
+		return ap.Addr()
+	}
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		e.L.ArgError(n, fmt.Sprintf("%q is not an address", s))
+	}
+	return addr
+}
+
+// checkPrefix returns the netmask s, which may be a single address.
+func (e *evaluator) checkPrefix(s string) netip.Prefix {
+	if !strings.Contains(s, "/") {
+		addr, err := netip.ParseAddr(s)
+		if err != nil {
+			e.L.RaiseError("%q is not a netmask", s)
+		}
+		return netip.PrefixFrom(addr, addr.BitLen())
+	}
+	p, err := netip.ParsePrefix(s)
+	if err != nil {
+		e.L.RaiseError("%q is not a netmask", s)
+	}
+	return p.Masked()
+}
+
+// tostring returns a value as a string, using the string of the objects.
+func tostring(v lua.LValue) string {
+	if ud, ok := v.(*lua.LUserData); ok {
+		switch x := ud.Value.(type) {
+		case string:
+			return x
+		case netip.Addr:
+			return x.String()
+		case netip.Prefix:
+			return x.String()
+		}
+	}
+	return lua.LVAsString(v)
+}
+
+func fqdn(name string) string {
+	if strings.HasSuffix(name, ".") {
+		return name
+	}
+	return name + "."
+}
+
+func labels(name string) []string {
+	name = strings.TrimSuffix(name, ".")
+	if name == "" {
+		return nil
+	}
+	return strings.Split(name, ".")
+}
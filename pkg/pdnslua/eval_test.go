@@ -0,0 +1,166 @@
+package pdnslua
+
+import (
+	"math/rand/v2"
+	"net/netip"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEval(t *testing.T) {
+	down := func(addrs ...string) func(string) bool {
+		return func(a string) bool { return !slices.Contains(addrs, a) }
+	}
+	env := Env{
+		QName:     "www.example.com.",
+		Zone:      "example.com.",
+		Who:       netip.MustParseAddr("198.51.100.7"),
+		Country:   "NL",
+		Continent: "EU",
+		ASN:       64500,
+	}
+	tests := []struct {
+		name    string
+		snippet string
+		up      func(string) bool
+		qname   string
+		want    []string
+		oneOf   []string // The result is one of these.
+		wantErr string
+	}{
+		{
+			name:    "string",
+			snippet: "'192.0.2.1'",
+			want:    []string{"192.0.2.1"},
+		},
+		{
+			name:    "table",
+			snippet: "{'192.0.2.1', '192.0.2.2'}",
+			want:    []string{"192.0.2.1", "192.0.2.2"},
+		},
+		{
+			name:    "ifportup all up",
+			snippet: "ifportup(443, {'192.0.2.1', '192.0.2.2'}, {selector='all'})",
+			want:    []string{"192.0.2.1", "192.0.2.2"},
+		},
+		{
+			name:    "ifportup one down",
+			snippet: "ifportup(443, {'192.0.2.1', '192.0.2.2'})",
+			up:      down("192.0.2.1"),
+			want:    []string{"192.0.2.2"},
+		},
+		{
+			name:    "ifportup all down uses backup selector",
+			snippet: "ifportup(443, {'192.0.2.1', '192.0.2.2'}, {backupSelector='all'})",
+			up:      down("192.0.2.1", "192.0.2.2"),
+			want:    []string{"192.0.2.1", "192.0.2.2"},
+		},
+		{
+			name:    "ifurlup sets",
+			snippet: "ifurlup('https://example.com/', {{'192.0.2.1', '192.0.2.2'}, {'198.51.100.1'}}, {selector='all'})",
+			up:      down("192.0.2.1", "192.0.2.2"),
+			want:    []string{"198.51.100.1"},
+		},
+		{
+			name:    "ifurlextup",
+			snippet: "ifurlextup({{['192.0.2.1']='https://a/', ['192.0.2.2']='https://b/'}}, {selector='all'})",
+			up:      down("192.0.2.2"),
+			want:    []string{"192.0.2.1"},
+		},
+		{
+			name:    "pickrandom",
+			snippet: "pickrandom({'192.0.2.1', '192.0.2.2'})",
+			oneOf:   []string{"192.0.2.1", "192.0.2.2"},
+		},
+		{
+			name:    "pickwrandom",
+			snippet: "pickwrandom({{0, '192.0.2.1'}, {100, '192.0.2.2'}})",
+			want:    []string{"192.0.2.2"},
+		},
+		{
+			name:    "pickhashed",
+			snippet: "pickhashed({'192.0.2.1', '192.0.2.2'})",
+			oneOf:   []string{"192.0.2.1", "192.0.2.2"},
+		},
+		{
+			name:    "pickrandomsample",
+			snippet: "#pickrandomsample(2, {'192.0.2.1', '192.0.2.2', '192.0.2.3'})",
+			want:    []string{"2"},
+		},
+		{
+			name:    "view",
+			snippet: "view({{{'192.0.2.0/24'}, {'10.0.0.1'}}, {{'0.0.0.0/0'}, {'192.0.2.1'}}})",
+			want:    []string{"192.0.2.1"},
+		},
+		{
+			name:    "location",
+			snippet: "; if country({'BE', 'NL'}) and continent('EU') and asnum(64500) and netmask({'198.51.100.0/24'}) then return countryCode() end return 'no'",
+			want:    []string{"NL"},
+		},
+		{
+			name:    "objects",
+			snippet: "; local n = newNMG() n:addMask('198.51.100.0/24') return qname:toStringNoDot() .. ' ' .. tostring(n:match(bestwho)) .. ' ' .. tostring(qname:isPartOf(zone))",
+			want:    []string{"www.example.com true true"},
+		},
+		{
+			name:    "createReverse",
+			snippet: "createReverse('ip-%5%.example.com')",
+			qname:   "1.2.0.192.in-addr.arpa.",
+			want:    []string{"ip-192-0-2-1.example.com"},
+		},
+		{
+			name:    "createForward",
+			snippet: "createForward()",
+			qname:   "ip-192-0-2-1.example.com.",
+			want:    []string{"192.0.2.1"},
+		},
+		{
+			name:    "filterForward",
+			snippet: "filterForward(createForward(), newNMG({'10.0.0.0/8'}))",
+			qname:   "192-0-2-1.example.com.",
+			want:    []string{"0.0.0.0"},
+		},
+		{
+			name:    "needs PowerDNS",
+			snippet: "include('other')",
+			wantErr: "include() needs data that only PowerDNS has",
+		},
+		{
+			name:    "runtime error",
+			snippet: "pickrandom({})",
+			wantErr: "table is empty",
+		},
+		{
+			name:    "sandbox",
+			snippet: "; os.execute('true')",
+			wantErr: "attempt to call a non-function object",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := env
+			env.Up = tt.up
+			env.Rand = rand.New(rand.NewPCG(1, 2))
+			if tt.qname != "" {
+				env.QName = tt.qname
+			}
+			got, err := Eval(tt.snippet, env)
+			if tt.wantErr != "" {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), tt.wantErr)
+				}
+				return
+			}
+			require.NoError(t, err)
+			if tt.oneOf != nil {
+				require.Len(t, got, 1)
+				assert.Contains(t, tt.oneOf, got[0])
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
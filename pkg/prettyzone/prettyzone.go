@@ -106,7 +106,7 @@ func (z *ZoneGenData) generateZoneFileHelper(w io.Writer) error {
 		// Fake types are commented out.
 		prefix := ""
 		_, ok := dnsv1.StringToType[rr.Type]
-		if !ok {
+		if _, generic := models.GenericType(rr.Type); !ok && !generic {
 			prefix = ";"
 		}
 
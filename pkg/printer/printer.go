@@ -181,7 +181,7 @@ func (c ConsolePrinter) EndProvider2(name string, numCorrections int) {
 // Debugf is called to print/format debug information.
 func (c ConsolePrinter) Debugf(format string, args ...any) {
 	if c.Verbose {
-		fmt.Fprintf(c.Writer, format, args...)
+		fmt.Fprint(c.Writer, Redact(fmt.Sprintf(format, args...)))
 	}
 }
 
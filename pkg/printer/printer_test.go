@@ -45,3 +45,17 @@ func TestVerbose(t *testing.T) {
 	p.Debugf("more debugging\n")
 	assert.Equal(t, "WARNING: a dire warning!\noutput\nmore debugging\n", output.String())
 }
+
+func TestDebugfRedacts(t *testing.T) {
+	output := &bytes.Buffer{}
+	p := ConsolePrinter{
+		Writer:  output,
+		Verbose: true,
+	}
+
+	AddSecret("s3cr3t-token")
+	AddSecret("on") // Too short to be redacted.
+	p.Debugf("Authorization: Bearer %s (%s)\n", "s3cr3t-token", "on")
+	p.Printf("output\n")
+	assert.Equal(t, "Authorization: Bearer [REDACTED] (on)\noutput\n", output.String())
+}
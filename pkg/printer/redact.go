@@ -0,0 +1,49 @@
+package printer
+
+import (
+	"slices"
+	"strings"
+	"sync"
+)
+
+// secrets are the values that must never appear in debug output, such as
+// the credentials resolved from a secret backend.
+var secrets struct {
+	sync.RWMutex
+	values   []string
+	replacer *strings.Replacer
+}
+
+// minSecretLen is the length below which values are not redacted: hiding
+// every "1" or "on" would make debug output useless.
+const minSecretLen = 4
+
+// AddSecret registers a value that Redact (and Debugf) will hide.
+func AddSecret(s string) {
+	if len(s) < minSecretLen {
+		return
+	}
+	secrets.Lock()
+	defer secrets.Unlock()
+	if slices.Contains(secrets.values, s) {
+		return
+	}
+	secrets.values = append(secrets.values, s)
+	// Longest first, so that a secret containing another is hidden whole.
+	slices.SortFunc(secrets.values, func(a, b string) int { return len(b) - len(a) })
+	var oldnew []string
+	for _, v := range secrets.values {
+		oldnew = append(oldnew, v, "[REDACTED]")
+	}
+	secrets.replacer = strings.NewReplacer(oldnew...)
+}
+
+// Redact returns s with every registered secret replaced by "[REDACTED]".
+func Redact(s string) string {
+	secrets.RLock()
+	defer secrets.RUnlock()
+	if secrets.replacer == nil {
+		return s
+	}
+	return secrets.replacer.Replace(s)
+}
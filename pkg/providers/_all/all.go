@@ -27,6 +27,7 @@ import (
 	_ "github.com/DNSControl/dnscontrol/v4/providers/dynu"
 	_ "github.com/DNSControl/dnscontrol/v4/providers/easyname"
 	_ "github.com/DNSControl/dnscontrol/v4/providers/exoscale"
+	_ "github.com/DNSControl/dnscontrol/v4/providers/fake"
 	_ "github.com/DNSControl/dnscontrol/v4/providers/fortigate"
 	_ "github.com/DNSControl/dnscontrol/v4/providers/gandiv5"
 	_ "github.com/DNSControl/dnscontrol/v4/providers/gcloud"
@@ -41,6 +42,7 @@ import (
 	_ "github.com/DNSControl/dnscontrol/v4/providers/internetbs"
 	_ "github.com/DNSControl/dnscontrol/v4/providers/inwx"
 	_ "github.com/DNSControl/dnscontrol/v4/providers/joker"
+	_ "github.com/DNSControl/dnscontrol/v4/providers/knot"
 	_ "github.com/DNSControl/dnscontrol/v4/providers/linode"
 	_ "github.com/DNSControl/dnscontrol/v4/providers/loopia"
 	_ "github.com/DNSControl/dnscontrol/v4/providers/luadns"
@@ -3,6 +3,7 @@
 package providers
 
 import (
+	"fmt"
 	"log"
 )
 
@@ -117,6 +118,40 @@ const (
 
 	// CanUseAKAMAITLC indicates the provider supports the specific AKAMAITLC records that only the Akamai EdgeDns provider supports.
 	CanUseAKAMAITLC
+
+	// CanUseRAW indicates the provider can handle records of any type, in the
+	// generic notation of RFC 3597 (RAW records).
+	CanUseRAW
+
+	// CanUseCDNSKEY indicates the provider can handle CDNSKEY records.
+	CanUseCDNSKEY
+
+	// CanUseCDS indicates the provider can handle CDS records.
+	CanUseCDS
+
+	// CanUseCERT indicates the provider can handle CERT records.
+	CanUseCERT
+
+	// CanUseHINFO indicates the provider can handle HINFO records.
+	CanUseHINFO
+
+	// CanUseIPSECKEY indicates the provider can handle IPSECKEY records.
+	CanUseIPSECKEY
+
+	// CanUseURI indicates the provider can handle URI records.
+	CanUseURI
+
+	// CanUseZONEMD indicates the provider can handle ZONEMD records.
+	CanUseZONEMD
+
+	// CanUseSteering indicates the provider can translate the traffic
+	// steering policies of STEER() (weight, geo and failover) to its own.
+	CanUseSteering
+
+	// CanUseHealthChecks indicates the provider can manage the health checks
+	// of HEALTH_CHECK() (see HealthCheckManager), so that records can refer
+	// to them by name.
+	CanUseHealthChecks
 )
 
 var providerCapabilities = map[string]map[Capability]bool{}
@@ -129,6 +164,37 @@ func ProviderHasCapability(pType string, capa Capability) bool {
 	return providerCapabilities[pType][capa]
 }
 
+// CapabilityOverrider should be implemented by providers whose capabilities
+// are chosen per instance at runtime (for example FAKE, whose capabilities
+// come from creds.json). OverrideCapability returns the value of capa for
+// this instance, and false if the instance uses the provider type's value.
+type CapabilityOverrider interface {
+	OverrideCapability(capa Capability) (value, ok bool)
+}
+
+// InstanceHasCapability returns true if driver, a provider of type pType,
+// has capability. The driver's CapabilityOverrider, if any, takes
+// precedence over the capabilities of the provider type.
+func InstanceHasCapability(pType string, driver any, capa Capability) bool {
+	if o, ok := driver.(CapabilityOverrider); ok {
+		if value, ok := o.OverrideCapability(capa); ok {
+			return value
+		}
+	}
+	return ProviderHasCapability(pType, capa)
+}
+
+// ParseCapability returns the Capability whose name is s (for example
+// "CanUseCAA").
+func ParseCapability(s string) (Capability, error) {
+	for i := range len(_Capability_index) - 1 {
+		if c := Capability(i); c.String() == s {
+			return c, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown capability %q", s)
+}
+
 // DocumentationNote is a way for providers to give more detail about what features they support.
 type DocumentationNote struct {
 	HasFeature    bool
@@ -140,7 +206,7 @@ type DocumentationNote struct {
 // DocumentationNotes is a full list of notes for a single provider.
 type DocumentationNotes map[Capability]*DocumentationNote
 
-// ProviderMetadata is a common interface for DocumentationNotes and Capability to be used interchangeably.
+// ProviderMetadata is a common interface for DocumentationNotes, Capability and TTLPolicy to be used interchangeably.
 type ProviderMetadata any
 
 // Notes is a collection of all documentation notes, keyed by provider type.
@@ -162,6 +228,8 @@ func unwrapProviderCapabilities(pName string, meta []ProviderMetadata) {
 				Notes[pName][k] = v
 				providerCapabilities[pName][k] = v.HasFeature
 			}
+		case TTLPolicy:
+			TTLPolicies[pName] = x
 		default:
 			log.Fatalf("Unrecognized ProviderMetadata type: %T", pm)
 		}
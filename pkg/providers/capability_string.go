@@ -38,11 +38,21 @@ func _() {
 	_ = x[DocDualHost-27]
 	_ = x[DocOfficiallySupported-28]
 	_ = x[CanUseAKAMAITLC-29]
+	_ = x[CanUseRAW-30]
+	_ = x[CanUseCDNSKEY-31]
+	_ = x[CanUseCDS-32]
+	_ = x[CanUseCERT-33]
+	_ = x[CanUseHINFO-34]
+	_ = x[CanUseIPSECKEY-35]
+	_ = x[CanUseURI-36]
+	_ = x[CanUseZONEMD-37]
+	_ = x[CanUseSteering-38]
+	_ = x[CanUseHealthChecks-39]
 }
 
-const _Capability_name = "CanAutoDNSSECCanConcurCanGetZonesCanOnlyDiff1FeaturesCanUseAKAMAICDNCanUseAliasCanUseAzureAliasCanUseCAACanUseDHCIDCanUseDNAMECanUseDSCanUseDSForChildrenCanUseHTTPSCanUseLOCCanUseNAPTRCanUsePTRCanUseRoute53AliasCanUseRPCanUseSMIMEACanUseSOACanUseSRVCanUseSSHFPCanUseSVCBCanUseTLSACanUseDNSKEYCanUseOPENPGPKEYDocCreateDomainsDocDualHostDocOfficiallySupportedCanUseAKAMAITLC"
+const _Capability_name = "CanAutoDNSSECCanConcurCanGetZonesCanOnlyDiff1FeaturesCanUseAKAMAICDNCanUseAliasCanUseAzureAliasCanUseCAACanUseDHCIDCanUseDNAMECanUseDSCanUseDSForChildrenCanUseHTTPSCanUseLOCCanUseNAPTRCanUsePTRCanUseRoute53AliasCanUseRPCanUseSMIMEACanUseSOACanUseSRVCanUseSSHFPCanUseSVCBCanUseTLSACanUseDNSKEYCanUseOPENPGPKEYDocCreateDomainsDocDualHostDocOfficiallySupportedCanUseAKAMAITLCCanUseRAWCanUseCDNSKEYCanUseCDSCanUseCERTCanUseHINFOCanUseIPSECKEYCanUseURICanUseZONEMDCanUseSteeringCanUseHealthChecks"
 
-var _Capability_index = [...]uint16{0, 13, 22, 33, 53, 68, 79, 95, 104, 115, 126, 134, 153, 164, 173, 184, 193, 211, 219, 231, 240, 249, 260, 270, 280, 292, 308, 324, 335, 357, 372}
+var _Capability_index = [...]uint16{0, 13, 22, 33, 53, 68, 79, 95, 104, 115, 126, 134, 153, 164, 173, 184, 193, 211, 219, 231, 240, 249, 260, 270, 280, 292, 308, 324, 335, 357, 372, 381, 394, 403, 413, 424, 438, 447, 459, 473, 491}
 
 func (i Capability) String() string {
 	idx := int(i) - 0
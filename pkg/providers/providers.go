@@ -7,6 +7,7 @@ import (
 	"log"
 
 	"github.com/DNSControl/dnscontrol/v4/models"
+	"github.com/DNSControl/dnscontrol/v4/pkg/dnssec"
 )
 
 // Registrar is an interface for a domain registrar. It can return a list of needed corrections to be applied in the future. Implement this only if the provider is a "registrar" (i.e. can update the NS records of the parent to a domain).
@@ -32,6 +33,78 @@ type ZoneLister interface {
 	ListZones() ([]string, error)
 }
 
+// ZoneDeleter should be implemented by providers that have the ability to
+// delete zones. It is used by the "prune-zones" command.
+type ZoneDeleter interface {
+	DeleteZone(domain string) error
+}
+
+// RecordTransformer should be implemented by providers that change the
+// desired records in GetZoneRecordsCorrections in a way that changes what
+// they serve (for example, by converting an ALIAS into a CNAME).
+// TransformRecords makes the same changes to dc.Records. It is used by the
+// consistency check of "preview" and "push".
+type RecordTransformer interface {
+	TransformRecords(dc *models.DomainConfig) error
+}
+
+// HealthCheckManager should be implemented by providers with the
+// CanUseHealthChecks capability. GetHealthCheckCorrections returns the
+// corrections that create or update the health checks, and those that
+// delete the health checks the provider created for dnscontrol that are no
+// longer in checks. "push" runs the deletions after the zones, since their
+// records may still use the health checks.
+type HealthCheckManager interface {
+	GetHealthCheckCorrections(checks []*models.HealthCheck) (corrections, deletions []*models.Correction, err error)
+}
+
+// ZoneVersioner should be implemented by providers that can cheaply tell
+// whether the records of a zone changed, for example with the zone's SOA
+// serial or an etag. GetZoneVersion returns a string that changes whenever
+// the records change, or "" if it can't tell for this zone. It is used by
+// the on-disk zone cache of "preview" and "push" (--zone-cache), which then
+// passes cached records to GetZoneRecordsCorrections. Those have no
+// Original: if GetZoneRecordsCorrections needs it, it must download the
+// records again when there are changes.
+type ZoneVersioner interface {
+	GetZoneVersion(dc *models.DomainConfig) (string, error)
+}
+
+// DNSSECKeyLister should be implemented by providers that can report the
+// DNSSEC keys of a zone. It is used by "dnssec status".
+type DNSSECKeyLister interface {
+	DNSSECKeys(zone string) ([]dnssec.Key, error)
+}
+
+// DNSSECKeyManager should be implemented by providers that can manage the
+// DNSSEC keys of a zone. It is used by "dnssec keygen" and "dnssec rollover",
+// which decide when keys change state (see pkg/dnssec).
+type DNSSECKeyManager interface {
+	DNSSECKeyLister
+	// DNSSECCreateKey creates a key with the role, algorithm and timing of
+	// key, and returns it with its ID, key tag and DNSKEY filled in.
+	DNSSECCreateKey(zone string, key dnssec.Key) (dnssec.Key, error)
+	// DNSSECUpdateKey stores the timing of a key, and publishes, activates,
+	// deactivates or removes it accordingly.
+	DNSSECUpdateKey(zone string, key dnssec.Key) error
+}
+
+// CatalogZoner should be implemented by providers that can maintain a
+// catalog zone (RFC 9432) listing the zones they serve. The catalog zone's
+// records are generated by pkg/catalogzone.
+type CatalogZoner interface {
+	// CatalogZone returns the name of the catalog zone, or "" if none is
+	// configured.
+	CatalogZone() string
+}
+
+// ZoneSettingsExporter should be implemented by providers that manage
+// zone-level settings with domain modifiers. get-zones outputs the domain
+// modifiers that ExportZoneSettings returns.
+type ZoneSettingsExporter interface {
+	ExportZoneSettings(zone string) ([]string, error)
+}
+
 // RegistrarInitializer is a function to create a registrar. Function will be passed the unprocessed json payload from the configuration file for the given provider.
 type RegistrarInitializer func(map[string]string) (Registrar, error)
 
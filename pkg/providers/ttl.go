@@ -0,0 +1,37 @@
+package providers
+
+import "slices"
+
+// TTLPolicy describes the TTLs a DNS provider accepts. A provider
+// registers it with its other ProviderMetadata. TTLs outside of the policy
+// are adjusted before the records are compared with the provider's, so
+// that a TTL the provider would change does not cause a perpetual diff.
+type TTLPolicy struct {
+	Min     uint32   // The smallest TTL, or 0 for no minimum.
+	Max     uint32   // The largest TTL, or 0 for no maximum.
+	Allowed []uint32 // If set, the only TTLs accepted, in increasing order.
+	Auto    uint32   // A TTL that means "automatic" and is accepted as is (Cloudflare uses 1), or 0.
+}
+
+// TTLPolicies stores the TTL policy of each provider type that has one.
+var TTLPolicies = map[string]TTLPolicy{}
+
+// Apply returns the TTL the provider would use for ttl: ttl clamped to
+// [Min, Max], then rounded up to the next allowed value (or down to the
+// largest).
+func (p TTLPolicy) Apply(ttl uint32) uint32 {
+	if p.Auto != 0 && ttl == p.Auto {
+		return ttl
+	}
+	if p.Min != 0 && ttl < p.Min {
+		ttl = p.Min
+	}
+	if p.Max != 0 && ttl > p.Max {
+		ttl = p.Max
+	}
+	if len(p.Allowed) != 0 && !slices.Contains(p.Allowed, ttl) {
+		i, _ := slices.BinarySearch(p.Allowed, ttl)
+		ttl = p.Allowed[min(i, len(p.Allowed)-1)]
+	}
+	return ttl
+}
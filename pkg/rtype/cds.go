@@ -0,0 +1,130 @@
+package rtype
+
+import (
+	"fmt"
+
+	"github.com/DNSControl/dnscontrol/v4/models"
+	"github.com/DNSControl/dnscontrol/v4/pkg/domaintags"
+	"github.com/DNSControl/dnscontrol/v4/pkg/rtypecontrol"
+	dnsv1 "github.com/miekg/dns"
+)
+
+func init() {
+	rtypecontrol.Register(&CDS{})
+	rtypecontrol.Register(&CDNSKEY{})
+}
+
+// CDS RR. See RFC 7344. The child's request to its parent for a DS RRset.
+type CDS struct {
+	dnsv1.CDS
+}
+
+// Name returns the DNS record type as a string.
+func (handle *CDS) Name() string {
+	return "CDS"
+}
+
+// FromArgs fills in the RecordConfig from []any, which is typically from a parsed config file.
+func (handle *CDS) FromArgs(dcn *domaintags.DomainNameVarieties, rec *models.RecordConfig, args []any) error {
+	if err := rtypecontrol.PaveArgs(args[1:], "wbbs"); err != nil {
+		return fmt.Errorf("ERROR: (%s) [CDS(%q, %v)]: %w",
+			rec.FilePos,
+			rec.Name, rtypecontrol.StringifyQuoted(args[1:]),
+			err)
+	}
+	fields := &dnsv1.CDS{
+		DS: dnsv1.DS{
+			KeyTag:     args[1].(uint16),
+			Algorithm:  args[2].(uint8),
+			DigestType: args[3].(uint8),
+			Digest:     args[4].(string),
+		},
+	}
+
+	return handle.FromStruct(dcn, rec, args[0].(string), fields)
+}
+
+// FromStruct fills in the RecordConfig from a struct, typically from an API response.
+func (handle *CDS) FromStruct(dcn *domaintags.DomainNameVarieties, rec *models.RecordConfig, name string, fields any) error {
+	cds, ok := fields.(*dnsv1.CDS)
+	if !ok {
+		return fmt.Errorf("fields is not *dns.CDS, got %T", fields)
+	}
+	rec.F = &CDS{*cds}
+
+	rec.ZonefilePartial = rec.GetTargetRFC1035Quoted()
+	rec.Comparable = rec.ZonefilePartial
+
+	handle.CopyToLegacyFields(rec)
+	return nil
+}
+
+// CopyToLegacyFields populates the legacy fields of the RecordConfig using the fields in .F.
+func (handle *CDS) CopyToLegacyFields(rec *models.RecordConfig) {
+	// CDS, like all new RRs, does not have legacy fields.
+}
+
+// CopyFromLegacyFields populates the legacy fields of the RecordConfig using the fields in .F.
+func (handle *CDS) CopyFromLegacyFields(rec *models.RecordConfig) {
+	// CDS is RecordConfigv2 and has no legacy fields.
+	rec.ZonefilePartial = rec.GetTargetRFC1035Quoted()
+	rec.Comparable = rec.ZonefilePartial
+}
+
+// CDNSKEY RR. See RFC 7344. The child's request to its parent for a DS
+// RRset, as the DNSKEY the DS records are computed from.
+type CDNSKEY struct {
+	dnsv1.CDNSKEY
+}
+
+// Name returns the DNS record type as a string.
+func (handle *CDNSKEY) Name() string {
+	return "CDNSKEY"
+}
+
+// FromArgs fills in the RecordConfig from []any, which is typically from a parsed config file.
+func (handle *CDNSKEY) FromArgs(dcn *domaintags.DomainNameVarieties, rec *models.RecordConfig, args []any) error {
+	if err := rtypecontrol.PaveArgs(args[1:], "wbbs"); err != nil {
+		return fmt.Errorf("ERROR: (%s) [CDNSKEY(%q, %v)]: %w",
+			rec.FilePos,
+			rec.Name, rtypecontrol.StringifyQuoted(args[1:]),
+			err)
+	}
+	fields := &dnsv1.CDNSKEY{
+		DNSKEY: dnsv1.DNSKEY{
+			Flags:     args[1].(uint16),
+			Protocol:  args[2].(uint8),
+			Algorithm: args[3].(uint8),
+			PublicKey: args[4].(string),
+		},
+	}
+
+	return handle.FromStruct(dcn, rec, args[0].(string), fields)
+}
+
+// FromStruct fills in the RecordConfig from a struct, typically from an API response.
+func (handle *CDNSKEY) FromStruct(dcn *domaintags.DomainNameVarieties, rec *models.RecordConfig, name string, fields any) error {
+	cdnskey, ok := fields.(*dnsv1.CDNSKEY)
+	if !ok {
+		return fmt.Errorf("fields is not *dns.CDNSKEY, got %T", fields)
+	}
+	rec.F = &CDNSKEY{*cdnskey}
+
+	rec.ZonefilePartial = rec.GetTargetRFC1035Quoted()
+	rec.Comparable = rec.ZonefilePartial
+
+	handle.CopyToLegacyFields(rec)
+	return nil
+}
+
+// CopyToLegacyFields populates the legacy fields of the RecordConfig using the fields in .F.
+func (handle *CDNSKEY) CopyToLegacyFields(rec *models.RecordConfig) {
+	// CDNSKEY, like all new RRs, does not have legacy fields.
+}
+
+// CopyFromLegacyFields populates the legacy fields of the RecordConfig using the fields in .F.
+func (handle *CDNSKEY) CopyFromLegacyFields(rec *models.RecordConfig) {
+	// CDNSKEY is RecordConfigv2 and has no legacy fields.
+	rec.ZonefilePartial = rec.GetTargetRFC1035Quoted()
+	rec.Comparable = rec.ZonefilePartial
+}
@@ -0,0 +1,72 @@
+package rtype
+
+import (
+	"fmt"
+
+	"github.com/DNSControl/dnscontrol/v4/models"
+	"github.com/DNSControl/dnscontrol/v4/pkg/domaintags"
+	"github.com/DNSControl/dnscontrol/v4/pkg/rtypecontrol"
+	dnsv1 "github.com/miekg/dns"
+)
+
+func init() {
+	rtypecontrol.Register(&CERT{})
+}
+
+// CERT RR. See RFC 4398.
+type CERT struct {
+	dnsv1.CERT
+}
+
+// Name returns the DNS record type as a string.
+func (handle *CERT) Name() string {
+	return "CERT"
+}
+
+// FromArgs fills in the RecordConfig from []any, which is typically from a parsed config file.
+// The certificate type and the algorithm may be numbers or mnemonics ("PKIX", "RSASHA256").
+func (handle *CERT) FromArgs(dcn *domaintags.DomainNameVarieties, rec *models.RecordConfig, args []any) error {
+	if err := rtypecontrol.PaveArgs(args[1:], "swss"); err != nil {
+		return fmt.Errorf("ERROR: (%s) [CERT(%q, %v)]: %w",
+			rec.FilePos,
+			rec.Name, rtypecontrol.StringifyQuoted(args[1:]),
+			err)
+	}
+	// The zonefile parser knows the mnemonics and validates the certificate.
+	rr, err := dnsv1.NewRR(fmt.Sprintf(". 0 IN CERT %s %d %s %s", args[1], args[2], args[3], args[4]))
+	if err != nil {
+		return fmt.Errorf("ERROR: (%s) [CERT(%q, %v)]: %w",
+			rec.FilePos,
+			rec.Name, rtypecontrol.StringifyQuoted(args[1:]),
+			err)
+	}
+
+	return handle.FromStruct(dcn, rec, args[0].(string), rr)
+}
+
+// FromStruct fills in the RecordConfig from a struct, typically from an API response.
+func (handle *CERT) FromStruct(dcn *domaintags.DomainNameVarieties, rec *models.RecordConfig, name string, fields any) error {
+	cert, ok := fields.(*dnsv1.CERT)
+	if !ok {
+		return fmt.Errorf("fields is not *dns.CERT, got %T", fields)
+	}
+	rec.F = &CERT{*cert}
+
+	rec.ZonefilePartial = rec.GetTargetRFC1035Quoted()
+	rec.Comparable = rec.ZonefilePartial
+
+	handle.CopyToLegacyFields(rec)
+	return nil
+}
+
+// CopyToLegacyFields populates the legacy fields of the RecordConfig using the fields in .F.
+func (handle *CERT) CopyToLegacyFields(rec *models.RecordConfig) {
+	// CERT, like all new RRs, does not have legacy fields.
+}
+
+// CopyFromLegacyFields populates the legacy fields of the RecordConfig using the fields in .F.
+func (handle *CERT) CopyFromLegacyFields(rec *models.RecordConfig) {
+	// CERT is RecordConfigv2 and has no legacy fields.
+	rec.ZonefilePartial = rec.GetTargetRFC1035Quoted()
+	rec.Comparable = rec.ZonefilePartial
+}
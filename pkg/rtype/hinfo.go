@@ -0,0 +1,68 @@
+package rtype
+
+import (
+	"fmt"
+
+	"github.com/DNSControl/dnscontrol/v4/models"
+	"github.com/DNSControl/dnscontrol/v4/pkg/domaintags"
+	"github.com/DNSControl/dnscontrol/v4/pkg/rtypecontrol"
+	dnsv1 "github.com/miekg/dns"
+)
+
+func init() {
+	rtypecontrol.Register(&HINFO{})
+}
+
+// HINFO RR. See RFC 1035. RFC 8482 uses it to answer ANY queries
+// (HINFO "RFC8482" "").
+type HINFO struct {
+	dnsv1.HINFO
+}
+
+// Name returns the DNS record type as a string.
+func (handle *HINFO) Name() string {
+	return "HINFO"
+}
+
+// FromArgs fills in the RecordConfig from []any, which is typically from a parsed config file.
+func (handle *HINFO) FromArgs(dcn *domaintags.DomainNameVarieties, rec *models.RecordConfig, args []any) error {
+	if err := rtypecontrol.PaveArgs(args[1:], "ss"); err != nil {
+		return fmt.Errorf("ERROR: (%s) [HINFO(%q, %v)]: %w",
+			rec.FilePos,
+			rec.Name, rtypecontrol.StringifyQuoted(args[1:]),
+			err)
+	}
+	fields := &dnsv1.HINFO{
+		Cpu: args[1].(string),
+		Os:  args[2].(string),
+	}
+
+	return handle.FromStruct(dcn, rec, args[0].(string), fields)
+}
+
+// FromStruct fills in the RecordConfig from a struct, typically from an API response.
+func (handle *HINFO) FromStruct(dcn *domaintags.DomainNameVarieties, rec *models.RecordConfig, name string, fields any) error {
+	hinfo, ok := fields.(*dnsv1.HINFO)
+	if !ok {
+		return fmt.Errorf("fields is not *dns.HINFO, got %T", fields)
+	}
+	rec.F = &HINFO{*hinfo}
+
+	rec.ZonefilePartial = rec.GetTargetRFC1035Quoted()
+	rec.Comparable = rec.ZonefilePartial
+
+	handle.CopyToLegacyFields(rec)
+	return nil
+}
+
+// CopyToLegacyFields populates the legacy fields of the RecordConfig using the fields in .F.
+func (handle *HINFO) CopyToLegacyFields(rec *models.RecordConfig) {
+	// HINFO, like all new RRs, does not have legacy fields.
+}
+
+// CopyFromLegacyFields populates the legacy fields of the RecordConfig using the fields in .F.
+func (handle *HINFO) CopyFromLegacyFields(rec *models.RecordConfig) {
+	// HINFO is RecordConfigv2 and has no legacy fields.
+	rec.ZonefilePartial = rec.GetTargetRFC1035Quoted()
+	rec.Comparable = rec.ZonefilePartial
+}
@@ -0,0 +1,82 @@
+package rtype
+
+import (
+	"fmt"
+
+	"github.com/DNSControl/dnscontrol/v4/models"
+	"github.com/DNSControl/dnscontrol/v4/pkg/domaintags"
+	"github.com/DNSControl/dnscontrol/v4/pkg/rtypecontrol"
+	dnsv1 "github.com/miekg/dns"
+	dnsutilv1 "github.com/miekg/dns/dnsutil"
+)
+
+func init() {
+	rtypecontrol.Register(&IPSECKEY{})
+}
+
+// IPSECKEY RR. See RFC 4025.
+type IPSECKEY struct {
+	dnsv1.IPSECKEY
+}
+
+// Name returns the DNS record type as a string.
+func (handle *IPSECKEY) Name() string {
+	return "IPSECKEY"
+}
+
+// FromArgs fills in the RecordConfig from []any, which is typically from a parsed config file.
+func (handle *IPSECKEY) FromArgs(dcn *domaintags.DomainNameVarieties, rec *models.RecordConfig, args []any) error {
+	if err := rtypecontrol.PaveArgs(args[1:], "bbbss"); err != nil {
+		return fmt.Errorf("ERROR: (%s) [IPSECKEY(%q, %v)]: %w",
+			rec.FilePos,
+			rec.Name, rtypecontrol.StringifyQuoted(args[1:]),
+			err)
+	}
+	gatewayType := args[2].(uint8)
+	gateway := args[4].(string)
+	switch gatewayType {
+	case dnsv1.IPSECGatewayNone:
+		if gateway == "" {
+			gateway = "."
+		}
+	case dnsv1.IPSECGatewayHost:
+		gateway = dnsutilv1.AddOrigin(gateway, dcn.NameASCII+".")
+	}
+	// The zonefile parser checks that the gateway matches its type.
+	rr, err := dnsv1.NewRR(fmt.Sprintf(". 0 IN IPSECKEY %d %d %d %s %s", args[1], gatewayType, args[3], gateway, args[5]))
+	if err != nil {
+		return fmt.Errorf("ERROR: (%s) [IPSECKEY(%q, %v)]: %w",
+			rec.FilePos,
+			rec.Name, rtypecontrol.StringifyQuoted(args[1:]),
+			err)
+	}
+
+	return handle.FromStruct(dcn, rec, args[0].(string), rr)
+}
+
+// FromStruct fills in the RecordConfig from a struct, typically from an API response.
+func (handle *IPSECKEY) FromStruct(dcn *domaintags.DomainNameVarieties, rec *models.RecordConfig, name string, fields any) error {
+	ipseckey, ok := fields.(*dnsv1.IPSECKEY)
+	if !ok {
+		return fmt.Errorf("fields is not *dns.IPSECKEY, got %T", fields)
+	}
+	rec.F = &IPSECKEY{*ipseckey}
+
+	rec.ZonefilePartial = rec.GetTargetRFC1035Quoted()
+	rec.Comparable = rec.ZonefilePartial
+
+	handle.CopyToLegacyFields(rec)
+	return nil
+}
+
+// CopyToLegacyFields populates the legacy fields of the RecordConfig using the fields in .F.
+func (handle *IPSECKEY) CopyToLegacyFields(rec *models.RecordConfig) {
+	// IPSECKEY, like all new RRs, does not have legacy fields.
+}
+
+// CopyFromLegacyFields populates the legacy fields of the RecordConfig using the fields in .F.
+func (handle *IPSECKEY) CopyFromLegacyFields(rec *models.RecordConfig) {
+	// IPSECKEY is RecordConfigv2 and has no legacy fields.
+	rec.ZonefilePartial = rec.GetTargetRFC1035Quoted()
+	rec.Comparable = rec.ZonefilePartial
+}
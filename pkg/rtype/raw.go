@@ -0,0 +1,119 @@
+package rtype
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/DNSControl/dnscontrol/v4/models"
+	"github.com/DNSControl/dnscontrol/v4/pkg/domaintags"
+	"github.com/DNSControl/dnscontrol/v4/pkg/rtypecontrol"
+	dnsv1 "github.com/miekg/dns"
+)
+
+func init() {
+	rtypecontrol.Register(&RAW{})
+}
+
+// RAW is a record of a type that DNSControl does not know, such as a
+// private-use type, with its rdata in the generic notation of RFC 3597:
+//
+//	RAW("label", "TYPE65534", "\\# 4 0a000001")
+//
+// The record's Type is the generic name of the type ("TYPE65534"), so that
+// records of different types are different RRsets. rtypecontrol.Get() maps
+// all such types to this rtype.
+type RAW struct {
+	dnsv1.RFC3597
+}
+
+// Name returns the DNS record type as a string.
+func (handle *RAW) Name() string {
+	return "RAW"
+}
+
+// String returns the record in zonefile format. Unlike dns.RFC3597, the
+// header is written like that of any other type ("IN TYPE65534", not
+// "CLASS1 TYPE65534") so that the rdata can be extracted from it.
+func (handle *RAW) String() string {
+	return handle.Hdr.String() + models.GenericRdata(handle.Rdata)
+}
+
+// FromArgs fills in the RecordConfig from []any, which is typically from a parsed config file.
+func (handle *RAW) FromArgs(dcn *domaintags.DomainNameVarieties, rec *models.RecordConfig, args []any) error {
+	if err := rtypecontrol.PaveArgs(args[1:], "ss"); err != nil {
+		return fmt.Errorf("ERROR: (%s) [RAW(%q, %v)]: %w",
+			rec.FilePos,
+			rec.Name, rtypecontrol.StringifyQuoted(args[1:]),
+			err)
+	}
+	rtype := strings.ToUpper(args[1].(string))
+	n, ok := models.GenericType(rtype)
+	if !ok {
+		return fmt.Errorf("ERROR: (%s) [RAW(%q, %q, ...)]: the type must be written TYPEnnn (RFC 3597)",
+			rec.FilePos, rec.Name, args[1])
+	}
+	if known, ok := dnsv1.TypeToString[n]; ok {
+		return fmt.Errorf("ERROR: (%s) [RAW(%q, %q, ...)]: %s is %s; RAW() is only for types unknown to DNSControl",
+			rec.FilePos, rec.Name, args[1], rtype, known)
+	}
+
+	rr, err := dnsv1.NewRR(fmt.Sprintf(". 0 IN %s %s", rtype, args[2].(string)))
+	if err != nil {
+		return fmt.Errorf("ERROR: (%s) [RAW(%q, %q, %q)]: %w",
+			rec.FilePos, rec.Name, args[1], args[2], err)
+	}
+	if rr == nil {
+		return fmt.Errorf("ERROR: (%s) [RAW(%q, %q, %q)]: empty rdata; use \"\\\\# 0\"",
+			rec.FilePos, rec.Name, args[1], args[2])
+	}
+
+	rec.Type = rtype
+	return handle.FromStruct(dcn, rec, args[0].(string), rr)
+}
+
+// FromStruct fills in the RecordConfig from a struct, typically from an API response.
+func (handle *RAW) FromStruct(dcn *domaintags.DomainNameVarieties, rec *models.RecordConfig, name string, fields any) error {
+	var rr dnsv1.RFC3597
+	switch f := fields.(type) {
+	case *dnsv1.RFC3597:
+		rr = *f
+	case *RAW:
+		rr = f.RFC3597
+	default:
+		return fmt.Errorf("fields is not *dns.RFC3597, got %T", fields)
+	}
+	if _, ok := models.GenericType(rec.Type); !ok {
+		return fmt.Errorf("RAW records must have a TYPEnnn type, got %q", rec.Type)
+	}
+	rr.Rdata = strings.ToLower(rr.Rdata)
+	rec.F = &RAW{rr}
+
+	rec.ZonefilePartial = models.GenericRdata(rr.Rdata)
+	rec.Comparable = rec.ZonefilePartial
+
+	handle.CopyToLegacyFields(rec)
+	return nil
+}
+
+// CopyToLegacyFields populates the legacy fields of the RecordConfig using the fields in .F.
+func (handle *RAW) CopyToLegacyFields(rec *models.RecordConfig) {
+	// RAW, like all new RRs, does not have legacy fields. .target holds
+	// the rdata for code that displays it.
+	_ = rec.SetTarget(rec.ZonefilePartial)
+}
+
+// CopyFromLegacyFields populates .F from the legacy fields. Providers that
+// have not been updated store the generic rdata in .target.
+func (handle *RAW) CopyFromLegacyFields(rec *models.RecordConfig) {
+	n, _ := models.GenericType(rec.Type)
+	rr, err := dnsv1.NewRR(fmt.Sprintf(". 0 IN %s %s", rec.Type, rec.GetTargetField()))
+	raw, ok := rr.(*dnsv1.RFC3597)
+	if err != nil || !ok {
+		raw = &dnsv1.RFC3597{Hdr: dnsv1.RR_Header{Rrtype: n, Class: dnsv1.ClassINET}}
+	}
+	rec.F = &RAW{*raw}
+
+	// Fix up ZonefilePartial and Comparable:
+	rec.ZonefilePartial = models.GenericRdata(raw.Rdata)
+	rec.Comparable = rec.ZonefilePartial
+}
@@ -0,0 +1,77 @@
+package rtype
+
+import (
+	"testing"
+
+	"github.com/DNSControl/dnscontrol/v4/pkg/domaintags"
+	"github.com/DNSControl/dnscontrol/v4/pkg/rtypecontrol"
+	dnsv1 "github.com/miekg/dns"
+)
+
+func TestRAW(t *testing.T) {
+	dcn := domaintags.MakeDomainNameVarieties("example.com")
+	rec, err := rtypecontrol.NewRecordConfigFromRaw(rtypecontrol.FromRawOpts{
+		Type: "RAW",
+		TTL:  300,
+		Args: []any{"inv", "type65534", `\# 4 0A000001`},
+		DCN:  dcn,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.Type != "TYPE65534" {
+		t.Errorf("Type = %q, want TYPE65534", rec.Type)
+	}
+	if want := `\# 4 0a000001`; rec.ZonefilePartial != want || rec.Comparable != want {
+		t.Errorf("ZonefilePartial = %q, Comparable = %q, want %q", rec.ZonefilePartial, rec.Comparable, want)
+	}
+	if got := rec.GetTargetCombined(); got != `\# 4 0a000001` {
+		t.Errorf("GetTargetCombined() = %q", got)
+	}
+
+	// Round trip through the wire format, as a provider would.
+	rr := rec.ToRR()
+	if got, want := rr.String(), "inv.example.com.\t300\tIN\tTYPE65534\t\\# 4 0a000001"; got != want {
+		t.Errorf("ToRR() = %q, want %q", got, want)
+	}
+	buf := make([]byte, 512)
+	off, err := dnsv1.PackRR(rr, buf, 0, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	back, _, err := dnsv1.UnpackRR(buf[:off], 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec2, err := rtypecontrol.NewRecordConfigFromStruct("inv", back.Header().Ttl, dnsv1.Type(back.Header().Rrtype).String(), back, dcn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec2.Type != rec.Type || rec2.Comparable != rec.Comparable {
+		t.Errorf("round trip: got %s %q, want %s %q", rec2.Type, rec2.Comparable, rec.Type, rec.Comparable)
+	}
+
+	// As a provider returning the rdata as a string would.
+	rec3, err := rtypecontrol.NewRecordConfigFromString("inv", 300, "TYPE65534", `\# 4 0A000001`, dcn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec3.Comparable != rec.Comparable {
+		t.Errorf("from string: got %q, want %q", rec3.Comparable, rec.Comparable)
+	}
+}
+
+func TestRAW_errors(t *testing.T) {
+	dcn := domaintags.MakeDomainNameVarieties("example.com")
+	for _, args := range [][]any{
+		{"inv", "65534", `\# 4 0a000001`},     // Not TYPEnnn.
+		{"inv", "TYPE1", `\# 4 0a000001`},     // A.
+		{"inv", "TYPE65534", `\# 3 0a000001`}, // Wrong length.
+		{"inv", "TYPE65534", "10.0.0.1"},      // Not generic rdata.
+	} {
+		_, err := rtypecontrol.NewRecordConfigFromRaw(rtypecontrol.FromRawOpts{Type: "RAW", Args: args, DCN: dcn})
+		if err == nil {
+			t.Errorf("RAW(%q): expected an error", args)
+		}
+	}
+}
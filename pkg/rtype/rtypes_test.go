@@ -0,0 +1,130 @@
+package rtype
+
+import (
+	"testing"
+
+	"github.com/DNSControl/dnscontrol/v4/pkg/domaintags"
+	"github.com/DNSControl/dnscontrol/v4/pkg/rtypecontrol"
+	dnsv1 "github.com/miekg/dns"
+)
+
+func TestRtypes(t *testing.T) {
+	dcn := domaintags.MakeDomainNameVarieties("example.com")
+	tests := []struct {
+		rtype string
+		args  []any
+		want  string // ZonefilePartial
+	}{
+		{"URI", []any{"_ftp._tcp", 10, 1, "ftp://ftp1.example.com/public"},
+			`10 1 "ftp://ftp1.example.com/public"`},
+		{"HINFO", []any{"host", "INTEL-386", "Windows"},
+			`"INTEL-386" "Windows"`},
+		{"CERT", []any{"@", "PGP", 0, "0", "AAECAw=="},
+			`PGP 0 0 AAECAw==`},
+		{"CERT", []any{"@", "3", 1, "RSASHA256", "AAECAw=="},
+			`PGP 1 RSASHA256 AAECAw==`},
+		{"IPSECKEY", []any{"@", 10, 1, 2, "192.0.2.38", "AQNRU3mG7TVTO2BkR47usntb102uFJtugbo6BSGvgqt4AQ=="},
+			`10 1 2 192.0.2.38 AQNRU3mG7TVTO2BkR47usntb102uFJtugbo6BSGvgqt4AQ==`},
+		{"IPSECKEY", []any{"@", 10, 0, 2, "", "AQNRU3mG7TVTO2BkR47usntb102uFJtugbo6BSGvgqt4AQ=="},
+			`10 0 2 . AQNRU3mG7TVTO2BkR47usntb102uFJtugbo6BSGvgqt4AQ==`},
+		{"IPSECKEY", []any{"@", 10, 3, 2, "gw", "AQNRU3mG7TVTO2BkR47usntb102uFJtugbo6BSGvgqt4AQ=="},
+			`10 3 2 gw.example.com. AQNRU3mG7TVTO2BkR47usntb102uFJtugbo6BSGvgqt4AQ==`},
+		{"CDS", []any{"@", 60485, 5, 1, "2BB183AF5F22588179A53B0A98631FAD1A292118"},
+			`60485 5 1 2BB183AF5F22588179A53B0A98631FAD1A292118`},
+		{"CDNSKEY", []any{"@", 257, 3, 13, "mdsswUyr3DPW132mOi8V9xESWE8jTo0dxCjjnopKl+GqJxpVXckHAeF+KkxLbxILfDLUT0rAK9iUzy1L53eKGQ=="},
+			`257 3 13 mdsswUyr3DPW132mOi8V9xESWE8jTo0dxCjjnopKl+GqJxpVXckHAeF+KkxLbxILfDLUT0rAK9iUzy1L53eKGQ==`},
+		{"ZONEMD", []any{"@", 2018031900, 1, 1, "C68090D90A7AED716BC459F9340E3D7C1370D4D24B7E2FC3A1DDC0B9A87153B9A9713B3C9AE5CC27777F98B8E730044C"},
+			`2018031900 1 1 c68090d90a7aed716bc459f9340e3d7c1370d4d24b7e2fc3a1ddc0b9a87153b9a9713b3c9ae5cc27777f98b8e730044c`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rtype, func(t *testing.T) {
+			rec, err := rtypecontrol.NewRecordConfigFromRaw(rtypecontrol.FromRawOpts{
+				Type: tt.rtype,
+				TTL:  300,
+				Args: tt.args,
+				DCN:  dcn,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if rec.ZonefilePartial != tt.want || rec.Comparable != tt.want {
+				t.Errorf("ZonefilePartial = %q, Comparable = %q, want %q", rec.ZonefilePartial, rec.Comparable, tt.want)
+			}
+			if got := rec.GetTargetCombined(); got != tt.want {
+				t.Errorf("GetTargetCombined() = %q, want %q", got, tt.want)
+			}
+
+			// Round trip through the wire format, as a provider would.
+			rr := rec.ToRR()
+			buf := make([]byte, 1024)
+			off, err := dnsv1.PackRR(rr, buf, 0, nil, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			back, _, err := dnsv1.UnpackRR(buf[:off], 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			label := tt.args[0].(string)
+			rec2, err := rtypecontrol.NewRecordConfigFromStruct(label, 300, tt.rtype, back, dcn)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if rec2.Comparable != rec.Comparable {
+				t.Errorf("round trip: got %q, want %q", rec2.Comparable, rec.Comparable)
+			}
+
+			// As a provider returning the rdata as a string would.
+			rec3, err := rtypecontrol.NewRecordConfigFromString(label, 300, tt.rtype, tt.want, dcn)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if rec3.Comparable != rec.Comparable {
+				t.Errorf("from string: got %q, want %q", rec3.Comparable, rec.Comparable)
+			}
+		})
+	}
+}
+
+func TestZONEMDFromStructKeepsFields(t *testing.T) {
+	dcn := domaintags.MakeDomainNameVarieties("example.com")
+	fields := &dnsv1.ZONEMD{Serial: 1, Scheme: 1, Hash: 1, Digest: "ABCDEF"}
+	rec, err := rtypecontrol.NewRecordConfigFromStruct("@", 300, "ZONEMD", fields, dcn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fields.Digest != "ABCDEF" {
+		t.Errorf("FromStruct changed the caller's digest to %q", fields.Digest)
+	}
+	if want := "1 1 1 abcdef"; rec.Comparable != want {
+		t.Errorf("Comparable = %q, want %q", rec.Comparable, want)
+	}
+}
+
+func TestRtypes_errors(t *testing.T) {
+	dcn := domaintags.MakeDomainNameVarieties("example.com")
+	tests := []struct {
+		rtype string
+		args  []any
+	}{
+		{"URI", []any{"@", 10, 1, ""}},
+		{"URI", []any{"@", 70000, 1, "https://example.com/"}},
+		{"HINFO", []any{"@", "INTEL-386"}},
+		{"CERT", []any{"@", "NOSUCH", 0, "0", "AAECAw=="}},
+		{"IPSECKEY", []any{"@", 10, 1, 2, "not-an-ip", "AQNR"}},
+		{"CDS", []any{"@", 60485, 5, 1}},
+		{"ZONEMD", []any{"@", -1, 1, 1, "00"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rtype, func(t *testing.T) {
+			_, err := rtypecontrol.NewRecordConfigFromRaw(rtypecontrol.FromRawOpts{
+				Type: tt.rtype,
+				Args: tt.args,
+				DCN:  dcn,
+			})
+			if err == nil {
+				t.Errorf("%s%v: expected an error", tt.rtype, tt.args)
+			}
+		})
+	}
+}
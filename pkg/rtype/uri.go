@@ -0,0 +1,73 @@
+package rtype
+
+import (
+	"fmt"
+
+	"github.com/DNSControl/dnscontrol/v4/models"
+	"github.com/DNSControl/dnscontrol/v4/pkg/domaintags"
+	"github.com/DNSControl/dnscontrol/v4/pkg/rtypecontrol"
+	dnsv1 "github.com/miekg/dns"
+)
+
+func init() {
+	rtypecontrol.Register(&URI{})
+}
+
+// URI RR. See RFC 7553.
+type URI struct {
+	dnsv1.URI
+}
+
+// Name returns the DNS record type as a string.
+func (handle *URI) Name() string {
+	return "URI"
+}
+
+// FromArgs fills in the RecordConfig from []any, which is typically from a parsed config file.
+func (handle *URI) FromArgs(dcn *domaintags.DomainNameVarieties, rec *models.RecordConfig, args []any) error {
+	if err := rtypecontrol.PaveArgs(args[1:], "wws"); err != nil {
+		return fmt.Errorf("ERROR: (%s) [URI(%q, %v)]: %w",
+			rec.FilePos,
+			rec.Name, rtypecontrol.StringifyQuoted(args[1:]),
+			err)
+	}
+	if args[3].(string) == "" {
+		return fmt.Errorf("ERROR: (%s) [URI(%q, %v)]: empty target",
+			rec.FilePos,
+			rec.Name, rtypecontrol.StringifyQuoted(args[1:]))
+	}
+	fields := &dnsv1.URI{
+		Priority: args[1].(uint16),
+		Weight:   args[2].(uint16),
+		Target:   args[3].(string),
+	}
+
+	return handle.FromStruct(dcn, rec, args[0].(string), fields)
+}
+
+// FromStruct fills in the RecordConfig from a struct, typically from an API response.
+func (handle *URI) FromStruct(dcn *domaintags.DomainNameVarieties, rec *models.RecordConfig, name string, fields any) error {
+	uri, ok := fields.(*dnsv1.URI)
+	if !ok {
+		return fmt.Errorf("fields is not *dns.URI, got %T", fields)
+	}
+	rec.F = &URI{*uri}
+
+	rec.ZonefilePartial = rec.GetTargetRFC1035Quoted()
+	rec.Comparable = rec.ZonefilePartial
+
+	handle.CopyToLegacyFields(rec)
+	return nil
+}
+
+// CopyToLegacyFields populates the legacy fields of the RecordConfig using the fields in .F.
+func (handle *URI) CopyToLegacyFields(rec *models.RecordConfig) {
+	// URI, like all new RRs, does not have legacy fields.
+}
+
+// CopyFromLegacyFields populates the legacy fields of the RecordConfig using the fields in .F.
+func (handle *URI) CopyFromLegacyFields(rec *models.RecordConfig) {
+	// URI is RecordConfigv2 and has no legacy fields.
+	rec.ZonefilePartial = rec.GetTargetRFC1035Quoted()
+	rec.Comparable = rec.ZonefilePartial
+}
@@ -0,0 +1,74 @@
+package rtype
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/DNSControl/dnscontrol/v4/models"
+	"github.com/DNSControl/dnscontrol/v4/pkg/domaintags"
+	"github.com/DNSControl/dnscontrol/v4/pkg/rtypecontrol"
+	dnsv1 "github.com/miekg/dns"
+)
+
+func init() {
+	rtypecontrol.Register(&ZONEMD{})
+}
+
+// ZONEMD RR. See RFC 8976.
+type ZONEMD struct {
+	dnsv1.ZONEMD
+}
+
+// Name returns the DNS record type as a string.
+func (handle *ZONEMD) Name() string {
+	return "ZONEMD"
+}
+
+// FromArgs fills in the RecordConfig from []any, which is typically from a parsed config file.
+func (handle *ZONEMD) FromArgs(dcn *domaintags.DomainNameVarieties, rec *models.RecordConfig, args []any) error {
+	if err := rtypecontrol.PaveArgs(args[1:], "dbbs"); err != nil {
+		return fmt.Errorf("ERROR: (%s) [ZONEMD(%q, %v)]: %w",
+			rec.FilePos,
+			rec.Name, rtypecontrol.StringifyQuoted(args[1:]),
+			err)
+	}
+	fields := &dnsv1.ZONEMD{
+		Serial: args[1].(uint32),
+		Scheme: args[2].(uint8),
+		Hash:   args[3].(uint8),
+		Digest: args[4].(string),
+	}
+
+	return handle.FromStruct(dcn, rec, args[0].(string), fields)
+}
+
+// FromStruct fills in the RecordConfig from a struct, typically from an API response.
+func (handle *ZONEMD) FromStruct(dcn *domaintags.DomainNameVarieties, rec *models.RecordConfig, name string, fields any) error {
+	zonemd, ok := fields.(*dnsv1.ZONEMD)
+	if !ok {
+		return fmt.Errorf("fields is not *dns.ZONEMD, got %T", fields)
+	}
+	// The digest is hex; servers differ in the case they use. Normalize a
+	// copy, not the caller's struct.
+	f := &ZONEMD{*zonemd}
+	f.Digest = strings.ToLower(f.Digest)
+	rec.F = f
+
+	rec.ZonefilePartial = rec.GetTargetRFC1035Quoted()
+	rec.Comparable = rec.ZonefilePartial
+
+	handle.CopyToLegacyFields(rec)
+	return nil
+}
+
+// CopyToLegacyFields populates the legacy fields of the RecordConfig using the fields in .F.
+func (handle *ZONEMD) CopyToLegacyFields(rec *models.RecordConfig) {
+	// ZONEMD, like all new RRs, does not have legacy fields.
+}
+
+// CopyFromLegacyFields populates the legacy fields of the RecordConfig using the fields in .F.
+func (handle *ZONEMD) CopyFromLegacyFields(rec *models.RecordConfig) {
+	// ZONEMD is RecordConfigv2 and has no legacy fields.
+	rec.ZonefilePartial = rec.GetTargetRFC1035Quoted()
+	rec.Comparable = rec.ZonefilePartial
+}
@@ -30,7 +30,7 @@ func FixLegacyRecord(rec *models.RecordConfig) {
 	if rec.F != nil {
 		return
 	}
-	if fixer, ok := Func[rec.Type]; ok {
+	if fixer, ok := Get(rec.Type); ok {
 		fixer.CopyFromLegacyFields(rec)
 	}
 }
@@ -87,7 +87,8 @@ func NewRecordConfigFromRaw(opts FromRawOpts) (*models.RecordConfig, error) {
 	dcn := opts.DCN
 	FilePos := opts.FilePos
 
-	if _, ok := Func[t]; !ok {
+	rt, ok := Get(t)
+	if !ok {
 		return nil, fmt.Errorf("record type %q is not supported", t)
 	}
 	if t == "" {
@@ -127,7 +128,7 @@ func NewRecordConfigFromRaw(opts FromRawOpts) (*models.RecordConfig, error) {
 	}
 
 	// Fill in the .F/.Fields* fields.
-	err := Func[t].FromArgs(dcn, rec, args)
+	err := rt.FromArgs(dcn, rec, args)
 	if err != nil {
 		return nil, err
 	}
@@ -139,7 +140,7 @@ func NewRecordConfigFromRaw(opts FromRawOpts) (*models.RecordConfig, error) {
 // format usually used in a zonefile but typically also used by providers
 // returning the fields of a record as a string.
 func NewRecordConfigFromString(name string, ttl uint32, t string, s string, dcn *domaintags.DomainNameVarieties) (*models.RecordConfig, error) {
-	if _, ok := Func[t]; !ok {
+	if _, ok := Get(t); !ok {
 		return nil, fmt.Errorf("record type %q is not supported", t)
 	}
 	if t == "" {
@@ -158,7 +159,8 @@ func NewRecordConfigFromString(name string, ttl uint32, t string, s string, dcn
 // a miekg/dns struct. It must be the exact struct type used by the FromStruct()
 // method of the rtype package.
 func NewRecordConfigFromStruct(name string, ttl uint32, t string, fields any, dcn *domaintags.DomainNameVarieties) (*models.RecordConfig, error) {
-	if _, ok := Func[t]; !ok {
+	rt, ok := Get(t)
+	if !ok {
 		return nil, fmt.Errorf("record type %q is not supported", t)
 	}
 	if t == "" {
@@ -184,7 +186,7 @@ func NewRecordConfigFromStruct(name string, ttl uint32, t string, fields any, dc
 		return nil, fmt.Errorf("label %q is not in zone %q", name, dcn.NameASCII+".")
 	}
 
-	err := Func[t].FromStruct(dcn, rec, name, fields)
+	err := rt.FromStruct(dcn, rec, name, fields)
 	if err != nil {
 		return nil, err
 	}
@@ -11,7 +11,7 @@ import (
 // 's': string (will convert other types to string using %v)
 // 'b': uint8 (will convert strings, truncate floats, etc)
 // 'w': uint16 (will convert strings, truncate floats, etc)
-// FUTURE 'd': uint32 (will convert strings, truncate floats, etc)
+// 'd': uint32 (will convert strings, truncate floats, etc)
 // FUTURE 'q': uint64 (will convert strings, truncate floats, etc)
 // FUTURE: Uppercase runes for signed types.
 func PaveArgs(args []any, argTypes string) error {
@@ -105,6 +105,44 @@ func PaveArgs(args []any, argTypes string) error {
 				return fmt.Errorf("value %q is type %T, expected uint16", arg, arg)
 			}
 
+		case 'd': // uint32
+			switch v := arg.(type) {
+			case uint8:
+				args[i] = uint32(v)
+			case uint16:
+				args[i] = uint32(v)
+			case uint32:
+				// already correct type
+			case int16:
+				if v < 0 {
+					return fmt.Errorf("value %q overflows uint32", arg)
+				}
+				args[i] = uint32(v)
+			case uint:
+				if v > math.MaxUint32 {
+					return fmt.Errorf("value %q overflows uint32", arg)
+				}
+				args[i] = uint32(v)
+			case int:
+				if v < 0 || v > math.MaxUint32 {
+					return fmt.Errorf("value %q overflows uint32", arg)
+				}
+				args[i] = uint32(v)
+			case float64:
+				if v < 0 || v > math.MaxUint32 {
+					return fmt.Errorf("value %q overflows uint32", arg)
+				}
+				args[i] = uint32(v)
+			case string:
+				ni, err := strconv.ParseUint(arg.(string), 10, 32)
+				if err != nil {
+					return fmt.Errorf("value %q is not a number (uint32 wanted)", arg)
+				}
+				args[i] = uint32(ni)
+			default:
+				return fmt.Errorf("value %q is type %T, expected uint32", arg, arg)
+			}
+
 		default:
 			return fmt.Errorf("unknown argType rune: %q", at)
 		}
@@ -19,8 +19,8 @@ func TestPaveArgs(t *testing.T) {
 		},
 		{
 			name:     "unknown arg type",
-			dataArgs: []any{uint32(100)},
-			dataRule: "d",
+			dataArgs: []any{uint64(100)},
+			dataRule: "q",
 			wantErr:  true,
 		},
 		{
@@ -91,6 +91,12 @@ func TestPaveArgsConversionsCompact(t *testing.T) {
 		{name: "float64 to uint16 valid", args: []any{float64(1234.5)}, argTypes: "w", want: []any{uint16(1234)}},
 		{name: "string to uint16 valid", args: []any{"65535"}, argTypes: "w", want: []any{uint16(65535)}},
 
+		// uint32 ('d') conversions - all should result in uint32 type
+		{name: "uint32 unchanged", args: []any{uint32(4000000000)}, argTypes: "d", want: []any{uint32(4000000000)}},
+		{name: "uint16 to uint32", args: []any{uint16(65535)}, argTypes: "d", want: []any{uint32(65535)}},
+		{name: "float64 to uint32 valid", args: []any{float64(2025010101)}, argTypes: "d", want: []any{uint32(2025010101)}},
+		{name: "string to uint32 valid", args: []any{"4294967295"}, argTypes: "d", want: []any{uint32(4294967295)}},
+
 		// Multiple arguments
 		{name: "multiple types", args: []any{uint8(1), "test", uint16(1000)}, argTypes: "bsw", want: []any{uint8(1), "test", uint16(1000)}},
 		{name: "all conversions", args: []any{"100", int(200), float64(50.5)}, argTypes: "bbs", want: []any{uint8(100), uint8(200), "50.5"}},
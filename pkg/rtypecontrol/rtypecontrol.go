@@ -39,3 +39,17 @@ func Register(t RType) {
 	// For compatibility with legacy systems:
 	providers.RegisterCustomRecordType(name, "", "")
 }
+
+// Get returns the RType that handles records of type t. Records whose type
+// is in the generic notation of RFC 3597 ("TYPE65534") are handled by the
+// rtype registered as "RAW".
+func Get(t string) (RType, bool) {
+	if rt, ok := Func[t]; ok {
+		return rt, true
+	}
+	if _, ok := models.GenericType(t); ok {
+		rt, ok := Func["RAW"]
+		return rt, ok
+	}
+	return nil, false
+}
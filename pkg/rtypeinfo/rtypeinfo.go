@@ -16,6 +16,6 @@ import "github.com/DNSControl/dnscontrol/v4/pkg/rtypecontrol"
 //
 // FUTURE(tlim): Once all record types have been migrated to use ".F", this function can be removed.
 func IsModernType(t string) bool {
-	_, ok := rtypecontrol.Func[t]
+	_, ok := rtypecontrol.Get(t)
 	return ok
 }
@@ -0,0 +1,95 @@
+// Package views compares the views of split horizon zones: the D()s that
+// share a zone name and differ by their tag ("example.com!internal",
+// "example.com!external").
+package views
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/DNSControl/dnscontrol/v4/models"
+)
+
+// Zones groups the domains by zone name. Only the zones with more than one
+// view are returned, in the order of their first D().
+func Zones(domains []*models.DomainConfig) [][]*models.DomainConfig {
+	var names []string
+	byName := map[string][]*models.DomainConfig{}
+	for _, dc := range domains {
+		if _, ok := byName[dc.Name]; !ok {
+			names = append(names, dc.Name)
+		}
+		byName[dc.Name] = append(byName[dc.Name], dc)
+	}
+	var zones [][]*models.DomainConfig
+	for _, name := range names {
+		if len(byName[name]) > 1 {
+			zones = append(zones, byName[name])
+		}
+	}
+	return zones
+}
+
+// Name is how a view is shown: "!tag", or "(untagged)".
+func Name(dc *models.DomainConfig) string {
+	if dc.Tag == "" {
+		return "(untagged)"
+	}
+	return "!" + dc.Tag
+}
+
+// LabelDiff is a label whose records differ between the views of a zone.
+type LabelDiff struct {
+	Label   string
+	Records [][]string // Records[i] are the records of view i, as "TTL TYPE TARGET", sorted.
+}
+
+// Compare returns the labels whose records (including their TTL) differ
+// between the views, sorted by label. If labels is not nil, only those
+// labels are compared.
+func Compare(views []*models.DomainConfig, labels []string) []LabelDiff {
+	sets := make([]models.Records, len(views))
+	for i, dc := range views {
+		sets[i] = dc.Records
+	}
+	return CompareRecords(sets, labels)
+}
+
+// CompareRecords is like Compare for sets of records of the same zone, for
+// example the records of each of its DNS providers.
+func CompareRecords(sets []models.Records, labels []string) []LabelDiff {
+	byLabel := map[string][][]string{}
+	for i, recs := range sets {
+		for _, r := range recs {
+			label := r.GetLabel()
+			if labels != nil && !slices.Contains(labels, label) {
+				continue
+			}
+			if byLabel[label] == nil {
+				byLabel[label] = make([][]string, len(sets))
+			}
+			byLabel[label][i] = append(byLabel[label][i], Format(r))
+		}
+	}
+
+	var diffs []LabelDiff
+	for label, records := range byLabel {
+		for _, rs := range records {
+			slices.Sort(rs)
+		}
+		for _, rs := range records[1:] {
+			if !slices.Equal(rs, records[0]) {
+				diffs = append(diffs, LabelDiff{Label: label, Records: records})
+				break
+			}
+		}
+	}
+	slices.SortFunc(diffs, func(a, b LabelDiff) int { return strings.Compare(a.Label, b.Label) })
+	return diffs
+}
+
+// Format returns a record as it is shown in a comparison.
+func Format(r *models.RecordConfig) string {
+	return fmt.Sprintf("%d %s %s", r.TTL, r.Type, r.ToComparableNoTTL())
+}
@@ -0,0 +1,66 @@
+package views
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/DNSControl/dnscontrol/v4/models"
+)
+
+func view(tag string, recs ...[3]string) *models.DomainConfig {
+	dc := &models.DomainConfig{Name: "example.com", Tag: tag}
+	for _, r := range recs {
+		rc := &models.RecordConfig{Type: r[1], TTL: 300}
+		rc.SetLabel(r[0], "example.com")
+		if err := rc.SetTarget(r[2]); err != nil {
+			panic(err)
+		}
+		dc.Records = append(dc.Records, rc)
+	}
+	return dc
+}
+
+func TestZones(t *testing.T) {
+	a, b, c := view("a"), view("b"), &models.DomainConfig{Name: "example.net"}
+	zones := Zones([]*models.DomainConfig{a, c, b})
+	if len(zones) != 1 || len(zones[0]) != 2 || zones[0][0] != a || zones[0][1] != b {
+		t.Errorf("Zones() = %v", zones)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	internal := view("internal",
+		[3]string{"@", "A", "192.0.2.1"},
+		[3]string{"www", "A", "10.0.0.80"},
+		[3]string{"db", "A", "10.0.0.5"},
+		[3]string{"mail", "A", "192.0.2.25"},
+		[3]string{"mail", "A", "192.0.2.26"},
+	)
+	external := view("external",
+		[3]string{"@", "A", "192.0.2.1"},
+		[3]string{"www", "A", "192.0.2.80"},
+		[3]string{"mail", "A", "192.0.2.26"},
+		[3]string{"mail", "A", "192.0.2.25"},
+	)
+	zone := []*models.DomainConfig{internal, external}
+
+	diffs := Compare(zone, nil)
+	var labels []string
+	for _, d := range diffs {
+		labels = append(labels, d.Label)
+	}
+	if want := []string{"db", "www"}; !slices.Equal(labels, want) {
+		t.Fatalf("labels = %v, want %v", labels, want)
+	}
+	if got := diffs[0].Records; len(got[0]) != 1 || got[0][0] != "300 A 10.0.0.5" || len(got[1]) != 0 {
+		t.Errorf("db = %q", got)
+	}
+
+	if diffs := Compare(zone, []string{"@", "mail"}); len(diffs) != 0 {
+		t.Errorf("Compare(@, mail) = %v, want none", diffs)
+	}
+	external.Records[0].TTL = 60
+	if diffs := Compare(zone, []string{"@"}); len(diffs) != 1 {
+		t.Errorf("Compare(@) with different TTLs = %v", diffs)
+	}
+}
@@ -0,0 +1,121 @@
+package zonecache
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/DNSControl/dnscontrol/v4/models"
+)
+
+// Disk is a cache of the records of zones that persists between runs. It
+// is a directory with a subdirectory per credential (creds.json entry) and
+// a file per zone. Each file holds the records that GetZoneRecords returned
+// and the version of the zone (for example its SOA serial) at the time.
+// Only providers that implement providers.ZoneVersioner use it.
+type Disk struct {
+	dir string
+}
+
+// NewDisk returns a Disk cache that stores its files in dir.
+func NewDisk(dir string) *Disk {
+	return &Disk{dir: dir}
+}
+
+type diskEntry struct {
+	Version string         `json:"version"`
+	Records models.Records `json:"records"`
+}
+
+func (c *Disk) path(credential, zone string) string {
+	return filepath.Join(c.dir, url.PathEscape(credential), url.PathEscape(zone)+".json")
+}
+
+// Get returns the cached records of zone, if they were stored with the
+// same version. A missing, stale or unreadable entry is a cache miss.
+func (c *Disk) Get(credential string, dc *models.DomainConfig, version string) (models.Records, bool) {
+	if c == nil || version == "" {
+		return nil, false
+	}
+	b, err := os.ReadFile(c.path(credential, dc.UniqueName))
+	if err != nil {
+		return nil, false
+	}
+	recs, ok := decodeEntry(b, dc.Name, version)
+	return recs, ok
+}
+
+// Put stores the records of zone with version. Zones with records that
+// would not be the same after reading them back from the cache are not
+// stored, and their previous entry is removed.
+func (c *Disk) Put(credential string, dc *models.DomainConfig, version string, recs models.Records) error {
+	if c == nil || version == "" {
+		return nil
+	}
+	path := c.path(credential, dc.UniqueName)
+	b, err := json.Marshal(diskEntry{Version: version, Records: recs})
+	if err != nil {
+		return err
+	}
+	if cached, ok := decodeEntry(b, dc.Name, version); !ok || !sameRecords(recs, cached) {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	// Write to a temporary file and rename it, so that concurrent runs never
+	// read a partial entry.
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("zone cache: %w", err)
+	}
+	return nil
+}
+
+// decodeEntry returns the records of a cache entry, if it has version.
+func decodeEntry(b []byte, origin string, version string) (models.Records, bool) {
+	var e diskEntry
+	if err := json.Unmarshal(b, &e); err != nil || e.Version != version {
+		return nil, false
+	}
+	for _, rc := range e.Records {
+		// The FQDN is not stored.
+		rc.SetLabel(rc.Name, origin)
+	}
+	return e.Records, true
+}
+
+// sameRecords reports whether the records read back from the cache are
+// the same as the ones that were stored. Only the provider-specific
+// Original is expected to be lost.
+func sameRecords(a, b models.Records) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].NameFQDN != b[i].NameFQDN || a[i].Type != b[i].Type || a[i].TTL != b[i].TTL ||
+			a[i].ToComparableNoTTL() != b[i].ToComparableNoTTL() ||
+			!maps.Equal(a[i].Metadata, b[i].Metadata) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,83 @@
+package zonecache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/DNSControl/dnscontrol/v4/models"
+)
+
+func diskTestRecord(name, rtype, target string) *models.RecordConfig {
+	rc := &models.RecordConfig{Type: rtype, TTL: 300, Metadata: map[string]string{}}
+	rc.SetLabel(name, "example.com")
+	if err := rc.SetTarget(target); err != nil {
+		panic(err)
+	}
+	return rc
+}
+
+func TestDisk(t *testing.T) {
+	dir := t.TempDir()
+	cache := NewDisk(dir)
+	dc := &models.DomainConfig{Name: "example.com", UniqueName: "example.com!internal"}
+	recs := models.Records{
+		diskTestRecord("www", "A", "192.0.2.1"),
+		diskTestRecord("@", "MX", "mail.example.com."),
+	}
+	recs[1].MxPreference = 10
+	recs[0].Original = "lost"
+
+	if _, ok := cache.Get("pdns", dc, "2024010101"); ok {
+		t.Fatal("unexpected hit in an empty cache")
+	}
+	if err := cache.Put("pdns", dc, "2024010101", recs); err != nil {
+		t.Fatal(err)
+	}
+	got, ok := cache.Get("pdns", dc, "2024010101")
+	if !ok {
+		t.Fatal("expected a hit")
+	}
+	if !sameRecords(recs, got) || got[0].NameFQDN != "www.example.com" || got[1].MxPreference != 10 {
+		t.Errorf("got %v, want %v", got, recs)
+	}
+
+	// A new version is a miss, and so are other credentials and zones.
+	if _, ok := cache.Get("pdns", dc, "2024010102"); ok {
+		t.Error("unexpected hit for a new version")
+	}
+	if _, ok := cache.Get("other", dc, "2024010101"); ok {
+		t.Error("unexpected hit for another credential")
+	}
+	if _, ok := cache.Get("pdns", &models.DomainConfig{Name: "example.com", UniqueName: "example.com"}, "2024010101"); ok {
+		t.Error("unexpected hit for another zone")
+	}
+	// No version, no cache.
+	if _, ok := cache.Get("pdns", dc, ""); ok {
+		t.Error("unexpected hit without a version")
+	}
+}
+
+func TestDiskUncachable(t *testing.T) {
+	dir := t.TempDir()
+	cache := NewDisk(dir)
+	dc := &models.DomainConfig{Name: "example.com", UniqueName: "example.com"}
+	if err := cache.Put("pdns", dc, "1", models.Records{diskTestRecord("www", "A", "192.0.2.1")}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The type of F is lost, so the record would not be the same.
+	modern := diskTestRecord("www", "A", "192.0.2.1")
+	modern.F = struct{ A string }{"192.0.2.1"}
+	modern.Comparable = "192.0.2.1 (modern)"
+	if err := cache.Put("pdns", dc, "2", models.Records{modern}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cache.Get("pdns", dc, "2"); ok {
+		t.Error("records that can't be cached were cached")
+	}
+	// The previous entry is removed, rather than left behind.
+	if _, err := os.Stat(filepath.Join(dir, "pdns", "example.com.json")); !os.IsNotExist(err) {
+		t.Errorf("expected the stale entry to be removed, got %v", err)
+	}
+}
@@ -0,0 +1,247 @@
+// Package zonemd computes and verifies ZONEMD digests (RFC 8976) using the
+// SIMPLE scheme.
+package zonemd
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"slices"
+	"strings"
+
+	dnsv1 "github.com/miekg/dns"
+)
+
+// Scheme and hash algorithm numbers (RFC 8976 section 5).
+const (
+	SchemeSimple = 1
+	HashSHA384   = 1
+	HashSHA512   = 2
+)
+
+// ErrNotFound is returned by Verify when the zone has no ZONEMD record at
+// the apex.
+var ErrNotFound = errors.New("no ZONEMD record at the zone apex")
+
+// HashByName returns the hash algorithm number for "sha384" or "sha512".
+func HashByName(name string) (uint8, error) {
+	switch strings.ToLower(name) {
+	case "sha384":
+		return HashSHA384, nil
+	case "sha512":
+		return HashSHA512, nil
+	}
+	return 0, fmt.Errorf("unsupported ZONEMD hash algorithm %q (expected sha384 or sha512)", name)
+}
+
+// Digest returns the hex digest of the zone using the SIMPLE scheme. rrs are
+// all the records of the zone origin. An apex ZONEMD RRset, and the RRSIGs
+// covering it, are excluded as required by RFC 8976 section 3.3.1.
+func Digest(rrs []dnsv1.RR, origin string, hashAlg uint8) (string, error) {
+	var h hash.Hash
+	switch hashAlg {
+	case HashSHA384:
+		h = sha512.New384()
+	case HashSHA512:
+		h = sha512.New()
+	default:
+		return "", fmt.Errorf("unsupported ZONEMD hash algorithm %d", hashAlg)
+	}
+
+	apex := dnsv1.CanonicalName(origin)
+	var recs []canonicalRR
+	for _, rr := range rrs {
+		name := dnsv1.CanonicalName(rr.Header().Name)
+		if name == apex {
+			if rr.Header().Rrtype == dnsv1.TypeZONEMD {
+				continue
+			}
+			if sig, ok := rr.(*dnsv1.RRSIG); ok && sig.TypeCovered == dnsv1.TypeZONEMD {
+				continue
+			}
+		}
+		c, err := toCanonical(rr)
+		if err != nil {
+			return "", err
+		}
+		recs = append(recs, c)
+	}
+
+	slices.SortFunc(recs, compareCanonical)
+	for i, r := range recs {
+		// Duplicate RRs are digested once (RFC 8976 section 3.3.1.1).
+		if i > 0 && bytes.Equal(r.wire, recs[i-1].wire) {
+			continue
+		}
+		h.Write(r.wire)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Verify checks the apex ZONEMD records of a zone against its content. As in
+// RFC 8976 section 4, it succeeds if any ZONEMD record whose scheme and hash
+// algorithm are supported matches the zone. It returns ErrNotFound if there
+// is no ZONEMD record at the apex.
+func Verify(rrs []dnsv1.RR, origin string) error {
+	apex := dnsv1.CanonicalName(origin)
+	var soa *dnsv1.SOA
+	var zonemds []*dnsv1.ZONEMD
+	for _, rr := range rrs {
+		if dnsv1.CanonicalName(rr.Header().Name) != apex {
+			continue
+		}
+		switch v := rr.(type) {
+		case *dnsv1.SOA:
+			soa = v
+		case *dnsv1.ZONEMD:
+			zonemds = append(zonemds, v)
+		}
+	}
+	if len(zonemds) == 0 {
+		return ErrNotFound
+	}
+	if soa == nil {
+		return errors.New("zone has no SOA record")
+	}
+
+	var errs []error
+	for _, z := range zonemds {
+		if z.Scheme != SchemeSimple || (z.Hash != HashSHA384 && z.Hash != HashSHA512) {
+			continue
+		}
+		if z.Serial != soa.Serial {
+			errs = append(errs, fmt.Errorf("ZONEMD serial %d does not match SOA serial %d", z.Serial, soa.Serial))
+			continue
+		}
+		digest, err := Digest(rrs, origin, z.Hash)
+		if err != nil {
+			return err
+		}
+		if strings.EqualFold(digest, z.Digest) {
+			return nil
+		}
+		errs = append(errs, fmt.Errorf("ZONEMD digest (hash algorithm %d) does not match the zone", z.Hash))
+	}
+	if len(errs) == 0 {
+		return errors.New("no ZONEMD record with a supported scheme and hash algorithm")
+	}
+	return errors.Join(errs...)
+}
+
+// canonicalRR is a record in the canonical form of RFC 4034 section 6.2.
+type canonicalRR struct {
+	labels [][]byte // Owner name labels, lowercased, from the root down.
+	rrtype uint16
+	wire   []byte // The whole record.
+	rdata  []byte // The RDATA part of wire.
+}
+
+func toCanonical(rr dnsv1.RR) (canonicalRR, error) {
+	rr = dnsv1.Copy(rr)
+	hdr := rr.Header()
+	hdr.Name = dnsv1.CanonicalName(hdr.Name)
+	lowercaseRdataNames(rr)
+
+	buf := make([]byte, dnsv1.Len(rr)+1)
+	off, err := dnsv1.PackRR(rr, buf, 0, nil, false)
+	if err != nil {
+		return canonicalRR{}, fmt.Errorf("packing %s: %w", rr.String(), err)
+	}
+	wire := buf[:off]
+
+	nameBuf := make([]byte, 256)
+	nameLen, err := dnsv1.PackDomainName(hdr.Name, nameBuf, 0, nil, false)
+	if err != nil {
+		return canonicalRR{}, err
+	}
+
+	// The RDATA follows the owner name and 10 bytes of type, class, TTL and
+	// RDLENGTH.
+	return canonicalRR{
+		labels: reversedLabels(nameBuf[:nameLen]),
+		rrtype: hdr.Rrtype,
+		wire:   wire,
+		rdata:  wire[nameLen+10:],
+	}, nil
+}
+
+// lowercaseRdataNames lowercases the domain names in the RDATA of the types
+// listed in RFC 4034 section 6.2, as amended by RFC 6840 section 5.1.
+func lowercaseRdataNames(rr dnsv1.RR) {
+	lc := dnsv1.CanonicalName
+	switch v := rr.(type) {
+	case *dnsv1.NS:
+		v.Ns = lc(v.Ns)
+	case *dnsv1.MD:
+		v.Md = lc(v.Md)
+	case *dnsv1.MF:
+		v.Mf = lc(v.Mf)
+	case *dnsv1.CNAME:
+		v.Target = lc(v.Target)
+	case *dnsv1.SOA:
+		v.Ns, v.Mbox = lc(v.Ns), lc(v.Mbox)
+	case *dnsv1.MB:
+		v.Mb = lc(v.Mb)
+	case *dnsv1.MG:
+		v.Mg = lc(v.Mg)
+	case *dnsv1.MR:
+		v.Mr = lc(v.Mr)
+	case *dnsv1.PTR:
+		v.Ptr = lc(v.Ptr)
+	case *dnsv1.MINFO:
+		v.Rmail, v.Email = lc(v.Rmail), lc(v.Email)
+	case *dnsv1.MX:
+		v.Mx = lc(v.Mx)
+	case *dnsv1.RP:
+		v.Mbox, v.Txt = lc(v.Mbox), lc(v.Txt)
+	case *dnsv1.AFSDB:
+		v.Hostname = lc(v.Hostname)
+	case *dnsv1.RT:
+		v.Host = lc(v.Host)
+	case *dnsv1.SIG:
+		v.SignerName = lc(v.SignerName)
+	case *dnsv1.PX:
+		v.Map822, v.Mapx400 = lc(v.Map822), lc(v.Mapx400)
+	case *dnsv1.NAPTR:
+		v.Replacement = lc(v.Replacement)
+	case *dnsv1.KX:
+		v.Exchanger = lc(v.Exchanger)
+	case *dnsv1.SRV:
+		v.Target = lc(v.Target)
+	case *dnsv1.DNAME:
+		v.Target = lc(v.Target)
+	case *dnsv1.RRSIG:
+		v.SignerName = lc(v.SignerName)
+	}
+}
+
+// reversedLabels splits a name in wire format into its labels, from the root
+// down, which is the order in which canonical ordering compares them.
+func reversedLabels(wire []byte) [][]byte {
+	var labels [][]byte
+	for i := 0; i < len(wire) && wire[i] != 0; i += int(wire[i]) + 1 {
+		labels = append(labels, wire[i+1:i+1+int(wire[i])])
+	}
+	slices.Reverse(labels)
+	return labels
+}
+
+// compareCanonical orders records by owner name (RFC 4034 section 6.1), then
+// type, then RDATA (RFC 4034 section 6.3).
+func compareCanonical(a, b canonicalRR) int {
+	for i := 0; i < len(a.labels) && i < len(b.labels); i++ {
+		if c := bytes.Compare(a.labels[i], b.labels[i]); c != 0 {
+			return c
+		}
+	}
+	if c := len(a.labels) - len(b.labels); c != 0 {
+		return c
+	}
+	if a.rrtype != b.rrtype {
+		return int(a.rrtype) - int(b.rrtype)
+	}
+	return bytes.Compare(a.rdata, b.rdata)
+}
@@ -0,0 +1,82 @@
+package zonemd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	dnsv1 "github.com/miekg/dns"
+)
+
+// The example zone of RFC 8976 appendix A.1.
+const simpleZone = `
+example.      86400  IN  SOA     ns1 admin 2018031900 (
+                                 1800 900 604800 86400 )
+              86400  IN  NS      ns1
+              86400  IN  NS      ns2
+              86400  IN  ZONEMD  2018031900 1 1 (
+                                 c68090d90a7aed716bc459f9340e3d7c
+                                 1370d4d24b7e2fc3a1ddc0b9a87153b9
+                                 a9713b3c9ae5cc27777f98b8e730044c )
+ns1           3600   IN  A       203.0.113.63
+ns2           3600   IN  AAAA    2001:db8::63
+`
+
+func parse(t *testing.T, zone string) []dnsv1.RR {
+	t.Helper()
+	zp := dnsv1.NewZoneParser(strings.NewReader(zone), "example.", "")
+	var rrs []dnsv1.RR
+	for rr, ok := zp.Next(); ok; rr, ok = zp.Next() {
+		rrs = append(rrs, rr)
+	}
+	if err := zp.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return rrs
+}
+
+func TestDigest(t *testing.T) {
+	rrs := parse(t, simpleZone)
+	got, err := Digest(rrs, "example.", HashSHA384)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "c68090d90a7aed716bc459f9340e3d7c1370d4d24b7e2fc3a1ddc0b9a87153b9a9713b3c9ae5cc27777f98b8e730044c"
+	if got != want {
+		t.Errorf("Digest() = %s, want %s", got, want)
+	}
+
+	// Order, case and duplicates do not change the digest.
+	reordered := parse(t, strings.NewReplacer("ns1           3600", "NS1           3600").Replace(simpleZone))
+	reordered = append(reordered, reordered[0])
+	reordered[0], reordered[len(reordered)-2] = reordered[len(reordered)-2], reordered[0]
+	if got, _ := Digest(reordered, "EXAMPLE.", HashSHA384); got != want {
+		t.Errorf("Digest(reordered) = %s, want %s", got, want)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	if err := Verify(parse(t, simpleZone), "example."); err != nil {
+		t.Errorf("Verify() = %v", err)
+	}
+
+	changed := parse(t, strings.Replace(simpleZone, "203.0.113.63", "203.0.113.64", 1))
+	if err := Verify(changed, "example."); err == nil {
+		t.Error("Verify(changed zone) succeeded")
+	}
+
+	bumped := parse(t, strings.Replace(simpleZone, "admin 2018031900", "admin 2018031901", 1))
+	if err := Verify(bumped, "example."); err == nil || !strings.Contains(err.Error(), "serial") {
+		t.Errorf("Verify(new serial) = %v", err)
+	}
+
+	var none []dnsv1.RR
+	for _, rr := range parse(t, simpleZone) {
+		if rr.Header().Rrtype != dnsv1.TypeZONEMD {
+			none = append(none, rr)
+		}
+	}
+	if err := Verify(none, "example."); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Verify(no ZONEMD) = %v, want ErrNotFound", err)
+	}
+}
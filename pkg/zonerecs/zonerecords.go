@@ -2,17 +2,28 @@ package zonerecs
 
 import (
 	"github.com/DNSControl/dnscontrol/v4/models"
+	"github.com/DNSControl/dnscontrol/v4/pkg/normalize"
 	"github.com/DNSControl/dnscontrol/v4/pkg/rtypecontrol"
 )
 
 // CorrectZoneRecords calls both GetZoneRecords, does any
 // post-processing, and then calls GetZoneRecordsCorrections.  The
 // name sucks because all the good names were taken.
-func CorrectZoneRecords(driver models.DNSProvider, dc *models.DomainConfig) ([]*models.Correction, []*models.Correction, int, error) {
+//
+// providerType is used to apply the provider's TTL policy. The TTLs it
+// adjusts are listed in the reports.
+func CorrectZoneRecords(driver models.DNSProvider, dc *models.DomainConfig, providerType string) ([]*models.Correction, []*models.Correction, int, error) {
 	existingRecords, err := driver.GetZoneRecords(dc)
 	if err != nil {
 		return nil, nil, 0, err
 	}
+	return CorrectZoneRecordsFrom(driver, dc, providerType, existingRecords)
+}
+
+// CorrectZoneRecordsFrom is like CorrectZoneRecords, but takes the records
+// that GetZoneRecords returned earlier, for example from a cache.
+func CorrectZoneRecordsFrom(driver models.DNSProvider, dc *models.DomainConfig, providerType string, existingRecords models.Records) ([]*models.Correction, []*models.Correction, int, error) {
+	var err error
 	rtypecontrol.FixLegacyRecords(&existingRecords) // Call this after GetZoneRecords() to fix providers that haven't been updated for RecordConfigV2.
 
 	// downcase
@@ -37,8 +48,14 @@ func CorrectZoneRecords(driver models.DNSProvider, dc *models.DomainConfig) ([]*
 	// FIXME(tlim) It is a waste to PunyCode every iteration.
 	// This should be moved to where the JavaScript is processed.
 
+	var ttlReports []*models.Correction
+	for _, msg := range normalize.ApplyTTLPolicy(dc, providerType) {
+		ttlReports = append(ttlReports, &models.Correction{Msg: msg})
+	}
+
 	everything, actualChangeCount, err := driver.GetZoneRecordsCorrections(dc, existingRecords)
 	reports, corrections := splitReportsAndCorrections(everything)
+	reports = append(ttlReports, reports...)
 	return reports, corrections, actualChangeCount, err
 }
 
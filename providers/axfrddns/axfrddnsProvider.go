@@ -7,7 +7,10 @@ axfrddns -
   push Dynamic DNS updates (RFC2136) to the same server.
 
   Both the AXFR request and the updates might be authentificated with
-  a TSIG.
+  a TSIG. The updates may alternatively be signed with SIG(0) (RFC2931).
+
+  Optionally, updates are guarded by prerequisites (RFC2136 section 2.4) and
+  zones are cached locally so that they can be read with IXFR (RFC1995).
 
 */
 
@@ -18,6 +21,7 @@ import (
 	"errors"
 	"fmt"
 	"net" // Verified not used for net.IP
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -43,24 +47,32 @@ var features = providers.DocumentationNotes{
 	providers.CanAutoDNSSEC:          providers.Can("Just warn when DNSSEC is requested but no RRSIG is found in the AXFR or warn when DNSSEC is not requested but RRSIG are found in the AXFR."),
 	providers.CanConcur:              providers.Can(),
 	providers.CanUseCAA:              providers.Can(),
+	providers.CanUseCDNSKEY:          providers.Can(),
+	providers.CanUseCDS:              providers.Can(),
+	providers.CanUseCERT:             providers.Can(),
 	providers.CanUseDHCID:            providers.Can(),
 	providers.CanUseDNAME:            providers.Can(),
 	providers.CanUseDS:               providers.Can(),
+	providers.CanUseHINFO:            providers.Can(),
 	providers.CanUseHTTPS:            providers.Can(),
+	providers.CanUseIPSECKEY:         providers.Can(),
 	providers.CanUseLOC:              providers.Can(),
 	providers.CanUseNAPTR:            providers.Can(),
 	providers.CanUseOPENPGPKEY:       providers.Can(),
 	providers.CanUsePTR:              providers.Can(),
+	providers.CanUseRAW:              providers.Can(),
 	providers.CanUseSMIMEA:           providers.Can(),
 	providers.CanUseSRV:              providers.Can(),
 	providers.CanUseSSHFP:            providers.Can(),
 	providers.CanUseSVCB:             providers.Can(),
 	providers.CanUseTLSA:             providers.Can(),
+	providers.CanUseURI:              providers.Can(),
+	providers.CanUseZONEMD:           providers.Can(),
 	providers.DocDualHost:            providers.Cannot(),
 	providers.DocOfficiallySupported: providers.Cannot(),
-	// Possible to support via catalog zones (RFC 9432), but those are not
-	// directly supported by DNSControl right now (although nothing is stopping
-	// you from manually updating a catalog zone using DNSControl if you wish).
+	// Possible to support via catalog zones (RFC 9432). DNSControl can
+	// maintain a catalog zone (see "catalog" in creds.json) but does not
+	// read one to list zones.
 	providers.CanGetZones:      providers.Cannot(),
 	providers.DocCreateDomains: providers.Cannot(),
 	// Not a valid RR type, so impossible to encode in an RFC-compliant DNS
@@ -81,6 +93,11 @@ type axfrddnsProvider struct {
 	nameservers    []*models.Nameserver
 	transferKey    *Key
 	updateKey      *Key
+	updateSig0     *sig0Key
+	prerequisites  bool       // Guard each RRset change with a prerequisite.
+	maxUpdateSize  int        // Maximum size of a single UPDATE message.
+	ixfrCache      *ixfrCache // nil unless IXFR is enabled.
+	catalog        string     // Catalog zone (RFC 9432) listing the zones, if any.
 
 	mu               sync.Mutex // protects hasDnssecRecords during concurrent collection.
 	hasDnssecRecords map[string]bool
@@ -159,6 +176,31 @@ func initAxfrDdns(config map[string]string, providermeta json.RawMessage) (provi
 	if err != nil {
 		return nil, err
 	}
+	api.updateSig0, err = readSig0Key(config["update-sig0-key"])
+	if err != nil {
+		return nil, err
+	}
+	if api.updateKey != nil && api.updateSig0 != nil {
+		return nil, errors.New("AXFRDDNS: `update-key` and `update-sig0-key` are mutually exclusive")
+	}
+	switch strings.ToLower(strings.TrimSpace(config["update-prerequisites"])) {
+	case "yes", "true":
+		api.prerequisites = true
+	case "", "no", "false":
+	default:
+		return nil, fmt.Errorf("AXFRDDNS: invalid `update-prerequisites` in `creds.json` (%s)", config["update-prerequisites"])
+	}
+	api.maxUpdateSize = defaultMaxUpdateSize
+	if config["update-max-size"] != "" {
+		api.maxUpdateSize, err = strconv.Atoi(config["update-max-size"])
+		if err != nil || api.maxUpdateSize < 512 || api.maxUpdateSize > 65535 {
+			return nil, fmt.Errorf("AXFRDDNS: `update-max-size` must be a number between 512 and 65535 (%s)", config["update-max-size"])
+		}
+	}
+	api.catalog = config["catalog"]
+	if config["transfer-ixfr-cache"] != "" {
+		api.ixfrCache = &ixfrCache{dir: config["transfer-ixfr-cache"]}
+	}
 	switch strings.ToLower(strings.TrimSpace(config["buggy-cname"])) {
 	case "yes", "true":
 		printer.Warnf("'buggy-cname' is deprecated as it is no longer necessary.\n")
@@ -168,12 +210,18 @@ func initAxfrDdns(config map[string]string, providermeta json.RawMessage) (provi
 		case "master",
 			"nameservers",
 			"update-key",
+			"update-sig0-key",
+			"update-prerequisites",
+			"update-max-size",
 			"transfer-key",
+			"transfer-ixfr-cache",
 			"transfer-server",
 			"update-mode",
 			"transfer-mode",
 			"buggy-cname",
+			"catalog",
 			"domain",
+			"max_concurrency",
 			"TYPE":
 			continue
 		default:
@@ -239,6 +287,12 @@ func readKey(raw string, kind string) (*Key, error) {
 	return &Key{algo: algo, id: id, secret: arr[2]}, nil
 }
 
+// CatalogZone returns the name of the catalog zone maintained by this
+// provider, or "" if none.
+func (c *axfrddnsProvider) CatalogZone() string {
+	return c.catalog
+}
+
 // GetNameservers returns the nameservers for a domain.
 func (c *axfrddnsProvider) GetNameservers(domain string) ([]*models.Nameserver, error) {
 	return c.nameservers, nil
@@ -263,6 +317,61 @@ func (c *axfrddnsProvider) getAxfrConnection() (*dnsv1.Transfer, error) {
 
 // FetchZoneRecords gets the records of a zone and returns them in dns.RR format.
 func (c *axfrddnsProvider) FetchZoneRecords(domain string) ([]dnsv1.RR, error) {
+	if c.ixfrCache != nil {
+		return c.fetchZoneRecordsIxfr(domain)
+	}
+	request := new(dnsv1.Msg)
+	request.SetAxfr(domain + ".")
+	return c.transferIn(domain, request)
+}
+
+// fetchZoneRecordsIxfr is FetchZoneRecords for when the IXFR cache is
+// enabled. It requests only the differences since the cached copy, falling
+// back to AXFR when there is no (usable) cached copy.
+func (c *axfrddnsProvider) fetchZoneRecordsIxfr(domain string) ([]dnsv1.RR, error) {
+	cached, err := c.ixfrCache.load(domain)
+	if err != nil {
+		printer.Warnf("AXFRDDNS: ignoring IXFR cache for %s: %s\n", domain, err)
+		cached = nil
+	}
+
+	var zone []dnsv1.RR
+	if cached != nil {
+		soa := cached[0].(*dnsv1.SOA)
+		request := new(dnsv1.Msg)
+		request.SetIxfr(domain+".", soa.Serial, soa.Ns, soa.Mbox)
+		resp, err := c.transferIn(domain, request)
+		if err == nil {
+			zone, err = applyIxfr(cached, resp)
+		}
+		if err != nil {
+			printer.Debugf("AXFRDDNS: IXFR of %s failed, falling back to AXFR: %s\n", domain, err)
+			zone = nil
+		}
+	}
+	if zone == nil {
+		request := new(dnsv1.Msg)
+		request.SetAxfr(domain + ".")
+		resp, err := c.transferIn(domain, request)
+		if err != nil {
+			return nil, err
+		}
+		if len(resp) >= 2 && resp[len(resp)-1].Header().Rrtype == dnsv1.TypeSOA {
+			resp = resp[:len(resp)-1]
+		}
+		zone = resp
+	}
+
+	if err := c.ixfrCache.save(domain, zone); err != nil {
+		printer.Warnf("AXFRDDNS: could not update the IXFR cache for %s: %s\n", domain, err)
+	}
+	// Return the zone as an AXFR would: with the SOA first and last.
+	return append(zone[:len(zone):len(zone)], zone[0]), nil
+}
+
+// transferIn sends a zone transfer request (AXFR or IXFR) to the transfer
+// server and returns all the RRs of the response.
+func (c *axfrddnsProvider) transferIn(domain string, request *dnsv1.Msg) ([]dnsv1.RR, error) {
 	transfer, err := c.getAxfrConnection()
 	if err != nil {
 		return nil, err
@@ -270,9 +379,6 @@ func (c *axfrddnsProvider) FetchZoneRecords(domain string) ([]dnsv1.RR, error) {
 	transfer.DialTimeout = dnsTimeout
 	transfer.ReadTimeout = dnsTimeout
 
-	request := new(dnsv1.Msg)
-	request.SetAxfr(domain + ".")
-
 	if c.transferKey != nil {
 		transfer.TsigSecret = map[string]string{c.transferKey.id: c.transferKey.secret}
 		request.SetTsig(c.transferKey.id, c.transferKey.algo, 300, time.Now().Unix())
@@ -386,22 +492,19 @@ func (c *axfrddnsProvider) BuildCorrection(dc *models.DomainConfig, msgs []strin
 		F: func() error {
 			for _, update := range updates {
 				update.Compress = true
-				client := new(dnsv1.Client)
-				client.Net = c.updateMode
-				client.Timeout = dnsTimeout
-				if c.updateKey != nil {
-					client.TsigSecret = map[string]string{c.updateKey.id: c.updateKey.secret}
-					update.SetTsig(c.updateKey.id, c.updateKey.algo, 300, time.Now().Unix())
-					if c.updateKey.algo == dnsv1.HmacMD5 {
-						client.TsigProvider = md5Provider(c.updateKey.secret)
-					}
-				}
-
-				msg, _, err := client.Exchange(update, c.master)
+				msg, err := c.sendUpdate(update)
 				if err != nil {
 					return err
 				}
-				if msg.Rcode != 0 {
+				switch msg.Rcode {
+				case dnsv1.RcodeSuccess:
+				case dnsv1.RcodeNXRrset, dnsv1.RcodeYXRrset, dnsv1.RcodeNameError, dnsv1.RcodeYXDomain:
+					if len(update.Answer) != 0 {
+						return fmt.Errorf("[Error] AXFRDDNS: a prerequisite failed (%s): the zone '%s' was modified since it was read. Run preview again",
+							dnsv1.RcodeToString[msg.Rcode], dc.Name)
+					}
+					fallthrough
+				default:
 					return fmt.Errorf("[Error] AXFRDDNS: nameserver refused to update the zone: %s (%d)",
 						dnsv1.RcodeToString[msg.Rcode],
 						msg.Rcode)
@@ -413,6 +516,45 @@ func (c *axfrddnsProvider) BuildCorrection(dc *models.DomainConfig, msgs []strin
 	}
 }
 
+// sendUpdate sends an UPDATE message to the primary master, signed with the
+// TSIG or SIG(0) key if any, and returns the response.
+func (c *axfrddnsProvider) sendUpdate(update *dnsv1.Msg) (*dnsv1.Msg, error) {
+	client := new(dnsv1.Client)
+	client.Net = c.updateMode
+	client.Timeout = dnsTimeout
+
+	if c.updateSig0 == nil {
+		if c.updateKey != nil {
+			client.TsigSecret = map[string]string{c.updateKey.id: c.updateKey.secret}
+			update.SetTsig(c.updateKey.id, c.updateKey.algo, 300, time.Now().Unix())
+			if c.updateKey.algo == dnsv1.HmacMD5 {
+				client.TsigProvider = md5Provider(c.updateKey.secret)
+			}
+		}
+		msg, _, err := client.Exchange(update, c.master)
+		return msg, err
+	}
+
+	// The SIG(0) signature covers the wire format of the message, thus the
+	// message is packed here and sent as-is.
+	buf, err := c.updateSig0.sign(update)
+	if err != nil {
+		return nil, err
+	}
+	co, err := client.Dial(c.master)
+	if err != nil {
+		return nil, err
+	}
+	defer co.Close()
+	if err := co.SetDeadline(time.Now().Add(dnsTimeout)); err != nil {
+		return nil, err
+	}
+	if _, err := co.Write(buf); err != nil {
+		return nil, err
+	}
+	return co.ReadMsg()
+}
+
 // hasNSDeletion returns true if there exist a correction that deletes or changes an NS record.
 func hasNSDeletion(changes diff2.ChangeList) bool {
 	for _, change := range changes {
@@ -462,20 +604,14 @@ func (c *axfrddnsProvider) GetZoneRecordsCorrections(dc *models.DomainConfig, fo
 	// we first insert a dummy NS record that we will remove
 	// at the end of the batched update.
 
-	var msgs []string
-	var reports []string
-	updates := []*dnsv1.Msg{}
-
-	dummyNs1, err := dnsv1.NewRR(dc.Name + ". IN NS dnscontrol.invalid.")
-	if err != nil {
-		return nil, 0, err
-	}
-	dummyNs2, err := dnsv1.NewRR(dc.Name + ". IN NS dnscontrol.invalid.")
-	if err != nil {
-		return nil, 0, err
+	var changes diff2.ChangeList
+	var actualChangeCount int
+	var err error
+	if c.prerequisites {
+		changes, actualChangeCount, err = diff2.ByRecordSet(foundRecords, dc, nil)
+	} else {
+		changes, actualChangeCount, err = diff2.ByRecord(foundRecords, dc, nil)
 	}
-
-	changes, actualChangeCount, err := diff2.ByRecord(foundRecords, dc, nil)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -483,86 +619,17 @@ func (c *axfrddnsProvider) GetZoneRecordsCorrections(dc *models.DomainConfig, fo
 		return nil, 0, nil
 	}
 
-	update := new(dnsv1.Msg)
-	update.SetUpdate(dc.Name + ".")
-
-	// A DNS server should silently ignore a DDNS update that removes
-	// the last NS record of a zone. Since modifying a record is
-	// implemented by successively a deletion of the old record and an
-	// insertion of the new one, then modifying all the NS record of a
-	// zone might will fail (even if the deletion and insertion
-	// are grouped in a single batched update).
-	//
-	// To avoid this case, we will first insert a dummy NS record,
-	// that will be removed at the end of the batched updates. This
-	// record needs to inserted only when all NS records are touched
-	// The current implementation insert this dummy record as soon as
-	// a NS record is deleted or changed.
-	hasNSDeletion := hasNSDeletion(changes)
-
-	if hasNSDeletion {
-		update.Insert([]dnsv1.RR{dummyNs1})
-	}
-
-	i := 1
-	appendFinalUpdate := true
-
-	for _, change := range changes {
-		switch change.Type {
-		case diff2.DELETE:
-			msgs = append(msgs, change.Msgs[0])
-			// It's semantically invalid for any RRs to exist alongside a
-			// CNAME RR
-			if change.Old[0].Type == "CNAME" {
-				update.RemoveName([]dnsv1.RR{change.Old[0].ToRR()})
-			} else {
-				update.Remove([]dnsv1.RR{change.Old[0].ToRR()})
-			}
-		case diff2.CREATE:
-			msgs = append(msgs, change.Msgs[0])
-			// It's semantically invalid for any RRs to exist alongside a
-			// CNAME RR
-			if change.New[0].Type == "CNAME" {
-				update.RemoveName([]dnsv1.RR{change.New[0].ToRR()})
-			}
-			update.Insert([]dnsv1.RR{change.New[0].ToRR()})
-		case diff2.CHANGE:
-			msgs = append(msgs, change.Msgs[0])
-			// It's semantically invalid for any RRs to exist alongside a
-			// CNAME RR
-			if (change.New[0].Type == "CNAME") || (change.Old[0].Type == "CNAME") {
-				update.RemoveName([]dnsv1.RR{change.Old[0].ToRR()})
-			} else {
-				update.Remove([]dnsv1.RR{change.Old[0].ToRR()})
-			}
-			update.Insert([]dnsv1.RR{change.New[0].ToRR()})
-		case diff2.REPORT:
-			reports = append(reports, change.Msgs...)
-		}
-
-		// Chunk packets that exceed 2^14 = 16 KiB.
-		// A single DNS RR can theoretically reach 64 KiB, the total packet limit.
-		// This is a compromise, succeeding whenever RRs are not bigger than about 64 KiB - 16 KiB = 48 KiB.
-		if update.Len() >= 2<<13 {
-			updates = append(updates, update)
-			update = new(dnsv1.Msg)
-			update.SetUpdate(dc.Name + ".")
-			appendFinalUpdate = false
-			i = 1
-		} else {
-			appendFinalUpdate = true
-			i++
+	var frags []fragment
+	if c.prerequisites {
+		frags, err = rrsetFragments(dc.Name, changes)
+		if err != nil {
+			return nil, 0, err
 		}
+	} else {
+		frags = recordFragments(dc.Name, changes)
 	}
-
-	if hasNSDeletion {
-		update.Remove([]dnsv1.RR{dummyNs2})
-		appendFinalUpdate = true
-	}
-
-	if appendFinalUpdate {
-		updates = append(updates, update)
-	}
+	updates := batchUpdates(dc.Name+".", frags, c.maxUpdateSize)
+	msgs, reports := changeMessages(changes)
 
 	returnValue := []*models.Correction{}
 
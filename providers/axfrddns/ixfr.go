@@ -0,0 +1,167 @@
+package axfrddns
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	dnsv1 "github.com/miekg/dns"
+)
+
+// ixfrCache keeps a copy of each zone on disk so that the next read can
+// request only the changes since then with an IXFR (RFC 1995).
+type ixfrCache struct {
+	dir string
+}
+
+func (c *ixfrCache) filename(domain string) string {
+	return filepath.Join(c.dir, strings.ToLower(domain)+".zone")
+}
+
+// load returns the cached copy of a zone, SOA first, or nil if there is none.
+func (c *ixfrCache) load(domain string) ([]dnsv1.RR, error) {
+	fname := c.filename(domain)
+	f, err := os.Open(fname)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var rrs []dnsv1.RR
+	zp := dnsv1.NewZoneParser(f, domain+".", fname)
+	for rr, ok := zp.Next(); ok; rr, ok = zp.Next() {
+		rrs = append(rrs, rr)
+	}
+	if err := zp.Err(); err != nil {
+		return nil, fmt.Errorf("reading IXFR cache: %w", err)
+	}
+	if len(rrs) == 0 || rrs[0].Header().Rrtype != dnsv1.TypeSOA {
+		// Unusable; the caller will fall back to AXFR.
+		return nil, nil
+	}
+	return rrs, nil
+}
+
+// save stores a copy of a zone. rrs must start with the SOA.
+func (c *ixfrCache) save(domain string, rrs []dnsv1.RR) error {
+	if err := os.MkdirAll(c.dir, 0o750); err != nil {
+		return err
+	}
+	fname := c.filename(domain)
+	tmp := fname + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	for _, rr := range rrs {
+		fmt.Fprintln(w, rr.String())
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, fname)
+}
+
+// rrKey identifies an RR irrespective of its TTL, which is how IXFR
+// deletions are matched.
+func rrKey(rr dnsv1.RR) string {
+	h := *rr.Header()
+	rdata := strings.TrimPrefix(rr.String(), h.String())
+	return strings.ToLower(h.Name) + " " + dnsv1.TypeToString[h.Rrtype] + " " + rdata
+}
+
+// applyIxfr returns the zone (SOA first, without the trailing SOA) that
+// results from applying an IXFR response to the cached copy of the zone.
+// The response may be a single SOA (nothing changed), a full zone transfer
+// (the server may always fall back to one) or a list of differences
+// (RFC 1995 section 4).
+func applyIxfr(cached []dnsv1.RR, resp []dnsv1.RR) ([]dnsv1.RR, error) {
+	if len(resp) == 0 {
+		return nil, errors.New("empty IXFR response")
+	}
+	newSOA, ok := resp[0].(*dnsv1.SOA)
+	if !ok {
+		return nil, errors.New("IXFR response does not start with a SOA")
+	}
+	if len(cached) == 0 {
+		return nil, errors.New("IXFR without a cached copy of the zone")
+	}
+	cachedSOA := cached[0].(*dnsv1.SOA)
+
+	// Up to date.
+	if len(resp) == 1 {
+		if newSOA.Serial != cachedSOA.Serial {
+			return nil, fmt.Errorf("IXFR: server has serial %d, but the cache has %d", newSOA.Serial, cachedSOA.Serial)
+		}
+		return cached, nil
+	}
+
+	// Full zone transfer: SOA, records, SOA.
+	second, isSOA := resp[1].(*dnsv1.SOA)
+	if !isSOA || (len(resp) == 2 && second.Serial == newSOA.Serial) {
+		if last, ok := resp[len(resp)-1].(*dnsv1.SOA); !ok || last.Serial != newSOA.Serial {
+			return nil, errors.New("truncated zone transfer")
+		}
+		return resp[:len(resp)-1], nil
+	}
+
+	// Incremental: a sequence of (old SOA, deleted RRs, new SOA, added RRs),
+	// followed by the final SOA.
+	if second.Serial != cachedSOA.Serial {
+		return nil, fmt.Errorf("IXFR: differences start at serial %d, but the cache has %d", second.Serial, cachedSOA.Serial)
+	}
+	var order []string
+	zone := map[string]dnsv1.RR{}
+	for _, rr := range cached[1:] {
+		k := rrKey(rr)
+		if _, ok := zone[k]; !ok {
+			order = append(order, k)
+		}
+		zone[k] = rr
+	}
+
+	adding := false
+	soaCount := 0
+	body := resp[1 : len(resp)-1]
+	for _, rr := range body {
+		if rr.Header().Rrtype == dnsv1.TypeSOA {
+			// Every difference sequence has two SOAs: the old and the new.
+			soaCount++
+			adding = soaCount%2 == 0
+			continue
+		}
+		k := rrKey(rr)
+		if adding {
+			if _, ok := zone[k]; !ok {
+				order = append(order, k)
+			}
+			zone[k] = rr
+		} else {
+			delete(zone, k)
+		}
+	}
+	if last, ok := resp[len(resp)-1].(*dnsv1.SOA); !ok || last.Serial != newSOA.Serial || soaCount%2 != 0 {
+		return nil, errors.New("truncated IXFR response")
+	}
+
+	result := []dnsv1.RR{newSOA}
+	for _, k := range order {
+		if rr, ok := zone[k]; ok {
+			result = append(result, rr)
+			// Guard against a key being re-added after a deletion.
+			delete(zone, k)
+		}
+	}
+	return result, nil
+}
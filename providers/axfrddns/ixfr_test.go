@@ -0,0 +1,134 @@
+package axfrddns
+
+import (
+	"slices"
+	"testing"
+
+	dnsv1 "github.com/miekg/dns"
+)
+
+func mustRRs(t *testing.T, lines ...string) []dnsv1.RR {
+	t.Helper()
+	var rrs []dnsv1.RR
+	for _, l := range lines {
+		rr, err := dnsv1.NewRR(l)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rrs = append(rrs, rr)
+	}
+	return rrs
+}
+
+func rrStrings(rrs []dnsv1.RR) []string {
+	var s []string
+	for _, rr := range rrs {
+		s = append(s, rr.String())
+	}
+	return s
+}
+
+const (
+	soa1 = "example.com. 3600 IN SOA ns.example.com. root.example.com. 1 3600 600 86400 300"
+	soa2 = "example.com. 3600 IN SOA ns.example.com. root.example.com. 2 3600 600 86400 300"
+	soa3 = "example.com. 3600 IN SOA ns.example.com. root.example.com. 3 3600 600 86400 300"
+)
+
+func TestApplyIxfr(t *testing.T) {
+	cached := []string{
+		soa1,
+		"www.example.com. 300 IN A 1.2.3.4",
+		"mail.example.com. 300 IN A 1.2.3.5",
+	}
+
+	tests := []struct {
+		name    string
+		resp    []string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "up to date",
+			resp: []string{soa1},
+			want: cached,
+		},
+		{
+			name: "full transfer",
+			resp: []string{soa2, "www.example.com. 300 IN A 10.0.0.1", soa2},
+			want: []string{soa2, "www.example.com. 300 IN A 10.0.0.1"},
+		},
+		{
+			name: "empty full transfer",
+			resp: []string{soa2, soa2},
+			want: []string{soa2},
+		},
+		{
+			name: "incremental",
+			resp: []string{
+				soa3,
+				soa1,
+				"WWW.example.com. 60 IN A 1.2.3.4", // TTL and case are ignored for deletions.
+				soa2,
+				"www.example.com. 300 IN A 10.0.0.1",
+				soa2,
+				"mail.example.com. 300 IN A 1.2.3.5",
+				soa3,
+				"new.example.com. 300 IN TXT \"hello\"",
+				soa3,
+			},
+			want: []string{
+				soa3,
+				"www.example.com. 300 IN A 10.0.0.1",
+				"new.example.com. 300 IN TXT \"hello\"",
+			},
+		},
+		{
+			name:    "wrong starting serial",
+			resp:    []string{soa3, soa2, soa3, soa3},
+			wantErr: true,
+		},
+		{
+			name:    "truncated",
+			resp:    []string{soa3, soa1, "www.example.com. 300 IN A 1.2.3.4", soa3},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := applyIxfr(mustRRs(t, cached...), mustRRs(t, tt.resp...))
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected an error, got %v", rrStrings(got))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := rrStrings(mustRRs(t, tt.want...))
+			if g := rrStrings(got); !slices.Equal(g, want) {
+				t.Errorf("got\n%v\nwant\n%v", g, want)
+			}
+		})
+	}
+}
+
+func TestIxfrCache(t *testing.T) {
+	c := &ixfrCache{dir: t.TempDir()}
+
+	if got, err := c.load("example.com"); err != nil || got != nil {
+		t.Fatalf("empty cache: got %v, %v", got, err)
+	}
+
+	zone := mustRRs(t, soa1, "www.example.com. 300 IN A 1.2.3.4", "txt.example.com. 300 IN TXT \"a b\" \"c\"")
+	if err := c.save("example.com", zone); err != nil {
+		t.Fatal(err)
+	}
+	got, err := c.load("example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g, w := rrStrings(got), rrStrings(zone); !slices.Equal(g, w) {
+		t.Errorf("got %v, want %v", g, w)
+	}
+}
@@ -0,0 +1,82 @@
+package axfrddns
+
+import (
+	"crypto"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	dnsv1 "github.com/miekg/dns"
+)
+
+// sig0Key is a private key used to sign DDNS updates with SIG(0) (RFC 2931).
+type sig0Key struct {
+	key    *dnsv1.KEY
+	signer crypto.Signer
+}
+
+// readSig0Key reads a key pair generated by `dnssec-keygen -T KEY` (or
+// `keymgr`). The argument is the common prefix of the two files, for example
+// "Kexample.com.+013+12345". A trailing ".key" or ".private" is ignored.
+func readSig0Key(prefix string) (*sig0Key, error) {
+	if prefix == "" {
+		return nil, nil
+	}
+	prefix = strings.TrimSuffix(strings.TrimSuffix(prefix, ".key"), ".private")
+
+	pubName := prefix + ".key"
+	pub, err := os.Open(pubName)
+	if err != nil {
+		return nil, fmt.Errorf("update-sig0-key: %w", err)
+	}
+	defer pub.Close()
+	rr, err := dnsv1.ReadRR(pub, pubName)
+	if err != nil {
+		return nil, fmt.Errorf("update-sig0-key: parsing %q: %w", pubName, err)
+	}
+	var key *dnsv1.KEY
+	switch v := rr.(type) {
+	case *dnsv1.KEY:
+		key = v
+	case *dnsv1.DNSKEY:
+		// The key material is the same; only the published type differs.
+		key = &dnsv1.KEY{DNSKEY: *v}
+		key.Hdr.Rrtype = dnsv1.TypeKEY
+	default:
+		return nil, fmt.Errorf("update-sig0-key: %q does not contain a KEY record", pubName)
+	}
+
+	privName := prefix + ".private"
+	priv, err := os.Open(privName)
+	if err != nil {
+		return nil, fmt.Errorf("update-sig0-key: %w", err)
+	}
+	defer priv.Close()
+	pk, err := key.ReadPrivateKey(priv, privName)
+	if err != nil {
+		return nil, fmt.Errorf("update-sig0-key: parsing %q: %w", privName, err)
+	}
+	signer, ok := pk.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("update-sig0-key: private key can not be used for signing")
+	}
+
+	return &sig0Key{key: key, signer: signer}, nil
+}
+
+// sign returns the wire format of m with a SIG(0) record appended. The
+// message must not be modified (or re-packed) afterwards, as that would
+// invalidate the signature.
+func (k *sig0Key) sign(m *dnsv1.Msg) ([]byte, error) {
+	now := time.Now().Unix()
+	sig := &dnsv1.SIG{}
+	sig.Algorithm = k.key.Algorithm
+	sig.SignerName = k.key.Hdr.Name
+	sig.KeyTag = k.key.KeyTag()
+	// Allow for some clock skew between us and the server.
+	sig.Inception = uint32(now - 300)
+	sig.Expiration = uint32(now + 300)
+	return sig.Sign(k.signer, m)
+}
@@ -0,0 +1,237 @@
+package axfrddns
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/DNSControl/dnscontrol/v4/pkg/diff2"
+	dnsv1 "github.com/miekg/dns"
+)
+
+// defaultMaxUpdateSize is the default limit on the size of a single UPDATE
+// message. A single DNS RR can theoretically reach 64 KiB, the total packet
+// limit. This is a compromise, succeeding whenever RRs are not bigger than
+// about 64 KiB - 16 KiB = 48 KiB.
+const defaultMaxUpdateSize = 2 << 13
+
+// fragment is a part of an UPDATE message that must not be split across
+// messages: a prerequisite and the updates it protects, or a deletion and
+// the insertion that replaces it.
+type fragment struct {
+	prereq []dnsv1.RR // Prerequisite section (RFC 2136 section 2.4).
+	update []dnsv1.RR // Update section (RFC 2136 section 2.5).
+}
+
+// newUpdate returns an empty UPDATE message for zone.
+func newUpdate(zone string) *dnsv1.Msg {
+	m := new(dnsv1.Msg)
+	m.SetUpdate(zone)
+	return m
+}
+
+// rrLen returns the uncompressed wire length of rrs.
+func rrLen(rrs []dnsv1.RR) int {
+	l := 0
+	for _, rr := range rrs {
+		l += dnsv1.Len(rr)
+	}
+	return l
+}
+
+// batchUpdates packs fragments, in order, into as few UPDATE messages as
+// possible without any message exceeding maxSize bytes. A fragment is never
+// split: a fragment larger than maxSize is sent in a message of its own.
+func batchUpdates(zone string, frags []fragment, maxSize int) []*dnsv1.Msg {
+	var updates []*dnsv1.Msg
+	base := newUpdate(zone).Len()
+
+	cur := newUpdate(zone)
+	size := base
+	for _, f := range frags {
+		l := rrLen(f.prereq) + rrLen(f.update)
+		if size > base && size+l > maxSize {
+			updates = append(updates, cur)
+			cur = newUpdate(zone)
+			size = base
+		}
+		cur.Answer = append(cur.Answer, f.prereq...)
+		cur.Ns = append(cur.Ns, f.update...)
+		size += l
+	}
+	if size > base || len(updates) == 0 {
+		updates = append(updates, cur)
+	}
+	return updates
+}
+
+// The helpers below build RRs the way the methods of dnsv1.Msg (Insert,
+// Remove, Used, ...) do, but return them instead of appending them to a
+// message, so that they can be grouped into fragments.
+
+// rrInsert returns rr as an "Add to an RRset" update (section 2.5.1).
+func rrInsert(rr dnsv1.RR) dnsv1.RR {
+	rr.Header().Class = dnsv1.ClassINET
+	return rr
+}
+
+// rrRemove returns rr as a "Delete an RR from an RRset" update (section 2.5.4).
+func rrRemove(rr dnsv1.RR) dnsv1.RR {
+	rr.Header().Ttl = 0
+	rr.Header().Class = dnsv1.ClassNONE
+	return rr
+}
+
+// rrRemoveName returns a "Delete all RRsets from a name" update (section 2.5.3).
+func rrRemoveName(rr dnsv1.RR) dnsv1.RR {
+	return &dnsv1.ANY{Hdr: dnsv1.RR_Header{Name: rr.Header().Name, Ttl: 0, Rrtype: dnsv1.TypeANY, Class: dnsv1.ClassANY}}
+}
+
+// rrUsed returns rr as an "RRset exists (value dependent)" prerequisite
+// (section 2.4.2).
+func rrUsed(rr dnsv1.RR) dnsv1.RR {
+	rr.Header().Ttl = 0
+	rr.Header().Class = dnsv1.ClassINET
+	return rr
+}
+
+// rrsetNotUsed returns an "RRset does not exist" prerequisite (section 2.4.3).
+func rrsetNotUsed(name string, rrtype uint16) dnsv1.RR {
+	return &dnsv1.ANY{Hdr: dnsv1.RR_Header{Name: name, Ttl: 0, Rrtype: rrtype, Class: dnsv1.ClassNONE}}
+}
+
+// rrsetFragments returns the fragments for changes generated by
+// diff2.ByRecordSet. Each RRset change is guarded by a prerequisite stating
+// what the RRset looked like when the zone was read, so that a concurrent
+// modification makes the server reject the whole UPDATE message instead of
+// silently overwriting it.
+//
+// Deletions are ordered first, as a server ignores a CNAME added next to
+// other data (and vice-versa).
+func rrsetFragments(domain string, changes diff2.ChangeList) ([]fragment, error) {
+	zone := domain + "."
+	var deletes, others []fragment
+	for _, change := range changes {
+		var f fragment
+		switch change.Type {
+		case diff2.REPORT:
+			continue
+		case diff2.CREATE:
+			rr := change.New[0].ToRR()
+			f.prereq = append(f.prereq, rrsetNotUsed(rr.Header().Name, rr.Header().Rrtype))
+		case diff2.CHANGE, diff2.DELETE:
+			for _, rc := range change.Old {
+				f.prereq = append(f.prereq, rrUsed(rc.ToRR()))
+			}
+		default:
+			return nil, fmt.Errorf("unexpected change type %v", change.Type)
+		}
+
+		// The RRset is replaced RR by RR: a "Delete an RRset" update is
+		// ignored for NS records at the apex (RFC 2136 section 3.4.2.3).
+		// Since the prerequisite guarantees the exact content of the RRset,
+		// this is equivalent.
+		apexNS := change.Key.Type == "NS" && change.Key.NameFQDN == domain
+		if apexNS {
+			f.update = append(f.update, rrInsert(dummyNS(zone)))
+		}
+		for _, rc := range change.Old {
+			f.update = append(f.update, rrRemove(rc.ToRR()))
+		}
+		for _, rc := range change.New {
+			f.update = append(f.update, rrInsert(rc.ToRR()))
+		}
+		if apexNS {
+			f.update = append(f.update, rrRemove(dummyNS(zone)))
+		}
+
+		if change.Type == diff2.DELETE {
+			deletes = append(deletes, f)
+		} else {
+			others = append(others, f)
+		}
+	}
+	return slices.Concat(deletes, others), nil
+}
+
+// recordFragments returns the fragments for changes generated by
+// diff2.ByRecord, without prerequisites.
+func recordFragments(domain string, changes diff2.ChangeList) []fragment {
+	zone := domain + "."
+	var frags []fragment
+
+	// A DNS server should silently ignore a DDNS update that removes
+	// the last NS record of a zone. Since modifying a record is
+	// implemented by successively a deletion of the old record and an
+	// insertion of the new one, then modifying all the NS record of a
+	// zone might will fail (even if the deletion and insertion
+	// are grouped in a single batched update).
+	//
+	// To avoid this case, we will first insert a dummy NS record,
+	// that will be removed at the end of the batched updates. This
+	// record needs to inserted only when all NS records are touched
+	// The current implementation insert this dummy record as soon as
+	// a NS record is deleted or changed.
+	hasNSDeletion := hasNSDeletion(changes)
+	if hasNSDeletion {
+		frags = append(frags, fragment{update: []dnsv1.RR{rrInsert(dummyNS(zone))}})
+	}
+
+	for _, change := range changes {
+		var f fragment
+		switch change.Type {
+		case diff2.DELETE:
+			// It's semantically invalid for any RRs to exist alongside a
+			// CNAME RR
+			if change.Old[0].Type == "CNAME" {
+				f.update = append(f.update, rrRemoveName(change.Old[0].ToRR()))
+			} else {
+				f.update = append(f.update, rrRemove(change.Old[0].ToRR()))
+			}
+		case diff2.CREATE:
+			// It's semantically invalid for any RRs to exist alongside a
+			// CNAME RR
+			if change.New[0].Type == "CNAME" {
+				f.update = append(f.update, rrRemoveName(change.New[0].ToRR()))
+			}
+			f.update = append(f.update, rrInsert(change.New[0].ToRR()))
+		case diff2.CHANGE:
+			// It's semantically invalid for any RRs to exist alongside a
+			// CNAME RR
+			if (change.New[0].Type == "CNAME") || (change.Old[0].Type == "CNAME") {
+				f.update = append(f.update, rrRemoveName(change.Old[0].ToRR()))
+			} else {
+				f.update = append(f.update, rrRemove(change.Old[0].ToRR()))
+			}
+			f.update = append(f.update, rrInsert(change.New[0].ToRR()))
+		case diff2.REPORT:
+			continue
+		}
+		frags = append(frags, f)
+	}
+
+	if hasNSDeletion {
+		frags = append(frags, fragment{update: []dnsv1.RR{rrRemove(dummyNS(zone))}})
+	}
+	return frags
+}
+
+// dummyNS returns the placeholder NS record that keeps a zone from losing
+// all its NS records in the middle of an update.
+func dummyNS(zone string) dnsv1.RR {
+	return &dnsv1.NS{
+		Hdr: dnsv1.RR_Header{Name: zone, Rrtype: dnsv1.TypeNS, Class: dnsv1.ClassINET, Ttl: 300},
+		Ns:  "dnscontrol.invalid.",
+	}
+}
+
+// changeMessages returns the human-readable messages and reports of changes.
+func changeMessages(changes diff2.ChangeList) (msgs, reports []string) {
+	for _, change := range changes {
+		if change.Type == diff2.REPORT {
+			reports = append(reports, change.Msgs...)
+		} else {
+			msgs = append(msgs, change.Msgs...)
+		}
+	}
+	return msgs, reports
+}
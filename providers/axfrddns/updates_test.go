@@ -0,0 +1,60 @@
+package axfrddns
+
+import (
+	"testing"
+
+	dnsv1 "github.com/miekg/dns"
+)
+
+func TestBatchUpdates(t *testing.T) {
+	rr := func(s string) dnsv1.RR {
+		r, err := dnsv1.NewRR(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return r
+	}
+	frag := func() fragment {
+		return fragment{
+			prereq: []dnsv1.RR{rrUsed(rr("www.example.com. 300 IN A 1.2.3.4"))},
+			update: []dnsv1.RR{
+				rrRemove(rr("www.example.com. 300 IN A 1.2.3.4")),
+				rrInsert(rr("www.example.com. 300 IN A 1.2.3.5")),
+			},
+		}
+	}
+	fragLen := rrLen(frag().prereq) + rrLen(frag().update)
+	base := newUpdate("example.com.").Len()
+
+	if got := batchUpdates("example.com.", nil, 512); len(got) != 1 {
+		t.Errorf("no fragments: got %d messages, want 1", len(got))
+	}
+
+	var frags []fragment
+	for range 10 {
+		frags = append(frags, frag())
+	}
+	// Room for exactly three fragments per message.
+	got := batchUpdates("example.com.", frags, base+3*fragLen)
+	if len(got) != 4 {
+		t.Fatalf("got %d messages, want 4", len(got))
+	}
+	for i, m := range got {
+		want := 3
+		if i == 3 {
+			want = 1
+		}
+		if len(m.Answer) != want || len(m.Ns) != 2*want {
+			t.Errorf("message %d: got %d prerequisites and %d updates, want %d and %d", i, len(m.Answer), len(m.Ns), want, 2*want)
+		}
+		if m.Len() > base+3*fragLen {
+			t.Errorf("message %d: size %d exceeds the limit", i, m.Len())
+		}
+	}
+
+	// A fragment bigger than the limit is sent on its own.
+	got = batchUpdates("example.com.", frags[:2], base+1)
+	if len(got) != 2 {
+		t.Errorf("oversized fragments: got %d messages, want 2", len(got))
+	}
+}
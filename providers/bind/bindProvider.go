@@ -26,11 +26,11 @@ import (
 	"github.com/DNSControl/dnscontrol/v4/pkg/diff2"
 	"github.com/DNSControl/dnscontrol/v4/pkg/dnsrr"
 	"github.com/DNSControl/dnscontrol/v4/pkg/domaintags"
-	"github.com/DNSControl/dnscontrol/v4/pkg/prettyzone"
 	"github.com/DNSControl/dnscontrol/v4/pkg/printer"
 	"github.com/DNSControl/dnscontrol/v4/pkg/providers"
 	"github.com/DNSControl/dnscontrol/v4/pkg/rtypecontrol"
 	"github.com/DNSControl/dnscontrol/v4/pkg/rtypeinfo"
+	"github.com/DNSControl/dnscontrol/v4/pkg/zonemd"
 	dnsv1 "github.com/miekg/dns"
 )
 
@@ -45,15 +45,21 @@ var features = providers.DocumentationNotes{
 	providers.CanConcur:              providers.Can(),
 	providers.CanGetZones:            providers.Can(),
 	providers.CanUseCAA:              providers.Can(),
+	providers.CanUseCDNSKEY:          providers.Can(),
+	providers.CanUseCDS:              providers.Can(),
+	providers.CanUseCERT:             providers.Can(),
 	providers.CanUseDHCID:            providers.Can(),
 	providers.CanUseDNAME:            providers.Can(),
 	providers.CanUseDNSKEY:           providers.Can(),
 	providers.CanUseDS:               providers.Can(),
+	providers.CanUseHINFO:            providers.Can(),
 	providers.CanUseHTTPS:            providers.Can(),
+	providers.CanUseIPSECKEY:         providers.Can(),
 	providers.CanUseLOC:              providers.Can(),
 	providers.CanUseNAPTR:            providers.Can(),
 	providers.CanUseOPENPGPKEY:       providers.Can(),
 	providers.CanUsePTR:              providers.Can(),
+	providers.CanUseRAW:              providers.Can(),
 	providers.CanUseRP:               providers.Can(),
 	providers.CanUseSMIMEA:           providers.Can(),
 	providers.CanUseSOA:              providers.Can(),
@@ -61,6 +67,8 @@ var features = providers.DocumentationNotes{
 	providers.CanUseSSHFP:            providers.Can(),
 	providers.CanUseSVCB:             providers.Can(),
 	providers.CanUseTLSA:             providers.Can(),
+	providers.CanUseURI:              providers.Can(),
+	providers.CanUseZONEMD:           providers.Can(),
 	providers.DocCreateDomains:       providers.Can("Driver just maintains list of zone files. It should automatically add missing ones."),
 	providers.DocDualHost:            providers.Can(),
 	providers.DocOfficiallySupported: providers.Can(),
@@ -72,6 +80,8 @@ func initBind(config map[string]string, providermeta json.RawMessage) (providers
 	api := &bindProvider{
 		directory:      config["directory"],
 		filenameformat: config["filenameformat"],
+		catalog:        config["catalog"],
+		keydirectory:   config["keydirectory"],
 	}
 	if api.directory == "" {
 		api.directory = "zones"
@@ -79,6 +89,15 @@ func initBind(config map[string]string, providermeta json.RawMessage) (providers
 	if api.filenameformat == "" {
 		api.filenameformat = "%c.zone"
 	}
+	if api.keydirectory == "" {
+		api.keydirectory = filepath.Join(api.directory, "keys")
+	}
+	if config["zonemd"] != "" {
+		var err error
+		if api.zonemd, err = zonemd.HashByName(config["zonemd"]); err != nil {
+			return nil, err
+		}
+	}
 	if len(providermeta) != 0 {
 		err := json.Unmarshal(providermeta, api)
 		if err != nil {
@@ -130,6 +149,21 @@ func init() {
 				Help:    "Format used for zone file names. Defaults to %c.zone.",
 				Default: "%c.zone",
 			},
+			{
+				Key:   "catalog",
+				Label: "Catalog zone",
+				Help:  "Optional name of an RFC 9432 catalog zone listing all the zones of this provider.",
+			},
+			{
+				Key:   "keydirectory",
+				Label: "DNSSEC key directory",
+				Help:  "Directory where the DNSSEC keys managed by \"dnscontrol dnssec\" are stored. Defaults to keys in the zone files directory.",
+			},
+			{
+				Key:   "zonemd",
+				Label: "ZONEMD hash",
+				Help:  "Optional hash algorithm (sha384 or sha512) of an RFC 8976 ZONEMD record added to each zone.",
+			},
 		},
 		PostWrite: func(fields map[string]string) error {
 			dir := fields["directory"]
@@ -164,6 +198,9 @@ type bindProvider struct {
 	nameservers    []*models.Nameserver
 	directory      string
 	filenameformat string
+	catalog        string // Catalog zone (RFC 9432) listing the zones, if any.
+	zonemd         uint8  // Hash algorithm of the generated ZONEMD (RFC 8976), or 0.
+	keydirectory   string // Where the DNSSEC keys are stored.
 }
 
 // GetNameservers returns the nameservers for a domain.
@@ -175,6 +212,12 @@ func (c *bindProvider) GetNameservers(string) ([]*models.Nameserver, error) {
 	return models.ToNameservers(r)
 }
 
+// CatalogZone returns the name of the catalog zone maintained by this
+// provider, or "" if none.
+func (c *bindProvider) CatalogZone() string {
+	return c.catalog
+}
+
 // ListZones returns all the zones in an account.
 func (c *bindProvider) ListZones() ([]string, error) {
 	if _, err := os.Stat(c.directory); os.IsNotExist(err) {
@@ -236,7 +279,15 @@ func (c *bindProvider) GetZoneRecords(dc *models.DomainConfig) (models.Records,
 		return nil, fmt.Errorf("can't open %s: %w", zonefile, err)
 	}
 
-	return ParseZoneContents(string(content), domain, zonefile)
+	records, err := ParseZoneContents(string(content), domain, zonefile)
+	if err != nil {
+		return nil, err
+	}
+	checkZonemd(string(content), domain, zonefile)
+	if c.zonemd != 0 {
+		records = withoutApexZonemd(records)
+	}
+	return records, nil
 }
 
 // ParseZoneContents parses a string as a BIND zone and returns the records.
@@ -250,7 +301,7 @@ func ParseZoneContents(content string, zoneName string, zonefileName string) (mo
 		var err error
 
 		rtype := rr.Header().Rrtype
-		rtypeStr := dnsv1.TypeToString[rtype]
+		rtypeStr := dnsv1.Type(rtype).String() // Unknown types are "TYPEnnn" (RFC 3597).
 		if rtypeinfo.IsModernType(rtypeStr) {
 			// Modern types:
 			name := rr.Header().Name
@@ -281,6 +332,16 @@ func (c *bindProvider) EnsureZoneExists(_ string, _ map[string]string) error {
 	return nil
 }
 
+// DeleteZone removes the zonefile of a zone. named.conf is not updated.
+func (c *bindProvider) DeleteZone(domain string) error {
+	ff := domaintags.MakeDomainNameVarieties(domain)
+	zonefile := filepath.Join(c.directory, makeFileName(c.filenameformat, *ff))
+	if err := os.Remove(zonefile); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("could not delete zonefile: %w", err)
+	}
+	return nil
+}
+
 // GetZoneRecordsCorrections returns a list of corrections that will turn existing records into dc.Records.
 func (c *bindProvider) GetZoneRecordsCorrections(dc *models.DomainConfig, foundRecords models.Records) ([]*models.Correction, int, error) {
 	var corrections []*models.Correction
@@ -289,6 +350,12 @@ func (c *bindProvider) GetZoneRecordsCorrections(dc *models.DomainConfig, foundR
 	changes := false
 	var msg string
 
+	if c.zonemd != 0 {
+		if err := checkNoApexZonemd(dc); err != nil {
+			return nil, 0, err
+		}
+	}
+
 	// Find the SOA records; use them to make or update the desired SOA.
 	var foundSoa *models.RecordConfig
 	for _, r := range foundRecords {
@@ -373,7 +440,7 @@ func (c *bindProvider) GetZoneRecordsCorrections(dc *models.DomainConfig, foundR
 				// Beware that if there are any fake types, then they will
 				// be commented out on write, but we don't reverse that when
 				// reading, so there will be a diff on every invocation.
-				err = prettyzone.WriteZoneFileRC(zf, result.DesiredPlus, dc.Name, 0, comments)
+				err = c.writeZone(zf, result.DesiredPlus, dc.Name, comments)
 				if err != nil {
 					return fmt.Errorf("failed WriteZoneFile: %w", err)
 				}
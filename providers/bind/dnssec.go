@@ -0,0 +1,159 @@
+package bind
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/DNSControl/dnscontrol/v4/pkg/dnssec"
+	dnsv1 "github.com/miekg/dns"
+)
+
+// DNSSEC keys are stored in c.keydirectory in the format of dnssec-keygen:
+// Kexample.com.+013+12345.key holds the DNSKEY record and
+// Kexample.com.+013+12345.private the private key. The timing of each key
+// is recorded with the metadata that dnssec-settime uses (Publish, Activate,
+// Inactive, Delete), so that named, when it maintains the zone's keys from
+// the key directory, follows the same schedule.
+
+// timingFormat is the format of the timing metadata in key files.
+const timingFormat = "20060102150405"
+
+// keyTimingFields are the timing metadata fields, in the order they are written.
+var keyTimingFields = []string{"Publish", "Activate", "Inactive", "Delete"}
+
+// DNSSECKeys returns the keys of a zone found in the key directory.
+func (c *bindProvider) DNSSECKeys(zone string) ([]dnssec.Key, error) {
+	pattern := filepath.Join(c.keydirectory, "K"+dnsv1.Fqdn(zone)+"+*.key")
+	files, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(files)
+	var keys []dnssec.Key
+	for _, f := range files {
+		k, err := readKey(strings.TrimSuffix(f, ".key"))
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
+
+// DNSSECCreateKey generates a key and writes its files.
+func (c *bindProvider) DNSSECCreateKey(zone string, key dnssec.Key) (dnssec.Key, error) {
+	if key.Role == dnssec.CSK {
+		return key, errors.New("BIND: use a KSK and a ZSK instead of a CSK")
+	}
+	dnskey, private, err := dnssec.Generate(zone, key.Role, key.Algorithm)
+	if err != nil {
+		return key, err
+	}
+	key.SetDNSKEY(dnskey)
+	if err := os.MkdirAll(c.keydirectory, 0o750); err != nil {
+		return key, err
+	}
+	key.ID = filepath.Join(c.keydirectory, fmt.Sprintf("K%s+%03d+%05d", dnsv1.Fqdn(zone), key.Algorithm, key.KeyTag))
+
+	private += "Created: " + time.Now().UTC().Format(timingFormat) + "\n"
+	if err := os.WriteFile(key.ID+".private", []byte(private), 0o600); err != nil {
+		return key, err
+	}
+	return key, writeKeyTiming(key.ID, zone, key)
+}
+
+// DNSSECUpdateKey records the timing of a key in its files.
+func (c *bindProvider) DNSSECUpdateKey(zone string, key dnssec.Key) error {
+	return writeKeyTiming(key.ID, zone, key)
+}
+
+// readKey reads the key whose files are base.key and base.private.
+func readKey(base string) (dnssec.Key, error) {
+	k := dnssec.Key{ID: base}
+	pub, err := os.ReadFile(base + ".key")
+	if err != nil {
+		return k, err
+	}
+	zp := dnsv1.NewZoneParser(strings.NewReader(string(pub)), "", base+".key")
+	rr, _ := zp.Next()
+	if err := zp.Err(); err != nil {
+		return k, err
+	}
+	dnskey, ok := rr.(*dnsv1.DNSKEY)
+	if !ok {
+		return k, fmt.Errorf("%s.key: no DNSKEY record", base)
+	}
+	k.SetDNSKEY(dnskey)
+
+	private, err := os.ReadFile(base + ".private")
+	if err != nil {
+		return k, err
+	}
+	for line := range strings.SplitSeq(string(private), "\n") {
+		name, value, ok := strings.Cut(line, ": ")
+		if !ok || !slices.Contains(keyTimingFields, name) {
+			continue
+		}
+		t, err := time.Parse(timingFormat, strings.TrimSpace(value))
+		if err != nil {
+			return k, fmt.Errorf("%s.private: %s: %w", base, name, err)
+		}
+		*keyTime(&k, name) = t
+	}
+	return k, nil
+}
+
+// writeKeyTiming rewrites the timing metadata of the key files.
+func writeKeyTiming(base string, zone string, k dnssec.Key) error {
+	private, err := os.ReadFile(base + ".private")
+	if err != nil {
+		return err
+	}
+	var lines []string
+	for line := range strings.SplitSeq(strings.TrimSuffix(string(private), "\n"), "\n") {
+		name, _, _ := strings.Cut(line, ": ")
+		if !slices.Contains(keyTimingFields, name) {
+			lines = append(lines, line)
+		}
+	}
+
+	kind := "zone-signing"
+	if k.Role != dnssec.ZSK {
+		kind = "key-signing"
+	}
+	pub := []string{fmt.Sprintf("; This is a %s key, keyid %d, for %s", kind, k.KeyTag, dnsv1.Fqdn(zone))}
+	for _, name := range keyTimingFields {
+		t := *keyTime(&k, name)
+		if t.IsZero() {
+			continue
+		}
+		t = t.UTC()
+		lines = append(lines, name+": "+t.Format(timingFormat))
+		pub = append(pub, fmt.Sprintf("; %s: %s (%s)", name, t.Format(timingFormat), t.Format(time.ANSIC)))
+	}
+	pub = append(pub, fmt.Sprintf("%s IN DNSKEY %s", dnsv1.Fqdn(zone), k.DNSKEY))
+
+	if err := os.WriteFile(base+".private", []byte(strings.Join(lines, "\n")+"\n"), 0o600); err != nil {
+		return err
+	}
+	return os.WriteFile(base+".key", []byte(strings.Join(pub, "\n")+"\n"), 0o644)
+}
+
+// keyTime returns the field of k for a timing metadata field.
+func keyTime(k *dnssec.Key, name string) *time.Time {
+	switch name {
+	case "Publish":
+		return &k.Published
+	case "Activate":
+		return &k.Activated
+	case "Inactive":
+		return &k.Retired
+	default:
+		return &k.Removed
+	}
+}
@@ -0,0 +1,63 @@
+package bind
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DNSControl/dnscontrol/v4/pkg/dnssec"
+)
+
+func TestDNSSECKeyFiles(t *testing.T) {
+	c := &bindProvider{keydirectory: t.TempDir()}
+	t0 := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	created, err := c.DNSSECCreateKey("example.com", dnssec.Key{Role: dnssec.KSK, Algorithm: 13, Published: t0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(created.ID, fmt.Sprintf("Kexample.com.+013+%05d", created.KeyTag)) {
+		t.Errorf("ID = %q", created.ID)
+	}
+	pub, err := os.ReadFile(created.ID + ".key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(pub), "; Publish: 20261019120000") {
+		t.Errorf(".key file:\n%s", pub)
+	}
+
+	created.Activated = t0.Add(time.Hour)
+	if err := c.DNSSECUpdateKey("example.com", created); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.DNSSECCreateKey("example.com", dnssec.Key{Role: dnssec.ZSK, Algorithm: 13, Published: t0, Activated: t0}); err != nil {
+		t.Fatal(err)
+	}
+
+	keys, err := c.DNSSECKeys("example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 2 {
+		t.Fatalf("got %d keys, want 2", len(keys))
+	}
+	for _, k := range keys {
+		if k.ID != created.ID {
+			continue
+		}
+		if k.Role != dnssec.KSK || k.DNSKEY != created.DNSKEY || !k.Published.Equal(t0) || !k.Activated.Equal(t0.Add(time.Hour)) {
+			t.Errorf("read back %+v, want %+v", k, created)
+		}
+		private, _ := os.ReadFile(k.ID + ".private")
+		if !strings.Contains(string(private), "PrivateKey: ") || !strings.Contains(string(private), "Activate: 20261019130000") {
+			t.Errorf(".private file:\n%s", private)
+		}
+	}
+
+	if _, err := c.DNSSECCreateKey("example.com", dnssec.Key{Role: dnssec.CSK, Algorithm: 13}); err == nil {
+		t.Error("creating a CSK should fail")
+	}
+}
@@ -0,0 +1,110 @@
+package bind
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/DNSControl/dnscontrol/v4/models"
+	"github.com/DNSControl/dnscontrol/v4/pkg/domaintags"
+	"github.com/DNSControl/dnscontrol/v4/pkg/prettyzone"
+	"github.com/DNSControl/dnscontrol/v4/pkg/printer"
+	"github.com/DNSControl/dnscontrol/v4/pkg/rtypecontrol"
+	"github.com/DNSControl/dnscontrol/v4/pkg/zonemd"
+	dnsv1 "github.com/miekg/dns"
+)
+
+// ZONEMD (RFC 8976) support. If "zonemd" is set in creds.json, the apex
+// ZONEMD record is computed over the zone as it is written, after the serial
+// has been bumped. It is owned by the provider: it is not part of
+// dnsconfig.js and is dropped from the records read back.
+
+// writeZone writes the zonefile, adding an apex ZONEMD record if c.zonemd
+// is set.
+func (c *bindProvider) writeZone(w io.Writer, records models.Records, origin string, comments []string) error {
+	if c.zonemd == 0 {
+		return prettyzone.WriteZoneFileRC(w, records, origin, 0, comments)
+	}
+
+	// Render the zone and digest the records as they will be read back.
+	var buf bytes.Buffer
+	if err := prettyzone.WriteZoneFileRC(&buf, records, origin, 0, comments); err != nil {
+		return err
+	}
+	rrs, err := parseRRs(buf.String(), origin)
+	if err != nil {
+		return err
+	}
+	var soa *dnsv1.SOA
+	for _, rr := range rrs {
+		if s, ok := rr.(*dnsv1.SOA); ok && dnsv1.CanonicalName(s.Hdr.Name) == dnsv1.CanonicalName(origin) {
+			soa = s
+		}
+	}
+	if soa == nil {
+		return errors.New("zonemd: zone has no SOA record")
+	}
+	digest, err := zonemd.Digest(rrs, origin, c.zonemd)
+	if err != nil {
+		return err
+	}
+
+	rec, err := rtypecontrol.NewRecordConfigFromStruct("@", soa.Hdr.Ttl, "ZONEMD", &dnsv1.ZONEMD{
+		Serial: soa.Serial,
+		Scheme: zonemd.SchemeSimple,
+		Hash:   c.zonemd,
+		Digest: digest,
+	}, domaintags.MakeDomainNameVarieties(origin))
+	if err != nil {
+		return err
+	}
+	withZonemd := append(models.Records{rec}, records...)
+	return prettyzone.WriteZoneFileRC(w, withZonemd, origin, 0, comments)
+}
+
+// checkZonemd verifies the apex ZONEMD of a zonefile, if it has one, and
+// warns if it does not match the zone.
+func checkZonemd(content string, origin string, zonefile string) {
+	rrs, err := parseRRs(content, origin)
+	if err != nil {
+		return // ParseZoneContents reports the error.
+	}
+	if err := zonemd.Verify(rrs, origin); err != nil && !errors.Is(err, zonemd.ErrNotFound) {
+		printer.Warnf("ZONEMD of %s does not verify: %v\n", zonefile, err)
+	}
+}
+
+// withoutApexZonemd removes the apex ZONEMD records, which are generated
+// when the zone is written.
+func withoutApexZonemd(records models.Records) models.Records {
+	var result models.Records
+	for _, r := range records {
+		if r.Type == "ZONEMD" && r.Name == "@" {
+			continue
+		}
+		result = append(result, r)
+	}
+	return result
+}
+
+// checkNoApexZonemd returns an error if dnsconfig.js has an apex ZONEMD
+// while the provider generates it.
+func checkNoApexZonemd(dc *models.DomainConfig) error {
+	for _, r := range dc.Records {
+		if r.Type == "ZONEMD" && r.Name == "@" {
+			return fmt.Errorf("%s: ZONEMD(\"@\", ...) conflicts with the zonemd setting of the BIND provider, which generates it", dc.Name)
+		}
+	}
+	return nil
+}
+
+func parseRRs(content string, origin string) ([]dnsv1.RR, error) {
+	zp := dnsv1.NewZoneParser(strings.NewReader(content), dnsv1.Fqdn(origin), "")
+	var rrs []dnsv1.RR
+	for rr, ok := zp.Next(); ok; rr, ok = zp.Next() {
+		rrs = append(rrs, rr)
+	}
+	return rrs, zp.Err()
+}
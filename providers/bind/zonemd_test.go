@@ -0,0 +1,65 @@
+package bind
+
+import (
+	"bytes"
+	"testing"
+
+	_ "github.com/DNSControl/dnscontrol/v4/pkg/rtype"
+	"github.com/DNSControl/dnscontrol/v4/pkg/zonemd"
+)
+
+func Test_writeZoneZonemd(t *testing.T) {
+	const zone = `$TTL 300
+@ IN SOA ns1.example.com. hostmaster.example.com. 2026101900 3600 600 604800 1440
+@ IN NS ns1.example.com.
+@ IN MX 10 Mail.Example.com.
+www 3600 IN A 192.0.2.1
+www IN TXT "hello \"world\"" "two"
+_ftp._tcp IN URI 10 1 "ftp://ftp.example.com/"
+`
+	records, err := ParseZoneContents(zone, "example.com", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := &bindProvider{zonemd: zonemd.HashSHA384}
+	var buf bytes.Buffer
+	if err := c.writeZone(&buf, records, "example.com", nil); err != nil {
+		t.Fatal(err)
+	}
+	rrs, err := parseRRs(buf.String(), "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := zonemd.Verify(rrs, "example.com"); err != nil {
+		t.Errorf("Verify() = %v\n%s", err, buf.String())
+	}
+
+	// Read back, the ZONEMD is dropped, and writing again gives the same zone.
+	readBack, err := ParseZoneContents(buf.String(), "example.com", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	readBack = withoutApexZonemd(readBack)
+	if len(readBack) != len(records) {
+		t.Errorf("read back %d records, want %d", len(readBack), len(records))
+	}
+	var buf2 bytes.Buffer
+	if err := c.writeZone(&buf2, readBack, "example.com", nil); err != nil {
+		t.Fatal(err)
+	}
+	if buf2.String() != buf.String() {
+		t.Errorf("second write differs:\n%s\n---\n%s", buf.String(), buf2.String())
+	}
+
+	// Without the setting no ZONEMD is added.
+	c.zonemd = 0
+	buf.Reset()
+	if err := c.writeZone(&buf, records, "example.com", nil); err != nil {
+		t.Fatal(err)
+	}
+	rrs, _ = parseRRs(buf.String(), "example.com")
+	if err := zonemd.Verify(rrs, "example.com"); err != zonemd.ErrNotFound {
+		t.Errorf("Verify() = %v, want ErrNotFound", err)
+	}
+}
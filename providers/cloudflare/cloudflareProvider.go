@@ -66,6 +66,9 @@ var features = providers.DocumentationNotes{
 	providers.DocOfficiallySupported: providers.Can(),
 }
 
+// ttlPolicy: "1" means automatic; other TTLs must be 60 to 86400.
+var ttlPolicy = providers.TTLPolicy{Min: 60, Max: 86400, Auto: 1}
+
 func init() {
 	const providerName = "CLOUDFLAREAPI"
 	const providerMaintainer = "@tresni"
@@ -73,7 +76,7 @@ func init() {
 		Initializer:   newCloudflare,
 		RecordAuditor: AuditRecords,
 	}
-	providers.RegisterDomainServiceProviderType(providerName, fns, features)
+	providers.RegisterDomainServiceProviderType(providerName, fns, features, ttlPolicy)
 	providers.RegisterCustomRecordType("CF_WORKER_ROUTE", providerName, "")
 	providers.RegisterMaintainer(providerName, providerMaintainer)
 	providers.RegisterCredsMetadata(providerName, providers.CredsMetadata{
@@ -132,6 +135,7 @@ type cloudflareProvider struct {
 	cfClient      *cloudflare.API
 	//
 	manageSingleRedirects bool // New "Single Redirects"-style redirects.
+	manageLoadBalancers   bool // Load balancers, with their pools and monitors.
 	//
 	// Used by
 	tcLogFilename string   // Transcode Log file name
@@ -173,6 +177,7 @@ func (c *cloudflareProvider) GetZoneRecords(dc *models.DomainConfig) (models.Rec
 	mainCh := make(chan result, 1)
 	redirectCh := make(chan result, 1)
 	workerCh := make(chan result, 1)
+	lbCh := make(chan result, 1)
 
 	// Fetch DNS records concurrently
 	go func() {
@@ -200,10 +205,21 @@ func (c *cloudflareProvider) GetZoneRecords(dc *models.DomainConfig) (models.Rec
 		workerCh <- result{records: nil, err: nil}
 	}
 
+	// Fetch Load Balancers concurrently if enabled
+	if c.manageLoadBalancers {
+		go func() {
+			lbs, err := c.getLoadBalancers(domainID, domain)
+			lbCh <- result{records: lbs, err: err}
+		}()
+	} else {
+		lbCh <- result{records: nil, err: nil}
+	}
+
 	// Collect results
 	mainRes := <-mainCh
 	redirectRes := <-redirectCh
 	workerRes := <-workerCh
+	lbRes := <-lbCh
 
 	if mainRes.err != nil {
 		return nil, mainRes.err
@@ -214,6 +230,9 @@ func (c *cloudflareProvider) GetZoneRecords(dc *models.DomainConfig) (models.Rec
 	if workerRes.err != nil {
 		return nil, workerRes.err
 	}
+	if lbRes.err != nil {
+		return nil, lbRes.err
+	}
 
 	records := mainRes.records
 
@@ -233,6 +252,7 @@ func (c *cloudflareProvider) GetZoneRecords(dc *models.DomainConfig) (models.Rec
 
 	records = append(records, redirectRes.records...)
 	records = append(records, workerRes.records...)
+	records = append(records, lbRes.records...)
 
 	// Normalize
 	models.PostProcessRecords(records)
@@ -294,6 +314,15 @@ func (c *cloudflareProvider) GetZoneRecordsCorrections(dc *models.DomainConfig,
 	if err != nil {
 		return nil, 0, err
 	}
+	// Cached records have no IDs: download them only if there are changes.
+	if needsOriginals(instructions) {
+		if records, err = c.GetZoneRecords(dc); err != nil {
+			return nil, 0, err
+		}
+		if instructions, actualChangeCount, err = diff2.ByRecord(records, dc, comparableFunc); err != nil {
+			return nil, 0, err
+		}
+	}
 
 	for _, inst := range instructions {
 		addToFront := false
@@ -343,12 +372,20 @@ func (c *cloudflareProvider) GetZoneRecordsCorrections(dc *models.DomainConfig,
 		})
 	}
 
+	// Add a correction for each zone setting that changed.
+	settingCorrections, err := c.getZoneSettingsCorrections(dc, domainID)
+	if err != nil {
+		return nil, 0, err
+	}
+	corrections = append(corrections, settingCorrections...)
+	actualChangeCount += len(settingCorrections)
+
 	return corrections, actualChangeCount, nil
 }
 
 func genComparableWithMgmt(rec *models.RecordConfig, manageComments, manageTags bool) string {
 	var parts []string
-	if rec.Type == "A" || rec.Type == "AAAA" || rec.Type == "CNAME" {
+	if rec.Type == "A" || rec.Type == "AAAA" || rec.Type == "CNAME" || rec.Type == "CF_LOAD_BALANCER" {
 		proxy := rec.Metadata[metaProxy]
 		if proxy != "" {
 			if proxy == "on" || proxy == "full" {
@@ -395,6 +432,11 @@ func (c *cloudflareProvider) mkCreateCorrection(newrec *models.RecordConfig, dom
 				return c.createSingleRedirect(domainID, *newrec.F.(*cfsingleredirect.SingleRedirectConfig))
 			},
 		}}
+	case "CF_LOAD_BALANCER":
+		return []*models.Correction{{
+			Msg: msg,
+			F:   func() error { return c.createLoadBalancer(domainID, newrec) },
+		}}
 	default:
 		return c.createRecDiff2(newrec, domainID, msg)
 	}
@@ -407,6 +449,8 @@ func (c *cloudflareProvider) mkChangeCorrection(oldrec, newrec *models.RecordCon
 		idTxt = oldrec.Original.(cloudflare.WorkerRoute).ID
 	case "CLOUDFLAREAPI_SINGLE_REDIRECT":
 		idTxt = oldrec.F.(*cfsingleredirect.SingleRedirectConfig).SRRRulesetID
+	case "CF_LOAD_BALANCER":
+		idTxt = oldrec.Original.(lbOriginal).lb.ID
 	default:
 		idTxt = oldrec.Original.(cloudflare.DNSRecord).ID
 	}
@@ -427,6 +471,11 @@ func (c *cloudflareProvider) mkChangeCorrection(oldrec, newrec *models.RecordCon
 				return c.updateWorkerRoute(idTxt, domainID, newrec.GetTargetField())
 			},
 		}}
+	case "CF_LOAD_BALANCER":
+		return []*models.Correction{{
+			Msg: msg,
+			F:   func() error { return c.updateLoadBalancer(domainID, oldrec, newrec) },
+		}}
 	default:
 		e := oldrec.Original.(cloudflare.DNSRecord)
 		proxy := e.Proxiable && newrec.Metadata[metaProxy] != "off"
@@ -447,6 +496,8 @@ func (c *cloudflareProvider) mkDeleteCorrection(recType string, origRec *models.
 		idTxt = origRec.Original.(cloudflare.WorkerRoute).ID
 	case "CLOUDFLAREAPI_SINGLE_REDIRECT":
 		idTxt = origRec.Original.(cloudflare.RulesetRule).ID
+	case "CF_LOAD_BALANCER":
+		idTxt = origRec.Original.(lbOriginal).lb.ID
 	default:
 		idTxt = origRec.Original.(cloudflare.DNSRecord).ID
 	}
@@ -462,6 +513,8 @@ func (c *cloudflareProvider) mkDeleteCorrection(recType string, origRec *models.
 				return c.deleteWorkerRoute(origRec.Original.(cloudflare.WorkerRoute).ID, domainID)
 			case "CLOUDFLAREAPI_SINGLE_REDIRECT":
 				return c.deleteSingleRedirects(domainID, *origRec.F.(*cfsingleredirect.SingleRedirectConfig))
+			case "CF_LOAD_BALANCER":
+				return c.deleteLoadBalancer(domainID, origRec)
 			default:
 				return c.deleteDNSRecord(origRec.Original.(cloudflare.DNSRecord), domainID)
 			}
@@ -517,6 +570,7 @@ const (
 	metaProxyDefault   = metaProxy + "_default"
 	metaOriginalIP     = "original_ip" // TODO(tlim): Unclear what this means.
 	metaUniversalSSL   = "cloudflare_universalssl"
+	metaZoneSettings   = "cloudflare_zone_settings"
 	metaCNAMEFlatten   = "cloudflare_cname_flatten"
 	metaComment        = "cloudflare_comment"
 	metaTags           = "cloudflare_tags"
@@ -568,6 +622,11 @@ func (c *cloudflareProvider) preprocessConfig(dc *models.DomainConfig) error {
 		}
 	}
 
+	// Check CF_ZONE_SETTINGS
+	if _, err := parseZoneSettings(dc.Metadata[metaZoneSettings]); err != nil {
+		return err
+	}
+
 	// Normalize the proxy setting for each record.
 	// A and CNAMEs: Validate. If null, set to default.
 	// else: Make sure it wasn't set.  Set to default.
@@ -583,11 +642,19 @@ func (c *cloudflareProvider) preprocessConfig(dc *models.DomainConfig) error {
 		if rec.TTL == 0 {
 			rec.TTL = 1
 		}
-		if rec.TTL != 1 && rec.TTL < 60 {
-			rec.TTL = 60
-		}
 
-		if rec.Type != "A" && rec.Type != "CNAME" && rec.Type != "AAAA" && rec.Type != "ALIAS" {
+		if rec.Type == "CF_LOAD_BALANCER" {
+			// Load balancers are proxied or not. There is no "full", but a
+			// default of "full" proxies them.
+			val := rec.Metadata[metaProxy]
+			if val == "" {
+				val = strings.Replace(defProxy, "full", "on", 1)
+			}
+			if val = strings.ToLower(val); val != "on" && val != "off" {
+				return fmt.Errorf("bad metadata value for cloudflare_proxy on CF_LOAD_BALANCER %#v: '%s'. Use on/off", rec.GetLabel(), val)
+			}
+			rec.Metadata[metaProxy] = val
+		} else if rec.Type != "A" && rec.Type != "CNAME" && rec.Type != "AAAA" && rec.Type != "ALIAS" {
 			if rec.Metadata[metaProxy] != "" {
 				return fmt.Errorf("cloudflare_proxy set on %v record: %#v cloudflare_proxy=%#v", rec.Type, rec.GetLabel(), rec.Metadata[metaProxy])
 			}
@@ -663,6 +730,11 @@ func (c *cloudflareProvider) preprocessConfig(dc *models.DomainConfig) error {
 			if !c.manageSingleRedirects {
 				return errors.New("you must add 'manage_single_redirects: true' metadata to cloudflare provider to use CLOUDFLAREAPI_SINGLE_REDIRECT records")
 			}
+		case "CF_LOAD_BALANCER":
+			// CF_LOAD_BALANCER record types. Verify they are enabled.
+			if !c.manageLoadBalancers {
+				return errors.New("you must add 'manage_load_balancers: true' metadata to cloudflare provider to use CF_LOAD_BALANCER records")
+			}
 		case "CF_WORKER_ROUTE":
 			// CF_WORKER_ROUTE record types. Encode target as $PATTERN,$SCRIPT
 			parts := strings.Split(rec.GetTargetField(), ",")
@@ -777,6 +849,7 @@ func newCloudflare(m map[string]string, metadata json.RawMessage) (providers.DNS
 			ManageWorkers bool     `json:"manage_workers"`
 			//
 			ManageSingleRedirects bool   `json:"manage_single_redirects"` // New-style Dynamic "Single Redirects"
+			ManageLoadBalancers   bool   `json:"manage_load_balancers"`   // CF_LOAD_BALANCER
 			TranscodeLogFilename  string `json:"transcode_log"`           // Log the PAGE_RULE conversions.
 		}{}
 		err := json.Unmarshal([]byte(metadata), parsedMeta)
@@ -786,6 +859,7 @@ func newCloudflare(m map[string]string, metadata json.RawMessage) (providers.DNS
 		api.manageSingleRedirects = parsedMeta.ManageSingleRedirects
 		api.tcLogFilename = parsedMeta.TranscodeLogFilename
 		api.manageWorkers = parsedMeta.ManageWorkers
+		api.manageLoadBalancers = parsedMeta.ManageLoadBalancers
 		// ignored_labels:
 		api.ignoredLabels = append(api.ignoredLabels, parsedMeta.IgnoredLabels...)
 		if len(api.ignoredLabels) > 0 {
@@ -799,6 +873,13 @@ func newCloudflare(m map[string]string, metadata json.RawMessage) (providers.DNS
 			}
 		}
 	}
+	// get-zones has no provider metadata, so creds.json may enable it too.
+	if m["manage_load_balancers"] == "true" {
+		api.manageLoadBalancers = true
+	}
+	if api.manageLoadBalancers && api.accountID == "" {
+		return nil, errors.New("cloudflare: manage_load_balancers requires accountid in creds.json, since pools and monitors belong to the account")
+	}
 	return api, nil
 }
 
@@ -0,0 +1,44 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/DNSControl/dnscontrol/v4/pkg/dnssec"
+	dnsv1 "github.com/miekg/dns"
+)
+
+// DNSSECKeys returns the key that Cloudflare signs the zone with, if DNSSEC
+// is enabled. Cloudflare uses a single CSK and manages it itself.
+func (c *cloudflareProvider) DNSSECKeys(zone string) ([]dnssec.Key, error) {
+	zoneID, err := c.getDomainID(zone)
+	if err != nil {
+		return nil, err
+	}
+	ds, err := c.cfClient.ZoneDNSSECSetting(context.Background(), zoneID)
+	if err != nil {
+		return nil, err
+	}
+	if ds.Status != "active" && ds.Status != "pending" {
+		return nil, nil
+	}
+
+	k := dnssec.Key{
+		ID:        strconv.Itoa(ds.KeyTag),
+		Role:      dnssec.CSK,
+		Published: dnssec.Unknown,
+	}
+	if ds.Status == "active" {
+		k.Activated = dnssec.Unknown
+	}
+	if err := k.ParseDNSKEY(fmt.Sprintf("%d 3 %s %s", ds.Flags, ds.Algorithm, ds.PublicKey)); err != nil {
+		return nil, fmt.Errorf("DNSSEC key of %s: %w", zone, err)
+	}
+	// The DS is a whole record ("example.com. 3600 IN DS ..."); keep the rdata.
+	if rr, err := dnsv1.NewRR(ds.DS); err == nil && rr != nil {
+		k.DS = []string{strings.TrimPrefix(rr.String(), rr.Header().String())}
+	}
+	return []dnssec.Key{k}, nil
+}
@@ -0,0 +1,365 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/DNSControl/dnscontrol/v4/models"
+	"github.com/DNSControl/dnscontrol/v4/pkg/domaintags"
+	"github.com/DNSControl/dnscontrol/v4/pkg/printer"
+	"github.com/DNSControl/dnscontrol/v4/pkg/rtypecontrol"
+	"github.com/DNSControl/dnscontrol/v4/providers/cloudflare/rtypes/cfloadbalancer"
+	"github.com/cloudflare/cloudflare-go"
+)
+
+// Load balancers belong to a zone, but their pools and monitors belong to
+// the account. Pools are identified by their name. Each pool has its own
+// monitor, which DNSControl creates and deletes with the pool.
+//
+// The account may have pools and monitors that DNSControl did not create.
+// Those are never changed or deleted: DNSControl only touches the pools and
+// monitors whose description carries its ownership marker.
+
+// lbOriginal is the Original of CF_LOAD_BALANCER records from the API.
+type lbOriginal struct {
+	lb    cloudflare.LoadBalancer
+	pools []cloudflare.LoadBalancerPool // The pools of lb, in order.
+}
+
+// lbPoolDescription is the description of the pools DNSControl creates.
+const lbPoolDescription = "dnscontrol"
+
+// lbMonitorDescription is the description of the monitors DNSControl
+// creates for a pool.
+func lbMonitorDescription(pool string) string {
+	return "dnscontrol: " + pool
+}
+
+// lbOwnsPool reports whether DNSControl created the pool.
+func lbOwnsPool(p cloudflare.LoadBalancerPool) bool {
+	return p.Description == lbPoolDescription
+}
+
+// lbOwnsMonitor reports whether DNSControl created the monitor for the pool.
+func lbOwnsMonitor(m cloudflare.LoadBalancerMonitor, pool string) bool {
+	return m.Description == lbMonitorDescription(pool)
+}
+
+func (c *cloudflareProvider) account() *cloudflare.ResourceContainer {
+	return cloudflare.AccountIdentifier(c.accountID)
+}
+
+// getLoadBalancers returns the load balancers of a zone as CF_LOAD_BALANCER
+// records.
+func (c *cloudflareProvider) getLoadBalancers(id string, domain string) ([]*models.RecordConfig, error) {
+	lbs, err := c.cfClient.ListLoadBalancers(context.Background(), cloudflare.ZoneIdentifier(id), cloudflare.ListLoadBalancerParams{})
+	if err != nil {
+		return nil, fmt.Errorf("failed fetching load balancer list from cloudflare: %w", err)
+	}
+	if len(lbs) == 0 {
+		return nil, nil
+	}
+	pools, monitors, err := c.getLoadBalancerPools()
+	if err != nil {
+		return nil, err
+	}
+	poolsByID := map[string]cloudflare.LoadBalancerPool{}
+	for _, p := range pools {
+		poolsByID[p.ID] = p
+	}
+
+	dcn := domaintags.MakeDomainNameVarieties(domain)
+	var recs []*models.RecordConfig
+	for _, lb := range lbs {
+		orig := lbOriginal{lb: lb}
+		ids := lb.DefaultPools
+		if lb.FallbackPool != "" && (len(ids) == 0 || ids[len(ids)-1] != lb.FallbackPool) {
+			// The fallback pool is always the last pool in dnsconfig.js. If
+			// it isn't, listing it again makes sure the load balancer is
+			// updated.
+			ids = append(slices.Clone(ids), lb.FallbackPool)
+		}
+		for _, pid := range ids {
+			p, ok := poolsByID[pid]
+			if !ok {
+				return nil, fmt.Errorf("load balancer %s uses unknown pool %s", lb.Name, pid)
+			}
+			orig.pools = append(orig.pools, p)
+		}
+
+		ttl := uint32(lb.TTL)
+		if lb.Proxied {
+			ttl = 1
+		}
+		rec, err := rtypecontrol.NewRecordConfigFromStruct(lb.Name+".", ttl, "CF_LOAD_BALANCER", nativeToLoadBalancer(orig, monitors), dcn)
+		if err != nil {
+			return nil, err
+		}
+		rec.Metadata[metaProxy] = strconv.FormatBool(lb.Proxied)
+		rec.Original = orig
+		recs = append(recs, rec)
+	}
+	return recs, nil
+}
+
+// getLoadBalancerPools returns the pools and monitors (by ID) of the account.
+func (c *cloudflareProvider) getLoadBalancerPools() ([]cloudflare.LoadBalancerPool, map[string]cloudflare.LoadBalancerMonitor, error) {
+	pools, err := c.cfClient.ListLoadBalancerPools(context.Background(), c.account(), cloudflare.ListLoadBalancerPoolParams{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed fetching load balancer pool list from cloudflare: %w", err)
+	}
+	list, err := c.cfClient.ListLoadBalancerMonitors(context.Background(), c.account(), cloudflare.ListLoadBalancerMonitorParams{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed fetching load balancer monitor list from cloudflare: %w", err)
+	}
+	monitors := map[string]cloudflare.LoadBalancerMonitor{}
+	for _, m := range list {
+		monitors[m.ID] = m
+	}
+	return pools, monitors, nil
+}
+
+// nativeToLoadBalancer converts a load balancer and its pools to the form
+// used by dnsconfig.js.
+func nativeToLoadBalancer(orig lbOriginal, monitors map[string]cloudflare.LoadBalancerMonitor) *cfloadbalancer.LoadBalancer {
+	lb := orig.lb
+	steering := lb.SteeringPolicy
+	if steering == "" {
+		// The default depends on whether there are geo pools.
+		steering = "off"
+		if len(lb.RegionPools) > 0 || len(lb.PopPools) > 0 || len(lb.CountryPools) > 0 {
+			steering = "geo"
+		}
+	}
+	result := &cfloadbalancer.LoadBalancer{Steering: steering}
+	for _, p := range orig.pools {
+		pool := cfloadbalancer.Pool{Name: p.Name}
+		for _, o := range p.Origins {
+			pool.Origins = append(pool.Origins, cfloadbalancer.Origin{
+				Name:     o.Name,
+				Address:  o.Address,
+				Weight:   o.Weight,
+				Disabled: !o.Enabled,
+			})
+		}
+		for region, ids := range lb.RegionPools {
+			if slices.Contains(ids, p.ID) {
+				pool.Regions = append(pool.Regions, region)
+			}
+		}
+		if lb.RandomSteering != nil {
+			pool.Weight = lb.RandomSteering.PoolWeights[p.ID]
+		}
+		if m, ok := monitors[p.Monitor]; ok {
+			pool.Monitor = &cfloadbalancer.Monitor{
+				Type:          m.Type,
+				Method:        m.Method,
+				Path:          m.Path,
+				Port:          m.Port,
+				ExpectedCodes: m.ExpectedCodes,
+				Interval:      m.Interval,
+				Timeout:       m.Timeout,
+				Retries:       m.Retries,
+			}
+		}
+		result.Pools = append(result.Pools, pool)
+	}
+	return result
+}
+
+// createLoadBalancer creates or updates the pools of rec, then creates the
+// load balancer.
+func (c *cloudflareProvider) createLoadBalancer(domainID string, rec *models.RecordConfig) error {
+	lb, err := c.loadBalancerToNative(rec)
+	if err != nil {
+		return err
+	}
+	_, err = c.cfClient.CreateLoadBalancer(context.Background(), cloudflare.ZoneIdentifier(domainID), cloudflare.CreateLoadBalancerParams{LoadBalancer: lb})
+	return err
+}
+
+// updateLoadBalancer creates or updates the pools of newrec, updates the
+// load balancer, and deletes the pools that it no longer uses.
+func (c *cloudflareProvider) updateLoadBalancer(domainID string, oldrec, newrec *models.RecordConfig) error {
+	lb, err := c.loadBalancerToNative(newrec)
+	if err != nil {
+		return err
+	}
+	orig := oldrec.Original.(lbOriginal)
+	lb.ID = orig.lb.ID
+	if _, err := c.cfClient.UpdateLoadBalancer(context.Background(), cloudflare.ZoneIdentifier(domainID), cloudflare.UpdateLoadBalancerParams{LoadBalancer: lb}); err != nil {
+		return err
+	}
+	c.deleteUnusedPools(orig.pools, lb.DefaultPools)
+	return nil
+}
+
+// deleteLoadBalancer deletes the load balancer, and the pools that it used.
+func (c *cloudflareProvider) deleteLoadBalancer(domainID string, rec *models.RecordConfig) error {
+	orig := rec.Original.(lbOriginal)
+	if err := c.cfClient.DeleteLoadBalancer(context.Background(), cloudflare.ZoneIdentifier(domainID), orig.lb.ID); err != nil {
+		return err
+	}
+	c.deleteUnusedPools(orig.pools, nil)
+	return nil
+}
+
+// deleteUnusedPools deletes the pools, and their monitors, that are not in
+// keep. Only the pools and monitors that DNSControl created are deleted.
+// Pools that are still used by another load balancer (for example in another
+// zone) can't be deleted; that is not an error.
+func (c *cloudflareProvider) deleteUnusedPools(pools []cloudflare.LoadBalancerPool, keep []string) {
+	var monitors map[string]cloudflare.LoadBalancerMonitor
+	for _, p := range pools {
+		if slices.Contains(keep, p.ID) || !lbOwnsPool(p) {
+			continue
+		}
+		if err := c.cfClient.DeleteLoadBalancerPool(context.Background(), c.account(), p.ID); err != nil {
+			printer.Warnf("Load balancer pool %q was not deleted (it may be used by another load balancer): %s\n", p.Name, err)
+			continue
+		}
+		if p.Monitor == "" {
+			continue
+		}
+		if monitors == nil {
+			var err error
+			if _, monitors, err = c.getLoadBalancerPools(); err != nil {
+				printer.Warnf("Load balancer monitor %s of pool %q was not deleted: %s\n", p.Monitor, p.Name, err)
+				continue
+			}
+		}
+		c.deleteOwnMonitor(monitors, p.Monitor, p.Name)
+	}
+}
+
+// deleteOwnMonitor deletes the monitor id, if DNSControl created it for the
+// pool.
+func (c *cloudflareProvider) deleteOwnMonitor(monitors map[string]cloudflare.LoadBalancerMonitor, id, pool string) {
+	m, ok := monitors[id]
+	if !ok || !lbOwnsMonitor(m, pool) {
+		return
+	}
+	if err := c.cfClient.DeleteLoadBalancerMonitor(context.Background(), c.account(), id); err != nil {
+		printer.Warnf("Load balancer monitor %s of pool %q was not deleted: %s\n", id, pool, err)
+	}
+}
+
+// loadBalancerToNative creates or updates the pools and monitors of rec,
+// and returns the load balancer that uses them.
+func (c *cloudflareProvider) loadBalancerToNative(rec *models.RecordConfig) (cloudflare.LoadBalancer, error) {
+	desired := rec.F.(*cfloadbalancer.LoadBalancer)
+	existing, monitors, err := c.getLoadBalancerPools()
+	if err != nil {
+		return cloudflare.LoadBalancer{}, err
+	}
+
+	proxied := rec.Metadata[metaProxy] == "on"
+	enabled := true
+	lb := cloudflare.LoadBalancer{
+		Name:           rec.GetLabelFQDN(),
+		TTL:            int(rec.TTL),
+		Proxied:        proxied,
+		Enabled:        &enabled,
+		SteeringPolicy: desired.Steering,
+		RegionPools:    map[string][]string{},
+	}
+	if proxied {
+		lb.TTL = 0 // Cloudflare chooses the TTL of proxied load balancers.
+	}
+	for _, p := range desired.Pools {
+		id, err := c.ensurePool(p, existing, monitors)
+		if err != nil {
+			return cloudflare.LoadBalancer{}, err
+		}
+		lb.DefaultPools = append(lb.DefaultPools, id)
+		for _, region := range p.Regions {
+			lb.RegionPools[region] = append(lb.RegionPools[region], id)
+		}
+		if p.Weight != 0 {
+			if lb.RandomSteering == nil {
+				lb.RandomSteering = &cloudflare.RandomSteering{PoolWeights: map[string]float64{}}
+			}
+			lb.RandomSteering.PoolWeights[id] = p.Weight
+		}
+	}
+	lb.FallbackPool = lb.DefaultPools[len(lb.DefaultPools)-1]
+	return lb, nil
+}
+
+// ensurePool creates the pool p, or updates the pool with the same name, and
+// returns its ID. A pool with the same name that DNSControl did not create is
+// an error.
+func (c *cloudflareProvider) ensurePool(p cfloadbalancer.Pool, existing []cloudflare.LoadBalancerPool, monitors map[string]cloudflare.LoadBalancerMonitor) (string, error) {
+	pool := cloudflare.LoadBalancerPool{Name: p.Name, Description: lbPoolDescription, Enabled: true}
+	for _, o := range p.Origins {
+		pool.Origins = append(pool.Origins, cloudflare.LoadBalancerOrigin{
+			Name:    o.Name,
+			Address: o.Address,
+			Weight:  o.Weight,
+			Enabled: !o.Disabled,
+		})
+	}
+	i := slices.IndexFunc(existing, func(e cloudflare.LoadBalancerPool) bool { return e.Name == p.Name })
+	if i >= 0 && !lbOwnsPool(existing[i]) {
+		return "", fmt.Errorf("load balancer pool %q already exists and was not created by dnscontrol (its description is not %q)", p.Name, lbPoolDescription)
+	}
+
+	// The monitor of the pool is kept if it is unchanged. Otherwise a new
+	// monitor is created: the old one may be shared with other pools, so
+	// it is never modified.
+	var oldMonitor string
+	if i >= 0 {
+		oldMonitor = existing[i].Monitor
+	}
+	if p.Monitor != nil {
+		m := cloudflare.LoadBalancerMonitor{
+			Type:          p.Monitor.Type,
+			Description:   lbMonitorDescription(p.Name),
+			Method:        p.Monitor.Method,
+			Path:          p.Monitor.Path,
+			Port:          p.Monitor.Port,
+			ExpectedCodes: p.Monitor.ExpectedCodes,
+			Interval:      p.Monitor.Interval,
+			Timeout:       p.Monitor.Timeout,
+			Retries:       p.Monitor.Retries,
+		}
+		if old, ok := monitors[oldMonitor]; ok && sameMonitor(old, m) {
+			pool.Monitor = oldMonitor
+		} else {
+			created, err := c.cfClient.CreateLoadBalancerMonitor(context.Background(), c.account(), cloudflare.CreateLoadBalancerMonitorParams{LoadBalancerMonitor: m})
+			if err != nil {
+				return "", err
+			}
+			pool.Monitor = created.ID
+		}
+	}
+
+	if i < 0 {
+		created, err := c.cfClient.CreateLoadBalancerPool(context.Background(), c.account(), cloudflare.CreateLoadBalancerPoolParams{LoadBalancerPool: pool})
+		return created.ID, err
+	}
+	pool.ID = existing[i].ID
+	if _, err := c.cfClient.UpdateLoadBalancerPool(context.Background(), c.account(), cloudflare.UpdateLoadBalancerPoolParams{LoadBalancer: pool}); err != nil {
+		return "", err
+	}
+	if oldMonitor != "" && oldMonitor != pool.Monitor {
+		c.deleteOwnMonitor(monitors, oldMonitor, p.Name)
+	}
+	return pool.ID, nil
+}
+
+// sameMonitor reports whether the existing monitor a is configured like the
+// desired monitor b, and was created by DNSControl for the same pool.
+func sameMonitor(a, b cloudflare.LoadBalancerMonitor) bool {
+	return a.Description == b.Description &&
+		a.Type == b.Type &&
+		a.Method == b.Method &&
+		a.Path == b.Path &&
+		a.Port == b.Port &&
+		a.ExpectedCodes == b.ExpectedCodes &&
+		a.Interval == b.Interval &&
+		a.Timeout == b.Timeout &&
+		a.Retries == b.Retries
+}
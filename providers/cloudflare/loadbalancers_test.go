@@ -0,0 +1,80 @@
+package cloudflare
+
+import (
+	"testing"
+
+	"github.com/DNSControl/dnscontrol/v4/models"
+	"github.com/DNSControl/dnscontrol/v4/providers/cloudflare/rtypes/cfloadbalancer"
+	"github.com/cloudflare/cloudflare-go"
+)
+
+func TestNativeToLoadBalancer(t *testing.T) {
+	// The same load balancer as in dnsconfig.js and as returned by the API
+	// must compare equal.
+	rec := &models.RecordConfig{}
+	err := (&cfloadbalancer.LoadBalancer{}).FromArgs(nil, rec, []any{"www", []any{
+		map[string]any{
+			"name":    "eu",
+			"origins": []any{map[string]any{"name": "web1", "address": "192.0.2.1"}},
+			"monitor": map[string]any{"path": "/health"},
+			"regions": []any{"WEU", "EEU"},
+		},
+		map[string]any{
+			"name":    "fallback",
+			"origins": []any{map[string]any{"name": "dr", "address": "198.51.100.1", "weight": 0.5}},
+		},
+	}, "geo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	orig := lbOriginal{
+		lb: cloudflare.LoadBalancer{
+			Name:         "www.example.com",
+			DefaultPools: []string{"p1", "p2"},
+			FallbackPool: "p2",
+			RegionPools:  map[string][]string{"WEU": {"p1"}, "EEU": {"p1"}},
+		},
+		pools: []cloudflare.LoadBalancerPool{
+			{ID: "p1", Name: "eu", Monitor: "m1", Origins: []cloudflare.LoadBalancerOrigin{{Name: "web1", Address: "192.0.2.1", Weight: 1, Enabled: true}}},
+			{ID: "p2", Name: "fallback", Origins: []cloudflare.LoadBalancerOrigin{{Name: "dr", Address: "198.51.100.1", Weight: 0.5, Enabled: true}}},
+		},
+	}
+	monitors := map[string]cloudflare.LoadBalancerMonitor{
+		"m1": {ID: "m1", Type: "http", Method: "GET", Path: "/health", ExpectedCodes: "200", Interval: 60, Timeout: 5, Retries: 2},
+	}
+	got := &models.RecordConfig{}
+	if err := (&cfloadbalancer.LoadBalancer{}).FromStruct(nil, got, "www", nativeToLoadBalancer(orig, monitors)); err != nil {
+		t.Fatal(err)
+	}
+	if got.Comparable != rec.Comparable {
+		t.Errorf("got  %s\nwant %s", got.Comparable, rec.Comparable)
+	}
+}
+
+func TestEnsurePoolNotOwned(t *testing.T) {
+	// A pool with the same name that DNSControl did not create must not be
+	// taken over.
+	existing := []cloudflare.LoadBalancerPool{{ID: "p1", Name: "eu", Description: "made by hand"}}
+	_, err := (&cloudflareProvider{}).ensurePool(cfloadbalancer.Pool{Name: "eu"}, existing, nil)
+	if err == nil {
+		t.Fatal("expected an error for a pool not created by dnscontrol")
+	}
+}
+
+func TestSameMonitor(t *testing.T) {
+	m := cloudflare.LoadBalancerMonitor{Type: "http", Description: lbMonitorDescription("eu"), Path: "/health"}
+	if !sameMonitor(m, m) {
+		t.Error("identical monitors must be the same")
+	}
+	other := m
+	other.Description = "shared"
+	if sameMonitor(other, m) {
+		t.Error("a monitor not created for the pool must not be reused")
+	}
+	other = m
+	other.Path = "/"
+	if sameMonitor(other, m) {
+		t.Error("monitors with different paths must differ")
+	}
+}
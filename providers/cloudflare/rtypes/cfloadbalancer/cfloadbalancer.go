@@ -0,0 +1,228 @@
+package cfloadbalancer
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/DNSControl/dnscontrol/v4/models"
+	"github.com/DNSControl/dnscontrol/v4/pkg/domaintags"
+	"github.com/DNSControl/dnscontrol/v4/pkg/rtypecontrol"
+)
+
+func init() {
+	rtypecontrol.Register(&LoadBalancer{})
+}
+
+// Steerings lists the steering policies that CF_LOAD_BALANCER accepts. They
+// are the names Cloudflare uses.
+var Steerings = []string{"off", "random", "geo", "dynamic_latency", "least_outstanding_requests", "least_connections"}
+
+// LoadBalancer contains info about a Cloudflare load balancer, with its
+// pools, their origins and monitors. The JSON field names are the ones used
+// in dnsconfig.js.
+type LoadBalancer struct {
+	Steering string `json:"steering"`
+	Pools    []Pool `json:"pools"` // In order of preference. The last pool is the fallback pool.
+}
+
+// Pool is a load balancer pool. Pools are identified by their name, which
+// must be unique in the Cloudflare account.
+type Pool struct {
+	Name    string   `json:"name"`
+	Origins []Origin `json:"origins"`
+	Monitor *Monitor `json:"monitor,omitempty"`
+	Regions []string `json:"regions,omitempty"` // Regions served by this pool, for the "geo" steering.
+	Weight  float64  `json:"weight,omitempty"`  // Weight of this pool, for the "random" and "least_*" steerings.
+}
+
+// Origin is a server in a pool.
+type Origin struct {
+	Name     string  `json:"name"`
+	Address  string  `json:"address"`
+	Weight   float64 `json:"weight,omitempty"` // 1 if not set.
+	Disabled bool    `json:"disabled,omitempty"`
+}
+
+// Monitor is the health check of the origins of a pool.
+type Monitor struct {
+	Type          string `json:"type,omitempty"`           // "http" (the default), "https", "tcp", "udp_icmp", "icmp_ping" or "smtp".
+	Method        string `json:"method,omitempty"`         // HTTP(S) only. "GET" if not set.
+	Path          string `json:"path,omitempty"`           // HTTP(S) only. "/" if not set.
+	Port          uint16 `json:"port,omitempty"`           // The default port of the type if not set.
+	ExpectedCodes string `json:"expected_codes,omitempty"` // HTTP(S) only. "200" if not set.
+	Interval      int    `json:"interval,omitempty"`       // Seconds. 60 if not set.
+	Timeout       int    `json:"timeout,omitempty"`        // Seconds. 5 if not set.
+	Retries       int    `json:"retries,omitempty"`        // 2 if not set.
+}
+
+// Name returns the text (all caps) name of the rtype.
+func (handle *LoadBalancer) Name() string {
+	return "CF_LOAD_BALANCER"
+}
+
+// FromArgs populates a RecordConfig from the raw ([]any) args: the label,
+// the pools and the steering policy.
+func (handle *LoadBalancer) FromArgs(dcn *domaintags.DomainNameVarieties, rec *models.RecordConfig, args []any) error {
+	if len(args) != 3 {
+		return fmt.Errorf("%s: CF_LOAD_BALANCER wants 3 arguments (name, pools, steering), got %d", rec.FilePos, len(args))
+	}
+	steering, ok := args[2].(string)
+	if !ok {
+		return fmt.Errorf("%s: CF_LOAD_BALANCER steering must be a string, got %T", rec.FilePos, args[2])
+	}
+
+	// The pools are a list of objects. Decode them strictly to catch typos.
+	b, err := json.Marshal(args[1])
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	lb := &LoadBalancer{Steering: steering}
+	if err := dec.Decode(&lb.Pools); err != nil {
+		return fmt.Errorf("%s: CF_LOAD_BALANCER pools: %w", rec.FilePos, err)
+	}
+	if err := lb.validate(); err != nil {
+		return fmt.Errorf("%s: CF_LOAD_BALANCER(%q): %w", rec.FilePos, args[0], err)
+	}
+
+	return handle.FromStruct(dcn, rec, args[0].(string), lb)
+}
+
+// FromStruct populates a RecordConfig from a *LoadBalancer, which will be
+// stored in rec.F. Defaults are filled in, so that a load balancer from
+// dnsconfig.js and the same one from the API are equal.
+func (handle *LoadBalancer) FromStruct(dcn *domaintags.DomainNameVarieties, rec *models.RecordConfig, name string, fields any) error {
+	lb, ok := fields.(*LoadBalancer)
+	if !ok {
+		return fmt.Errorf("fields is not *cfloadbalancer.LoadBalancer, got %T", fields)
+	}
+	lb.setDefaults()
+	rec.F = lb
+
+	display := lb.String()
+	rec.Comparable = display
+	rec.ZonefilePartial = display
+	handle.CopyToLegacyFields(rec)
+	return nil
+}
+
+func (lb *LoadBalancer) validate() error {
+	if !slices.Contains(Steerings, lb.Steering) {
+		return fmt.Errorf("steering %q is not one of %s", lb.Steering, strings.Join(Steerings, ", "))
+	}
+	if len(lb.Pools) == 0 {
+		return errors.New("no pools")
+	}
+	seen := map[string]bool{}
+	for _, p := range lb.Pools {
+		if p.Name == "" {
+			return errors.New("a pool has no name")
+		}
+		if seen[p.Name] {
+			return fmt.Errorf("pool %q is listed twice", p.Name)
+		}
+		seen[p.Name] = true
+		if len(p.Origins) == 0 {
+			return fmt.Errorf("pool %q has no origins", p.Name)
+		}
+		for _, o := range p.Origins {
+			if o.Name == "" || o.Address == "" {
+				return fmt.Errorf("pool %q: origins need a name and an address", p.Name)
+			}
+		}
+		if len(p.Regions) > 0 && lb.Steering != "geo" {
+			return fmt.Errorf("pool %q: regions are only used by the \"geo\" steering", p.Name)
+		}
+	}
+	return nil
+}
+
+func (lb *LoadBalancer) setDefaults() {
+	for i := range lb.Pools {
+		p := &lb.Pools[i]
+		for j := range p.Origins {
+			if p.Origins[j].Weight == 0 {
+				p.Origins[j].Weight = 1
+			}
+		}
+		slices.Sort(p.Regions)
+		if m := p.Monitor; m != nil {
+			if m.Type == "" {
+				m.Type = "http"
+			}
+			if m.Type == "http" || m.Type == "https" {
+				m.Method = cmp.Or(m.Method, "GET")
+				m.Path = cmp.Or(m.Path, "/")
+				m.ExpectedCodes = cmp.Or(m.ExpectedCodes, "200")
+			} else {
+				m.Method, m.Path, m.ExpectedCodes = "", "", ""
+			}
+			m.Interval = cmp.Or(m.Interval, 60)
+			m.Timeout = cmp.Or(m.Timeout, 5)
+			m.Retries = cmp.Or(m.Retries, 2)
+		}
+	}
+}
+
+// String returns the text used to display and compare load balancers.
+func (lb *LoadBalancer) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "steering=%s", lb.Steering)
+	for _, p := range lb.Pools {
+		fmt.Fprintf(&sb, " pool=%s(", p.Name)
+		for i, o := range p.Origins {
+			if i > 0 {
+				sb.WriteString(" ")
+			}
+			fmt.Fprintf(&sb, "%s=%s", o.Name, o.Address)
+			if o.Weight != 1 {
+				fmt.Fprintf(&sb, "*%g", o.Weight)
+			}
+			if o.Disabled {
+				sb.WriteString(",disabled")
+			}
+		}
+		sb.WriteString(")")
+		if len(p.Regions) > 0 {
+			fmt.Fprintf(&sb, " regions=%s", strings.Join(p.Regions, ","))
+		}
+		if p.Weight != 0 {
+			fmt.Fprintf(&sb, " weight=%g", p.Weight)
+		}
+		if m := p.Monitor; m != nil {
+			fmt.Fprintf(&sb, " monitor=%s", m.Type)
+			if m.Port != 0 {
+				fmt.Fprintf(&sb, ":%d", m.Port)
+			}
+			if m.Method != "" {
+				fmt.Fprintf(&sb, ",%s %s,%s", m.Method, m.Path, m.ExpectedCodes)
+			}
+			fmt.Fprintf(&sb, ",%ds/%ds/%d", m.Interval, m.Timeout, m.Retries)
+		}
+	}
+	return sb.String()
+}
+
+// CopyToLegacyFields copies data from rec.F to the legacy fields in rec.
+func (handle *LoadBalancer) CopyToLegacyFields(rec *models.RecordConfig) {
+	_ = rec.SetTarget(rec.F.(*LoadBalancer).String())
+}
+
+// CopyFromLegacyFields populates rec.F from the legacy RecordType fields.
+func (handle *LoadBalancer) CopyFromLegacyFields(rec *models.RecordConfig) {
+	// Nothing needs to be copied.  The CF_LOAD_BALANCER is built in FromArgs.
+
+	// However, we add some assertions here to catch mistakes.
+	if rec.F == nil {
+		panic("assertion failed: LoadBalancer CopyFromLegacyFields called with rec.F == nil")
+	}
+	if rec.Comparable == "" {
+		panic("assertion failed: LoadBalancer CopyFromLegacyFields called with rec.Comparable == \"\"")
+	}
+}
@@ -0,0 +1,74 @@
+package cfloadbalancer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/DNSControl/dnscontrol/v4/models"
+)
+
+func TestFromArgs(t *testing.T) {
+	pools := func(extra map[string]any) []any {
+		p := map[string]any{
+			"name": "main",
+			"origins": []any{
+				map[string]any{"name": "web1", "address": "192.0.2.1"},
+				map[string]any{"name": "web2", "address": "192.0.2.2", "weight": 0.5, "disabled": true},
+			},
+			"monitor": map[string]any{"path": "/health"},
+		}
+		for k, v := range extra {
+			p[k] = v
+		}
+		return []any{p, map[string]any{"name": "fallback", "origins": []any{map[string]any{"name": "dr", "address": "198.51.100.1"}}}}
+	}
+
+	tests := []struct {
+		name     string
+		args     []any
+		want     string
+		wantErrs string
+	}{
+		{
+			name: "defaults",
+			args: []any{"www", pools(nil), "off"},
+			want: "steering=off pool=main(web1=192.0.2.1 web2=192.0.2.2*0.5,disabled) monitor=http,GET /health,200,60s/5s/2 pool=fallback(dr=198.51.100.1)",
+		},
+		{
+			name: "geo",
+			args: []any{"www", pools(map[string]any{"regions": []any{"WEU", "ENAM"}}), "geo"},
+			want: "steering=geo pool=main(web1=192.0.2.1 web2=192.0.2.2*0.5,disabled) regions=ENAM,WEU monitor=http,GET /health,200,60s/5s/2 pool=fallback(dr=198.51.100.1)",
+		},
+		{
+			name: "tcp monitor",
+			args: []any{"www", pools(map[string]any{"monitor": map[string]any{"type": "tcp", "port": 443, "path": "/ignored"}}), "random"},
+			want: "steering=random pool=main(web1=192.0.2.1 web2=192.0.2.2*0.5,disabled) monitor=tcp:443,60s/5s/2 pool=fallback(dr=198.51.100.1)",
+		},
+		{name: "bad steering", args: []any{"www", pools(nil), "round_robin"}, wantErrs: `steering "round_robin" is not one of`},
+		{name: "regions without geo", args: []any{"www", pools(map[string]any{"regions": []any{"WEU"}}), "off"}, wantErrs: `regions are only used by the "geo" steering`},
+		{name: "typo", args: []any{"www", pools(map[string]any{"weigth": 2}), "off"}, wantErrs: `unknown field "weigth"`},
+		{name: "no pools", args: []any{"www", []any{}, "off"}, wantErrs: "no pools"},
+		{name: "wrong number of args", args: []any{"www", pools(nil)}, wantErrs: "wants 3 arguments"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &models.RecordConfig{}
+			err := (&LoadBalancer{}).FromArgs(nil, rec, tt.args)
+			if tt.wantErrs != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErrs) {
+					t.Fatalf("got error %v, want %q", err, tt.wantErrs)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if rec.Comparable != tt.want {
+				t.Errorf("got  %s\nwant %s", rec.Comparable, tt.want)
+			}
+			if rec.GetTargetField() != tt.want {
+				t.Errorf("target is %q", rec.GetTargetField())
+			}
+		})
+	}
+}
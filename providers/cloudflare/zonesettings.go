@@ -0,0 +1,296 @@
+package cloudflare
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+
+	"github.com/DNSControl/dnscontrol/v4/models"
+	"github.com/cloudflare/cloudflare-go"
+)
+
+// Zone settings (CF_ZONE_SETTINGS) are stored as JSON in the domain's
+// metadata. The names are Cloudflare's setting IDs, except for these:
+//
+//   - "dnssec" is the DNSSEC state of the zone ("on" or "off"), which has
+//     its own API.
+//   - "hsts" is the strict_transport_security part of "security_header".
+//
+// Only the fields of an object that are in dnsconfig.js are compared, so
+// {hsts: {enabled: true}} leaves the other HSTS fields alone.
+
+const (
+	zoneSettingDNSSEC = "dnssec"
+	zoneSettingHSTS   = "hsts"
+)
+
+// zoneSettingValues lists the values that are accepted for some well-known
+// settings. Other settings are passed to Cloudflare as they are.
+var zoneSettingValues = map[string][]string{
+	"ssl":                      {"off", "flexible", "full", "strict"},
+	"min_tls_version":          {"1.0", "1.1", "1.2", "1.3"},
+	"always_use_https":         {"on", "off"},
+	"ipv6":                     {"on", "off"},
+	"cname_flattening":         {"flatten_at_root", "flatten_all"},
+	zoneSettingDNSSEC:          {"on", "off"},
+	"tls_1_3":                  {"on", "off", "zrt"},
+	"automatic_https_rewrites": {"on", "off"},
+}
+
+// exportedZoneSettings are the settings that get-zones outputs.
+var exportedZoneSettings = []string{"always_use_https", "cname_flattening", zoneSettingDNSSEC, zoneSettingHSTS, "ipv6", "min_tls_version", "ssl"}
+
+var zoneSettingName = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// parseZoneSettings returns the settings of CF_ZONE_SETTINGS, and checks
+// them.
+func parseZoneSettings(s string) (map[string]any, error) {
+	settings := map[string]any{}
+	if s == "" {
+		return settings, nil
+	}
+	if err := json.Unmarshal([]byte(s), &settings); err != nil {
+		return nil, fmt.Errorf("bad metadata value for %s: %w", metaZoneSettings, err)
+	}
+	for name, v := range settings {
+		if !zoneSettingName.MatchString(name) {
+			return nil, fmt.Errorf("CF_ZONE_SETTINGS: %q is not a Cloudflare zone setting", name)
+		}
+		if allowed, ok := zoneSettingValues[name]; ok {
+			if s, ok := v.(string); !ok || !slices.Contains(allowed, s) {
+				return nil, fmt.Errorf("CF_ZONE_SETTINGS: %s must be one of %q, got %v", name, allowed, v)
+			}
+		}
+		if _, ok := v.(map[string]any); name == zoneSettingHSTS && !ok {
+			return nil, fmt.Errorf("CF_ZONE_SETTINGS: %s must be an object, such as {enabled: true, max_age: 31536000}", name)
+		}
+	}
+	return settings, nil
+}
+
+// zoneSettingID returns the ID and value of the Cloudflare setting for
+// a CF_ZONE_SETTINGS entry.
+func zoneSettingID(name string, v any) (string, any) {
+	if name == zoneSettingHSTS {
+		return "security_header", map[string]any{"strict_transport_security": v}
+	}
+	return name, v
+}
+
+// zoneSettingMatches reports whether the actual value of a setting has the
+// desired value. For objects, only the desired fields are compared.
+func zoneSettingMatches(want, have any) bool {
+	if w, ok := want.(map[string]any); ok {
+		h, ok := have.(map[string]any)
+		if !ok {
+			return false
+		}
+		for k, v := range w {
+			if !zoneSettingMatches(v, h[k]) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(want, have)
+}
+
+// zoneSettingMerge returns have with the fields of want, which is the value
+// to send to Cloudflare.
+func zoneSettingMerge(want, have any) any {
+	w, ok := want.(map[string]any)
+	if !ok {
+		return want
+	}
+	h, ok := have.(map[string]any)
+	if !ok {
+		return want
+	}
+	merged := map[string]any{}
+	for k, v := range h {
+		merged[k] = v
+	}
+	for k, v := range w {
+		merged[k] = zoneSettingMerge(v, h[k])
+	}
+	return merged
+}
+
+// zoneSettingSubset returns the fields of have that are in want, to show
+// only what is being changed.
+func zoneSettingSubset(want, have any) any {
+	w, ok := want.(map[string]any)
+	if !ok {
+		return have
+	}
+	h, ok := have.(map[string]any)
+	if !ok {
+		return have
+	}
+	subset := map[string]any{}
+	for k, v := range w {
+		if hv, ok := h[k]; ok {
+			subset[k] = zoneSettingSubset(v, hv)
+		}
+	}
+	return subset
+}
+
+func zoneSettingString(v any) string {
+	if v == nil {
+		return "(unset)"
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+// dnssecState returns "on" or "off" for a Cloudflare DNSSEC status.
+func dnssecState(status string) string {
+	if status == "active" || status == "pending" {
+		return "on"
+	}
+	return "off"
+}
+
+// getZoneSettingsCorrections returns a correction for each setting of
+// CF_ZONE_SETTINGS that is not the same at Cloudflare.
+func (c *cloudflareProvider) getZoneSettingsCorrections(dc *models.DomainConfig, domainID string) ([]*models.Correction, error) {
+	settings, err := parseZoneSettings(dc.Metadata[metaZoneSettings])
+	if err != nil || len(settings) == 0 {
+		return nil, err
+	}
+	current, err := c.getZoneSettings(domainID)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(settings))
+	for name := range settings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var corrections []*models.Correction
+	for _, name := range names {
+		want := settings[name]
+		if name == zoneSettingDNSSEC {
+			result, err := c.cfClient.ZoneDNSSECSetting(context.Background(), domainID)
+			if err != nil {
+				return nil, fmt.Errorf("failed fetching DNSSEC state from cloudflare: %w", err)
+			}
+			if have := dnssecState(result.Status); have != want {
+				status := "active"
+				if want == "off" {
+					status = "disabled"
+				}
+				corrections = append(corrections, &models.Correction{
+					Msg: fmt.Sprintf("Zone setting %s: %q -> %q", name, have, want),
+					F: func() error {
+						_, err := c.cfClient.UpdateZoneDNSSEC(context.Background(), domainID, cloudflare.ZoneDNSSECUpdateOptions{Status: status})
+						return err
+					},
+				})
+			}
+			continue
+		}
+
+		id, want := zoneSettingID(name, want)
+		have, ok := current[id]
+		if !ok {
+			return nil, fmt.Errorf("CF_ZONE_SETTINGS: cloudflare has no zone setting %q for %s", name, dc.Name)
+		}
+		if zoneSettingMatches(want, have) {
+			continue
+		}
+		value := zoneSettingMerge(want, have)
+		corrections = append(corrections, &models.Correction{
+			Msg: fmt.Sprintf("Zone setting %s: %s -> %s", name, zoneSettingString(zoneSettingSubset(want, have)), zoneSettingString(zoneSettingSubset(want, value))),
+			F: func() error {
+				_, err := c.cfClient.UpdateZoneSetting(context.Background(), cloudflare.ZoneIdentifier(domainID), cloudflare.UpdateZoneSettingParams{Name: id, Value: value})
+				return err
+			},
+		})
+	}
+	return corrections, nil
+}
+
+// getZoneSettings returns the value of each setting of the zone.
+func (c *cloudflareProvider) getZoneSettings(domainID string) (map[string]any, error) {
+	result, err := c.cfClient.ZoneSettings(context.Background(), domainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed fetching zone settings from cloudflare: %w", err)
+	}
+	return zoneSettingsByID(result.Result)
+}
+
+// zoneSettingsByID returns the value of each setting. The values are
+// decoded again from JSON, so that they compare equal to the ones from
+// dnsconfig.js.
+func zoneSettingsByID(list []cloudflare.ZoneSetting) (map[string]any, error) {
+	b, err := json.Marshal(list)
+	if err != nil {
+		return nil, err
+	}
+	var decoded []struct {
+		ID    string `json:"id"`
+		Value any    `json:"value"`
+	}
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		return nil, err
+	}
+	settings := map[string]any{}
+	for _, s := range decoded {
+		settings[s.ID] = s.Value
+	}
+	return settings, nil
+}
+
+// exportZoneSettings returns the CF_ZONE_SETTINGS() of the well-known
+// settings.
+func exportZoneSettings(current map[string]any, dnssecStatus string) string {
+	settings := map[string]any{zoneSettingDNSSEC: dnssecState(dnssecStatus)}
+	for _, name := range exportedZoneSettings {
+		if name == zoneSettingDNSSEC {
+			continue
+		}
+		id, _ := zoneSettingID(name, nil)
+		v, ok := current[id]
+		if !ok {
+			continue
+		}
+		if name == zoneSettingHSTS {
+			if h, ok := v.(map[string]any); ok {
+				v = h["strict_transport_security"]
+			}
+		}
+		if v != nil {
+			settings[name] = v
+		}
+	}
+	return "CF_ZONE_SETTINGS(" + zoneSettingString(settings) + ")"
+}
+
+// ExportZoneSettings returns the domain modifiers for the zone settings
+// of zone (get-zones).
+func (c *cloudflareProvider) ExportZoneSettings(zone string) ([]string, error) {
+	domainID, err := c.getDomainID(zone)
+	if err != nil {
+		return nil, err
+	}
+	current, err := c.getZoneSettings(domainID)
+	if err != nil {
+		return nil, err
+	}
+	result, err := c.cfClient.ZoneDNSSECSetting(context.Background(), domainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed fetching DNSSEC state from cloudflare: %w", err)
+	}
+	return []string{exportZoneSettings(current, result.Status)}, nil
+}
@@ -0,0 +1,74 @@
+package cloudflare
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cloudflare/cloudflare-go"
+)
+
+func TestParseZoneSettings(t *testing.T) {
+	tests := []struct {
+		meta    string
+		wantErr string
+	}{
+		{meta: ``},
+		{meta: `{"ssl":"strict","min_tls_version":"1.2","hsts":{"enabled":true},"browser_cache_ttl":14400}`},
+		{meta: `{"ssl":"on"}`, wantErr: `ssl must be one of`},
+		{meta: `{"min_tls_version":1.2}`, wantErr: `min_tls_version must be one of`},
+		{meta: `{"hsts":"on"}`, wantErr: `hsts must be an object`},
+		{meta: `{"Always Use HTTPS":"on"}`, wantErr: `is not a Cloudflare zone setting`},
+		{meta: `{`, wantErr: `bad metadata value`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.meta, func(t *testing.T) {
+			_, err := parseZoneSettings(tt.meta)
+			if tt.wantErr == "" && err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("got error %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestZoneSettingValues(t *testing.T) {
+	settings, err := zoneSettingsByID([]cloudflare.ZoneSetting{
+		{ID: "ssl", Value: "full"},
+		{ID: "browser_cache_ttl", Value: 14400},
+		{ID: "security_header", Value: map[string]any{"strict_transport_security": map[string]any{
+			"enabled": false, "max_age": 0, "include_subdomains": false, "preload": false, "nosniff": false,
+		}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wanted, err := parseZoneSettings(`{"ssl":"strict","browser_cache_ttl":14400,"hsts":{"enabled":true,"max_age":31536000}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, wantMatch := range map[string]bool{"ssl": false, "browser_cache_ttl": true, "hsts": false} {
+		id, want := zoneSettingID(name, wanted[name])
+		if got := zoneSettingMatches(want, settings[id]); got != wantMatch {
+			t.Errorf("%s: zoneSettingMatches = %v, want %v", name, got, wantMatch)
+		}
+	}
+
+	id, want := zoneSettingID("hsts", wanted["hsts"])
+	merged := zoneSettingMerge(want, settings[id])
+	if got, w := zoneSettingString(merged), `{"strict_transport_security":{"enabled":true,"include_subdomains":false,"max_age":31536000,"nosniff":false,"preload":false}}`; got != w {
+		t.Errorf("zoneSettingMerge:\ngot  %s\nwant %s", got, w)
+	}
+	if !zoneSettingMatches(want, merged) {
+		t.Error("the merged value does not match")
+	}
+	if got, w := zoneSettingString(zoneSettingSubset(want, settings[id])), `{"strict_transport_security":{"enabled":false,"max_age":0}}`; got != w {
+		t.Errorf("zoneSettingSubset:\ngot  %s\nwant %s", got, w)
+	}
+
+	if got, w := exportZoneSettings(settings, "active"), `CF_ZONE_SETTINGS({"dnssec":"on","hsts":{"enabled":false,"include_subdomains":false,"max_age":0,"nosniff":false,"preload":false},"ssl":"full"})`; got != w {
+		t.Errorf("exportZoneSettings:\ngot  %s\nwant %s", got, w)
+	}
+}
@@ -0,0 +1,75 @@
+package cloudflare
+
+import (
+	"errors"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/DNSControl/dnscontrol/v4/models"
+	"github.com/DNSControl/dnscontrol/v4/pkg/diff2"
+	"github.com/DNSControl/dnscontrol/v4/pkg/zonecache"
+	dnsv1 "github.com/miekg/dns"
+)
+
+// soaTimeout is how long GetZoneVersion waits for each nameserver.
+const soaTimeout = 5 * time.Second
+
+// GetZoneVersion implements providers.ZoneVersioner with the SOA serial
+// that the Cloudflare nameservers of the zone serve. Cloudflare increases
+// it with every change of the DNS records (the modified_on time of the zone
+// does not change with them). The serial does not cover single redirects,
+// worker routes and load balancers, so if any of them are managed the zone
+// is not versioned. Neither is it if no nameserver answers.
+func (c *cloudflareProvider) GetZoneVersion(dc *models.DomainConfig) (string, error) {
+	if c.manageSingleRedirects || c.manageWorkers || c.manageLoadBalancers {
+		return "", nil
+	}
+	z, err := c.zoneCache.GetZone(dc.Name)
+	if errors.Is(err, zonecache.ErrZoneNotFound) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+	for _, ns := range z.NameServers {
+		if serial, ok := querySerial(dc.Name, net.JoinHostPort(ns, "53")); ok {
+			return strconv.FormatUint(uint64(serial), 10), nil
+		}
+	}
+	return "", nil
+}
+
+// querySerial asks the nameserver at addr for the SOA serial of zone.
+func querySerial(zone, addr string) (uint32, bool) {
+	msg := new(dnsv1.Msg)
+	msg.SetQuestion(dnsv1.Fqdn(zone), dnsv1.TypeSOA)
+	msg.RecursionDesired = false
+	client := &dnsv1.Client{Timeout: soaTimeout}
+	resp, _, err := client.Exchange(msg, addr)
+	if err != nil || resp.Rcode != dnsv1.RcodeSuccess || !resp.Authoritative {
+		return 0, false
+	}
+	for _, rr := range resp.Answer {
+		if soa, ok := rr.(*dnsv1.SOA); ok {
+			return soa.Serial, true
+		}
+	}
+	return 0, false
+}
+
+// needsOriginals reports whether instructions change or delete records
+// without an Original, which the corrections need for the IDs of the
+// records. The records from the zone cache (see GetZoneVersion) have none.
+func needsOriginals(instructions diff2.ChangeList) bool {
+	for _, inst := range instructions {
+		if inst.Type != diff2.CHANGE && inst.Type != diff2.DELETE {
+			continue
+		}
+		for _, rec := range inst.Old {
+			if rec.Original == nil {
+				return true
+			}
+		}
+	}
+	return false
+}
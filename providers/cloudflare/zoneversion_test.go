@@ -0,0 +1,58 @@
+package cloudflare
+
+import (
+	"net"
+	"testing"
+
+	"github.com/DNSControl/dnscontrol/v4/models"
+	"github.com/DNSControl/dnscontrol/v4/pkg/diff2"
+	"github.com/cloudflare/cloudflare-go"
+	dnsv1 "github.com/miekg/dns"
+)
+
+func TestQuerySerial(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip(err)
+	}
+	server := &dnsv1.Server{PacketConn: pc, Handler: dnsv1.HandlerFunc(func(w dnsv1.ResponseWriter, req *dnsv1.Msg) {
+		resp := new(dnsv1.Msg)
+		resp.SetReply(req)
+		if req.Question[0].Name == "example.com." {
+			resp.Authoritative = true
+			soa, _ := dnsv1.NewRR("example.com. 3600 IN SOA ns.cloudflare.com. dns.cloudflare.com. 2345678901 10000 2400 604800 1800")
+			resp.Answer = append(resp.Answer, soa)
+		} else {
+			resp.Rcode = dnsv1.RcodeRefused
+		}
+		w.WriteMsg(resp)
+	})}
+	go server.ActivateAndServe()
+	defer server.Shutdown()
+
+	if serial, ok := querySerial("example.com", pc.LocalAddr().String()); !ok || serial != 2345678901 {
+		t.Errorf("example.com: got %d, %v", serial, ok)
+	}
+	if _, ok := querySerial("example.net", pc.LocalAddr().String()); ok {
+		t.Error("example.net: got a serial for a refused query")
+	}
+}
+
+func TestNeedsOriginals(t *testing.T) {
+	cached := &models.RecordConfig{Type: "A"}
+	downloaded := &models.RecordConfig{Type: "A", Original: cloudflare.DNSRecord{ID: "1"}}
+
+	for _, tst := range []struct {
+		inst diff2.Change
+		want bool
+	}{
+		{diff2.Change{Type: diff2.CREATE, New: models.Records{cached}}, false},
+		{diff2.Change{Type: diff2.CHANGE, Old: models.Records{downloaded}}, false},
+		{diff2.Change{Type: diff2.CHANGE, Old: models.Records{cached}}, true},
+		{diff2.Change{Type: diff2.DELETE, Old: models.Records{cached}}, true},
+	} {
+		if got := needsOriginals(diff2.ChangeList{tst.inst}); got != tst.want {
+			t.Errorf("%v of a record with Original %v: got %v, want %v", tst.inst.Type, tst.inst.Old, got, tst.want)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package desec
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/DNSControl/dnscontrol/v4/pkg/dnssec"
+)
+
+// DNSSECKeys returns the keys that deSEC signs the zone with. deSEC manages
+// the keys itself, so they are all reported as active.
+func (c *desecProvider) DNSSECKeys(zone string) ([]dnssec.Key, error) {
+	body, _, err := c.get(fmt.Sprintf("/domains/%s/", zone), "GET")
+	if err != nil {
+		return nil, fmt.Errorf("failed fetching domain %s (deSEC): %w", zone, err)
+	}
+	var dm domainObject
+	if err := json.Unmarshal(body, &dm); err != nil {
+		return nil, err
+	}
+	var keys []dnssec.Key
+	for _, dk := range dm.Keys {
+		k := dnssec.Key{
+			Role:      dnssec.Role(strings.ToUpper(dk.Keytype)),
+			DS:        dk.Ds,
+			Published: dnssec.Unknown,
+			Activated: dnssec.Unknown,
+		}
+		if err := k.ParseDNSKEY(dk.Dnskey); err != nil {
+			return nil, err
+		}
+		k.ID = strconv.Itoa(int(k.KeyTag))
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
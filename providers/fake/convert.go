@@ -0,0 +1,47 @@
+package fake
+
+// Convert between the store's records and models.RecordConfig.
+
+import (
+	"fmt"
+
+	"github.com/DNSControl/dnscontrol/v4/models"
+	"github.com/DNSControl/dnscontrol/v4/pkg/domaintags"
+	"github.com/DNSControl/dnscontrol/v4/pkg/rtypecontrol"
+	"github.com/DNSControl/dnscontrol/v4/pkg/rtypeinfo"
+	"github.com/DNSControl/dnscontrol/v4/pkg/txtutil"
+)
+
+// toRecordConfig converts a stored record to a RecordConfig.
+func toRecordConfig(fr fakeRecord, origin string) (*models.RecordConfig, error) {
+	var rc *models.RecordConfig
+	var err error
+
+	if rtypeinfo.IsModernType(fr.Type) {
+		rc, err = rtypecontrol.NewRecordConfigFromString(fr.Name, fr.TTL, fr.Type, fr.Content, domaintags.MakeDomainNameVarieties(origin))
+	} else {
+		rc = &models.RecordConfig{TTL: fr.TTL}
+		rc.SetLabel(fr.Name, origin)
+		err = rc.PopulateFromStringFunc(fr.Type, fr.Content, origin, txtutil.ParseQuoted)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("fake: unparsable record %s %s %q: %w", fr.Name, fr.Type, fr.Content, err)
+	}
+	rc.Original = fr
+	return rc, nil
+}
+
+// toFakeRecord converts a RecordConfig to the form held by the store. The ID
+// is left for the caller to fill in.
+func toFakeRecord(rc *models.RecordConfig) fakeRecord {
+	content := rc.GetTargetCombinedFunc(txtutil.EncodeQuoted)
+	if rc.IsModernType() {
+		content = rc.ZonefilePartial
+	}
+	return fakeRecord{
+		Name:    rc.GetLabel(),
+		Type:    rc.Type,
+		TTL:     rc.TTL,
+		Content: content,
+	}
+}
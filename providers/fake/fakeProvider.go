@@ -0,0 +1,296 @@
+package fake
+
+/*
+
+fake -
+  An in-memory DNS provider for testing.
+
+	Zones are kept in memory and optionally persisted to a JSON file.
+	The provider's capabilities, and the failures it should simulate
+	(errors, latency, eventually consistent reads), are set in creds.json.
+	This lets the integration tests and the preview/push pipeline be
+	exercised end-to-end without a real DNS service.
+
+*/
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/DNSControl/dnscontrol/v4/models"
+	"github.com/DNSControl/dnscontrol/v4/pkg/diff2"
+	"github.com/DNSControl/dnscontrol/v4/pkg/providers"
+)
+
+const providerName = "FAKE"
+
+var features = providers.DocumentationNotes{
+	// The default for unlisted capabilities is 'Cannot'.
+	// See providers/capabilities.go for the entire list of capabilities.
+	// These are the defaults. They may be changed with the "capabilities" field in creds.json.
+	providers.CanConcur:              providers.Can(),
+	providers.CanGetZones:            providers.Can(),
+	providers.CanUseCAA:              providers.Can(),
+	providers.CanUseDHCID:            providers.Can(),
+	providers.CanUseDNAME:            providers.Can(),
+	providers.CanUseDNSKEY:           providers.Can(),
+	providers.CanUseDS:               providers.Can(),
+	providers.CanUseHTTPS:            providers.Can(),
+	providers.CanUseLOC:              providers.Can(),
+	providers.CanUseNAPTR:            providers.Can(),
+	providers.CanUseOPENPGPKEY:       providers.Can(),
+	providers.CanUsePTR:              providers.Can(),
+	providers.CanUseRP:               providers.Can(),
+	providers.CanUseSMIMEA:           providers.Can(),
+	providers.CanUseSRV:              providers.Can(),
+	providers.CanUseSSHFP:            providers.Can(),
+	providers.CanUseSVCB:             providers.Can(),
+	providers.CanUseTLSA:             providers.Can(),
+	providers.DocCreateDomains:       providers.Can(),
+	providers.DocDualHost:            providers.Can(),
+	providers.DocOfficiallySupported: providers.Cannot("For testing only"),
+}
+
+func init() {
+	const providerMaintainer = "@TomOnTime"
+	fns := providers.DspFuncs{
+		Initializer:   newFake,
+		RecordAuditor: AuditRecords,
+	}
+	providers.RegisterDomainServiceProviderType(providerName, fns, features)
+	providers.RegisterMaintainer(providerName, providerMaintainer)
+	providers.RegisterCredsMetadata(providerName, providers.CredsMetadata{
+		DisplayName: "Fake (in-memory, for testing)",
+		Kind:        providers.KindDNS,
+		DocsURL:     "https://docs.dnscontrol.org/provider/fake",
+		Notes:       "FAKE stores zones in memory (or a JSON file) and never contacts a real DNS service.",
+		Fields: []providers.CredsField{
+			{
+				Key:   "file",
+				Label: "State file",
+				Help:  "Optional JSON file where zones are persisted between runs. Leave empty to keep zones in memory.",
+			},
+			{
+				Key:   "store",
+				Label: "Store name",
+				Help:  "Optional name of the in-memory store. Entries with the same store (or the same file) share their zones.",
+			},
+		},
+	})
+}
+
+// fakeProvider is the provider handle for the FAKE driver.
+type fakeProvider struct {
+	store        *store
+	faults       faults
+	nameservers  []*models.Nameserver
+	capabilities map[providers.Capability]bool // Overrides of the defaults in features.
+}
+
+func newFake(config map[string]string, _ json.RawMessage) (providers.DNSServiceProvider, error) {
+	f, err := parseFaults(config["fail"], config["latency"])
+	if err != nil {
+		return nil, fmt.Errorf("fake: %w", err)
+	}
+
+	var delay time.Duration
+	if v := config["consistency_delay"]; v != "" {
+		if delay, err = time.ParseDuration(v); err != nil {
+			return nil, fmt.Errorf("fake: invalid consistency_delay %q: %w", v, err)
+		}
+	}
+
+	capabilities, err := parseCapabilities(config["capabilities"])
+	if err != nil {
+		return nil, fmt.Errorf("fake: %w", err)
+	}
+
+	key := config["store"]
+	if key == "" {
+		key = config["file"]
+	}
+	s, err := getStore(key, config["file"])
+	if err != nil {
+		return nil, err
+	}
+	s.delay = delay
+
+	// Zones listed in "zones" are created up front (and are immediately
+	// visible) so that tests that don't create zones can use them.
+	for zone := range strings.SplitSeq(config["zones"], ",") {
+		if zone = strings.ToLower(strings.TrimSpace(zone)); zone != "" {
+			s.preload(zone)
+		}
+	}
+
+	var nss []string
+	for ns := range strings.SplitSeq(config["nameservers"], ",") {
+		if ns = strings.TrimSpace(ns); ns != "" {
+			nss = append(nss, ns)
+		}
+	}
+	nameservers, err := models.ToNameservers(nss)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fakeProvider{
+		store:        s,
+		faults:       f,
+		nameservers:  nameservers,
+		capabilities: capabilities,
+	}, nil
+}
+
+// parseCapabilities parses the "capabilities" field from creds.json. It is a
+// comma-separated list of capability names such as "CanUseAlias". A name
+// prefixed with "-" removes the capability.
+func parseCapabilities(list string) (map[providers.Capability]bool, error) {
+	capabilities := map[providers.Capability]bool{}
+	for item := range strings.SplitSeq(list, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name, remove := strings.CutPrefix(item, "-")
+		capa, err := providers.ParseCapability(name)
+		if err != nil {
+			return nil, err
+		}
+		capabilities[capa] = !remove
+	}
+	return capabilities, nil
+}
+
+// OverrideCapability implements providers.CapabilityOverrider with the
+// "capabilities" field of this entry in creds.json.
+func (c *fakeProvider) OverrideCapability(capa providers.Capability) (bool, bool) {
+	value, ok := c.capabilities[capa]
+	return value, ok
+}
+
+// GetNameservers returns the nameservers for a domain.
+func (c *fakeProvider) GetNameservers(domain string) ([]*models.Nameserver, error) {
+	if err := c.faults.call("GetNameservers", domain); err != nil {
+		return nil, err
+	}
+	return slices.Clone(c.nameservers), nil
+}
+
+// ListZones returns all the zones in an account.
+func (c *fakeProvider) ListZones() ([]string, error) {
+	if err := c.faults.call("ListZones", ""); err != nil {
+		return nil, err
+	}
+	return c.store.listZones(), nil
+}
+
+// EnsureZoneExists creates a zone if it does not exist.
+func (c *fakeProvider) EnsureZoneExists(domain string, _ map[string]string) error {
+	if err := c.faults.call("EnsureZoneExists", domain); err != nil {
+		return err
+	}
+	return c.store.createZone(domain)
+}
+
+// DeleteZone deletes a zone.
+func (c *fakeProvider) DeleteZone(domain string) error {
+	if err := c.faults.call("DeleteZone", domain); err != nil {
+		return err
+	}
+	return c.store.deleteZone(domain)
+}
+
+// GetZoneRecords gets the records of a zone and returns them in RecordConfig format.
+func (c *fakeProvider) GetZoneRecords(dc *models.DomainConfig) (models.Records, error) {
+	if err := c.faults.call("GetZoneRecords", dc.Name); err != nil {
+		return nil, err
+	}
+	frs, ok := c.store.getRecords(dc.Name)
+	if !ok {
+		return nil, fmt.Errorf("zone %q does not exist", dc.Name)
+	}
+
+	existingRecords := make(models.Records, 0, len(frs))
+	for _, fr := range frs {
+		rc, err := toRecordConfig(fr, dc.Name)
+		if err != nil {
+			return nil, err
+		}
+		existingRecords = append(existingRecords, rc)
+	}
+	return existingRecords, nil
+}
+
+// GetZoneRecordsCorrections returns a list of corrections that will turn existing records into dc.Records.
+func (c *fakeProvider) GetZoneRecordsCorrections(dc *models.DomainConfig, existingRecords models.Records) ([]*models.Correction, int, error) {
+	changes, actualChangeCount, err := diff2.ByRecord(existingRecords, dc, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var corrections []*models.Correction
+	for _, change := range changes {
+		var corr *models.Correction
+		switch change.Type {
+		case diff2.REPORT:
+			corr = change.CreateMessage()
+		case diff2.CREATE:
+			n := toFakeRecord(change.New[0])
+			corr = change.CreateCorrection(func() error { return c.createRecord(dc.Name, n) })
+		case diff2.CHANGE:
+			id := change.Old[0].Original.(fakeRecord).ID
+			n := toFakeRecord(change.New[0])
+			corr = change.CreateCorrection(func() error { return c.modifyRecord(dc.Name, id, n) })
+		case diff2.DELETE:
+			id := change.Old[0].Original.(fakeRecord).ID
+			corr = change.CreateCorrection(func() error { return c.deleteRecord(dc.Name, id) })
+		default:
+			panic(fmt.Sprintf("unhandled change.Type %s", change.Type))
+		}
+		corrections = append(corrections, corr)
+	}
+
+	return corrections, actualChangeCount, nil
+}
+
+func (c *fakeProvider) createRecord(zone string, n fakeRecord) error {
+	if err := c.faults.call("CreateRecord", zone); err != nil {
+		return err
+	}
+	return c.store.update(zone, func(recs []fakeRecord) ([]fakeRecord, error) {
+		n.ID = c.store.nextID()
+		return append(recs, n), nil
+	})
+}
+
+func (c *fakeProvider) modifyRecord(zone string, id uint64, n fakeRecord) error {
+	if err := c.faults.call("ModifyRecord", zone); err != nil {
+		return err
+	}
+	return c.store.update(zone, func(recs []fakeRecord) ([]fakeRecord, error) {
+		i := slices.IndexFunc(recs, func(r fakeRecord) bool { return r.ID == id })
+		if i == -1 {
+			return nil, fmt.Errorf("record %d not found in zone %q", id, zone)
+		}
+		n.ID = id
+		recs[i] = n
+		return recs, nil
+	})
+}
+
+func (c *fakeProvider) deleteRecord(zone string, id uint64) error {
+	if err := c.faults.call("DeleteRecord", zone); err != nil {
+		return err
+	}
+	return c.store.update(zone, func(recs []fakeRecord) ([]fakeRecord, error) {
+		i := slices.IndexFunc(recs, func(r fakeRecord) bool { return r.ID == id })
+		if i == -1 {
+			return nil, fmt.Errorf("record %d not found in zone %q", id, zone)
+		}
+		return slices.Delete(recs, i, i+1), nil
+	})
+}
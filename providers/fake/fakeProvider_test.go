@@ -0,0 +1,175 @@
+package fake
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/DNSControl/dnscontrol/v4/models"
+	"github.com/DNSControl/dnscontrol/v4/pkg/providers"
+)
+
+func mustNew(t *testing.T, config map[string]string) *fakeProvider {
+	t.Helper()
+	p, err := newFake(config, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p.(*fakeProvider)
+}
+
+func makeDC(name string, recs ...*models.RecordConfig) *models.DomainConfig {
+	dc := &models.DomainConfig{Name: name, Records: recs}
+	dc.PostProcess()
+	return dc
+}
+
+func makeA(label, ip string) *models.RecordConfig {
+	rc := &models.RecordConfig{Type: "A", TTL: 300}
+	rc.SetLabel(label, "example.com")
+	rc.MustSetTarget(ip)
+	return rc
+}
+
+// push runs all the corrections needed to make the zone match dc.
+func push(t *testing.T, p *fakeProvider, dc *models.DomainConfig) int {
+	t.Helper()
+	existing, err := p.GetZoneRecords(dc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	corrections, count, err := p.GetZoneRecordsCorrections(dc, existing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range corrections {
+		if c.F == nil {
+			continue
+		}
+		if err := c.F(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return count
+}
+
+func TestFake_RoundTrip(t *testing.T) {
+	p := mustNew(t, map[string]string{"zones": "example.com"})
+
+	if n := push(t, p, makeDC("example.com", makeA("www", "1.2.3.4"), makeA("@", "1.2.3.5"))); n != 2 {
+		t.Errorf("first push: got %d changes, want 2", n)
+	}
+	if n := push(t, p, makeDC("example.com", makeA("www", "1.2.3.4"), makeA("@", "1.2.3.6"))); n != 1 {
+		t.Errorf("second push: got %d changes, want 1", n)
+	}
+	if n := push(t, p, makeDC("example.com", makeA("www", "1.2.3.4"), makeA("@", "1.2.3.6"))); n != 0 {
+		t.Errorf("third push: got %d changes, want 0", n)
+	}
+	if n := push(t, p, makeDC("example.com")); n != 2 {
+		t.Errorf("fourth push: got %d changes, want 2", n)
+	}
+}
+
+func TestFake_File(t *testing.T) {
+	fname := filepath.Join(t.TempDir(), "fake.json")
+
+	p := mustNew(t, map[string]string{"file": fname, "store": "TestFake_File_1"})
+	if err := p.EnsureZoneExists("example.com", nil); err != nil {
+		t.Fatal(err)
+	}
+	push(t, p, makeDC("example.com", makeA("www", "1.2.3.4")))
+
+	// A new store reading the same file sees the same data.
+	p2 := mustNew(t, map[string]string{"file": fname, "store": "TestFake_File_2"})
+	recs, err := p2.GetZoneRecords(makeDC("example.com"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recs) != 1 || recs[0].GetTargetField() != "1.2.3.4" {
+		t.Errorf("got %v, want one A record", recs)
+	}
+}
+
+func TestFake_Faults(t *testing.T) {
+	p := mustNew(t, map[string]string{
+		"zones": "example.com,example.net",
+		"fail":  "GetZoneRecords:example.net, ListZones",
+	})
+
+	if _, err := p.GetZoneRecords(makeDC("example.com")); err != nil {
+		t.Errorf("example.com: unexpected error %v", err)
+	}
+	if _, err := p.GetZoneRecords(makeDC("example.net")); !errors.Is(err, errInjected) {
+		t.Errorf("example.net: got %v, want injected failure", err)
+	}
+	if _, err := p.ListZones(); !errors.Is(err, errInjected) {
+		t.Errorf("ListZones: got %v, want injected failure", err)
+	}
+
+	if _, err := newFake(map[string]string{"fail": "Bogus"}, nil); err == nil {
+		t.Error("expected error for unknown method")
+	}
+}
+
+func TestFake_ConsistencyDelay(t *testing.T) {
+	p := mustNew(t, map[string]string{"consistency_delay": "1m"})
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	p.store.now = func() time.Time { return now }
+
+	if err := p.EnsureZoneExists("example.com", nil); err != nil {
+		t.Fatal(err)
+	}
+	if zones, _ := p.ListZones(); len(zones) != 0 {
+		t.Errorf("new zone visible too early: %v", zones)
+	}
+
+	now = now.Add(time.Minute)
+	if zones, _ := p.ListZones(); len(zones) != 1 {
+		t.Errorf("new zone not visible after the delay: %v", zones)
+	}
+}
+
+func TestFake_DeleteZone(t *testing.T) {
+	p := mustNew(t, map[string]string{"zones": "example.com,example.net"})
+
+	if err := p.DeleteZone("example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if zones, _ := p.ListZones(); len(zones) != 1 || zones[0] != "example.net" {
+		t.Errorf("ListZones: got %v, want [example.net]", zones)
+	}
+	if _, err := p.GetZoneRecords(makeDC("example.com")); err == nil {
+		t.Error("deleted zone is still readable")
+	}
+	// Deleting a missing zone is not an error.
+	if err := p.DeleteZone("example.com"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestFake_Capabilities(t *testing.T) {
+	// Each entry in creds.json has its own capabilities.
+	a := mustNew(t, map[string]string{"capabilities": "CanUseAlias,-CanUseLOC"})
+	b := mustNew(t, map[string]string{"capabilities": "-CanUseAlias"})
+
+	for _, tst := range []struct {
+		p    *fakeProvider
+		capa providers.Capability
+		want bool
+	}{
+		{a, providers.CanUseAlias, true},
+		{a, providers.CanUseLOC, false},
+		{a, providers.CanUseCAA, true}, // The default of the type.
+		{b, providers.CanUseAlias, false},
+		{b, providers.CanUseLOC, true},
+	} {
+		if got := providers.InstanceHasCapability(providerName, tst.p, tst.capa); got != tst.want {
+			t.Errorf("%v: got %v, want %v", tst.capa, got, tst.want)
+		}
+	}
+
+	if _, err := newFake(map[string]string{"capabilities": "CanFly"}, nil); err == nil {
+		t.Error("expected an error for an unknown capability")
+	}
+}
@@ -0,0 +1,85 @@
+package fake
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// The API calls that can be made to fail. The names match the methods
+// of the provider that perform them.
+var faultMethods = []string{
+	"GetNameservers",
+	"GetZoneRecords",
+	"ListZones",
+	"EnsureZoneExists",
+	"DeleteZone",
+	"CreateRecord",
+	"ModifyRecord",
+	"DeleteRecord",
+}
+
+// errInjected is wrapped by every error returned because of a fault
+// configured in creds.json.
+var errInjected = errors.New("injected failure")
+
+// fault describes a call that should fail.
+type fault struct {
+	method string
+	zone   string // If empty, the call fails for every zone.
+}
+
+// faults is the failure-injection configuration of a provider instance.
+type faults struct {
+	latency time.Duration
+	fail    []fault
+}
+
+// parseFaults parses the "fail" and "latency" fields from creds.json.
+//
+// "fail" is a comma-separated list of METHOD or METHOD:ZONE items.
+// "latency" is a duration such as "250ms".
+func parseFaults(fail, latency string) (faults, error) {
+	var f faults
+
+	if latency != "" {
+		d, err := time.ParseDuration(latency)
+		if err != nil {
+			return f, fmt.Errorf("invalid latency %q: %w", latency, err)
+		}
+		f.latency = d
+	}
+
+	for item := range strings.SplitSeq(fail, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		method, zone, _ := strings.Cut(item, ":")
+		if !slices.Contains(faultMethods, method) {
+			return f, fmt.Errorf("invalid fail item %q: method must be one of %s", item, strings.Join(faultMethods, ", "))
+		}
+		f.fail = append(f.fail, fault{method: method, zone: strings.ToLower(zone)})
+	}
+
+	return f, nil
+}
+
+// call simulates the overhead of an API call. It sleeps for the configured
+// latency, then returns an error if the call was configured to fail.
+func (f faults) call(method, zone string) error {
+	if f.latency > 0 {
+		time.Sleep(f.latency)
+	}
+	for _, x := range f.fail {
+		if x.method == method && (x.zone == "" || x.zone == zone) {
+			if zone == "" {
+				return fmt.Errorf("%s: %w", method, errInjected)
+			}
+			return fmt.Errorf("%s(%s): %w", method, zone, errInjected)
+		}
+	}
+	return nil
+}
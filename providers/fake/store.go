@@ -0,0 +1,249 @@
+package fake
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+// fakeRecord is how the store holds a record. The rdata is kept as a string
+// so that the store is independent of models.RecordConfig and can be
+// serialized to a JSON file as-is.
+type fakeRecord struct {
+	ID      uint64 `json:"id"`
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	TTL     uint32 `json:"ttl"`
+	Content string `json:"content"`
+}
+
+// zoneVersion is the content of a zone as of a point in time. A nil records
+// list means the zone did not exist at that time.
+type zoneVersion struct {
+	at      time.Time
+	records []fakeRecord
+}
+
+// store is the "server side" of the FAKE provider. It keeps a history of
+// every zone so that reads can be served from a stale version, simulating a
+// provider with eventually consistent reads.
+type store struct {
+	mu       sync.Mutex
+	filename string // If non-empty, the latest state is persisted here.
+	delay    time.Duration
+	now      func() time.Time
+	lastID   uint64
+	zones    map[string][]zoneVersion
+}
+
+// storeFile is the on-disk format of a store.
+type storeFile struct {
+	Zones map[string][]fakeRecord `json:"zones"`
+}
+
+var (
+	sharedMu     sync.Mutex
+	sharedStores = map[string]*store{}
+)
+
+// getStore returns the store named key, creating it if needed. Instances of
+// the provider that use the same key share their data. An empty key always
+// returns a new, private store.
+func getStore(key string, filename string) (*store, error) {
+	if key == "" {
+		return newStore(filename)
+	}
+	sharedMu.Lock()
+	defer sharedMu.Unlock()
+	if s, ok := sharedStores[key]; ok {
+		return s, nil
+	}
+	s, err := newStore(filename)
+	if err != nil {
+		return nil, err
+	}
+	sharedStores[key] = s
+	return s, nil
+}
+
+func newStore(filename string) (*store, error) {
+	s := &store{
+		filename: filename,
+		now:      time.Now,
+		zones:    map[string][]zoneVersion{},
+	}
+	if filename == "" {
+		return s, nil
+	}
+
+	content, err := os.ReadFile(filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("fake: reading %q: %w", filename, err)
+	}
+	var sf storeFile
+	if err := json.Unmarshal(content, &sf); err != nil {
+		return nil, fmt.Errorf("fake: parsing %q: %w", filename, err)
+	}
+	for zone, recs := range sf.Zones {
+		if recs == nil {
+			recs = []fakeRecord{}
+		}
+		for _, r := range recs {
+			s.lastID = max(s.lastID, r.ID)
+		}
+		// Data loaded from the file is visible immediately.
+		s.zones[zone] = []zoneVersion{{records: recs}}
+	}
+	return s, nil
+}
+
+// visible returns the version of zone that a reader sees now.
+func (s *store) visible(zone string) []fakeRecord {
+	versions := s.zones[zone]
+	cutoff := s.now().Add(-s.delay)
+	for i := len(versions) - 1; i >= 0; i-- {
+		if !versions[i].at.After(cutoff) {
+			return versions[i].records
+		}
+	}
+	return nil
+}
+
+// latest returns the most recent version of zone, regardless of whether
+// readers can see it yet.
+func (s *store) latest(zone string) []fakeRecord {
+	versions := s.zones[zone]
+	if len(versions) == 0 {
+		return nil
+	}
+	return versions[len(versions)-1].records
+}
+
+// listZones returns the names of the zones readers can see now.
+func (s *store) listZones() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var names []string
+	for zone := range s.zones {
+		if s.visible(zone) != nil {
+			names = append(names, zone)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// getRecords returns a copy of the records of zone as readers see them now,
+// and whether the zone exists.
+func (s *store) getRecords(zone string) ([]fakeRecord, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	recs := s.visible(zone)
+	if recs == nil {
+		return nil, false
+	}
+	return slices.Clone(recs), true
+}
+
+// createZone creates an empty zone. It is not an error if the zone exists.
+func (s *store) createZone(zone string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.latest(zone) != nil {
+		return nil
+	}
+	return s.commit(zone, []fakeRecord{})
+}
+
+// deleteZone deletes a zone. It is not an error if the zone does not exist.
+func (s *store) deleteZone(zone string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.latest(zone) == nil {
+		return nil
+	}
+	return s.commit(zone, nil)
+}
+
+// preload creates an empty zone that is visible to readers immediately. It
+// is a no-op if the zone exists.
+func (s *store) preload(zone string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.latest(zone) == nil {
+		s.zones[zone] = []zoneVersion{{records: []fakeRecord{}}}
+	}
+}
+
+// update applies fn to the latest version of zone and stores the result as
+// a new version.
+func (s *store) update(zone string, fn func([]fakeRecord) ([]fakeRecord, error)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cur := s.latest(zone)
+	if cur == nil {
+		return fmt.Errorf("zone %q does not exist", zone)
+	}
+	next, err := fn(slices.Clone(cur))
+	if err != nil {
+		return err
+	}
+	return s.commit(zone, next)
+}
+
+// nextID returns a new unique record ID. The caller must hold s.mu.
+func (s *store) nextID() uint64 {
+	s.lastID++
+	return s.lastID
+}
+
+// commit stores a new version of zone. The caller must hold s.mu.
+func (s *store) commit(zone string, recs []fakeRecord) error {
+	now := s.now()
+	versions := append(s.zones[zone], zoneVersion{at: now, records: recs})
+
+	// Versions older than the newest visible one will never be read again.
+	cutoff := now.Add(-s.delay)
+	for len(versions) > 1 && !versions[1].at.After(cutoff) {
+		versions = versions[1:]
+	}
+	s.zones[zone] = versions
+
+	return s.save()
+}
+
+// save writes the latest version of every zone to the file (if any). The
+// caller must hold s.mu.
+func (s *store) save() error {
+	if s.filename == "" {
+		return nil
+	}
+	sf := storeFile{Zones: map[string][]fakeRecord{}}
+	for zone := range s.zones {
+		if recs := s.latest(zone); recs != nil {
+			sf.Zones[zone] = recs
+		}
+	}
+	content, err := json.MarshalIndent(sf, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(s.filename, content, 0o644); err != nil {
+		return fmt.Errorf("fake: writing %q: %w", s.filename, err)
+	}
+	return nil
+}
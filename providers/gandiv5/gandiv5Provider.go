@@ -43,7 +43,7 @@ func init() {
 		Initializer:   newDsp,
 		RecordAuditor: AuditRecords,
 	}
-	providers.RegisterDomainServiceProviderType(providerName, fns, features)
+	providers.RegisterDomainServiceProviderType(providerName, fns, features, ttlPolicy)
 	providers.RegisterRegistrarType(providerName, newReg)
 	providers.RegisterMaintainer(providerName, providerMaintainer)
 	providers.RegisterCredsMetadata(providerName, providers.CredsMetadata{
@@ -105,6 +105,9 @@ var features = providers.DocumentationNotes{
 	providers.DocOfficiallySupported: providers.Cannot(),
 }
 
+// ttlPolicy: Gandi does not support TTLs under 5 minutes or over 30 days.
+var ttlPolicy = providers.TTLPolicy{Min: 300, Max: 2592000}
+
 // DNSSEC: platform supports it, but it doesn't fit our GetDomainCorrections
 // model, so deferring for now.
 
@@ -212,21 +215,9 @@ func PrepDesiredRecords(dc *models.DomainConfig) {
 	// provider.  We try to do minimal changes otherwise it gets
 	// confusing.
 
+	aliasToCNAME(dc)
 	recordsToKeep := make([]*models.RecordConfig, 0, len(dc.Records))
 	for _, rec := range dc.Records {
-		if rec.Type == "ALIAS" && rec.Name != "@" {
-			// GANDI only permits aliases on a naked domain.
-			// Therefore, we change this to a CNAME.
-			rec.ChangeType("CNAME", dc.Name)
-		}
-		if rec.TTL < 300 {
-			printer.Warnf("Gandi does not support ttls < 300. Setting %s from %d to 300\n", rec.GetLabelFQDN(), rec.TTL)
-			rec.TTL = 300
-		}
-		if rec.TTL > 2592000 {
-			printer.Warnf("Gandi does not support ttls > 30 days. Setting %s from %d to 2592000\n", rec.GetLabelFQDN(), rec.TTL)
-			rec.TTL = 2592000
-		}
 		if rec.Type == "NS" && rec.GetLabel() == "@" {
 			if !strings.HasSuffix(rec.GetTargetField(), ".gandi.net.") {
 				printer.Warnf("Gandi does not support changing apex NS records. Ignoring %s\n", rec.GetTargetField())
@@ -238,6 +229,22 @@ func PrepDesiredRecords(dc *models.DomainConfig) {
 	dc.Records = recordsToKeep
 }
 
+// aliasToCNAME changes the ALIAS records that are not at the apex into
+// CNAMEs, since GANDI only permits aliases on a naked domain.
+func aliasToCNAME(dc *models.DomainConfig) {
+	for _, rec := range dc.Records {
+		if rec.Type == "ALIAS" && rec.Name != "@" {
+			rec.ChangeType("CNAME", dc.Name)
+		}
+	}
+}
+
+// TransformRecords implements providers.RecordTransformer.
+func (client *gandiv5Provider) TransformRecords(dc *models.DomainConfig) error {
+	aliasToCNAME(dc)
+	return nil
+}
+
 // GetZoneRecordsCorrections returns a list of corrections that will turn existing records into dc.Records.
 func (client *gandiv5Provider) GetZoneRecordsCorrections(dc *models.DomainConfig, existing models.Records) ([]*models.Correction, int, error) {
 	var corrections []*models.Correction
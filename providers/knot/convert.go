@@ -0,0 +1,48 @@
+package knot
+
+// Convert between knotc's text format and models.RecordConfig.
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/DNSControl/dnscontrol/v4/models"
+	"github.com/DNSControl/dnscontrol/v4/pkg/domaintags"
+	"github.com/DNSControl/dnscontrol/v4/pkg/rtypecontrol"
+	"github.com/DNSControl/dnscontrol/v4/pkg/rtypeinfo"
+	"github.com/DNSControl/dnscontrol/v4/pkg/txtutil"
+)
+
+// toRecordConfig converts a record read from knotc to a RecordConfig.
+func toRecordConfig(zl zoneLine, origin string) (*models.RecordConfig, error) {
+	var rc *models.RecordConfig
+	var err error
+
+	if rtypeinfo.IsModernType(zl.rtype) {
+		label := &models.RecordConfig{}
+		label.SetLabelFromFQDN(zl.owner, origin)
+		rc, err = rtypecontrol.NewRecordConfigFromString(label.Name, zl.ttl, zl.rtype, zl.rdata, domaintags.MakeDomainNameVarieties(origin))
+	} else {
+		rc = &models.RecordConfig{TTL: zl.ttl}
+		rc.SetLabelFromFQDN(zl.owner, origin)
+		err = rc.PopulateFromStringFunc(zl.rtype, zl.rdata, origin, txtutil.ParseQuoted)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("knot: unparsable record %s %s %q: %w", zl.owner, zl.rtype, zl.rdata, err)
+	}
+	rc.Original = zl
+	return rc, nil
+}
+
+// zoneSetArgs returns the arguments of "knotc zone-set" that add rc to zone.
+func zoneSetArgs(zone string, rc *models.RecordConfig) []string {
+	return []string{"zone-set", zone, rc.GetLabelFQDN() + ".", strconv.FormatUint(uint64(rc.TTL), 10), rc.Type, rdata(rc)}
+}
+
+// rdata returns the rdata of rc in zonefile format.
+func rdata(rc *models.RecordConfig) string {
+	if rc.IsModernType() {
+		return rc.ZonefilePartial
+	}
+	return rc.GetTargetCombinedFunc(txtutil.EncodeQuoted)
+}
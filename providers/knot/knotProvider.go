@@ -0,0 +1,352 @@
+package knot
+
+/*
+
+knot -
+  Manage zones on a running Knot DNS server.
+
+	Records are read with "knotc zone-read" and changed in a zone
+	transaction (zone-begin, zone-set/zone-unset, zone-commit), so that
+	each push is applied atomically. Missing zones are added to the
+	server configuration (optionally as members of a catalog zone) and
+	AUTODNSSEC toggles the zone's DNSSEC signing policy.
+
+*/
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/DNSControl/dnscontrol/v4/models"
+	"github.com/DNSControl/dnscontrol/v4/pkg/diff2"
+	"github.com/DNSControl/dnscontrol/v4/pkg/providers"
+)
+
+const providerName = "KNOT"
+
+var features = providers.DocumentationNotes{
+	// The default for unlisted capabilities is 'Cannot'.
+	// See providers/capabilities.go for the entire list of capabilities.
+	providers.CanAutoDNSSEC:          providers.Can("Sets dnssec-signing (and dnssec-policy if configured) for the zone"),
+	providers.CanConcur:              providers.Can(),
+	providers.CanGetZones:            providers.Can(),
+	providers.CanUseCAA:              providers.Can(),
+	providers.CanUseDHCID:            providers.Can(),
+	providers.CanUseDNAME:            providers.Can(),
+	providers.CanUseDS:               providers.Can(),
+	providers.CanUseHTTPS:            providers.Can(),
+	providers.CanUseLOC:              providers.Can(),
+	providers.CanUseNAPTR:            providers.Can(),
+	providers.CanUseOPENPGPKEY:       providers.Can(),
+	providers.CanUsePTR:              providers.Can(),
+	providers.CanUseRP:               providers.Can(),
+	providers.CanUseSMIMEA:           providers.Can(),
+	providers.CanUseSRV:              providers.Can(),
+	providers.CanUseSSHFP:            providers.Can(),
+	providers.CanUseSVCB:             providers.Can(),
+	providers.CanUseTLSA:             providers.Can(),
+	providers.DocCreateDomains:       providers.Can(),
+	providers.DocDualHost:            providers.Can(),
+	providers.DocOfficiallySupported: providers.Cannot(),
+	// Knot maintains the SOA serial itself, and the DNSSEC records when
+	// signing is enabled.
+	providers.CanUseSOA:    providers.Cannot(),
+	providers.CanUseDNSKEY: providers.Cannot(),
+}
+
+func init() {
+	const providerMaintainer = "NEEDS VOLUNTEER"
+	fns := providers.DspFuncs{
+		Initializer:   newKnot,
+		RecordAuditor: AuditRecords,
+	}
+	providers.RegisterDomainServiceProviderType(providerName, fns, features)
+	providers.RegisterMaintainer(providerName, providerMaintainer)
+	providers.RegisterCredsMetadata(providerName, providers.CredsMetadata{
+		DisplayName: "Knot DNS",
+		Kind:        providers.KindDNS,
+		DocsURL:     "https://docs.dnscontrol.org/provider/knot",
+		Notes:       "KNOT manages zones on a running Knot DNS server through knotc.",
+		Fields: []providers.CredsField{
+			{
+				Key:   "socket",
+				Label: "Control socket",
+				Help:  "Path of knotd's control socket. Leave empty for the default.",
+			},
+			{
+				Key:   "nameservers",
+				Label: "Nameservers",
+				Help:  "Comma-separated list of the zones' nameservers.",
+			},
+		},
+	})
+}
+
+// dnssecTypes are maintained by knotd when signing is enabled. They are
+// hidden from DNSControl.
+var dnssecTypes = []string{"CDNSKEY", "CDS", "DNSKEY", "NSEC", "NSEC3", "NSEC3PARAM", "RRSIG", "ZONEMD"}
+
+// knotProvider is the provider handle for the KNOT driver.
+type knotProvider struct {
+	ctl          controller
+	nameservers  []*models.Nameserver
+	template     string // Configuration template for new zones.
+	catalog      string // Catalog zone that new zones are members of.
+	catalogGroup string // Catalog group of new zones.
+	dnssecPolicy string // Policy used when AUTODNSSEC is on.
+
+	confMu sync.Mutex // knotd allows one configuration transaction at a time.
+}
+
+func newKnot(config map[string]string, _ json.RawMessage) (providers.DNSServiceProvider, error) {
+	path := config["knotc"]
+	if path == "" {
+		path = "knotc"
+	}
+	return newProvider(config, &knotc{
+		path:    path,
+		socket:  config["socket"],
+		timeout: config["timeout"],
+	})
+}
+
+// newProvider returns a provider that drives knotd through ctl.
+func newProvider(config map[string]string, ctl controller) (*knotProvider, error) {
+	var nss []string
+	for ns := range strings.SplitSeq(config["nameservers"], ",") {
+		if ns = strings.TrimSpace(ns); ns != "" {
+			nss = append(nss, ns)
+		}
+	}
+	nameservers, err := models.ToNameservers(nss)
+	if err != nil {
+		return nil, err
+	}
+	if config["catalog_group"] != "" && config["catalog"] == "" {
+		return nil, fmt.Errorf("knot: catalog_group requires catalog")
+	}
+
+	return &knotProvider{
+		ctl:          ctl,
+		nameservers:  nameservers,
+		template:     config["template"],
+		catalog:      strings.TrimSuffix(config["catalog"], "."),
+		catalogGroup: config["catalog_group"],
+		dnssecPolicy: config["dnssec_policy"],
+	}, nil
+}
+
+// GetNameservers returns the nameservers for a domain.
+func (c *knotProvider) GetNameservers(domain string) ([]*models.Nameserver, error) {
+	return slices.Clone(c.nameservers), nil
+}
+
+// ListZones returns all the zones configured in knotd.
+func (c *knotProvider) ListZones() ([]string, error) {
+	out, err := c.ctl.run("zone-status")
+	if err != nil {
+		return nil, err
+	}
+	zones := parseZoneStatus(out)
+	slices.Sort(zones)
+	return zones, nil
+}
+
+// EnsureZoneExists adds a zone to knotd's configuration if it is missing,
+// and gives it an initial SOA and NS records.
+func (c *knotProvider) EnsureZoneExists(domain string, _ map[string]string) error {
+	zones, err := c.ListZones()
+	if err != nil {
+		return err
+	}
+	if slices.Contains(zones, domain) {
+		return nil
+	}
+
+	c.confMu.Lock()
+	err = confTransaction(c.ctl, func() error {
+		item := confZone(domain)
+		settings := [][]string{{item}}
+		if c.template != "" {
+			settings = append(settings, []string{item + ".template", c.template})
+		}
+		if c.catalog != "" {
+			settings = append(settings,
+				[]string{item + ".catalog-role", "member"},
+				[]string{item + ".catalog-zone", c.catalog + "."})
+			if c.catalogGroup != "" {
+				settings = append(settings, []string{item + ".catalog-group", c.catalogGroup})
+			}
+		}
+		for _, s := range settings {
+			if _, err := c.ctl.run(append([]string{"conf-set"}, s...)...); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	c.confMu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	// A new zone has no contents. Knot creates them in a transaction.
+	return zoneTransaction(c.ctl, domain, func() error {
+		mname := "ns." + domain + "."
+		if len(c.nameservers) != 0 {
+			mname = c.nameservers[0].Name + "."
+		}
+		soa := fmt.Sprintf("%s hostmaster.%s. 1 3600 900 1209600 300", mname, domain)
+		if _, err := c.ctl.run("zone-set", domain, domain+".", "3600", "SOA", soa); err != nil {
+			return err
+		}
+		for _, ns := range c.nameservers {
+			if _, err := c.ctl.run("zone-set", domain, domain+".", "3600", "NS", ns.Name+"."); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteZone removes a zone from knotd's configuration. knotd stops serving
+// it; its journal and zone file are left alone (see "knotc zone-purge").
+func (c *knotProvider) DeleteZone(domain string) error {
+	c.confMu.Lock()
+	defer c.confMu.Unlock()
+	return confTransaction(c.ctl, func() error {
+		_, err := c.ctl.run("conf-unset", confZone(domain))
+		return err
+	})
+}
+
+// GetZoneRecords gets the records of a zone and returns them in RecordConfig format.
+func (c *knotProvider) GetZoneRecords(dc *models.DomainConfig) (models.Records, error) {
+	out, err := c.ctl.run("zone-read", dc.Name)
+	if err != nil {
+		return nil, err
+	}
+	lines, err := parseZoneRead(out)
+	if err != nil {
+		return nil, err
+	}
+
+	existingRecords := make(models.Records, 0, len(lines))
+	for _, zl := range lines {
+		if zl.rtype == "SOA" || slices.Contains(dnssecTypes, zl.rtype) {
+			continue
+		}
+		rc, err := toRecordConfig(zl, dc.Name)
+		if err != nil {
+			return nil, err
+		}
+		existingRecords = append(existingRecords, rc)
+	}
+	return existingRecords, nil
+}
+
+// GetZoneRecordsCorrections returns a list of corrections that will turn existing records into dc.Records.
+func (c *knotProvider) GetZoneRecordsCorrections(dc *models.DomainConfig, existingRecords models.Records) ([]*models.Correction, int, error) {
+	var corrections []*models.Correction
+
+	dnssecCorrection, err := c.getDNSSECCorrection(dc)
+	if err != nil {
+		return nil, 0, err
+	}
+	if dnssecCorrection != nil {
+		corrections = append(corrections, dnssecCorrection)
+	}
+
+	changes, actualChangeCount, err := diff2.ByRecordSet(existingRecords, dc, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// All the changes are applied in a single zone transaction.
+	var msgs []string
+	var commands [][]string
+	for _, change := range changes {
+		switch change.Type {
+		case diff2.REPORT:
+			corrections = append(corrections, change.CreateMessage())
+			continue
+		case diff2.CREATE, diff2.CHANGE, diff2.DELETE:
+		default:
+			panic(fmt.Sprintf("unhandled change.Type %s", change.Type))
+		}
+		msgs = append(msgs, change.MsgsJoined)
+		if len(change.Old) != 0 {
+			commands = append(commands, []string{"zone-unset", dc.Name, change.Key.NameFQDN + ".", change.Key.Type})
+		}
+		for _, rc := range change.New {
+			commands = append(commands, zoneSetArgs(dc.Name, rc))
+		}
+	}
+	if len(commands) != 0 {
+		corrections = append(corrections, &models.Correction{
+			Msg: strings.Join(msgs, "\n"),
+			F: func() error {
+				return zoneTransaction(c.ctl, dc.Name, func() error {
+					for _, args := range commands {
+						if _, err := c.ctl.run(args...); err != nil {
+							return err
+						}
+					}
+					return nil
+				})
+			},
+		})
+	}
+
+	return corrections, actualChangeCount, nil
+}
+
+// getDNSSECCorrection returns a correction that enables or disables DNSSEC
+// signing according to AUTODNSSEC, or nil if nothing needs to change.
+func (c *knotProvider) getDNSSECCorrection(dc *models.DomainConfig) (*models.Correction, error) {
+	if dc.AutoDNSSEC == "" {
+		return nil, nil
+	}
+	item := confZone(dc.Name)
+	out, err := c.ctl.run("conf-read", item+".dnssec-signing")
+	if err != nil {
+		return nil, err
+	}
+	signing := parseConfRead(out) == "on"
+
+	var msg string
+	var settings [][]string
+	switch {
+	case dc.AutoDNSSEC == "on" && !signing:
+		msg = "Enable DNSSEC signing"
+		settings = append(settings, []string{item + ".dnssec-signing", "on"})
+		if c.dnssecPolicy != "" {
+			msg += fmt.Sprintf(" (policy %q)", c.dnssecPolicy)
+			settings = append(settings, []string{item + ".dnssec-policy", c.dnssecPolicy})
+		}
+	case dc.AutoDNSSEC == "off" && signing:
+		msg = "Disable DNSSEC signing"
+		settings = append(settings, []string{item + ".dnssec-signing", "off"})
+	default:
+		return nil, nil
+	}
+
+	return &models.Correction{
+		Msg: msg,
+		F: func() error {
+			c.confMu.Lock()
+			defer c.confMu.Unlock()
+			return confTransaction(c.ctl, func() error {
+				for _, s := range settings {
+					if _, err := c.ctl.run(append([]string{"conf-set"}, s...)...); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		},
+	}, nil
+}
@@ -0,0 +1,202 @@
+package knot
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/DNSControl/dnscontrol/v4/models"
+)
+
+func makeDC(name string, recs ...*models.RecordConfig) *models.DomainConfig {
+	dc := &models.DomainConfig{Name: name, Records: recs}
+	dc.PostProcess()
+	return dc
+}
+
+func makeRC(label, rtype, target string) *models.RecordConfig {
+	rc := &models.RecordConfig{TTL: 300}
+	rc.SetLabel(label, "example.com")
+	if err := rc.PopulateFromString(rtype, target, "example.com"); err != nil {
+		panic(err)
+	}
+	return rc
+}
+
+// push runs all the corrections needed to make the zone match dc.
+func push(t *testing.T, p *knotProvider, dc *models.DomainConfig) (int, error) {
+	t.Helper()
+	existing, err := p.GetZoneRecords(dc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	corrections, count, err := p.GetZoneRecordsCorrections(dc, existing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range corrections {
+		if c.F == nil {
+			continue
+		}
+		if err := c.F(); err != nil {
+			return count, err
+		}
+	}
+	return count, nil
+}
+
+func TestParseZoneRead(t *testing.T) {
+	out := "[example.com.] example.com. 3600 SOA ns. hostmaster. 1 3600 900 1209600 300\n" +
+		"[example.com.] www.example.com. 300 TXT \"hello world\" \"x\"\n"
+	got, err := parseZoneRead(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []zoneLine{
+		{owner: "example.com.", ttl: 3600, rtype: "SOA", rdata: "ns. hostmaster. 1 3600 900 1209600 300"},
+		{owner: "www.example.com.", ttl: 300, rtype: "TXT", rdata: `"hello world" "x"`},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	if _, err := parseZoneRead("[example.com.] garbage"); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestKnot_RoundTrip(t *testing.T) {
+	stub := newStub("example.com")
+	p, err := newProvider(map[string]string{}, stub)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recs := []*models.RecordConfig{
+		makeRC("www", "A", "1.2.3.4"),
+		makeRC("www", "A", "1.2.3.5"),
+		makeRC("@", "MX", "10 mx.example.com."),
+		makeRC("txt", "TXT", "hello world"),
+	}
+	if n, err := push(t, p, makeDC("example.com", recs...)); err != nil || n != 4 {
+		t.Errorf("first push: got %d changes (%v), want 4", n, err)
+	}
+	if n, err := push(t, p, makeDC("example.com", recs...)); err != nil || n != 0 {
+		t.Errorf("second push: got %d changes (%v), want 0", n, err)
+	}
+	if n, err := push(t, p, makeDC("example.com", recs[0], recs[3])); err != nil || n != 2 {
+		t.Errorf("third push: got %d changes (%v), want 2", n, err)
+	}
+	if got := len(stub.zones["example.com"]); got != 3 {
+		t.Errorf("got %d records in the zone, want 3 (SOA, A, TXT): %v", got, stub.zones["example.com"])
+	}
+}
+
+func TestKnot_TransactionAborted(t *testing.T) {
+	stub := newStub("example.com")
+	p, _ := newProvider(map[string]string{}, stub)
+	stub.failOn = "zone-commit"
+
+	if _, err := push(t, p, makeDC("example.com", makeRC("www", "A", "1.2.3.4"))); err == nil {
+		t.Fatal("expected an error")
+	}
+	if len(stub.txn) != 0 {
+		t.Error("transaction left open")
+	}
+	if got := len(stub.zones["example.com"]); got != 1 {
+		t.Errorf("zone was modified: %v", stub.zones["example.com"])
+	}
+}
+
+func TestKnot_EnsureZoneExists(t *testing.T) {
+	stub := newStub()
+	p, err := newProvider(map[string]string{
+		"nameservers": "ns1.example.net,ns2.example.net",
+		"template":    "secondary",
+		"catalog":     "catalog.example.",
+	}, stub)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := p.EnsureZoneExists("example.com", nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := stub.conf["zone[example.com.].catalog-zone"]; got != "catalog.example." {
+		t.Errorf("catalog-zone: got %q", got)
+	}
+	if got := stub.conf["zone[example.com.].template"]; got != "secondary" {
+		t.Errorf("template: got %q", got)
+	}
+	if zones, _ := p.ListZones(); !slices.Equal(zones, []string{"example.com"}) {
+		t.Errorf("ListZones: got %v", zones)
+	}
+	recs, err := p.GetZoneRecords(makeDC("example.com"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recs) != 2 || recs[0].Type != "NS" {
+		t.Errorf("got %v, want two NS records", recs)
+	}
+
+	// A second call is a no-op.
+	n := len(stub.log)
+	if err := p.EnsureZoneExists("example.com", nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(stub.log) != n+1 {
+		t.Errorf("unexpected commands: %v", stub.log[n:])
+	}
+}
+
+func TestKnot_AutoDNSSEC(t *testing.T) {
+	stub := newStub("example.com")
+	p, _ := newProvider(map[string]string{"dnssec_policy": "default"}, stub)
+
+	dc := makeDC("example.com")
+	dc.AutoDNSSEC = "on"
+	if _, err := push(t, p, dc); err != nil {
+		t.Fatal(err)
+	}
+	if stub.conf["zone[example.com.].dnssec-signing"] != "on" || stub.conf["zone[example.com.].dnssec-policy"] != "default" {
+		t.Errorf("signing not enabled: %v", stub.conf)
+	}
+
+	// Records maintained by the signer are ignored.
+	stub.zones["example.com"] = append(stub.zones["example.com"], "example.com. 3600 RRSIG SOA 13 2 3600 20300101000000 20200101000000 1 example.com. AAAA")
+	corrections, _, err := p.GetZoneRecordsCorrections(dc, mustGet(t, p, dc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(corrections) != 0 {
+		t.Errorf("unexpected corrections: %v", corrections)
+	}
+
+	dc.AutoDNSSEC = "off"
+	if _, err := push(t, p, dc); err != nil {
+		t.Fatal(err)
+	}
+	if stub.conf["zone[example.com.].dnssec-signing"] != "off" {
+		t.Errorf("signing not disabled: %v", stub.conf)
+	}
+}
+
+func mustGet(t *testing.T, p *knotProvider, dc *models.DomainConfig) models.Records {
+	t.Helper()
+	recs, err := p.GetZoneRecords(dc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return recs
+}
+
+func TestKnot_DeleteZone(t *testing.T) {
+	stub := newStub("example.com", "example.net")
+	p, _ := newProvider(map[string]string{}, stub)
+
+	if err := p.DeleteZone("example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if zones, _ := p.ListZones(); !slices.Equal(zones, []string{"example.net"}) {
+		t.Errorf("ListZones: got %v", zones)
+	}
+}
@@ -0,0 +1,155 @@
+package knot
+
+// Drive a running Knot DNS server with knotc(8).
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// controller runs knotc commands and returns their output. It is an
+// interface so that the tests can replace knotc with a stub.
+type controller interface {
+	run(args ...string) (string, error)
+}
+
+// knotc runs the knotc binary, which talks to knotd over its control socket.
+type knotc struct {
+	path    string // The knotc binary.
+	socket  string // The control socket, if not the default.
+	timeout string // The control timeout in seconds, if not the default.
+}
+
+func (k *knotc) run(args ...string) (string, error) {
+	var full []string
+	if k.socket != "" {
+		full = append(full, "-s", k.socket)
+	}
+	if k.timeout != "" {
+		full = append(full, "-t", k.timeout)
+	}
+	full = append(full, args...)
+
+	out, err := exec.Command(k.path, full...).CombinedOutput()
+	text := strings.TrimSpace(string(out))
+	if err != nil || strings.HasPrefix(text, "error:") {
+		if text == "" {
+			text = err.Error()
+		}
+		return "", fmt.Errorf("knotc %s: %s", strings.Join(args, " "), text)
+	}
+	return string(out), nil
+}
+
+// zoneTransaction runs fn in a zone transaction (zone-begin/zone-commit).
+// The transaction is aborted if fn or the commit fails, leaving the zone
+// unchanged.
+func zoneTransaction(ctl controller, zone string, fn func() error) error {
+	if _, err := ctl.run("zone-begin", zone); err != nil {
+		return err
+	}
+	err := fn()
+	if err == nil {
+		_, err = ctl.run("zone-commit", zone)
+	}
+	if err != nil {
+		if _, aerr := ctl.run("zone-abort", zone); aerr != nil {
+			return errors.Join(err, aerr)
+		}
+	}
+	return err
+}
+
+// confTransaction runs fn in a configuration transaction
+// (conf-begin/conf-commit). knotd allows only one at a time.
+func confTransaction(ctl controller, fn func() error) error {
+	if _, err := ctl.run("conf-begin"); err != nil {
+		return err
+	}
+	err := fn()
+	if err == nil {
+		_, err = ctl.run("conf-commit")
+	}
+	if err != nil {
+		if _, aerr := ctl.run("conf-abort"); aerr != nil {
+			return errors.Join(err, aerr)
+		}
+	}
+	return err
+}
+
+// zoneLine is a record as output by "knotc zone-read".
+type zoneLine struct {
+	owner string
+	ttl   uint32
+	rtype string
+	rdata string
+}
+
+// parseZoneRead parses the output of "knotc zone-read", one record per line:
+//
+//	[example.com.] www.example.com. 300 A 192.0.2.1
+func parseZoneRead(out string) ([]zoneLine, error) {
+	var lines []zoneLine
+	for line := range strings.SplitSeq(out, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			_, line, _ = strings.Cut(line, "] ")
+		}
+		owner, rest, _ := strings.Cut(line, " ")
+		ttl, rest, _ := strings.Cut(rest, " ")
+		rtype, rdata, _ := strings.Cut(rest, " ")
+		var zl zoneLine
+		if _, err := fmt.Sscan(ttl, &zl.ttl); err != nil || rtype == "" {
+			return nil, fmt.Errorf("unparsable zone-read output: %q", line)
+		}
+		zl.owner = owner
+		zl.rtype = rtype
+		zl.rdata = strings.TrimSpace(rdata)
+		lines = append(lines, zl)
+	}
+	return lines, nil
+}
+
+// parseZoneStatus returns the zone names from the output of
+// "knotc zone-status", which starts each line with "[zone.]".
+func parseZoneStatus(out string) []string {
+	var zones []string
+	for line := range strings.SplitSeq(out, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "[") {
+			continue
+		}
+		name, _, ok := strings.Cut(line[1:], "]")
+		if ok && name != "" {
+			zones = append(zones, strings.TrimSuffix(name, "."))
+		}
+	}
+	return zones
+}
+
+// parseConfRead returns the value from the output of "knotc conf-read" for
+// a single item, such as:
+//
+//	zone[example.com.].dnssec-signing = on
+//
+// It returns "" if the item is not set.
+func parseConfRead(out string) string {
+	for line := range strings.SplitSeq(out, "\n") {
+		if _, value, ok := strings.Cut(line, " = "); ok {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+// confZone returns the configuration item for zone, such as
+// "zone[example.com.]".
+func confZone(zone string) string {
+	return "zone[" + zone + ".]"
+}
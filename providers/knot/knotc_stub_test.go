@@ -0,0 +1,157 @@
+package knot
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// stubKnotc is an in-memory stand-in for knotc/knotd that implements the
+// subset of the control protocol the provider uses.
+type stubKnotc struct {
+	zones  map[string][]string // zone -> "owner ttl type rdata"
+	txn    map[string][]string // zones with an open transaction
+	conf   map[string]string
+	confTx map[string]string // nil unless a configuration transaction is open
+	log    []string
+	failOn string // A command that fails, for testing error handling.
+}
+
+func newStub(zones ...string) *stubKnotc {
+	s := &stubKnotc{
+		zones: map[string][]string{},
+		txn:   map[string][]string{},
+		conf:  map[string]string{},
+	}
+	for _, z := range zones {
+		s.zones[z] = []string{z + ". 3600 SOA ns. hostmaster. 1 3600 900 1209600 300"}
+		s.conf[confZone(z)] = ""
+	}
+	return s
+}
+
+func (s *stubKnotc) run(args ...string) (string, error) {
+	s.log = append(s.log, strings.Join(args, " "))
+	if len(args) == 0 {
+		return "", fmt.Errorf("no command")
+	}
+	if s.failOn != "" && args[0] == s.failOn {
+		return "", fmt.Errorf("knotc %s: error: (injected)", args[0])
+	}
+	zone := ""
+	if len(args) > 1 {
+		zone = strings.TrimSuffix(args[1], ".")
+	}
+
+	switch args[0] {
+	case "zone-status":
+		var b strings.Builder
+		for _, z := range slices.Sorted(maps.Keys(s.zones)) {
+			fmt.Fprintf(&b, "[%s.] role: master | serial: 1\n", z)
+		}
+		return b.String(), nil
+
+	case "zone-read":
+		recs, ok := s.zones[zone]
+		if !ok {
+			return "", fmt.Errorf("error: [%s.] (no such zone found)", zone)
+		}
+		var b strings.Builder
+		for _, r := range recs {
+			fmt.Fprintf(&b, "[%s.] %s\n", zone, r)
+		}
+		return b.String(), nil
+
+	case "zone-begin":
+		if _, ok := s.conf[confZone(zone)]; !ok {
+			return "", fmt.Errorf("error: [%s.] (no such zone found)", zone)
+		}
+		if _, ok := s.txn[zone]; ok {
+			return "", fmt.Errorf("error: [%s.] (too many transactions)", zone)
+		}
+		s.txn[zone] = slices.Clone(s.zones[zone])
+		return "OK\n", nil
+
+	case "zone-set":
+		recs, ok := s.txn[zone]
+		if !ok {
+			return "", fmt.Errorf("error: [%s.] (no active transaction)", zone)
+		}
+		s.txn[zone] = append(recs, strings.Join(args[2:], " "))
+		return "OK\n", nil
+
+	case "zone-unset":
+		recs, ok := s.txn[zone]
+		if !ok {
+			return "", fmt.Errorf("error: [%s.] (no active transaction)", zone)
+		}
+		prefix := args[2] + " "
+		s.txn[zone] = slices.DeleteFunc(recs, func(r string) bool {
+			owner, rest, _ := strings.Cut(r, " ")
+			_, rest, _ = strings.Cut(rest, " ")
+			rtype, _, _ := strings.Cut(rest, " ")
+			return owner+" " == prefix && (len(args) < 4 || rtype == args[3])
+		})
+		return "OK\n", nil
+
+	case "zone-commit":
+		recs, ok := s.txn[zone]
+		if !ok {
+			return "", fmt.Errorf("error: [%s.] (no active transaction)", zone)
+		}
+		s.zones[zone] = recs
+		delete(s.txn, zone)
+		return "OK\n", nil
+
+	case "zone-abort":
+		delete(s.txn, zone)
+		return "OK\n", nil
+
+	case "conf-begin":
+		if s.confTx != nil {
+			return "", fmt.Errorf("error: (too many transactions)")
+		}
+		s.confTx = maps.Clone(s.conf)
+		return "OK\n", nil
+
+	case "conf-set":
+		if s.confTx == nil {
+			return "", fmt.Errorf("error: (no active transaction)")
+		}
+		s.confTx[args[1]] = strings.Join(args[2:], " ")
+		return "OK\n", nil
+
+	case "conf-unset":
+		if s.confTx == nil {
+			return "", fmt.Errorf("error: (no active transaction)")
+		}
+		maps.DeleteFunc(s.confTx, func(k, _ string) bool { return k == args[1] || strings.HasPrefix(k, args[1]+".") })
+		return "OK\n", nil
+
+	case "conf-commit":
+		if s.confTx == nil {
+			return "", fmt.Errorf("error: (no active transaction)")
+		}
+		s.conf = s.confTx
+		s.confTx = nil
+		// Zones removed from the configuration are no longer served.
+		for z := range s.zones {
+			if _, ok := s.conf[confZone(z)]; !ok {
+				delete(s.zones, z)
+			}
+		}
+		return "OK\n", nil
+
+	case "conf-abort":
+		s.confTx = nil
+		return "OK\n", nil
+
+	case "conf-read":
+		if v, ok := s.conf[args[1]]; ok {
+			return fmt.Sprintf("%s = %s\n", args[1], v), nil
+		}
+		return "", nil
+	}
+	return "", fmt.Errorf("error: unknown command %q", args[0])
+}
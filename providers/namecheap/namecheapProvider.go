@@ -73,6 +73,9 @@ var features = providers.DocumentationNotes{
 	providers.DocOfficiallySupported: providers.Cannot(),
 }
 
+// ttlPolicy: the API accepts TTLs from 60 to 60000.
+var ttlPolicy = providers.TTLPolicy{Min: 60, Max: 60000}
+
 func init() {
 	const providerName = "NAMECHEAP"
 	const providerMaintainer = "@willpower232"
@@ -81,7 +84,7 @@ func init() {
 		Initializer:   newDsp,
 		RecordAuditor: AuditRecords,
 	}
-	providers.RegisterDomainServiceProviderType(providerName, fns, features)
+	providers.RegisterDomainServiceProviderType(providerName, fns, features, ttlPolicy)
 	providers.RegisterCustomRecordType("URL", providerName, "")
 	providers.RegisterCustomRecordType("URL301", providerName, "")
 	providers.RegisterCustomRecordType("FRAME", providerName, "")
@@ -16,10 +16,6 @@ import (
 	dnsutilv1 "github.com/miekg/dns/dnsutil"
 )
 
-const (
-	minimumTTL = 600
-)
-
 const (
 	metaType        = "type"
 	metaIncludePath = "includePath"
@@ -100,6 +96,9 @@ var features = providers.DocumentationNotes{
 	providers.DocOfficiallySupported: providers.Cannot(),
 }
 
+// ttlPolicy: Porkbun does not support TTLs under 600.
+var ttlPolicy = providers.TTLPolicy{Min: 600}
+
 func init() {
 	const providerName = "PORKBUN"
 	const providerMaintainer = "@imlonghao"
@@ -108,7 +107,7 @@ func init() {
 		Initializer:   newDsp,
 		RecordAuditor: AuditRecords,
 	}
-	providers.RegisterDomainServiceProviderType(providerName, fns, features)
+	providers.RegisterDomainServiceProviderType(providerName, fns, features, ttlPolicy)
 	providers.RegisterMaintainer(providerName, providerMaintainer)
 	providers.RegisterCustomRecordType("PORKBUN_URLFWD", providerName, "")
 	providers.RegisterCustomRecordType("URL", providerName, "")
@@ -139,9 +138,7 @@ func (c *porkbunProvider) GetZoneRecordsCorrections(dc *models.DomainConfig, exi
 	// Block changes to NS records for base domain
 	checkNSModifications(dc)
 
-	// Make sure TTL larger than the minimum TTL
 	for _, record := range dc.Records {
-		record.TTL = fixTTL(record.TTL)
 		if isURLForwardingType(record.Type) {
 			record.TTL = 0
 			if record.Metadata == nil {
@@ -445,13 +442,6 @@ func checkNSModifications(dc *models.DomainConfig) {
 	dc.Records = newList
 }
 
-func fixTTL(ttl uint32) uint32 {
-	if ttl > minimumTTL {
-		return ttl
-	}
-	return minimumTTL
-}
-
 func (c *porkbunProvider) GetRegistrarCorrections(dc *models.DomainConfig) ([]*models.Correction, error) {
 	nss, err := c.getNameservers(dc.Name)
 	if err != nil {
@@ -6,6 +6,9 @@ import (
 	"strings"
 
 	"github.com/DNSControl/dnscontrol/v4/models"
+	"github.com/DNSControl/dnscontrol/v4/pkg/domaintags"
+	"github.com/DNSControl/dnscontrol/v4/pkg/rtypecontrol"
+	"github.com/DNSControl/dnscontrol/v4/pkg/rtypeinfo"
 	"github.com/mittwald/go-powerdns/apis/zones"
 )
 
@@ -22,6 +25,12 @@ func toRecordConfig(domain string, r zones.Record, ttl int, name string, rtype s
 	}
 	rc.SetLabel(name, domain)
 
+	if rtypeinfo.IsModernType(rtype) {
+		// Types implemented in pkg/rtype, including RAW records (PowerDNS
+		// returns their rdata in the generic notation of RFC 3597).
+		return rtypecontrol.NewRecordConfigFromString(rc.GetLabel(), uint32(ttl), rtype, r.Content, domaintags.MakeDomainNameVarieties(domain))
+	}
+
 	switch rtype {
 	case "TXT":
 		// PowerDNS API accepts long TXTs without requiring to split them.
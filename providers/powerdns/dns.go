@@ -3,6 +3,7 @@ package powerdns
 import (
 	"context"
 	"net/http"
+	"strconv"
 
 	"github.com/DNSControl/dnscontrol/v4/models"
 	"github.com/mittwald/go-powerdns/apis/zones"
@@ -21,10 +22,9 @@ func (dsp *powerdnsProvider) GetNameservers(string) ([]*models.Nameserver, error
 // GetZoneRecords gets the records of a zone and returns them in RecordConfig format.
 func (dsp *powerdnsProvider) GetZoneRecords(dc *models.DomainConfig) (models.Records, error) {
 	domain := dc.Name
-	meta := dc.Metadata
 
 	curRecords := models.Records{}
-	domainVariant := dsp.zoneName(domain, meta[models.DomainTag])
+	domainVariant := dsp.dcZoneName(dc)
 	zone, err := dsp.client.Zones().GetZone(context.Background(), dsp.ServerName, domainVariant)
 	if err != nil {
 		if _, ok := err.(pdnshttp.ErrNotFound); ok {
@@ -49,6 +49,24 @@ func (dsp *powerdnsProvider) GetZoneRecords(dc *models.DomainConfig) (models.Rec
 	return curRecords, nil
 }
 
+// GetZoneVersion implements providers.ZoneVersioner with the serial of the
+// zone. The serial only changes with every change made through the API if
+// the zone's SOA-EDIT-API is set.
+func (dsp *powerdnsProvider) GetZoneVersion(dc *models.DomainConfig) (string, error) {
+	domainVariant := dsp.dcZoneName(dc)
+	zone, err := dsp.client.Zones().GetZone(context.Background(), dsp.ServerName, domainVariant, zones.WithoutResourceRecordSets())
+	if err != nil {
+		if _, ok := err.(pdnshttp.ErrNotFound); ok {
+			return "", nil
+		}
+		return "", err
+	}
+	if zone.SOAEditAPI == 0 || zone.SOAEditAPI == zones.ZoneSOAEditAPINone {
+		return "", nil
+	}
+	return strconv.Itoa(zone.Serial), nil
+}
+
 // GetZoneRecordsCorrections returns a list of corrections that will turn existing records into dc.Records.
 func (dsp *powerdnsProvider) GetZoneRecordsCorrections(dc *models.DomainConfig, existing models.Records) ([]*models.Correction, int, error) {
 	corrections, actualChangeCount, err := dsp.getDiff2DomainCorrections(dc, existing)
@@ -62,8 +80,16 @@ func (dsp *powerdnsProvider) GetZoneRecordsCorrections(dc *models.DomainConfig,
 		return nil, 0, err
 	}
 	actualChangeCount += len(dnssecCorrections)
+	corrections = append(corrections, dnssecCorrections...)
+
+	// Zone settings and metadata, after DNSSEC since NSEC3PARAM needs it.
+	metaCorrections, err := dsp.getZoneMetaCorrections(dc)
+	if err != nil {
+		return nil, 0, err
+	}
+	actualChangeCount += len(metaCorrections)
 
-	return append(corrections, dnssecCorrections...), actualChangeCount, nil
+	return append(corrections, metaCorrections...), actualChangeCount, nil
 }
 
 // EnsureZoneExists creates a zone if it does not exist.
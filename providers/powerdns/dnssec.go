@@ -2,8 +2,16 @@ package powerdns
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/DNSControl/dnscontrol/v4/models"
+	"github.com/DNSControl/dnscontrol/v4/pkg/dnssec"
+	dnsv1 "github.com/miekg/dns"
 	"github.com/mittwald/go-powerdns/apis/cryptokeys"
 	"github.com/mittwald/go-powerdns/pdnshttp"
 )
@@ -15,7 +23,7 @@ func (dsp *powerdnsProvider) getDNSSECCorrections(dc *models.DomainConfig) ([]*m
 		return nil, nil
 	}
 
-	domainVariant := dsp.zoneName(dc.Name, dc.Tag)
+	domainVariant := dsp.dcZoneName(dc)
 	zoneCryptokeys, getErr := dsp.client.Cryptokeys().ListCryptokeys(context.Background(), dsp.ServerName, domainVariant)
 	if getErr != nil {
 		if pdnshttp.IsNotFound(getErr) {
@@ -70,3 +78,165 @@ func (dsp *powerdnsProvider) getDNSSECCorrections(dc *models.DomainConfig) ([]*m
 
 	return nil, nil
 }
+
+// Key management for "dnscontrol dnssec". PowerDNS keeps the keys and signs
+// the zone; a key is pre-published or retired by clearing its active flag
+// while leaving it published. The API does not tell those two states apart,
+// nor record when keys changed state, so the timing of each key is stored in
+// the zone metadata dnssecTimingKind.
+
+// dnssecTimingKind is the zone metadata holding the timing of the keys.
+// PowerDNS requires custom metadata kinds to start with "X-".
+const dnssecTimingKind = "X-DNSCONTROL-DNSSEC-TIMING"
+
+// keyTiming is the timing of a key, stored as JSON in dnssecTimingKind.
+type keyTiming struct {
+	Published time.Time `json:"published,omitzero"`
+	Activated time.Time `json:"activated,omitzero"`
+	Retired   time.Time `json:"retired,omitzero"`
+	Removed   time.Time `json:"removed,omitzero"`
+}
+
+// zoneMetadata is a metadata entry of a zone.
+type zoneMetadata struct {
+	Kind     string   `json:"kind"`
+	Metadata []string `json:"metadata"`
+}
+
+// cryptokeyState is the body of the requests that create or update a
+// cryptokey. Unlike cryptokeys.Cryptokey, false flags are sent.
+type cryptokeyState struct {
+	KeyType   string `json:"keytype,omitempty"`
+	Algorithm string `json:"algorithm,omitempty"`
+	Active    bool   `json:"active"`
+	Published bool   `json:"published"`
+}
+
+// DNSSECKeys returns the cryptokeys of a zone.
+func (dsp *powerdnsProvider) DNSSECKeys(zone string) ([]dnssec.Key, error) {
+	zoneID := dsp.zoneName(zone, "")
+	cks, err := dsp.client.Cryptokeys().ListCryptokeys(context.Background(), dsp.ServerName, zoneID)
+	if err != nil {
+		return nil, err
+	}
+	timings, err := dsp.keyTimings(zoneID)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []dnssec.Key
+	for _, ck := range cks {
+		k := dnssec.Key{
+			ID:   strconv.Itoa(ck.ID),
+			Role: dnssec.Role(strings.ToUpper(ck.KeyType)),
+			DS:   ck.DS,
+		}
+		if err := k.ParseDNSKEY(ck.DNSKey); err != nil {
+			return nil, fmt.Errorf("cryptokey %d: %w", ck.ID, err)
+		}
+		if t, ok := timings[k.ID]; ok {
+			k.Published, k.Activated, k.Retired, k.Removed = t.Published, t.Activated, t.Retired, t.Removed
+		} else {
+			// Not created by DNSControl; the flags are all we know.
+			if ck.Published {
+				k.Published = dnssec.Unknown
+			}
+			if ck.Active {
+				k.Activated = dnssec.Unknown
+			}
+		}
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
+
+// DNSSECCreateKey creates a cryptokey.
+func (dsp *powerdnsProvider) DNSSECCreateKey(zone string, key dnssec.Key) (dnssec.Key, error) {
+	zoneID := dsp.zoneName(zone, "")
+	now := time.Now()
+	var ck cryptokeys.Cryptokey
+	err := dsp.api.Post(context.Background(), dsp.cryptokeysPath(zoneID), &ck, pdnshttp.WithJSONRequestBody(cryptokeyState{
+		KeyType:   strings.ToLower(string(key.Role)),
+		Algorithm: dnsv1.AlgorithmToString[key.Algorithm],
+		Active:    key.State(now) == dnssec.StateActive,
+		Published: key.State(now) != dnssec.StateCreated,
+	}))
+	if err != nil {
+		return key, err
+	}
+	key.ID = strconv.Itoa(ck.ID)
+	key.DS = ck.DS
+	if err := key.ParseDNSKEY(ck.DNSKey); err != nil {
+		return key, err
+	}
+	return key, dsp.setKeyTiming(zoneID, key)
+}
+
+// DNSSECUpdateKey sets the flags of a cryptokey to match its state, or
+// deletes it once it is removed.
+func (dsp *powerdnsProvider) DNSSECUpdateKey(zone string, key dnssec.Key) error {
+	zoneID := dsp.zoneName(zone, "")
+	id, err := strconv.Atoi(key.ID)
+	if err != nil {
+		return fmt.Errorf("invalid cryptokey ID %q", key.ID)
+	}
+	state := key.State(time.Now())
+	if state == dnssec.StateRemoved {
+		if err := dsp.client.Cryptokeys().DeleteCryptokey(context.Background(), dsp.ServerName, zoneID, id); err != nil {
+			return err
+		}
+	} else {
+		err := dsp.api.Put(context.Background(), dsp.cryptokeysPath(zoneID)+"/"+key.ID, nil, pdnshttp.WithJSONRequestBody(cryptokeyState{
+			Active:    state == dnssec.StateActive,
+			Published: state != dnssec.StateCreated,
+		}))
+		if err != nil {
+			return err
+		}
+	}
+	return dsp.setKeyTiming(zoneID, key)
+}
+
+func (dsp *powerdnsProvider) cryptokeysPath(zoneID string) string {
+	return fmt.Sprintf("/servers/%s/zones/%s/cryptokeys", url.PathEscape(dsp.ServerName), url.PathEscape(zoneID))
+}
+
+func (dsp *powerdnsProvider) timingPath(zoneID string) string {
+	return dsp.metadataPath(zoneID, dnssecTimingKind)
+}
+
+// keyTimings reads the timing of the keys of a zone, by cryptokey ID.
+func (dsp *powerdnsProvider) keyTimings(zoneID string) (map[string]keyTiming, error) {
+	var md zoneMetadata
+	if err := dsp.api.Get(context.Background(), dsp.timingPath(zoneID), &md); err != nil && !pdnshttp.IsNotFound(err) {
+		return nil, err
+	}
+	timings := map[string]keyTiming{}
+	if len(md.Metadata) != 0 {
+		if err := json.Unmarshal([]byte(md.Metadata[0]), &timings); err != nil {
+			return nil, fmt.Errorf("zone metadata %s: %w", dnssecTimingKind, err)
+		}
+	}
+	return timings, nil
+}
+
+// setKeyTiming stores the timing of a key. Removed keys are forgotten.
+func (dsp *powerdnsProvider) setKeyTiming(zoneID string, key dnssec.Key) error {
+	timings, err := dsp.keyTimings(zoneID)
+	if err != nil {
+		return err
+	}
+	if key.State(time.Now()) == dnssec.StateRemoved {
+		delete(timings, key.ID)
+	} else {
+		timings[key.ID] = keyTiming{key.Published, key.Activated, key.Retired, key.Removed}
+	}
+	value, err := json.Marshal(timings)
+	if err != nil {
+		return err
+	}
+	return dsp.api.Put(context.Background(), dsp.timingPath(zoneID), nil, pdnshttp.WithJSONRequestBody(zoneMetadata{
+		Kind:     dnssecTimingKind,
+		Metadata: []string{string(value)},
+	}))
+}
@@ -3,11 +3,14 @@ package powerdns
 import (
 	"encoding/json"
 	"errors"
+	"io"
+	"net/http"
 
 	"github.com/DNSControl/dnscontrol/v4/models"
 	"github.com/DNSControl/dnscontrol/v4/pkg/providers"
 	pdns "github.com/mittwald/go-powerdns"
 	"github.com/mittwald/go-powerdns/apis/zones"
+	"github.com/mittwald/go-powerdns/pdnshttp"
 )
 
 var features = providers.DocumentationNotes{
@@ -18,18 +21,26 @@ var features = providers.DocumentationNotes{
 	providers.CanConcur:              providers.Unimplemented(),
 	providers.CanUseAlias:            providers.Can("Needs to be enabled in PowerDNS first", "https://doc.powerdns.com/authoritative/guides/alias.html"),
 	providers.CanUseCAA:              providers.Can(),
+	providers.CanUseCDNSKEY:          providers.Can(),
+	providers.CanUseCDS:              providers.Can(),
+	providers.CanUseCERT:             providers.Can(),
 	providers.CanUseDS:               providers.Can(),
 	providers.CanUseDHCID:            providers.Can(),
 	providers.CanUseLOC:              providers.Unimplemented("Normalization within the PowerDNS API seems to be buggy, so disabled", "https://github.com/PowerDNS/pdns/issues/10558"),
 	providers.CanUseNAPTR:            providers.Can(),
 	providers.CanUseOPENPGPKEY:       providers.Can(),
 	providers.CanUsePTR:              providers.Can(),
+	providers.CanUseRAW:              providers.Can(),
 	providers.CanUseSOA:              providers.Can(),
 	providers.CanUseSRV:              providers.Can(),
 	providers.CanUseSSHFP:            providers.Can(),
 	providers.CanUseTLSA:             providers.Can(),
+	providers.CanUseURI:              providers.Can(),
+	providers.CanUseZONEMD:           providers.Can(),
 	providers.CanUseDNAME:            providers.Can("Needs to be enabled in PowerDNS first", "https://doc.powerdns.com/authoritative/settings.html#setting-dname-processing"),
+	providers.CanUseHINFO:            providers.Can(),
 	providers.CanUseHTTPS:            providers.Can(),
+	providers.CanUseIPSECKEY:         providers.Can(),
 	providers.CanUseSVCB:             providers.Can(),
 	providers.CanUseDNSKEY:           providers.Can(),
 	providers.DocCreateDomains:       providers.Can(),
@@ -52,6 +63,7 @@ func init() {
 // powerdnsProvider represents the powerdnsProvider DNSServiceProvider.
 type powerdnsProvider struct {
 	client         pdns.Client
+	api            *pdnshttp.Client // For the requests that client does not support.
 	APIKey         string
 	APIUrl         string
 	ServerName     string
@@ -75,6 +87,12 @@ func (dsp *powerdnsProvider) zoneName(domain string, tag string) string {
 	return base
 }
 
+// dcZoneName returns the PowerDNS zone name of a domain, including its view.
+// All the API calls for a domain use it, so that they address the same zone.
+func (dsp *powerdnsProvider) dcZoneName(dc *models.DomainConfig) string {
+	return dsp.zoneName(dc.Name, dc.Tag)
+}
+
 // newDSP initializes a PowerDNS DNSServiceProvider.
 func newDSP(m map[string]string, metadata json.RawMessage) (providers.DNSServiceProvider, error) {
 	dsp := &powerdnsProvider{}
@@ -116,5 +134,9 @@ func newDSP(m map[string]string, metadata json.RawMessage) (providers.DNSService
 		pdns.WithBaseURL(dsp.APIUrl),
 		pdns.WithAPIKeyAuthentication(dsp.APIKey),
 	)
-	return dsp, clientErr
+	if clientErr != nil {
+		return dsp, clientErr
+	}
+	dsp.api = pdnshttp.NewClient(dsp.APIUrl, http.DefaultClient, &pdnshttp.APIKeyAuthenticator{APIKey: dsp.APIKey}, io.Discard)
+	return dsp, nil
 }
@@ -0,0 +1,199 @@
+package powerdns
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/DNSControl/dnscontrol/v4/models"
+	"github.com/mittwald/go-powerdns/pdnshttp"
+)
+
+// Zone settings and metadata set with the PDNS_* domain modifiers. Each is
+// stored in the domain's metadata, as a comma-separated list for the lists.
+// If the metadata is not set, the setting is left alone; if it is empty, the
+// setting is cleared.
+const (
+	metaZoneKind       = "pdns_zone_kind"
+	metaMasters        = "pdns_masters"
+	metaSOAEditAPI     = "pdns_soa_edit_api"
+	metaNSEC3Param     = "pdns_nsec3param"
+	metaAllowAXFRFrom  = "pdns_allow_axfr_from"
+	metaTSIGAllowAXFR  = "pdns_tsig_allow_axfr"
+	metaAXFRMasterTSIG = "pdns_axfr_master_tsig"
+)
+
+// zoneKinds are the zone kinds PowerDNS accepts.
+var zoneKinds = []string{"Native", "Master", "Slave", "Producer", "Consumer"}
+
+// soaEditAPIs are the values of SOA-EDIT-API. "" turns it off.
+var soaEditAPIs = []string{"DEFAULT", "INCREASE", "EPOCH", "SOA-EDIT", "SOA-EDIT-INCREASE", ""}
+
+// zoneMetadataKinds maps the domain metadata to the zone metadata kinds that
+// are set with the metadata API. The values of these kinds are unordered.
+var zoneMetadataKinds = []struct{ meta, kind string }{
+	{metaAllowAXFRFrom, "ALLOW-AXFR-FROM"},
+	{metaTSIGAllowAXFR, "TSIG-ALLOW-AXFR"},
+	{metaAXFRMasterTSIG, "AXFR-MASTER-TSIG"},
+}
+
+// zoneBasicData is the part of a zone that is changed with a PUT to the
+// zone. Unlike zones.ZoneBasicDataUpdate, empty values can be sent.
+type zoneBasicData struct {
+	Kind       string   `json:"kind"`
+	Masters    []string `json:"masters"`
+	SOAEditAPI string   `json:"soa_edit_api"`
+	NSEC3Param string   `json:"nsec3param"`
+}
+
+// normalizeZoneKind returns the name PowerDNS uses for a zone kind. The
+// newer names "Primary" and "Secondary" are accepted too.
+func normalizeZoneKind(kind string) (string, error) {
+	switch strings.ToLower(kind) {
+	case "primary":
+		return "Master", nil
+	case "secondary":
+		return "Slave", nil
+	}
+	for _, k := range zoneKinds {
+		if strings.EqualFold(k, kind) {
+			return k, nil
+		}
+	}
+	return "", fmt.Errorf("PDNS_ZONE_KIND: %q is not one of %s", kind, strings.Join(zoneKinds, ", "))
+}
+
+// normalizeSOAEditAPI returns the SOA-EDIT-API value as PowerDNS shows it.
+// "NONE" is what the API returns when it is off.
+func normalizeSOAEditAPI(v string) (string, error) {
+	v = strings.ToUpper(v)
+	if v == "NONE" {
+		v = ""
+	}
+	if !slices.Contains(soaEditAPIs, v) {
+		return "", fmt.Errorf("PDNS_SOA_EDIT_API: %q is not one of %s", v, strings.Join(soaEditAPIs[:len(soaEditAPIs)-1], ", "))
+	}
+	return v, nil
+}
+
+// metaList returns the list stored in the domain metadata key, and whether
+// it is set.
+func metaList(meta map[string]string, key string) ([]string, bool) {
+	v, ok := meta[key]
+	if !ok || v == "" {
+		return []string{}, ok
+	}
+	return strings.Split(v, ","), true
+}
+
+// getZoneMetaCorrections returns a correction for each zone setting or
+// metadata of the PDNS_* domain modifiers that is different in PowerDNS.
+func (dsp *powerdnsProvider) getZoneMetaCorrections(dc *models.DomainConfig) ([]*models.Correction, error) {
+	managed := false
+	for k := range dc.Metadata {
+		if strings.HasPrefix(k, "pdns_") {
+			managed = true
+		}
+	}
+	if !managed {
+		return nil, nil
+	}
+
+	zoneID := dsp.dcZoneName(dc)
+	var current zoneBasicData
+	err := dsp.api.Get(context.Background(), dsp.zonePath(zoneID), &current, pdnshttp.WithQueryValue("rrsets", "false"))
+	if err != nil && !pdnshttp.IsNotFound(err) {
+		return nil, err
+	}
+	current.SOAEditAPI, _ = normalizeSOAEditAPI(current.SOAEditAPI)
+
+	var corrections []*models.Correction
+	update := func(msg string, field string, value any) {
+		corrections = append(corrections, &models.Correction{
+			Msg: msg,
+			F: func() error {
+				return dsp.api.Put(context.Background(), dsp.zonePath(zoneID), nil, pdnshttp.WithJSONRequestBody(map[string]any{field: value}))
+			},
+		})
+	}
+
+	// The masters first, so that a zone has them when it becomes a Slave.
+	if want, ok := metaList(dc.Metadata, metaMasters); ok && !slices.Equal(want, current.Masters) {
+		update(fmt.Sprintf("Change masters from %s to %s", listString(current.Masters), listString(want)), "masters", want)
+	}
+	if v, ok := dc.Metadata[metaZoneKind]; ok {
+		want, err := normalizeZoneKind(v)
+		if err != nil {
+			return nil, err
+		}
+		if have, _ := normalizeZoneKind(current.Kind); have != want {
+			update(fmt.Sprintf("Change zone kind from %s to %s", valueString(current.Kind), want), "kind", want)
+		}
+	}
+	if v, ok := dc.Metadata[metaSOAEditAPI]; ok {
+		want, err := normalizeSOAEditAPI(v)
+		if err != nil {
+			return nil, err
+		}
+		if want != current.SOAEditAPI {
+			update(fmt.Sprintf("Change SOA-EDIT-API from %s to %s", valueString(current.SOAEditAPI), valueString(want)), "soa_edit_api", want)
+		}
+	}
+	if want, ok := dc.Metadata[metaNSEC3Param]; ok && want != current.NSEC3Param {
+		update(fmt.Sprintf("Change NSEC3PARAM from %s to %s", valueString(current.NSEC3Param), valueString(want)), "nsec3param", want)
+	}
+
+	for _, mk := range zoneMetadataKinds {
+		want, ok := metaList(dc.Metadata, mk.meta)
+		if !ok {
+			continue
+		}
+		var md zoneMetadata
+		if err := dsp.api.Get(context.Background(), dsp.metadataPath(zoneID, mk.kind), &md); err != nil && !pdnshttp.IsNotFound(err) {
+			return nil, err
+		}
+		have := slices.Sorted(slices.Values(md.Metadata))
+		want = slices.Sorted(slices.Values(want))
+		if slices.Equal(have, want) {
+			continue
+		}
+		kind := mk.kind
+		corrections = append(corrections, &models.Correction{
+			Msg: fmt.Sprintf("Change %s from %s to %s", kind, listString(have), listString(want)),
+			F: func() error {
+				if len(want) == 0 {
+					return dsp.api.Delete(context.Background(), dsp.metadataPath(zoneID, kind), nil)
+				}
+				return dsp.api.Put(context.Background(), dsp.metadataPath(zoneID, kind), nil, pdnshttp.WithJSONRequestBody(zoneMetadata{
+					Kind:     kind,
+					Metadata: want,
+				}))
+			},
+		})
+	}
+	return corrections, nil
+}
+
+func (dsp *powerdnsProvider) zonePath(zoneID string) string {
+	return fmt.Sprintf("/servers/%s/zones/%s", url.PathEscape(dsp.ServerName), url.PathEscape(zoneID))
+}
+
+func (dsp *powerdnsProvider) metadataPath(zoneID, kind string) string {
+	return fmt.Sprintf("/servers/%s/zones/%s/metadata/%s", url.PathEscape(dsp.ServerName), url.PathEscape(zoneID), kind)
+}
+
+func listString(l []string) string {
+	if len(l) == 0 {
+		return "(none)"
+	}
+	return strings.Join(l, ", ")
+}
+
+func valueString(v string) string {
+	if v == "" {
+		return "(none)"
+	}
+	return v
+}
@@ -0,0 +1,84 @@
+package powerdns
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DNSControl/dnscontrol/v4/models"
+	"github.com/mittwald/go-powerdns/pdnshttp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetZoneMetaCorrections(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Method != http.MethodGet {
+			requests = append(requests, r.Method+" "+r.URL.Path+" "+strings.TrimSpace(string(body)))
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		switch r.URL.Path {
+		case "/api/v1/servers/localhost/zones/example.com.":
+			_, _ = io.WriteString(w, `{"kind":"Native","masters":[],"soa_edit_api":"DEFAULT","nsec3param":""}`)
+		case "/api/v1/servers/localhost/zones/example.com./metadata/ALLOW-AXFR-FROM":
+			_, _ = io.WriteString(w, `{"kind":"ALLOW-AXFR-FROM","metadata":["AUTO-NS","10.0.0.0/8"]}`)
+		case "/api/v1/servers/localhost/zones/example.com./metadata/TSIG-ALLOW-AXFR":
+			_, _ = io.WriteString(w, `{"kind":"TSIG-ALLOW-AXFR","metadata":["old-key"]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	dsp := &powerdnsProvider{
+		api:        pdnshttp.NewClient(server.URL, http.DefaultClient, &pdnshttp.APIKeyAuthenticator{APIKey: "secret"}, io.Discard),
+		ServerName: "localhost",
+	}
+	corrections, err := dsp.getZoneMetaCorrections(&models.DomainConfig{
+		Name: "example.com",
+		Metadata: map[string]string{
+			metaZoneKind:      "master",
+			metaMasters:       "",
+			metaSOAEditAPI:    "DEFAULT",
+			metaAllowAXFRFrom: "10.0.0.0/8,AUTO-NS",
+			metaTSIGAllowAXFR: "",
+			metaNSEC3Param:    "1 0 0 -",
+		},
+	})
+	require.NoError(t, err)
+
+	var msgs []string
+	for _, c := range corrections {
+		msgs = append(msgs, c.Msg)
+		require.NoError(t, c.F())
+	}
+	assert.Equal(t, []string{
+		"Change zone kind from Native to Master",
+		"Change NSEC3PARAM from (none) to 1 0 0 -",
+		"Change TSIG-ALLOW-AXFR from old-key to (none)",
+	}, msgs)
+	assert.Equal(t, []string{
+		`PUT /api/v1/servers/localhost/zones/example.com. {"kind":"Master"}`,
+		`PUT /api/v1/servers/localhost/zones/example.com. {"nsec3param":"1 0 0 -"}`,
+		`DELETE /api/v1/servers/localhost/zones/example.com./metadata/TSIG-ALLOW-AXFR `,
+	}, requests)
+}
+
+func TestGetZoneMetaCorrectionsChecksValues(t *testing.T) {
+	dsp := &powerdnsProvider{}
+	corrections, err := dsp.getZoneMetaCorrections(&models.DomainConfig{Name: "example.com"})
+	require.NoError(t, err)
+	assert.Empty(t, corrections)
+
+	_, err = normalizeZoneKind("Secondary")
+	require.NoError(t, err)
+	_, err = normalizeZoneKind("Stub")
+	require.ErrorContains(t, err, "is not one of")
+	_, err = normalizeSOAEditAPI("SOMETIMES")
+	require.ErrorContains(t, err, "is not one of")
+}
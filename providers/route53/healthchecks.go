@@ -0,0 +1,274 @@
+package route53
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	r53 "github.com/aws/aws-sdk-go-v2/service/route53"
+	r53Types "github.com/aws/aws-sdk-go-v2/service/route53/types"
+
+	"github.com/DNSControl/dnscontrol/v4/models"
+)
+
+// healthCheckTag is the tag with which dnscontrol marks the health checks
+// it creates. Its value is the name of the HEALTH_CHECK(). Health checks
+// without it are never changed or deleted.
+const healthCheckTag = "dnscontrol-health-check"
+
+// loadHealthChecksLocked fetches the health checks created by dnscontrol,
+// by name. r.healthChecksMu must be held.
+func (r *route53Provider) loadHealthChecksLocked() error {
+	if r.healthChecks != nil {
+		return nil
+	}
+
+	var all []r53Types.HealthCheck
+	var marker *string
+	for {
+		var list *r53.ListHealthChecksOutput
+		var err error
+		withRetry(func() error {
+			list, err = r.client.ListHealthChecks(context.Background(), &r53.ListHealthChecksInput{Marker: marker})
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		all = append(all, list.HealthChecks...)
+		if !list.IsTruncated {
+			break
+		}
+		marker = list.NextMarker
+	}
+
+	checks := map[string]r53Types.HealthCheck{}
+	byID := map[string]r53Types.HealthCheck{}
+	var ids []string
+	for _, hc := range all {
+		byID[aws.ToString(hc.Id)] = hc
+		ids = append(ids, aws.ToString(hc.Id))
+	}
+	// ListTagsForResources accepts at most 10 IDs.
+	for batch := range slices.Chunk(ids, 10) {
+		var tags *r53.ListTagsForResourcesOutput
+		var err error
+		withRetry(func() error {
+			tags, err = r.client.ListTagsForResources(context.Background(), &r53.ListTagsForResourcesInput{
+				ResourceType: r53Types.TagResourceTypeHealthcheck,
+				ResourceIds:  batch,
+			})
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		for _, set := range tags.ResourceTagSets {
+			for _, tag := range set.Tags {
+				if aws.ToString(tag.Key) == healthCheckTag {
+					checks[aws.ToString(tag.Value)] = byID[aws.ToString(set.ResourceId)]
+				}
+			}
+		}
+	}
+	r.healthChecks = checks
+	return nil
+}
+
+// healthCheckConfig converts a HEALTH_CHECK() to a Route 53 health check
+// configuration.
+func healthCheckConfig(hc *models.HealthCheck) (*r53Types.HealthCheckConfig, error) {
+	if hc.Interval != 10 && hc.Interval != 30 {
+		return nil, fmt.Errorf("HEALTH_CHECK %q: ROUTE53 only supports an interval of 10 or 30 seconds", hc.Name)
+	}
+	if hc.FailureThreshold > 10 {
+		return nil, fmt.Errorf("HEALTH_CHECK %q: ROUTE53 only supports a failure_threshold of 1 to 10", hc.Name)
+	}
+	c := &r53Types.HealthCheckConfig{
+		Type:             r53Types.HealthCheckType(hc.Type),
+		Port:             aws.Int32(int32(hc.Port)),
+		RequestInterval:  aws.Int32(int32(hc.Interval)),
+		FailureThreshold: aws.Int32(int32(hc.FailureThreshold)),
+	}
+	if hc.Host != "" {
+		c.FullyQualifiedDomainName = aws.String(hc.Host)
+	}
+	if hc.IP != "" {
+		c.IPAddress = aws.String(hc.IP)
+	}
+	if hc.Path != "" {
+		c.ResourcePath = aws.String(hc.Path)
+	}
+	if hc.Type == "HTTPS" && hc.Host != "" {
+		c.EnableSNI = aws.Bool(true)
+	}
+	return c, nil
+}
+
+// describeHealthCheckConfig returns the fields of c that dnscontrol
+// manages, to compare the existing and desired health checks.
+func describeHealthCheckConfig(c *r53Types.HealthCheckConfig) string {
+	return fmt.Sprintf("%s %s %s:%d%s every %ds, unhealthy after %d failures, sni=%t",
+		c.Type, aws.ToString(c.FullyQualifiedDomainName), aws.ToString(c.IPAddress), aws.ToInt32(c.Port),
+		aws.ToString(c.ResourcePath), aws.ToInt32(c.RequestInterval), aws.ToInt32(c.FailureThreshold), aws.ToBool(c.EnableSNI))
+}
+
+// GetHealthCheckCorrections implements providers.HealthCheckManager.
+func (r *route53Provider) GetHealthCheckCorrections(checks []*models.HealthCheck) (corrections, deletions []*models.Correction, err error) {
+	r.healthChecksMu.Lock()
+	defer r.healthChecksMu.Unlock()
+	if err := r.loadHealthChecksLocked(); err != nil {
+		return nil, nil, err
+	}
+
+	for _, hc := range checks {
+		want, err := healthCheckConfig(hc)
+		if err != nil {
+			return nil, nil, err
+		}
+		existing, ok := r.healthChecks[hc.Name]
+		if !ok {
+			corrections = append(corrections, &models.Correction{
+				Msg: fmt.Sprintf("+ CREATE HEALTH_CHECK %s: %s", hc.Name, hc),
+				F:   func() error { return r.createHealthCheck(hc.Name, want) },
+			})
+			continue
+		}
+
+		have := existing.HealthCheckConfig
+		if describeHealthCheckConfig(have) == describeHealthCheckConfig(want) {
+			continue
+		}
+		if have.Type != want.Type || aws.ToInt32(have.RequestInterval) != aws.ToInt32(want.RequestInterval) {
+			return nil, nil, fmt.Errorf("HEALTH_CHECK %q: ROUTE53 can not change the type or interval of a health check; give it a new name to replace it", hc.Name)
+		}
+		input := &r53.UpdateHealthCheckInput{
+			HealthCheckId:            existing.Id,
+			HealthCheckVersion:       existing.HealthCheckVersion,
+			FullyQualifiedDomainName: want.FullyQualifiedDomainName,
+			IPAddress:                want.IPAddress,
+			Port:                     want.Port,
+			ResourcePath:             want.ResourcePath,
+			FailureThreshold:         want.FailureThreshold,
+			EnableSNI:                aws.Bool(aws.ToBool(want.EnableSNI)),
+		}
+		if want.FullyQualifiedDomainName == nil && have.FullyQualifiedDomainName != nil {
+			input.ResetElements = append(input.ResetElements, r53Types.ResettableElementNameFullyQualifiedDomainName)
+		}
+		if want.ResourcePath == nil && have.ResourcePath != nil {
+			input.ResetElements = append(input.ResetElements, r53Types.ResettableElementNameResourcePath)
+		}
+		corrections = append(corrections, &models.Correction{
+			Msg: fmt.Sprintf("± MODIFY HEALTH_CHECK %s: %s (id %s)", hc.Name, hc, aws.ToString(existing.Id)),
+			F:   func() error { return r.updateHealthCheck(hc.Name, input) },
+		})
+	}
+
+	var unused []string
+	for name := range r.healthChecks {
+		if !slices.ContainsFunc(checks, func(hc *models.HealthCheck) bool { return hc.Name == name }) {
+			unused = append(unused, name)
+		}
+	}
+	slices.Sort(unused)
+	for _, name := range unused {
+		id := r.healthChecks[name].Id
+		deletions = append(deletions, &models.Correction{
+			Msg: fmt.Sprintf("- DELETE HEALTH_CHECK %s (id %s)", name, aws.ToString(id)),
+			F:   func() error { return r.deleteHealthCheck(name, id) },
+		})
+	}
+	return corrections, deletions, nil
+}
+
+func (r *route53Provider) createHealthCheck(name string, config *r53Types.HealthCheckConfig) error {
+	var out *r53.CreateHealthCheckOutput
+	var err error
+	withRetry(func() error {
+		out, err = r.client.CreateHealthCheck(context.Background(), &r53.CreateHealthCheckInput{
+			// The caller reference must be unique, even across deleted health checks.
+			CallerReference:   aws.String(name + "-" + strconv.FormatInt(time.Now().UnixNano(), 36)),
+			HealthCheckConfig: config,
+		})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	withRetry(func() error {
+		_, err = r.client.ChangeTagsForResource(context.Background(), &r53.ChangeTagsForResourceInput{
+			ResourceId:   out.HealthCheck.Id,
+			ResourceType: r53Types.TagResourceTypeHealthcheck,
+			AddTags: []r53Types.Tag{
+				{Key: aws.String(healthCheckTag), Value: aws.String(name)},
+				{Key: aws.String("Name"), Value: aws.String(name)},
+			},
+		})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("health check %s was created with id %s, but could not be tagged: %w", name, aws.ToString(out.HealthCheck.Id), err)
+	}
+	r.healthChecksMu.Lock()
+	r.healthChecks[name] = *out.HealthCheck
+	r.healthChecksMu.Unlock()
+	return nil
+}
+
+func (r *route53Provider) updateHealthCheck(name string, input *r53.UpdateHealthCheckInput) error {
+	var out *r53.UpdateHealthCheckOutput
+	var err error
+	withRetry(func() error {
+		out, err = r.client.UpdateHealthCheck(context.Background(), input)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	r.healthChecksMu.Lock()
+	r.healthChecks[name] = *out.HealthCheck
+	r.healthChecksMu.Unlock()
+	return nil
+}
+
+func (r *route53Provider) deleteHealthCheck(name string, id *string) error {
+	var err error
+	withRetry(func() error {
+		_, err = r.client.DeleteHealthCheck(context.Background(), &r53.DeleteHealthCheckInput{HealthCheckId: id})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	r.healthChecksMu.Lock()
+	delete(r.healthChecks, name)
+	r.healthChecksMu.Unlock()
+	return nil
+}
+
+// resolveHealthChecks replaces the names of the HEALTH_CHECK()s used by
+// the records of dc with their IDs. A health check that does not exist
+// yet keeps its name, which "push" replaces once it has created it.
+func (r *route53Provider) resolveHealthChecks(dc *models.DomainConfig) error {
+	if !slices.ContainsFunc(dc.Records, func(rc *models.RecordConfig) bool { return rc.Metadata["health_check"] != "" }) {
+		return nil
+	}
+	r.healthChecksMu.Lock()
+	defer r.healthChecksMu.Unlock()
+	if err := r.loadHealthChecksLocked(); err != nil {
+		return err
+	}
+	for _, rc := range dc.Records {
+		name := rc.Metadata["health_check"]
+		if name == "" || rc.Metadata["r53_health_check_id"] != name {
+			continue
+		}
+		if hc, ok := r.healthChecks[name]; ok {
+			rc.Metadata["r53_health_check_id"] = aws.ToString(hc.Id)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package route53
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	r53Types "github.com/aws/aws-sdk-go-v2/service/route53/types"
+
+	"github.com/DNSControl/dnscontrol/v4/models"
+)
+
+func TestHealthCheckConfig(t *testing.T) {
+	hc := &models.HealthCheck{Name: "web", Type: "HTTPS", Host: "www.example.com", Port: 443, Path: "/", Interval: 30, FailureThreshold: 3}
+	got, err := healthCheckConfig(hc)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The configuration Route 53 returns for the same health check.
+	existing := &r53Types.HealthCheckConfig{
+		Type:                     r53Types.HealthCheckTypeHttps,
+		FullyQualifiedDomainName: aws.String("www.example.com"),
+		Port:                     aws.Int32(443),
+		ResourcePath:             aws.String("/"),
+		RequestInterval:          aws.Int32(30),
+		FailureThreshold:         aws.Int32(3),
+		EnableSNI:                aws.Bool(true),
+	}
+	if describeHealthCheckConfig(got) != describeHealthCheckConfig(existing) {
+		t.Errorf("got %q, want %q", describeHealthCheckConfig(got), describeHealthCheckConfig(existing))
+	}
+
+	existing.ResourcePath = aws.String("/health")
+	if describeHealthCheckConfig(got) == describeHealthCheckConfig(existing) {
+		t.Errorf("a different path was not detected")
+	}
+
+	for _, bad := range []models.HealthCheck{
+		{Name: "slow", Type: "HTTP", Host: "example.com", Interval: 60, FailureThreshold: 3},
+		{Name: "patient", Type: "HTTP", Host: "example.com", Interval: 30, FailureThreshold: 11},
+	} {
+		if _, err := healthCheckConfig(&bad); err == nil || !strings.Contains(err.Error(), bad.Name) {
+			t.Errorf("%+v: expected an error, got %v", bad, err)
+		}
+	}
+}
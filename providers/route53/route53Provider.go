@@ -37,6 +37,9 @@ type route53Provider struct {
 	zonesMu       sync.Mutex
 	zonesByID     map[string]r53Types.HostedZone
 	zonesByDomain map[string]r53Types.HostedZone
+
+	healthChecksMu sync.Mutex
+	healthChecks   map[string]r53Types.HealthCheck // HEALTH_CHECK() name to health check
 }
 
 func newRoute53Reg(conf map[string]string) (providers.Registrar, error) {
@@ -125,12 +128,14 @@ var features = providers.DocumentationNotes{
 	providers.CanConcur:              providers.Can(),
 	providers.CanUseAlias:            providers.Cannot("R53 does not provide a generic ALIAS functionality. Use R53_ALIAS instead."),
 	providers.CanUseCAA:              providers.Can(),
+	providers.CanUseHealthChecks:     providers.Can(),
 	providers.CanUseHTTPS:            providers.Can(),
 	providers.CanUseLOC:              providers.Cannot(),
 	providers.CanUsePTR:              providers.Can(),
 	providers.CanUseRoute53Alias:     providers.Can(),
 	providers.CanUseSRV:              providers.Can(),
 	providers.CanUseSSHFP:            providers.Can(),
+	providers.CanUseSteering:         providers.Can("STEER() is translated to weighted, geolocation or failover routing."),
 	providers.CanUseSVCB:             providers.Can(),
 	providers.CanUseTLSA:             providers.Can(),
 	providers.DocCreateDomains:       providers.Can(),
@@ -411,6 +416,10 @@ func (r *route53Provider) GetZoneRecordsCorrections(dc *models.DomainConfig, exi
 		return nil, 0, err
 	}
 
+	if err := r.resolveHealthChecks(dc); err != nil {
+		return nil, 0, err
+	}
+
 	// update zone_id to current zone.id if not specified by the user
 	for _, want := range dc.Records {
 		if want.Type == "R53_ALIAS" && want.R53Alias["zone_id"] == "" {
@@ -621,51 +630,6 @@ func nativeToRecords(set r53Types.ResourceRecordSet, origin string) ([]*models.R
 	return results, nil
 }
 
-// applyR53RoutingMeta populates RecordConfig metadata from native Route 53
-// routing-policy fields (SetIdentifier, Weight, HealthCheckId).
-func applyR53RoutingMeta(rc *models.RecordConfig, set r53Types.ResourceRecordSet) {
-	if set.SetIdentifier == nil {
-		return
-	}
-	if rc.Metadata == nil {
-		rc.Metadata = map[string]string{}
-	}
-	rc.Metadata["r53_set_identifier"] = aws.ToString(set.SetIdentifier)
-	if set.Weight != nil {
-		rc.Metadata["r53_weight"] = strconv.FormatInt(*set.Weight, 10)
-	}
-	if set.HealthCheckId != nil {
-		rc.Metadata["r53_health_check_id"] = aws.ToString(set.HealthCheckId)
-	}
-}
-
-// r53ComparableFunc includes Route 53 routing-policy metadata in record
-// comparison so that changes to weight or health check are detected by the diff.
-func r53ComparableFunc(rc *models.RecordConfig) string {
-	var parts []string
-	if w, ok := rc.Metadata["r53_weight"]; ok && w != "" {
-		parts = append(parts, "r53_weight="+w)
-	}
-	if hc, ok := rc.Metadata["r53_health_check_id"]; ok && hc != "" {
-		parts = append(parts, "r53_health_check_id="+hc)
-	}
-	return strings.Join(parts, ",")
-}
-
-// applyR53RoutingFieldsToRRSet sets the Route 53 weighted routing fields on a
-// ResourceRecordSet based on the RecordConfig metadata.
-func applyR53RoutingFieldsToRRSet(rrset *r53Types.ResourceRecordSet, rc *models.RecordConfig) {
-	if w, ok := rc.Metadata["r53_weight"]; ok && w != "" {
-		weight, err := strconv.ParseInt(w, 10, 64)
-		if err == nil {
-			rrset.Weight = &weight
-		}
-	}
-	if hc, ok := rc.Metadata["r53_health_check_id"]; ok && hc != "" {
-		rrset.HealthCheckId = aws.String(hc)
-	}
-}
-
 func aliasToRRSet(zone r53Types.HostedZone, r *models.RecordConfig) *r53Types.ResourceRecordSet {
 	target := r.GetTargetField()
 	zoneID := getZoneID(zone, r)
@@ -0,0 +1,309 @@
+package route53
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	r53Types "github.com/aws/aws-sdk-go-v2/service/route53/types"
+
+	"github.com/DNSControl/dnscontrol/v4/models"
+)
+
+// r53RoutingPolicies returns the names of the routing policies set in the
+// metadata of rc. A valid record has at most one.
+func r53RoutingPolicies(rc *models.RecordConfig) []string {
+	var policies []string
+	has := func(keys ...string) bool {
+		return slices.ContainsFunc(keys, func(k string) bool { return rc.Metadata[k] != "" })
+	}
+	if has("r53_weight") {
+		policies = append(policies, "weighted")
+	}
+	if has("r53_region") {
+		policies = append(policies, "latency")
+	}
+	if has("r53_geo_continent", "r53_geo_country", "r53_geo_subdivision") {
+		policies = append(policies, "geolocation")
+	}
+	if has("r53_geoproximity_region", "r53_geoproximity_local_zone_group", "r53_geoproximity_coordinates", "r53_geoproximity_bias") {
+		policies = append(policies, "geoproximity")
+	}
+	if has("r53_failover") {
+		policies = append(policies, "failover")
+	}
+	if has("r53_multivalue") {
+		policies = append(policies, "multivalue")
+	}
+	return policies
+}
+
+// parseR53Coordinates splits the "latitude,longitude" of
+// r53_geoproximity_coordinates.
+func parseR53Coordinates(s string) (lat, long string, err error) {
+	lat, long, ok := strings.Cut(s, ",")
+	if !ok {
+		return "", "", fmt.Errorf("r53_geoproximity_coordinates %q is not \"latitude,longitude\"", s)
+	}
+	lat, long = strings.TrimSpace(lat), strings.TrimSpace(long)
+	if f, err := strconv.ParseFloat(lat, 64); err != nil || f < -90 || f > 90 {
+		return "", "", fmt.Errorf("r53_geoproximity_coordinates latitude %q must be between -90 and 90", lat)
+	}
+	if f, err := strconv.ParseFloat(long, 64); err != nil || f < -180 || f > 180 {
+		return "", "", fmt.Errorf("r53_geoproximity_coordinates longitude %q must be between -180 and 180", long)
+	}
+	return lat, long, nil
+}
+
+// applyR53RoutingMeta populates RecordConfig metadata from native Route 53
+// routing-policy fields.
+func applyR53RoutingMeta(rc *models.RecordConfig, set r53Types.ResourceRecordSet) {
+	if set.SetIdentifier == nil {
+		return
+	}
+	if rc.Metadata == nil {
+		rc.Metadata = map[string]string{}
+	}
+	m := rc.Metadata
+	m["r53_set_identifier"] = aws.ToString(set.SetIdentifier)
+	if set.Weight != nil {
+		m["r53_weight"] = strconv.FormatInt(*set.Weight, 10)
+	}
+	if set.Region != "" {
+		m["r53_region"] = string(set.Region)
+	}
+	if g := set.GeoLocation; g != nil {
+		if g.ContinentCode != nil {
+			m["r53_geo_continent"] = aws.ToString(g.ContinentCode)
+		}
+		if g.CountryCode != nil {
+			m["r53_geo_country"] = aws.ToString(g.CountryCode)
+		}
+		if g.SubdivisionCode != nil {
+			m["r53_geo_subdivision"] = aws.ToString(g.SubdivisionCode)
+		}
+	}
+	if g := set.GeoProximityLocation; g != nil {
+		if g.AWSRegion != nil {
+			m["r53_geoproximity_region"] = aws.ToString(g.AWSRegion)
+		}
+		if g.LocalZoneGroup != nil {
+			m["r53_geoproximity_local_zone_group"] = aws.ToString(g.LocalZoneGroup)
+		}
+		if c := g.Coordinates; c != nil {
+			m["r53_geoproximity_coordinates"] = aws.ToString(c.Latitude) + "," + aws.ToString(c.Longitude)
+		}
+		m["r53_geoproximity_bias"] = strconv.Itoa(int(aws.ToInt32(g.Bias)))
+	}
+	if set.Failover != "" {
+		m["r53_failover"] = string(set.Failover)
+	}
+	if aws.ToBool(set.MultiValueAnswer) {
+		m["r53_multivalue"] = "true"
+	}
+	if set.HealthCheckId != nil {
+		m["r53_health_check_id"] = aws.ToString(set.HealthCheckId)
+	}
+}
+
+// r53ComparableFunc includes Route 53 routing-policy metadata in record
+// comparison so that changes to the routing policy or health check are
+// detected by the diff.
+func r53ComparableFunc(rc *models.RecordConfig) string {
+	var parts []string
+	for _, k := range models.R53RoutingKeys {
+		v := rc.Metadata[k.Key]
+		if v == "" || (k.Key == "r53_geoproximity_bias" && v == "0") {
+			continue
+		}
+		parts = append(parts, k.Key+"="+v)
+	}
+	return strings.Join(parts, ",")
+}
+
+// applyR53RoutingFieldsToRRSet sets the Route 53 routing fields on a
+// ResourceRecordSet based on the RecordConfig metadata.
+func applyR53RoutingFieldsToRRSet(rrset *r53Types.ResourceRecordSet, rc *models.RecordConfig) {
+	m := rc.Metadata
+	if w := m["r53_weight"]; w != "" {
+		weight, err := strconv.ParseInt(w, 10, 64)
+		if err == nil {
+			rrset.Weight = &weight
+		}
+	}
+	if r := m["r53_region"]; r != "" {
+		rrset.Region = r53Types.ResourceRecordSetRegion(r)
+	}
+	if m["r53_geo_continent"] != "" || m["r53_geo_country"] != "" {
+		g := &r53Types.GeoLocation{}
+		if v := m["r53_geo_continent"]; v != "" {
+			g.ContinentCode = aws.String(v)
+		}
+		if v := m["r53_geo_country"]; v != "" {
+			g.CountryCode = aws.String(v)
+		}
+		if v := m["r53_geo_subdivision"]; v != "" {
+			g.SubdivisionCode = aws.String(v)
+		}
+		rrset.GeoLocation = g
+	}
+	if m["r53_geoproximity_region"] != "" || m["r53_geoproximity_local_zone_group"] != "" || m["r53_geoproximity_coordinates"] != "" {
+		g := &r53Types.GeoProximityLocation{}
+		if v := m["r53_geoproximity_region"]; v != "" {
+			g.AWSRegion = aws.String(v)
+		}
+		if v := m["r53_geoproximity_local_zone_group"]; v != "" {
+			g.LocalZoneGroup = aws.String(v)
+		}
+		if v := m["r53_geoproximity_coordinates"]; v != "" {
+			if lat, long, err := parseR53Coordinates(v); err == nil {
+				g.Coordinates = &r53Types.Coordinates{Latitude: aws.String(lat), Longitude: aws.String(long)}
+			}
+		}
+		if v := m["r53_geoproximity_bias"]; v != "" {
+			if bias, err := strconv.ParseInt(v, 10, 32); err == nil && bias != 0 {
+				g.Bias = aws.Int32(int32(bias))
+			}
+		}
+		rrset.GeoProximityLocation = g
+	}
+	if f := m["r53_failover"]; f != "" {
+		rrset.Failover = r53Types.ResourceRecordSetFailover(f)
+	}
+	if m["r53_multivalue"] == "true" {
+		rrset.MultiValueAnswer = aws.Bool(true)
+	}
+	if hc := m["r53_health_check_id"]; hc != "" {
+		rrset.HealthCheckId = aws.String(hc)
+	}
+}
+
+// rejectifInvalidR53Routing validates Route 53 routing-policy metadata.
+func rejectifInvalidR53Routing(rc *models.RecordConfig) error {
+	setID := rc.Metadata["r53_set_identifier"]
+	policies := r53RoutingPolicies(rc)
+	where := rc.Type + " " + rc.GetLabelFQDN()
+
+	if len(policies) == 0 && setID == "" {
+		return nil
+	}
+	if len(policies) > 1 {
+		return fmt.Errorf("only one Route 53 routing policy can be used, found %s on %s", strings.Join(policies, " and "), where)
+	}
+
+	if len(policies) == 0 {
+		return fmt.Errorf("r53_set_identifier is set but r53_weight is missing on %s", where)
+	}
+	if setID == "" {
+		if policies[0] == "weighted" {
+			return fmt.Errorf("r53_weight is set but r53_set_identifier is missing on %s", where)
+		}
+		return fmt.Errorf("%s routing is set but r53_set_identifier is missing on %s", policies[0], where)
+	}
+	if len(setID) > 128 {
+		return fmt.Errorf("r53_set_identifier must be 128 characters or fewer on %s", where)
+	}
+
+	m := rc.Metadata
+	switch policies[0] {
+	case "weighted":
+		weight := m["r53_weight"]
+		w, err := strconv.ParseInt(weight, 10, 64)
+		if err != nil {
+			return fmt.Errorf("r53_weight %q is not a valid integer on %s", weight, where)
+		}
+		if w < 0 || w > 255 {
+			return fmt.Errorf("r53_weight %d must be between 0 and 255 on %s", w, where)
+		}
+	case "geolocation":
+		continent, country, subdivision := m["r53_geo_continent"], m["r53_geo_country"], m["r53_geo_subdivision"]
+		switch {
+		case continent != "" && (country != "" || subdivision != ""):
+			return fmt.Errorf("r53_geo_continent can not be combined with r53_geo_country on %s", where)
+		case continent != "" && !slices.Contains(models.Continents, continent):
+			return fmt.Errorf("r53_geo_continent %q must be one of %s on %s", continent, strings.Join(models.Continents, ", "), where)
+		case subdivision != "" && country == "":
+			return fmt.Errorf("r53_geo_subdivision requires r53_geo_country on %s", where)
+		}
+	case "geoproximity":
+		n := 0
+		for _, k := range []string{"r53_geoproximity_region", "r53_geoproximity_local_zone_group", "r53_geoproximity_coordinates"} {
+			if m[k] != "" {
+				n++
+			}
+		}
+		if n != 1 {
+			return fmt.Errorf("geoproximity routing needs exactly one of a region, a local zone group or coordinates on %s", where)
+		}
+		if c := m["r53_geoproximity_coordinates"]; c != "" {
+			if _, _, err := parseR53Coordinates(c); err != nil {
+				return fmt.Errorf("%w on %s", err, where)
+			}
+		}
+		if b := m["r53_geoproximity_bias"]; b != "" {
+			bias, err := strconv.ParseInt(b, 10, 32)
+			if err != nil || bias < -99 || bias > 99 {
+				return fmt.Errorf("r53_geoproximity_bias %q must be an integer between -99 and 99 on %s", b, where)
+			}
+		}
+	case "failover":
+		if f := m["r53_failover"]; f != string(r53Types.ResourceRecordSetFailoverPrimary) && f != string(r53Types.ResourceRecordSetFailoverSecondary) {
+			return fmt.Errorf("r53_failover %q must be PRIMARY or SECONDARY on %s", f, where)
+		}
+	case "multivalue":
+		if v := m["r53_multivalue"]; v != "true" {
+			return fmt.Errorf("r53_multivalue %q must be \"true\" on %s", v, where)
+		}
+	}
+	return nil
+}
+
+// steerToR53 maps the STEER() metadata keys to the Route 53 ones.
+var steerToR53 = map[string]string{
+	"steer_id":              "r53_set_identifier",
+	"steer_weight":          "r53_weight",
+	"steer_geo_continent":   "r53_geo_continent",
+	"steer_geo_country":     "r53_geo_country",
+	"steer_geo_subdivision": "r53_geo_subdivision",
+	"steer_failover":        "r53_failover",
+	"steer_health_check":    "r53_health_check_id",
+}
+
+// translateSteering sets the Route 53 routing metadata of the records with
+// a provider-neutral STEER() policy. The STEER() id becomes the set
+// identifier and the health check a health check ID. A record can not
+// also set a different Route 53 routing policy.
+func translateSteering(records []*models.RecordConfig) (errs []error) {
+	for _, rc := range records {
+		if rc.Metadata["steer_id"] == "" {
+			continue
+		}
+		want := map[string]string{}
+		for steer, r53 := range steerToR53 {
+			if v := rc.Metadata[steer]; v != "" {
+				want[r53] = v
+			}
+		}
+		conflict := ""
+		keys := []string{"r53_set_identifier"}
+		for _, k := range models.R53RoutingKeys {
+			keys = append(keys, k.Key)
+		}
+		for _, k := range keys {
+			if v := rc.Metadata[k]; v != "" && v != want[k] {
+				conflict = k
+				break
+			}
+		}
+		if conflict != "" {
+			errs = append(errs, fmt.Errorf("STEER() can not be combined with %s on %s %s", conflict, rc.Type, rc.GetLabelFQDN()))
+			continue
+		}
+		for k, v := range want {
+			rc.Metadata[k] = v
+		}
+	}
+	return errs
+}
@@ -0,0 +1,98 @@
+package route53
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	r53Types "github.com/aws/aws-sdk-go-v2/service/route53/types"
+
+	"github.com/DNSControl/dnscontrol/v4/models"
+)
+
+func TestR53RoutingRoundTrip(t *testing.T) {
+	tests := []map[string]string{
+		{"r53_set_identifier": "a", "r53_weight": "70", "r53_health_check_id": "hc-1"},
+		{"r53_set_identifier": "a", "r53_region": "us-east-1"},
+		{"r53_set_identifier": "a", "r53_geo_continent": "EU"},
+		{"r53_set_identifier": "a", "r53_geo_country": "US", "r53_geo_subdivision": "CA"},
+		{"r53_set_identifier": "a", "r53_geo_country": "*"},
+		{"r53_set_identifier": "a", "r53_geoproximity_region": "eu-west-1", "r53_geoproximity_bias": "-20"},
+		{"r53_set_identifier": "a", "r53_geoproximity_coordinates": "49.22,-74.01", "r53_geoproximity_bias": "0"},
+		{"r53_set_identifier": "a", "r53_geoproximity_local_zone_group": "us-east-1-bue-1", "r53_geoproximity_bias": "5"},
+		{"r53_set_identifier": "a", "r53_failover": "SECONDARY", "r53_health_check_id": "hc-2"},
+		{"r53_set_identifier": "a", "r53_multivalue": "true"},
+	}
+	for _, meta := range tests {
+		rc := &models.RecordConfig{Type: "A", Metadata: meta}
+		if err := rejectifInvalidR53Routing(rc); err != nil {
+			t.Errorf("%v: unexpected audit error: %v", meta, err)
+		}
+		rrset := &r53Types.ResourceRecordSet{SetIdentifier: aws.String(meta["r53_set_identifier"])}
+		applyR53RoutingFieldsToRRSet(rrset, rc)
+		got := &models.RecordConfig{Type: "A"}
+		applyR53RoutingMeta(got, *rrset)
+		if want, have := r53ComparableFunc(rc), r53ComparableFunc(got); want != have {
+			t.Errorf("%v: round trip changed the routing: want %q, got %q", meta, want, have)
+		}
+	}
+}
+
+func TestRejectifInvalidR53Routing(t *testing.T) {
+	tests := []struct {
+		meta map[string]string
+		want string
+	}{
+		{map[string]string{}, ""},
+		{map[string]string{"r53_weight": "10"}, "r53_set_identifier is missing"},
+		{map[string]string{"r53_set_identifier": "a"}, "r53_weight is missing"},
+		{map[string]string{"r53_region": "us-east-1"}, "latency routing is set but r53_set_identifier is missing"},
+		{map[string]string{"r53_set_identifier": "a", "r53_weight": "10", "r53_failover": "PRIMARY"}, "found weighted and failover"},
+		{map[string]string{"r53_set_identifier": "a", "r53_weight": "256"}, "between 0 and 255"},
+		{map[string]string{"r53_set_identifier": "a", "r53_geo_continent": "XX"}, "r53_geo_continent \"XX\""},
+		{map[string]string{"r53_set_identifier": "a", "r53_geo_continent": "EU", "r53_geo_country": "FR"}, "can not be combined"},
+		{map[string]string{"r53_set_identifier": "a", "r53_geo_subdivision": "CA"}, "requires r53_geo_country"},
+		{map[string]string{"r53_set_identifier": "a", "r53_geoproximity_bias": "10"}, "exactly one of"},
+		{map[string]string{"r53_set_identifier": "a", "r53_geoproximity_region": "us-east-1", "r53_geoproximity_bias": "100"}, "between -99 and 99"},
+		{map[string]string{"r53_set_identifier": "a", "r53_geoproximity_coordinates": "91,0"}, "latitude"},
+		{map[string]string{"r53_set_identifier": "a", "r53_failover": "TERTIARY"}, "PRIMARY or SECONDARY"},
+		{map[string]string{"r53_set_identifier": strings.Repeat("x", 129), "r53_multivalue": "true"}, "128 characters"},
+	}
+	for _, tt := range tests {
+		rc := &models.RecordConfig{Type: "A", Metadata: tt.meta}
+		rc.SetLabel("www", "example.com")
+		err := rejectifInvalidR53Routing(rc)
+		switch {
+		case tt.want == "" && err != nil:
+			t.Errorf("%v: unexpected error: %v", tt.meta, err)
+		case tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)):
+			t.Errorf("%v: expected an error containing %q, got %v", tt.meta, tt.want, err)
+		}
+	}
+}
+
+func TestTranslateSteering(t *testing.T) {
+	rc := &models.RecordConfig{Type: "A", Metadata: map[string]string{
+		"steer_id": "main", "steer_failover": "PRIMARY", "steer_health_check": "hc-1",
+	}}
+	rc.SetLabel("www", "example.com")
+	if errs := translateSteering([]*models.RecordConfig{rc}); len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	want := "r53_failover=PRIMARY,r53_health_check_id=hc-1"
+	if got := r53ComparableFunc(rc); got != want || rc.Metadata["r53_set_identifier"] != "main" {
+		t.Errorf("got %q (set identifier %q), want %q (set identifier \"main\")", got, rc.Metadata["r53_set_identifier"], want)
+	}
+	// Translating again is a no-op.
+	if errs := translateSteering([]*models.RecordConfig{rc}); len(errs) != 0 {
+		t.Errorf("unexpected errors on the second translation: %v", errs)
+	}
+
+	conflict := &models.RecordConfig{Type: "A", Metadata: map[string]string{
+		"steer_id": "main", "steer_weight": "10", "r53_region": "us-east-1",
+	}}
+	conflict.SetLabel("www", "example.com")
+	if errs := translateSteering([]*models.RecordConfig{conflict}); len(errs) != 1 || !strings.Contains(errs[0].Error(), "r53_region") {
+		t.Errorf("expected an r53_region conflict, got %v", errs)
+	}
+}